@@ -0,0 +1,60 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/containeroo/autovpa/internal/config"
+)
+
+// Subcommand names.
+const (
+	commandSchema        string = "schema"
+	commandPrintProfiles string = "print-profiles"
+)
+
+// runCommand executes the subcommand named by args[0].
+// It reports handled=false when args do not start with a known subcommand.
+func runCommand(ctx context.Context, args []string, version string, stdOut, stdErr io.Writer) (handled bool, err error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case commandSchema:
+		return true, runSchema(stdOut, stdErr)
+	case commandPrintProfiles:
+		return true, runPrintProfiles(ctx, args[1:], version, stdOut, stdErr)
+	default:
+		return false, nil
+	}
+}
+
+// runSchema prints the JSON Schema of the profiles file.
+func runSchema(stdOut, stdErr io.Writer) error {
+	out, err := json.MarshalIndent(config.Schema(), "", "  ")
+	if err != nil {
+		_, _ = fmt.Fprintln(stdErr, err)
+		return fmt.Errorf("marshal schema: %w", err)
+	}
+	_, _ = fmt.Fprintln(stdOut, string(out))
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Schema", func(t *testing.T) {
+		t.Parallel()
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+
+		err := Run(t.Context(), "v0.0.0", []string{"schema"}, out, errOut)
+		require.NoError(t, err)
+		assert.Empty(t, errOut.String())
+
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+		props := schema["properties"].(map[string]any)
+		assert.Contains(t, props, "defaultProfile")
+		assert.Contains(t, props, "profiles")
+		assert.Contains(t, out.String(), "nameTemplate")
+		assert.Contains(t, out.String(), "targetRef")
+	})
+
+	t.Run("Print profiles", func(t *testing.T) {
+		t.Parallel()
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		path := writeProfilesFile(t, printProfilesConfig)
+
+		err := Run(t.Context(), "v0.0.0", []string{"print-profiles", "--config", path}, out, errOut)
+		require.NoError(t, err)
+		assert.Empty(t, errOut.String())
+		assert.Contains(t, out.String(), "defaultProfile: standard")
+		assert.Contains(t, out.String(), "updateMode: \"Off\"")
+	})
+
+	t.Run("Print profiles with invalid config", func(t *testing.T) {
+		t.Parallel()
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		path := writeProfilesFile(t, "profiles: {}\n")
+
+		err := Run(t.Context(), "v0.0.0", []string{"print-profiles", "--config", path}, out, errOut)
+		require.Error(t, err)
+		assert.Empty(t, out.String())
+		assert.Contains(t, errOut.String(), "profiles must be set")
+	})
+
+	t.Run("Not a command", func(t *testing.T) {
+		t.Parallel()
+		handled, err := runCommand(context.Background(), []string{"--config", "x.yaml"}, "v0.0.0", &bytes.Buffer{}, &bytes.Buffer{})
+		assert.False(t, handled)
+		assert.NoError(t, err)
+	})
+
+	t.Run("No args", func(t *testing.T) {
+		t.Parallel()
+		handled, err := runCommand(context.Background(), nil, "v0.0.0", &bytes.Buffer{}, &bytes.Buffer{})
+		assert.False(t, handled)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/controller"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+// Results recorded in autovpa_config_reloads_total.
+const (
+	configReloadSuccess = "success"
+	configReloadFailure = "failure"
+)
+
+// ConfigReloader re-reads the profiles file every Interval and stores changed
+// profiles in Store, from where the reconcilers pick them up on their next
+// reconcile. A file that fails to load or validate is logged and counted once
+// per distinct error; the current profiles stay in place.
+type ConfigReloader struct {
+	Flags    flag.Options              // Flags the profiles were built from at startup.
+	Store    *controller.ProfileStore  // Profiles read by the reconcilers.
+	Metrics  *internalmetrics.Registry // Registry receiving the reload metrics.
+	Interval time.Duration             // Time between re-reads.
+	Logger   logr.Logger
+
+	lastErr string // Error of the last failed reload, reported once.
+}
+
+// NeedLeaderElection returns false so standby replicas take over with current profiles.
+func (r *ConfigReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start re-reads the file every Interval until ctx is cancelled.
+func (r *ConfigReloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.check()
+		}
+	}
+}
+
+// check reloads the profiles file and stores it when it differs from the
+// current profiles.
+func (r *ConfigReloader) check() {
+	cfg, profiles, err := r.load()
+	if err != nil {
+		if err.Error() == r.lastErr {
+			return
+		}
+		r.lastErr = err.Error()
+		r.Metrics.IncConfigReloads(configReloadFailure)
+		r.Logger.Error(err, "failed to reload profiles; keeping the current profiles", "path", r.Flags.ConfigPath)
+		return
+	}
+	r.lastErr = ""
+
+	current := r.Store.Load()
+	// Whether the VPA supports in-place updates was decided at startup.
+	profiles.DowngradeInPlace = current.DowngradeInPlace
+	if apiequality.Semantic.DeepEqual(profiles, current) {
+		return
+	}
+
+	r.Store.Store(profiles)
+	r.Metrics.SetProfileContainerPolicies(cfg.ContainerPolicyCounts())
+	r.Metrics.IncConfigReloads(configReloadSuccess)
+	r.Metrics.SetConfigLastReload(time.Now())
+	for _, warning := range cfg.Warnings() {
+		r.Logger.Info("profile configuration warning", "warning", warning)
+	}
+	r.Logger.Info(
+		"reloaded profiles; changes apply on each workload's next reconcile",
+		"path", r.Flags.ConfigPath,
+		"profiles", len(cfg.Profiles),
+		"defaultProfile", cfg.DefaultProfile,
+	)
+}
+
+// load reads and validates the profiles file as at startup.
+func (r *ConfigReloader) load() (*config.Config, controller.ProfileConfig, error) {
+	cfg, err := config.LoadFile(r.Flags.ConfigPath, config.Format(r.Flags.ConfigFormat))
+	if err != nil {
+		return nil, controller.ProfileConfig{}, err
+	}
+	cfg.StrictNameTemplates = r.Flags.StrictNameTemplates
+	cfg.AdditionalKinds = kindNames(r.Flags.AdditionalTargetKinds)
+	if err := cfg.Validate(r.Flags.DefaultNameTemplate); err != nil {
+		return nil, controller.ProfileConfig{}, fmt.Errorf("validate profiles: %w", err)
+	}
+
+	profiles, err := newProfileConfig(cfg, r.Flags)
+	if err != nil {
+		return nil, controller.ProfileConfig{}, err
+	}
+	return cfg, profiles, nil
+}
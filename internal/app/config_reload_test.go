@@ -0,0 +1,183 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/controller"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	reloadConfigV1 = "defaultProfile: standard\nprofiles:\n  standard: {}\n"
+	reloadConfigV2 = "defaultProfile: quiet\nprofiles:\n  standard: {}\n  quiet:\n    updatePolicy:\n      updateMode: \"Off\"\n"
+)
+
+// newTestConfigReloader returns a reloader for path whose store holds the
+// profiles path contains now.
+func newTestConfigReloader(t *testing.T, path string) (*ConfigReloader, *prometheus.Registry) {
+	t.Helper()
+
+	flags := parseFlags(t, "--config", path)
+	cfg, err := config.LoadFile(path, config.FormatAuto)
+	require.NoError(t, err)
+	profiles, err := newProfileConfig(cfg, flags)
+	require.NoError(t, err)
+
+	promReg := prometheus.NewRegistry()
+	return &ConfigReloader{
+		Flags:    flags,
+		Store:    controller.NewProfileStore(profiles),
+		Metrics:  internalmetrics.NewRegistry(promReg),
+		Interval: time.Minute,
+		Logger:   logr.Discard(),
+	}, promReg
+}
+
+func TestConfigReloader_Check(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Keeps unchanged profiles without counting a reload", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, reloadConfigV1)
+		reloader, promReg := newTestConfigReloader(t, path)
+
+		reloader.check()
+		assert.Equal(t, float64(0), reloadCount(t, promReg, configReloadSuccess))
+		assert.Equal(t, float64(0), lastReloadTimestamp(t, promReg))
+	})
+
+	t.Run("Stores changed profiles", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, reloadConfigV1)
+		reloader, promReg := newTestConfigReloader(t, path)
+
+		require.NoError(t, os.WriteFile(path, []byte(reloadConfigV2), 0o600))
+		before := float64(time.Now().Unix())
+		reloader.check()
+
+		profiles := reloader.Store.Load()
+		assert.Equal(t, "quiet", profiles.Default)
+		assert.Contains(t, profiles.Entries, "quiet")
+		assert.Equal(t, float64(1), reloadCount(t, promReg, configReloadSuccess))
+		assert.GreaterOrEqual(t, lastReloadTimestamp(t, promReg), before)
+
+		reloader.check()
+		assert.Equal(t, float64(1), reloadCount(t, promReg, configReloadSuccess), "unchanged file is not reloaded again")
+	})
+
+	t.Run("Keeps the current profiles when the file turns invalid", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, reloadConfigV1)
+		reloader, promReg := newTestConfigReloader(t, path)
+
+		require.NoError(t, os.WriteFile(path, []byte(reloadConfigV2), 0o600))
+		reloader.check()
+		lastReload := lastReloadTimestamp(t, promReg)
+
+		require.NoError(t, os.WriteFile(path, []byte("defaultProfile: missing\nprofiles:\n  standard: {}\n"), 0o600))
+		reloader.check()
+		reloader.check()
+
+		assert.Equal(t, "quiet", reloader.Store.Load().Default)
+		assert.Equal(t, float64(1), reloadCount(t, promReg, configReloadSuccess))
+		assert.Equal(t, float64(1), reloadCount(t, promReg, configReloadFailure), "the same error is counted once")
+		assert.Equal(t, lastReload, lastReloadTimestamp(t, promReg), "failed reload must not move the timestamp")
+
+		require.NoError(t, os.WriteFile(path, []byte(reloadConfigV1), 0o600))
+		reloader.check()
+		assert.Equal(t, "standard", reloader.Store.Load().Default)
+		assert.Equal(t, float64(2), reloadCount(t, promReg, configReloadSuccess))
+	})
+
+	t.Run("Counts an unreadable file as a failure", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, reloadConfigV1)
+		reloader, promReg := newTestConfigReloader(t, path)
+
+		require.NoError(t, os.Remove(path))
+		reloader.check()
+
+		assert.Equal(t, "standard", reloader.Store.Load().Default)
+		assert.Equal(t, float64(1), reloadCount(t, promReg, configReloadFailure))
+	})
+
+	t.Run("Keeps the in-place downgrade decided at startup", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, reloadConfigV1)
+		reloader, _ := newTestConfigReloader(t, path)
+		profiles := reloader.Store.Load()
+		profiles.DowngradeInPlace = true
+		reloader.Store.Store(profiles)
+
+		require.NoError(t, os.WriteFile(path, []byte(reloadConfigV2), 0o600))
+		reloader.check()
+
+		assert.Equal(t, "quiet", reloader.Store.Load().Default)
+		assert.True(t, reloader.Store.Load().DowngradeInPlace)
+	})
+}
+
+// reloadCount returns autovpa_config_reloads_total for result, or 0 when unset.
+func reloadCount(t *testing.T, g prometheus.Gatherer, result string) float64 {
+	t.Helper()
+
+	mfs, err := g.Gather()
+	require.NoError(t, err)
+	for _, mf := range mfs {
+		if mf.GetName() != "autovpa_config_reloads_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "result" && lp.GetValue() == result {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// lastReloadTimestamp returns autovpa_config_last_reload_timestamp_seconds.
+func lastReloadTimestamp(t *testing.T, g prometheus.Gatherer) float64 {
+	t.Helper()
+
+	mfs, err := g.Gather()
+	require.NoError(t, err)
+	for _, mf := range mfs {
+		if mf.GetName() == "autovpa_config_last_reload_timestamp_seconds" {
+			return mf.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatal("autovpa_config_last_reload_timestamp_seconds not gathered")
+	return 0
+}
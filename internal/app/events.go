@@ -0,0 +1,42 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+)
+
+// eventRecorderProvider hands out named event recorders; the manager implements it.
+type eventRecorderProvider interface {
+	GetEventRecorder(name string) events.EventRecorder
+}
+
+// newEventRecorder returns the provider's recorder for name, or one that drops
+// every event when disabled is set.
+func newEventRecorder(provider eventRecorderProvider, name string, disabled bool) events.EventRecorder {
+	if disabled {
+		return noopRecorder{}
+	}
+	return provider.GetEventRecorder(name)
+}
+
+// noopRecorder is an events.EventRecorder that discards all events.
+type noopRecorder struct{}
+
+// Eventf discards the event.
+func (noopRecorder) Eventf(runtime.Object, runtime.Object, string, string, string, string, ...any) {}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+// fakeRecorderProvider hands out a single fake recorder and remembers the requested name.
+type fakeRecorderProvider struct {
+	recorder *events.FakeRecorder
+	name     string
+}
+
+func (p *fakeRecorderProvider) GetEventRecorder(name string) events.EventRecorder {
+	p.name = name
+	return p.recorder
+}
+
+func TestNewEventRecorder(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{}
+
+	t.Run("Enabled records events", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &fakeRecorderProvider{recorder: events.NewFakeRecorder(1)}
+		rec := newEventRecorder(provider, "deployment-controller", false)
+
+		rec.Eventf(pod, nil, corev1.EventTypeNormal, "VPACreated", "CreateVPA", "Created VPA %s", "app-vpa")
+
+		assert.Equal(t, "deployment-controller", provider.name)
+		require.Len(t, provider.recorder.Events, 1)
+		assert.Equal(t, "Normal VPACreated Created VPA app-vpa", <-provider.recorder.Events)
+	})
+
+	t.Run("Disabled records no events", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &fakeRecorderProvider{recorder: events.NewFakeRecorder(1)}
+		rec := newEventRecorder(provider, "deployment-controller", true)
+
+		rec.Eventf(pod, nil, corev1.EventTypeWarning, "HPAConflict", "SkipVPA", "Skipped VPA %s", "app-vpa")
+
+		assert.Empty(t, provider.name)
+		assert.Empty(t, provider.recorder.Events)
+	})
+}
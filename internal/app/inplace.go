@@ -0,0 +1,144 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/containeroo/autovpa/internal/config"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InPlaceUpdatesAnnotation on the VPA CRD declares whether the VPA
+// installation supports in-place updates ("true" or "false"). It overrides
+// the schema check, since the VPA feature gate itself is not discoverable.
+const InPlaceUpdatesAnnotation = "autovpa.containeroo.ch/in-place-updates"
+
+// crdGVK is the GroupVersionKind of CustomResourceDefinitions.
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// inPlaceProfiles returns the sorted names of the profiles rendering
+// updateMode InPlaceOrRecreate, either set on the profile or injected as
+// defaultMode.
+func inPlaceProfiles(profiles map[string]config.Profile, defaultMode vpaautoscaling.UpdateMode) []string {
+	var names []string
+	for name, profile := range profiles {
+		mode := defaultMode
+		if policy := profile.Spec.UpdatePolicy; policy != nil && policy.UpdateMode != nil {
+			mode = *policy.UpdateMode
+		}
+		if mode == vpaautoscaling.UpdateModeInPlaceOrRecreate {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// inPlaceUpdatesSupported reads the VPA CRD serving gvk and reports whether
+// its installation supports updateMode InPlaceOrRecreate. An
+// InPlaceUpdatesAnnotation on the CRD decides; otherwise the updateMode enum
+// of the served version's schema must list InPlaceOrRecreate. A schema
+// without an enum counts as supported.
+func inPlaceUpdatesSupported(ctx context.Context, reader client.Reader, gvk schema.GroupVersionKind) (bool, error) {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	name := "verticalpodautoscalers." + gvk.Group
+	if err := reader.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+		return false, fmt.Errorf("get CRD %s: %w", name, err)
+	}
+
+	if value, ok := crd.GetAnnotations()[InPlaceUpdatesAnnotation]; ok {
+		supported, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("annotation %s on CRD %s: %w", InPlaceUpdatesAnnotation, name, err)
+		}
+		return supported, nil
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]any)
+		if !ok || version["name"] != gvk.Version {
+			continue
+		}
+		modes, found, _ := unstructured.NestedSlice(version,
+			"schema", "openAPIV3Schema", "properties", "spec", "properties",
+			"updatePolicy", "properties", "updateMode", "enum",
+		)
+		if !found {
+			return true, nil
+		}
+		return slices.Contains(modes, any(string(vpaautoscaling.UpdateModeInPlaceOrRecreate))), nil
+	}
+	return false, fmt.Errorf("CRD %s does not serve version %s", name, gvk.Version)
+}
+
+// checkInPlaceUpdates warns about every profile rendering InPlaceOrRecreate
+// when the VPA installation lacks in-place updates, and reports whether those
+// profiles should be downgraded to Recreate. With downgrade set the CRD is read
+// even without such profiles, since inline updateMode overrides may still ask
+// for InPlaceOrRecreate. Failing to read the CRD (e.g. missing RBAC) is logged
+// and leaves the update modes as they are.
+func checkInPlaceUpdates(
+	ctx context.Context,
+	reader client.Reader,
+	gvk schema.GroupVersionKind,
+	profiles []string,
+	downgrade bool,
+	log logr.Logger,
+) bool {
+	if len(profiles) == 0 && !downgrade {
+		return false
+	}
+
+	supported, err := inPlaceUpdatesSupported(ctx, reader, gvk)
+	if err != nil {
+		log.Info("unable to check VPA in-place update support; leaving update modes unchanged", "error", err.Error())
+		return false
+	}
+	if supported {
+		return false
+	}
+
+	for _, profile := range profiles {
+		if downgrade {
+			log.Info(
+				"VPA does not support in-place updates; downgrading profile update mode",
+				"profile", profile,
+				"updateMode", vpaautoscaling.UpdateModeInPlaceOrRecreate,
+				"renderedUpdateMode", vpaautoscaling.UpdateModeRecreate,
+			)
+			continue
+		}
+		log.Info(
+			"VPA does not support in-place updates; profile may fail or behave like Recreate",
+			"profile", profile,
+			"updateMode", vpaautoscaling.UpdateModeInPlaceOrRecreate,
+			"downgradeFlag", "--downgrade-unsupported-update-mode",
+		)
+	}
+	return downgrade
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr/funcr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var testVPAGVK = schema.GroupVersionKind{Group: "autoscaling.k8s.io", Version: "v1", Kind: "VerticalPodAutoscaler"}
+
+// newVPACRD returns a VPA CRD serving v1 whose updateMode enum lists modes;
+// nil modes leave the enum out.
+func newVPACRD(modes []any, annotations map[string]string) *unstructured.Unstructured {
+	updateMode := map[string]any{"type": "string"}
+	if modes != nil {
+		updateMode["enum"] = modes
+	}
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"versions": []any{
+				map[string]any{"name": "v1beta2"},
+				map[string]any{
+					"name": "v1",
+					"schema": map[string]any{"openAPIV3Schema": map[string]any{
+						"properties": map[string]any{"spec": map[string]any{
+							"properties": map[string]any{"updatePolicy": map[string]any{
+								"properties": map[string]any{"updateMode": updateMode},
+							}},
+						}},
+					}},
+				},
+			},
+		},
+	}}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName("verticalpodautoscalers.autoscaling.k8s.io")
+	crd.SetAnnotations(annotations)
+	return crd
+}
+
+func newCRDReader(objs ...client.Object) client.Reader {
+	return fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(objs...).Build()
+}
+
+func TestInPlaceProfiles(t *testing.T) {
+	t.Parallel()
+
+	withMode := func(mode vpaautoscaling.UpdateMode) config.Profile {
+		return config.Profile{Spec: config.ProfileSpec{
+			UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{UpdateMode: ptr.To(mode)},
+		}}
+	}
+	profiles := map[string]config.Profile{
+		"b-inplace": withMode(vpaautoscaling.UpdateModeInPlaceOrRecreate),
+		"a-inplace": withMode(vpaautoscaling.UpdateModeInPlaceOrRecreate),
+		"recreate":  withMode(vpaautoscaling.UpdateModeRecreate),
+		"unset":     {},
+	}
+
+	t.Run("Lists profiles setting InPlaceOrRecreate", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"a-inplace", "b-inplace"}, inPlaceProfiles(profiles, ""))
+	})
+
+	t.Run("Includes profiles getting it as default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"a-inplace", "b-inplace", "unset"}, inPlaceProfiles(profiles, vpaautoscaling.UpdateModeInPlaceOrRecreate))
+	})
+
+	t.Run("Returns nothing without in-place profiles", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, inPlaceProfiles(map[string]config.Profile{"recreate": profiles["recreate"]}, ""))
+	})
+}
+
+func TestInPlaceUpdatesSupported(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name string
+		crd  *unstructured.Unstructured
+		want bool
+	}{
+		{name: "Supported when the enum lists InPlaceOrRecreate", crd: newVPACRD([]any{"Off", "Initial", "Recreate", "InPlaceOrRecreate", "Auto"}, nil), want: true},
+		{name: "Unsupported when the enum lacks InPlaceOrRecreate", crd: newVPACRD([]any{"Off", "Initial", "Recreate", "Auto"}, nil), want: false},
+		{name: "Supported without an enum", crd: newVPACRD(nil, nil), want: true},
+		{name: "Annotation declares support", crd: newVPACRD([]any{"Off"}, map[string]string{InPlaceUpdatesAnnotation: "true"}), want: true},
+		{name: "Annotation declares no support", crd: newVPACRD([]any{"InPlaceOrRecreate"}, map[string]string{InPlaceUpdatesAnnotation: "false"}), want: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			supported, err := inPlaceUpdatesSupported(ctx, newCRDReader(tc.crd), testVPAGVK)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, supported)
+		})
+	}
+
+	t.Run("Rejects an invalid annotation", func(t *testing.T) {
+		t.Parallel()
+		crd := newVPACRD(nil, map[string]string{InPlaceUpdatesAnnotation: "maybe"})
+		_, err := inPlaceUpdatesSupported(ctx, newCRDReader(crd), testVPAGVK)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "annotation autovpa.containeroo.ch/in-place-updates on CRD verticalpodautoscalers.autoscaling.k8s.io")
+	})
+
+	t.Run("Fails when the version is not served", func(t *testing.T) {
+		t.Parallel()
+		gvk := testVPAGVK
+		gvk.Version = "v2"
+		_, err := inPlaceUpdatesSupported(ctx, newCRDReader(newVPACRD(nil, nil)), gvk)
+		assert.EqualError(t, err, "CRD verticalpodautoscalers.autoscaling.k8s.io does not serve version v2")
+	})
+
+	t.Run("Returns read errors", func(t *testing.T) {
+		t.Parallel()
+		reader := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return errors.New("forbidden")
+			},
+		}).Build()
+		_, err := inPlaceUpdatesSupported(ctx, reader, testVPAGVK)
+		assert.EqualError(t, err, "get CRD verticalpodautoscalers.autoscaling.k8s.io: forbidden")
+	})
+}
+
+func TestCheckInPlaceUpdates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	unsupported := newVPACRD([]any{"Off", "Recreate"}, nil)
+
+	newLogger := func() (*[]string, func(prefix, args string)) {
+		var logs []string
+		return &logs, func(_, args string) { logs = append(logs, args) }
+	}
+
+	t.Run("Downgrades and warns per profile when unsupported", func(t *testing.T) {
+		t.Parallel()
+		logs, sink := newLogger()
+
+		downgrade := checkInPlaceUpdates(ctx, newCRDReader(unsupported), testVPAGVK, []string{"a", "b"}, true, funcr.New(sink, funcr.Options{}))
+
+		assert.True(t, downgrade)
+		require.Len(t, *logs, 2)
+		assert.Contains(t, (*logs)[0], `"profile"="a"`)
+		assert.Contains(t, (*logs)[0], "downgrading profile update mode")
+		assert.Contains(t, (*logs)[1], `"profile"="b"`)
+	})
+
+	t.Run("Only warns without downgrade", func(t *testing.T) {
+		t.Parallel()
+		logs, sink := newLogger()
+
+		downgrade := checkInPlaceUpdates(ctx, newCRDReader(unsupported), testVPAGVK, []string{"a"}, false, funcr.New(sink, funcr.Options{}))
+
+		assert.False(t, downgrade)
+		require.Len(t, *logs, 1)
+		assert.Contains(t, (*logs)[0], "--downgrade-unsupported-update-mode")
+	})
+
+	t.Run("Keeps profiles when supported", func(t *testing.T) {
+		t.Parallel()
+		logs, sink := newLogger()
+		supported := newVPACRD([]any{"InPlaceOrRecreate"}, nil)
+
+		downgrade := checkInPlaceUpdates(ctx, newCRDReader(supported), testVPAGVK, []string{"a"}, true, funcr.New(sink, funcr.Options{}))
+
+		assert.False(t, downgrade)
+		assert.Empty(t, *logs)
+	})
+
+	t.Run("Skips the check without in-place profiles", func(t *testing.T) {
+		t.Parallel()
+		logs, sink := newLogger()
+
+		downgrade := checkInPlaceUpdates(ctx, newCRDReader(), testVPAGVK, nil, false, funcr.New(sink, funcr.Options{}))
+
+		assert.False(t, downgrade)
+		assert.Empty(t, *logs)
+	})
+
+	t.Run("Downgrades without in-place profiles for inline overrides", func(t *testing.T) {
+		t.Parallel()
+		logs, sink := newLogger()
+
+		downgrade := checkInPlaceUpdates(ctx, newCRDReader(unsupported), testVPAGVK, nil, true, funcr.New(sink, funcr.Options{}))
+
+		assert.True(t, downgrade)
+		assert.Empty(t, *logs)
+	})
+
+	t.Run("Keeps inline overrides when supported", func(t *testing.T) {
+		t.Parallel()
+		logs, sink := newLogger()
+		supported := newVPACRD([]any{"InPlaceOrRecreate"}, nil)
+
+		downgrade := checkInPlaceUpdates(ctx, newCRDReader(supported), testVPAGVK, nil, true, funcr.New(sink, funcr.Options{}))
+
+		assert.False(t, downgrade)
+		assert.Empty(t, *logs)
+	})
+
+	t.Run("Leaves profiles unchanged when the CRD cannot be read", func(t *testing.T) {
+		t.Parallel()
+		logs, sink := newLogger()
+
+		downgrade := checkInPlaceUpdates(ctx, newCRDReader(), testVPAGVK, []string{"a"}, true, funcr.New(sink, funcr.Options{}))
+
+		assert.False(t, downgrade)
+		require.Len(t, *logs, 1)
+		assert.Contains(t, (*logs)[0], "unable to check VPA in-place update support")
+	})
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"github.com/containeroo/autovpa/internal/flag"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// applyLeaderElectionOptions copies the leader election flags onto the manager
+// options. The lease ID and timings are set even with leader election
+// disabled; the manager ignores them then.
+func applyLeaderElectionOptions(opts *ctrl.Options, flags flag.Options) {
+	opts.LeaderElection = flags.LeaderElection
+	opts.LeaderElectionID = flags.LeaderElectionID
+	opts.LeaseDuration = &flags.LeaseDuration
+	opts.RenewDeadline = &flags.RenewDeadline
+	opts.RetryPeriod = &flags.RetryPeriod
+}
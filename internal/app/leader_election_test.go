@@ -0,0 +1,71 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestApplyLeaderElectionOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Parallel()
+
+		flags, err := flag.ParseArgs(nil, "0.0.0")
+		require.NoError(t, err)
+
+		var opts ctrl.Options
+		applyLeaderElectionOptions(&opts, flags)
+
+		assert.True(t, opts.LeaderElection)
+		assert.Equal(t, "fc1fdccd.autovpa.containeroo.ch", opts.LeaderElectionID)
+		require.NotNil(t, opts.LeaseDuration)
+		assert.Equal(t, 15*time.Second, *opts.LeaseDuration)
+		require.NotNil(t, opts.RenewDeadline)
+		assert.Equal(t, 10*time.Second, *opts.RenewDeadline)
+		require.NotNil(t, opts.RetryPeriod)
+		assert.Equal(t, 2*time.Second, *opts.RetryPeriod)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Parallel()
+
+		flags, err := flag.ParseArgs([]string{
+			"--leader-election-id", "team-a.autovpa.containeroo.ch",
+			"--leader-election-lease-duration", "1m",
+			"--leader-election-renew-deadline", "40s",
+			"--leader-election-retry-period", "5s",
+		}, "0.0.0")
+		require.NoError(t, err)
+
+		var opts ctrl.Options
+		applyLeaderElectionOptions(&opts, flags)
+
+		assert.Equal(t, "team-a.autovpa.containeroo.ch", opts.LeaderElectionID)
+		assert.Equal(t, time.Minute, *opts.LeaseDuration)
+		assert.Equal(t, 40*time.Second, *opts.RenewDeadline)
+		assert.Equal(t, 5*time.Second, *opts.RetryPeriod)
+	})
+}
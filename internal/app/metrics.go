@@ -0,0 +1,85 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+
+	"github.com/containeroo/autovpa/internal/flag"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// defaultMetricsPath is the path controller-runtime always serves metrics on.
+const defaultMetricsPath = "/metrics"
+
+// newMetricsServerOptions returns the metrics server options for flags.
+// A custom metrics path is served as an extra handler next to /metrics, as
+// controller-runtime does not allow moving the built-in route.
+// With a certificate directory, the returned CertWatcher serves the mounted
+// certificate; it must be added to the manager so rotations are picked up.
+// Without one, the watcher is nil and a self-signed certificate is used.
+func newMetricsServerOptions(
+	flags flag.Options,
+	tlsOpts []func(*tls.Config),
+) (metricsserver.Options, *certwatcher.CertWatcher, error) {
+	if !flags.EnableMetrics {
+		return metricsserver.Options{BindAddress: "0"}, nil, nil
+	}
+
+	opts := metricsserver.Options{
+		BindAddress:   flags.MetricsAddr,
+		SecureServing: flags.SecureMetrics,
+		TLSOpts:       tlsOpts,
+	}
+	if flags.MetricsPath != "" && flags.MetricsPath != defaultMetricsPath {
+		opts.ExtraHandlers = map[string]http.Handler{
+			flags.MetricsPath: promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{
+				ErrorHandling: promhttp.HTTPErrorOnError,
+			}),
+		}
+	}
+	if !flags.SecureMetrics {
+		return opts, nil, nil
+	}
+	opts.FilterProvider = filters.WithAuthenticationAndAuthorization
+
+	if flags.MetricsCertDir == "" {
+		return opts, nil, nil
+	}
+
+	watcher, err := certwatcher.New(
+		filepath.Join(flags.MetricsCertDir, flags.MetricsCertName),
+		filepath.Join(flags.MetricsCertDir, flags.MetricsKeyName),
+	)
+	if err != nil {
+		return metricsserver.Options{}, nil, fmt.Errorf("load metrics certificate: %w", err)
+	}
+	opts.TLSOpts = append(slices.Clone(tlsOpts), func(c *tls.Config) {
+		c.GetCertificate = watcher.GetCertificate
+	})
+
+	return opts, watcher, nil
+}
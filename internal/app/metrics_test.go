@@ -0,0 +1,202 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestNewMetricsServerOptions(t *testing.T) {
+	t.Parallel()
+
+	baseFlags := flag.Options{
+		EnableMetrics:   true,
+		SecureMetrics:   true,
+		MetricsAddr:     ":8443",
+		MetricsCertName: "tls.crt",
+		MetricsKeyName:  "tls.key",
+	}
+	http1 := func(c *tls.Config) { c.NextProtos = []string{"http/1.1"} }
+
+	applyTLSOpts := func(opts []func(*tls.Config)) *tls.Config {
+		cfg := &tls.Config{}
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		return cfg
+	}
+
+	t.Run("Disabled metrics", func(t *testing.T) {
+		t.Parallel()
+
+		flags := baseFlags
+		flags.EnableMetrics = false
+
+		opts, watcher, err := newMetricsServerOptions(flags, nil)
+		require.NoError(t, err)
+		assert.Nil(t, watcher)
+		assert.Equal(t, "0", opts.BindAddress)
+	})
+
+	t.Run("Plain HTTP", func(t *testing.T) {
+		t.Parallel()
+
+		flags := baseFlags
+		flags.SecureMetrics = false
+		flags.MetricsCertDir = t.TempDir()
+
+		opts, watcher, err := newMetricsServerOptions(flags, nil)
+		require.NoError(t, err)
+		assert.Nil(t, watcher)
+		assert.False(t, opts.SecureServing)
+		assert.Nil(t, opts.FilterProvider)
+	})
+
+	t.Run("Default path adds no extra handler", func(t *testing.T) {
+		t.Parallel()
+
+		flags := baseFlags
+		flags.MetricsPath = "/metrics"
+
+		opts, _, err := newMetricsServerOptions(flags, nil)
+		require.NoError(t, err)
+		assert.Empty(t, opts.ExtraHandlers)
+	})
+
+	t.Run("Serves metrics on a custom path", func(t *testing.T) {
+		t.Parallel()
+
+		counter := prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "autovpa_test_metrics_path_total",
+			Help: "Test counter for the custom metrics path.",
+		})
+		require.NoError(t, ctrlmetrics.Registry.Register(counter))
+		t.Cleanup(func() { ctrlmetrics.Registry.Unregister(counter) })
+		counter.Inc()
+
+		flags := baseFlags
+		flags.MetricsPath = "/autovpa/metrics"
+
+		opts, _, err := newMetricsServerOptions(flags, nil)
+		require.NoError(t, err)
+		require.Contains(t, opts.ExtraHandlers, "/autovpa/metrics")
+		assert.NotNil(t, opts.FilterProvider, "extra handlers are filtered like /metrics")
+
+		rec := httptest.NewRecorder()
+		opts.ExtraHandlers["/autovpa/metrics"].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/autovpa/metrics", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "autovpa_test_metrics_path_total 1")
+	})
+
+	t.Run("Self-signed without cert dir", func(t *testing.T) {
+		t.Parallel()
+
+		opts, watcher, err := newMetricsServerOptions(baseFlags, []func(*tls.Config){http1})
+		require.NoError(t, err)
+		assert.Nil(t, watcher)
+		assert.True(t, opts.SecureServing)
+		assert.NotNil(t, opts.FilterProvider)
+		assert.Nil(t, applyTLSOpts(opts.TLSOpts).GetCertificate)
+	})
+
+	t.Run("Serves mounted certificate", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeTestCertificate(t, dir, "cert.pem", "key.pem", "autovpa-metrics")
+
+		flags := baseFlags
+		flags.MetricsCertDir = dir
+		flags.MetricsCertName = "cert.pem"
+		flags.MetricsKeyName = "key.pem"
+
+		tlsOpts := []func(*tls.Config){http1}
+		opts, watcher, err := newMetricsServerOptions(flags, tlsOpts)
+		require.NoError(t, err)
+		require.NotNil(t, watcher)
+		assert.Len(t, tlsOpts, 1, "shared TLS options must not be modified")
+
+		cfg := applyTLSOpts(opts.TLSOpts)
+		assert.Equal(t, []string{"http/1.1"}, cfg.NextProtos)
+		require.NotNil(t, cfg.GetCertificate)
+
+		cert, err := cfg.GetCertificate(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		assert.Equal(t, "autovpa-metrics", leaf.Subject.CommonName)
+	})
+
+	t.Run("Fails on missing certificate", func(t *testing.T) {
+		t.Parallel()
+
+		flags := baseFlags
+		flags.MetricsCertDir = t.TempDir()
+
+		_, watcher, err := newMetricsServerOptions(flags, nil)
+		require.Error(t, err)
+		assert.Nil(t, watcher)
+		assert.Contains(t, err.Error(), "load metrics certificate")
+	})
+}
+
+// writeTestCertificate writes a self-signed certificate and key into dir.
+func writeTestCertificate(t *testing.T, dir, certName, keyName, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, certName), certPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, keyName), keyPEM, 0o600))
+}
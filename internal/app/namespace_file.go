@@ -0,0 +1,87 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/config"
+
+	"github.com/go-logr/logr"
+)
+
+// namespaceFilePollInterval is how often the watched namespace file is re-read.
+const namespaceFilePollInterval = 30 * time.Second
+
+// NamespaceFileWatcher re-reads the --watch-namespace-file and warns when its
+// namespaces differ from the ones loaded at startup. The cache scope is fixed
+// when the manager starts, so a changed file only takes effect after a restart.
+type NamespaceFileWatcher struct {
+	Path       string        // Path to the namespace file.
+	Namespaces []string      // Namespaces the file listed at startup.
+	Interval   time.Duration // Time between re-reads.
+	Logger     logr.Logger
+
+	reported []string // Last namespaces a change was reported for.
+}
+
+// NeedLeaderElection returns false so every replica warns about its own stale scope.
+func (w *NamespaceFileWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start re-reads the file every Interval until ctx is cancelled.
+func (w *NamespaceFileWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check re-reads the file and logs a warning once per distinct change.
+func (w *NamespaceFileWatcher) check() {
+	namespaces, err := config.LoadNamespaceFile(w.Path)
+	if err != nil {
+		w.Logger.Error(err, "unable to re-read watched namespace file", "path", w.Path)
+		return
+	}
+
+	if slices.Equal(namespaces, w.Namespaces) {
+		w.reported = nil
+		return
+	}
+	if slices.Equal(namespaces, w.reported) {
+		return
+	}
+	w.reported = namespaces
+
+	w.Logger.Info(
+		"watched namespace file changed; restart the operator to apply it",
+		"path", w.Path,
+		"namespaces", w.Namespaces,
+		"fileNamespaces", namespaces,
+	)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestNamespaceFileWatcher(t *testing.T) {
+	t.Parallel()
+
+	newWatcher := func(t *testing.T, content string) (*NamespaceFileWatcher, *[]string) {
+		t.Helper()
+		path := t.TempDir() + "/namespaces"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+		var logs []string
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{})
+
+		return &NamespaceFileWatcher{
+			Path:       path,
+			Namespaces: []string{"team-a"},
+			Interval:   10 * time.Millisecond,
+			Logger:     logger,
+		}, &logs
+	}
+
+	t.Run("Stays quiet while the file is unchanged", func(t *testing.T) {
+		t.Parallel()
+		watcher, logs := newWatcher(t, "team-a\n")
+
+		watcher.check()
+		assert.Empty(t, *logs)
+	})
+
+	t.Run("Warns once per change", func(t *testing.T) {
+		t.Parallel()
+		watcher, logs := newWatcher(t, "team-a\nteam-b\n")
+
+		watcher.check()
+		watcher.check()
+		require.Len(t, *logs, 1)
+		assert.Contains(t, (*logs)[0], `"msg"="watched namespace file changed; restart the operator to apply it"`)
+		assert.Contains(t, (*logs)[0], `"fileNamespaces"=["team-a" "team-b"]`)
+
+		require.NoError(t, os.WriteFile(watcher.Path, []byte("team-c\n"), 0o644))
+		watcher.check()
+		assert.Len(t, *logs, 2)
+	})
+
+	t.Run("Logs read errors", func(t *testing.T) {
+		t.Parallel()
+		watcher, logs := newWatcher(t, "team-a\n")
+		require.NoError(t, os.Remove(watcher.Path))
+
+		watcher.check()
+		require.Len(t, *logs, 1)
+		assert.Contains(t, (*logs)[0], `"msg"="unable to re-read watched namespace file"`)
+	})
+
+	t.Run("Start returns on cancel", func(t *testing.T) {
+		t.Parallel()
+		watcher, _ := newWatcher(t, "team-a\n")
+		ctx, cancel := context.WithCancel(t.Context())
+
+		done := make(chan error, 1)
+		go func() { done <- watcher.Start(ctx) }()
+		time.Sleep(30 * time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Start did not return after cancel")
+		}
+		assert.False(t, watcher.NeedLeaderElection())
+	})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namespaceSelectorPollInterval is how often namespaces matching the
+// --namespace-label-selector are listed again.
+const namespaceSelectorPollInterval = time.Minute
+
+// errNoSelectedNamespaces is returned when the namespace label selector matches
+// no namespace, since an empty namespace list would widen the cache to the
+// whole cluster.
+var errNoSelectedNamespaces = errors.New("no namespace matches the namespace label selector")
+
+// selectNamespaces returns the sorted names of the namespaces matching selector.
+func selectNamespaces(ctx context.Context, reader client.Reader, selector labels.Selector) ([]string, error) {
+	list := &corev1.NamespaceList{}
+	if err := reader.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("list namespaces: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for i := range list.Items {
+		names = append(names, list.Items[i].Name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// NamespaceSelectorWatcher lists the namespaces matching the
+// --namespace-label-selector and warns when they differ from the ones
+// resolved at startup. The cache scope is fixed when the manager starts, so
+// newly labelled or unlabelled namespaces only take effect after a restart.
+type NamespaceSelectorWatcher struct {
+	Reader     client.Reader   // Uncached reader; the cache does not see namespaces outside the scope.
+	Selector   labels.Selector // Namespace label selector.
+	Namespaces []string        // Namespaces the selector matched at startup, sorted.
+	Interval   time.Duration   // Time between lookups.
+	Logger     logr.Logger
+
+	reported []string // Last namespaces a change was reported for.
+}
+
+// NeedLeaderElection returns false so every replica warns about its own stale scope.
+func (w *NamespaceSelectorWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start lists the matching namespaces every Interval until ctx is cancelled.
+func (w *NamespaceSelectorWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.check(ctx)
+		}
+	}
+}
+
+// check lists the matching namespaces and logs a warning once per distinct change.
+func (w *NamespaceSelectorWatcher) check(ctx context.Context) {
+	namespaces, err := selectNamespaces(ctx, w.Reader, w.Selector)
+	if err != nil {
+		w.Logger.Error(err, "unable to list namespaces matching the namespace label selector", "selector", w.Selector.String())
+		return
+	}
+
+	if slices.Equal(namespaces, w.Namespaces) {
+		w.reported = nil
+		return
+	}
+	if slices.Equal(namespaces, w.reported) {
+		return
+	}
+	w.reported = namespaces
+
+	w.Logger.Info(
+		"namespaces matching the label selector changed; restart the operator to apply it",
+		"selector", w.Selector.String(),
+		"namespaces", w.Namespaces,
+		"selectedNamespaces", namespaces,
+	)
+}
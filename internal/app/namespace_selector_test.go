@@ -0,0 +1,205 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newLabelledNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newNamespaceReader(t *testing.T, objs ...client.Object) client.WithWatch {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestSelectNamespaces(t *testing.T) {
+	t.Parallel()
+
+	enabled := map[string]string{"autovpa": "enabled"}
+
+	t.Run("Returns matching namespaces sorted", func(t *testing.T) {
+		t.Parallel()
+		reader := newNamespaceReader(t,
+			newLabelledNamespace("team-b", enabled),
+			newLabelledNamespace("team-a", enabled),
+			newLabelledNamespace("sandbox", map[string]string{"autovpa": "disabled"}),
+			newLabelledNamespace("kube-system", nil),
+		)
+
+		namespaces, err := selectNamespaces(t.Context(), reader, labels.SelectorFromSet(enabled))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a", "team-b"}, namespaces)
+	})
+
+	t.Run("Supports set-based selectors", func(t *testing.T) {
+		t.Parallel()
+		reader := newNamespaceReader(t,
+			newLabelledNamespace("team-a", map[string]string{"autovpa": "enabled", "tier": "prod"}),
+			newLabelledNamespace("team-b", map[string]string{"autovpa": "enabled", "tier": "sandbox"}),
+		)
+		selector, err := labels.Parse("autovpa=enabled,tier notin (sandbox)")
+		require.NoError(t, err)
+
+		namespaces, err := selectNamespaces(t.Context(), reader, selector)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a"}, namespaces)
+	})
+
+	t.Run("Returns no namespaces when nothing matches", func(t *testing.T) {
+		t.Parallel()
+		reader := newNamespaceReader(t, newLabelledNamespace("team-a", nil))
+
+		namespaces, err := selectNamespaces(t.Context(), reader, labels.SelectorFromSet(enabled))
+		require.NoError(t, err)
+		assert.Empty(t, namespaces)
+	})
+
+	t.Run("Returns list errors", func(t *testing.T) {
+		t.Parallel()
+		reader := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return errors.New("forbidden")
+			},
+		}).Build()
+
+		_, err := selectNamespaces(t.Context(), reader, labels.SelectorFromSet(enabled))
+		require.Error(t, err)
+		assert.EqualError(t, err, "list namespaces: forbidden")
+	})
+
+	t.Run("Scopes the cache to the matching namespaces", func(t *testing.T) {
+		t.Parallel()
+		reader := newNamespaceReader(t,
+			newLabelledNamespace("team-a", enabled),
+			newLabelledNamespace("team-b", enabled),
+			newLabelledNamespace("sandbox", nil),
+		)
+
+		namespaces, err := selectNamespaces(t.Context(), reader, labels.SelectorFromSet(enabled))
+		require.NoError(t, err)
+
+		opts := utils.ToCacheOptions(namespaces)
+		assert.Len(t, opts.DefaultNamespaces, 2)
+		assert.Contains(t, opts.DefaultNamespaces, "team-a")
+		assert.Contains(t, opts.DefaultNamespaces, "team-b")
+	})
+}
+
+func TestNamespaceSelectorWatcher(t *testing.T) {
+	t.Parallel()
+
+	enabled := map[string]string{"autovpa": "enabled"}
+
+	newWatcher := func(t *testing.T, objs ...client.Object) (*NamespaceSelectorWatcher, client.WithWatch, *[]string) {
+		t.Helper()
+		reader := newNamespaceReader(t, objs...)
+
+		var logs []string
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{})
+
+		return &NamespaceSelectorWatcher{
+			Reader:     reader,
+			Selector:   labels.SelectorFromSet(enabled),
+			Namespaces: []string{"team-a"},
+			Interval:   10 * time.Millisecond,
+			Logger:     logger,
+		}, reader, &logs
+	}
+
+	t.Run("Stays quiet while the matching namespaces are unchanged", func(t *testing.T) {
+		t.Parallel()
+		watcher, _, logs := newWatcher(t, newLabelledNamespace("team-a", enabled))
+
+		watcher.check(t.Context())
+		assert.Empty(t, *logs)
+	})
+
+	t.Run("Warns once per change", func(t *testing.T) {
+		t.Parallel()
+		watcher, reader, logs := newWatcher(t,
+			newLabelledNamespace("team-a", enabled),
+			newLabelledNamespace("team-b", enabled),
+		)
+
+		watcher.check(t.Context())
+		watcher.check(t.Context())
+		require.Len(t, *logs, 1)
+		assert.Contains(t, (*logs)[0], `"msg"="namespaces matching the label selector changed; restart the operator to apply it"`)
+		assert.Contains(t, (*logs)[0], `"selectedNamespaces"=["team-a" "team-b"]`)
+
+		require.NoError(t, reader.Create(t.Context(), newLabelledNamespace("team-c", enabled)))
+		watcher.check(t.Context())
+		assert.Len(t, *logs, 2)
+	})
+
+	t.Run("Logs list errors", func(t *testing.T) {
+		t.Parallel()
+		watcher, _, logs := newWatcher(t)
+		watcher.Reader = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return errors.New("forbidden")
+			},
+		}).Build()
+
+		watcher.check(t.Context())
+		require.Len(t, *logs, 1)
+		assert.Contains(t, (*logs)[0], `"msg"="unable to list namespaces matching the namespace label selector"`)
+	})
+
+	t.Run("Start returns on cancel", func(t *testing.T) {
+		t.Parallel()
+		watcher, _, _ := newWatcher(t, newLabelledNamespace("team-a", enabled))
+		ctx, cancel := context.WithCancel(t.Context())
+
+		done := make(chan error, 1)
+		go func() { done <- watcher.Start(ctx) }()
+		time.Sleep(30 * time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Start did not return after cancel")
+		}
+	})
+}
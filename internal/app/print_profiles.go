@@ -0,0 +1,150 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	"github.com/containeroo/autovpa/internal/utils"
+
+	"github.com/containeroo/tinyflags"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// effectiveConfig is the resolved profiles file printed by print-profiles.
+type effectiveConfig struct {
+	DefaultProfile   string                    `json:"defaultProfile"`
+	KindDefaults     map[string]string         `json:"kindDefaults,omitempty"`
+	KindRestrictions map[string][]string       `json:"profileKindRestrictions,omitempty"`
+	Profiles         map[string]map[string]any `json:"profiles"`
+}
+
+// readerFunc returns a client for reading cluster state. print-profiles only
+// calls it when the output depends on the cluster.
+type readerFunc func() (client.Reader, error)
+
+// runPrintProfiles loads and validates the profiles file named by --config and
+// prints the profiles as the operator renders them: update modes normalized,
+// and the name template, update mode, recommender, controlled resources and
+// bounds defaults from the flags filled in. With
+// --downgrade-unsupported-update-mode the VPA CRD is read to decide whether
+// InPlaceOrRecreate renders as Recreate. Validation warnings go to stdErr.
+func runPrintProfiles(ctx context.Context, args []string, version string, stdOut, stdErr io.Writer) error {
+	flags, err := flag.ParseArgs(args, version)
+	if err != nil {
+		if tinyflags.IsHelpRequested(err) || tinyflags.IsVersionRequested(err) {
+			_, _ = fmt.Fprint(stdOut, err)
+			return nil
+		}
+		_, _ = fmt.Fprintln(stdErr, err)
+		return err
+	}
+
+	newReader := func() (client.Reader, error) {
+		restCfg, err := ctrl.GetConfig()
+		if err != nil {
+			return nil, err
+		}
+		return client.New(restCfg, client.Options{})
+	}
+	out, err := printProfiles(ctx, flags, newReader)
+	if err != nil {
+		_, _ = fmt.Fprintln(stdErr, err)
+		return err
+	}
+	_, _ = fmt.Fprint(stdOut, string(out))
+	return nil
+}
+
+// printProfiles renders the effective profiles for flags as YAML. Warnings
+// are prefixed with "# " so the output stays valid YAML.
+func printProfiles(ctx context.Context, flags flag.Options, newReader readerFunc) ([]byte, error) {
+	cfg, err := config.LoadFile(flags.ConfigPath, config.Format(flags.ConfigFormat))
+	if err != nil {
+		return nil, err
+	}
+	cfg.StrictNameTemplates = flags.StrictNameTemplates
+	cfg.AdditionalKinds = kindNames(flags.AdditionalTargetKinds)
+	if err := cfg.Validate(flags.DefaultNameTemplate); err != nil {
+		return nil, fmt.Errorf("validate profiles: %w", err)
+	}
+
+	profilesCfg, err := newProfileConfig(cfg, flags)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default update mode: %w", err)
+	}
+
+	warnings := cfg.Warnings()
+	if flags.DowngradeUpdateMode && !flags.ForceUpdateModeOff && len(inPlaceProfiles(cfg.Profiles, profilesCfg.DefaultUpdateMode)) > 0 {
+		gvk := schema.GroupVersion{Group: flags.VPAAPIGroup, Version: flags.VPAAPIVersion}.WithKind("VerticalPodAutoscaler")
+		supported, err := inPlaceSupport(ctx, newReader, gvk)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to check VPA in-place update support; leaving update modes unchanged: %v", err))
+		}
+		profilesCfg.DowngradeInPlace = err == nil && !supported
+	}
+
+	effective := effectiveConfig{
+		DefaultProfile:   cfg.DefaultProfile,
+		KindDefaults:     cfg.KindDefaults,
+		KindRestrictions: cfg.ProfileKindRestrictions,
+		Profiles:         make(map[string]map[string]any, len(cfg.Profiles)),
+	}
+	for name, profile := range cfg.Profiles {
+		resolved, err := profilesCfg.EffectiveSpec(profile)
+		if err != nil {
+			return nil, fmt.Errorf("render profile %q: %w", name, err)
+		}
+		resolved["nameTemplate"] = utils.DefaultIfZero(profile.NameTemplate, flags.DefaultNameTemplate)
+		if profile.TargetAPIVersion != "" {
+			resolved["targetApiVersion"] = profile.TargetAPIVersion
+		}
+		resolved["enabled"] = profile.IsEnabled()
+		if len(profile.ExcludeContainers) > 0 {
+			resolved["excludeContainers"] = profile.ExcludeContainers
+		}
+		effective.Profiles[name] = resolved
+	}
+
+	out, err := yaml.Marshal(effective)
+	if err != nil {
+		return nil, fmt.Errorf("marshal profiles: %w", err)
+	}
+
+	var header []byte
+	for _, warning := range warnings {
+		header = fmt.Appendf(header, "# warning: %s\n", warning)
+	}
+	return append(header, out...), nil
+}
+
+// inPlaceSupport reports whether the VPA installation serving gvk supports
+// in-place updates, reading its CRD through a client from newReader.
+func inPlaceSupport(ctx context.Context, newReader readerFunc, gvk schema.GroupVersionKind) (bool, error) {
+	reader, err := newReader()
+	if err != nil {
+		return false, fmt.Errorf("create client: %w", err)
+	}
+	return inPlaceUpdatesSupported(ctx, reader, gvk)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/flag"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const printProfilesConfig = `defaultProfile: standard
+kindDefaults:
+  DaemonSet: quiet
+profiles:
+  standard:
+    excludeContainers: [istio-proxy]
+    resourcePolicy:
+      containerPolicies:
+        - containerName: "*"
+          maxAllowed:
+            cpu: "2"
+  quiet:
+    nameTemplate: "{{ .Kind | toLower }}-{{ .WorkloadName }}-quiet"
+    updatePolicy:
+      updateMode: false
+  pinned:
+    enabled: false
+    recommenders:
+      - name: pinned
+    updatePolicy:
+      updateMode: recreate
+`
+
+// writeProfilesFile writes content to a profiles file in a temporary directory.
+func writeProfilesFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// noCluster is a readerFunc for runs without a cluster.
+func noCluster() (client.Reader, error) {
+	return nil, errors.New("no cluster")
+}
+
+// parseFlags parses args as the operator flags.
+func parseFlags(t *testing.T, args ...string) flag.Options {
+	t.Helper()
+	flags, err := flag.ParseArgs(args, "0.0.0")
+	require.NoError(t, err)
+	return flags
+}
+
+func TestPrintProfiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Prints the normalized profiles", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, printProfilesConfig)
+		out, err := printProfiles(t.Context(), parseFlags(t, "--config", path, "--vpa-name-template", "{{ .Kind | toLower }}-{{ .WorkloadName }}-{{ .Profile }}"), noCluster)
+		require.NoError(t, err)
+		assert.NotContains(t, string(out), "# warning")
+
+		var got effectiveConfig
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Equal(t, "standard", got.DefaultProfile)
+		assert.Equal(t, map[string]string{"DaemonSet": "quiet"}, got.KindDefaults)
+
+		assert.Equal(t, map[string]any{
+			"enabled":           true,
+			"excludeContainers": []any{"istio-proxy"},
+			"nameTemplate":      "{{ .Kind | toLower }}-{{ .WorkloadName }}-{{ .Profile }}",
+			"resourcePolicy": map[string]any{"containerPolicies": []any{
+				map[string]any{
+					"containerName": "*",
+					"maxAllowed":    map[string]any{"cpu": "2"},
+				},
+				map[string]any{
+					"containerName": "istio-proxy",
+					"mode":          "Off",
+				},
+			}},
+		}, got.Profiles["standard"])
+		assert.Equal(t, map[string]any{
+			"enabled":      true,
+			"nameTemplate": "{{ .Kind | toLower }}-{{ .WorkloadName }}-quiet",
+			"updatePolicy": map[string]any{"updateMode": "Off"},
+		}, got.Profiles["quiet"])
+		assert.Equal(t, map[string]any{
+			"enabled":      false,
+			"nameTemplate": "{{ .Kind | toLower }}-{{ .WorkloadName }}-{{ .Profile }}",
+			"recommenders": []any{map[string]any{"name": "pinned"}},
+			"updatePolicy": map[string]any{"updateMode": "Recreate"},
+		}, got.Profiles["pinned"])
+	})
+
+	t.Run("Applies flag defaults", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, printProfilesConfig)
+		out, err := printProfiles(t.Context(), parseFlags(t,
+			"--config", path,
+			"--default-update-mode", "Initial",
+			"--default-recommender", "custom",
+		), noCluster)
+		require.NoError(t, err)
+
+		var got effectiveConfig
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Equal(t, map[string]any{"updateMode": "Initial"}, got.Profiles["standard"]["updatePolicy"])
+		assert.Equal(t, []any{map[string]any{"name": "custom"}}, got.Profiles["standard"]["recommenders"])
+		assert.Equal(t, map[string]any{"updateMode": "Off"}, got.Profiles["quiet"]["updatePolicy"], "explicit update mode is kept")
+		assert.Equal(t, []any{map[string]any{"name": "pinned"}}, got.Profiles["pinned"]["recommenders"], "explicit recommenders are kept")
+	})
+
+	t.Run("Applies default bounds and controlled resources", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, printProfilesConfig)
+		out, err := printProfiles(t.Context(), parseFlags(t,
+			"--config", path,
+			"--default-min-cpu", "50m",
+			"--default-max-memory", "1Gi",
+			"--default-controlled-resources", "cpu",
+		), noCluster)
+		require.NoError(t, err)
+
+		var got effectiveConfig
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Equal(t, map[string]any{"containerPolicies": []any{
+			map[string]any{
+				"containerName":       "*",
+				"controlledResources": []any{"cpu"},
+				"minAllowed":          map[string]any{"cpu": "50m"},
+				"maxAllowed":          map[string]any{"cpu": "2", "memory": "1Gi"},
+			},
+			map[string]any{
+				"containerName": "istio-proxy",
+				"mode":          "Off",
+			},
+		}}, got.Profiles["standard"]["resourcePolicy"])
+		assert.Equal(t, map[string]any{"containerPolicies": []any{
+			map[string]any{
+				"containerName":       "*",
+				"controlledResources": []any{"cpu"},
+				"minAllowed":          map[string]any{"cpu": "50m"},
+				"maxAllowed":          map[string]any{"memory": "1Gi"},
+			},
+		}}, got.Profiles["quiet"]["resourcePolicy"], "profiles without policies get a wildcard policy")
+	})
+
+	t.Run("Downgrades in-place updates the VPA does not support", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, printProfilesConfig)
+		crd := newVPACRD(nil, map[string]string{InPlaceUpdatesAnnotation: "false"})
+		newReader := func() (client.Reader, error) { return newCRDReader(crd), nil }
+
+		out, err := printProfiles(t.Context(), parseFlags(t,
+			"--config", path,
+			"--default-update-mode", "InPlaceOrRecreate",
+		), newReader)
+		require.NoError(t, err)
+		var got effectiveConfig
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Equal(t, map[string]any{"updateMode": "InPlaceOrRecreate"}, got.Profiles["standard"]["updatePolicy"], "not downgraded without the flag")
+
+		out, err = printProfiles(t.Context(), parseFlags(t,
+			"--config", path,
+			"--default-update-mode", "InPlaceOrRecreate",
+			"--downgrade-unsupported-update-mode=true",
+		), newReader)
+		require.NoError(t, err)
+		got = effectiveConfig{}
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Equal(t, map[string]any{"updateMode": "Recreate"}, got.Profiles["standard"]["updatePolicy"])
+		assert.Equal(t, map[string]any{"updateMode": "Off"}, got.Profiles["quiet"]["updatePolicy"])
+	})
+
+	t.Run("Warns when in-place support cannot be checked", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, printProfilesConfig)
+		out, err := printProfiles(t.Context(), parseFlags(t,
+			"--config", path,
+			"--default-update-mode", "InPlaceOrRecreate",
+			"--downgrade-unsupported-update-mode=true",
+		), noCluster)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(out), "# warning: unable to check VPA in-place update support; leaving update modes unchanged: create client: no cluster")
+		var got effectiveConfig
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Equal(t, map[string]any{"updateMode": "InPlaceOrRecreate"}, got.Profiles["standard"]["updatePolicy"])
+	})
+
+	t.Run("Forces update mode Off", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, printProfilesConfig)
+		out, err := printProfiles(t.Context(), parseFlags(t, "--config", path, "--force-update-mode-off=true"), noCluster)
+		require.NoError(t, err)
+
+		var got effectiveConfig
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		for name, profile := range got.Profiles {
+			assert.Equal(t, map[string]any{"updateMode": "Off"}, profile["updatePolicy"], name)
+		}
+	})
+
+	t.Run("Prefixes warnings as comments", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, printProfilesConfig)
+		out, err := printProfiles(t.Context(), parseFlags(t, "--config", path, "--vpa-name-template", "{{ .WorkloadName }}-{{ .Profile }}"), noCluster)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(out), "# warning: default name template")
+		var got effectiveConfig
+		require.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Len(t, got.Profiles, 3)
+	})
+
+	t.Run("Fails on invalid profiles", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, "defaultProfile: missing\nprofiles:\n  standard: {}\n")
+		_, err := printProfiles(t.Context(), parseFlags(t, "--config", path), noCluster)
+		require.Error(t, err)
+		assert.EqualError(t, err, `validate profiles: defaultProfile "missing" not found in profiles`)
+	})
+
+	t.Run("Accepts kind restrictions naming additional target kinds", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeProfilesFile(t, "defaultProfile: standard\nprofileKindRestrictions:\n  standard: [Rollout]\nprofiles:\n  standard: {}\n")
+		_, err := printProfiles(t.Context(), parseFlags(t, "--config", path), noCluster)
+		assert.EqualError(t, err, `validate profiles: profileKindRestrictions["standard"]: unknown kind "Rollout"`)
+
+		_, err = printProfiles(t.Context(), parseFlags(t, "--config", path, "--additional-target-kind", "argoproj.io/v1alpha1/Rollout"), noCluster)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fails on a missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := printProfiles(t.Context(), parseFlags(t, "--config", filepath.Join(t.TempDir(), "missing.yaml")), noCluster)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read profiles file")
+	})
+}
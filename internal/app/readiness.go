@@ -0,0 +1,70 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// cacheSyncCheck is a readiness check that fails until the manager's caches
+// have synced. If the sync does not finish within timeout, the check keeps
+// failing with an error instead of waiting forever.
+type cacheSyncCheck struct {
+	timeout time.Duration
+	done    chan struct{}
+	synced  atomic.Bool
+}
+
+// startCacheSyncCheck runs waitForSync in the background and returns the check
+// reporting its outcome. A timeout of 0 waits until ctx is cancelled.
+func startCacheSyncCheck(
+	ctx context.Context,
+	waitForSync func(context.Context) bool,
+	timeout time.Duration,
+) *cacheSyncCheck {
+	c := &cacheSyncCheck{timeout: timeout, done: make(chan struct{})}
+
+	go func() {
+		defer close(c.done)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		c.synced.Store(waitForSync(ctx))
+	}()
+
+	return c
+}
+
+// Check implements healthz.Checker.
+func (c *cacheSyncCheck) Check(_ *http.Request) error {
+	select {
+	case <-c.done:
+	default:
+		return errors.New("caches have not synced yet")
+	}
+	if !c.synced.Load() {
+		return fmt.Errorf("caches did not sync within %s", c.timeout)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheSyncCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Not ready while syncing", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+		check := startCacheSyncCheck(t.Context(), func(context.Context) bool {
+			<-release
+			return true
+		}, time.Minute)
+
+		assert.EqualError(t, check.Check(nil), "caches have not synced yet")
+	})
+
+	t.Run("Ready once synced", func(t *testing.T) {
+		t.Parallel()
+
+		check := startCacheSyncCheck(t.Context(), func(context.Context) bool { return true }, time.Minute)
+
+		assert.Eventually(t, func() bool { return check.Check(nil) == nil }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Fails after timeout", func(t *testing.T) {
+		t.Parallel()
+
+		check := startCacheSyncCheck(t.Context(), func(ctx context.Context) bool {
+			<-ctx.Done()
+			return false
+		}, 50*time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			err := check.Check(nil)
+			return err != nil && err.Error() == "caches did not sync within 50ms"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("No timeout waits for sync", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		check := startCacheSyncCheck(t.Context(), func(ctx context.Context) bool {
+			_, hasDeadline := ctx.Deadline()
+			<-release
+			return !hasDeadline
+		}, 0)
+
+		assert.Error(t, check.Check(nil))
+		close(release)
+		assert.Eventually(t, func() bool { return check.Check(nil) == nil }, time.Second, 10*time.Millisecond)
+	})
+}
@@ -19,8 +19,11 @@ package app
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/containeroo/tinyflags"
 
@@ -31,14 +34,16 @@ import (
 	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
 	"github.com/containeroo/autovpa/internal/utils"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
-	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
@@ -50,6 +55,10 @@ func init() {
 
 // Run is the main function of the application.
 func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.Writer) error {
+	if handled, err := runCommand(ctx, args, version, stdOut, stdErr); handled {
+		return err
+	}
+
 	flags, err := flag.ParseArgs(args, version)
 	if err != nil {
 		if tinyflags.IsHelpRequested(err) || tinyflags.IsVersionRequested(err) {
@@ -60,19 +69,33 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		return err
 	}
 
-	logger := logging.InitLogging(flags, stdOut)
+	logger, err := logging.InitLogging(flags, stdOut)
+	if err != nil {
+		_, _ = fmt.Fprintln(stdErr, err)
+		return err
+	}
 	setupLog := logger.WithName("setup")
 	setupLog.Info("initializing autovpa", "version", version)
 
-	cfg, err := config.LoadFile(flags.ConfigPath)
+	cfg, err := config.LoadFile(flags.ConfigPath, config.Format(flags.ConfigFormat))
 	if err != nil {
+		var parseErr *config.ParseError
+		if errors.As(err, &parseErr) {
+			setupLog.Error(err, "failed to parse profiles", "path", flags.ConfigPath, "line", parseErr.Line, "column", parseErr.Column)
+			return err
+		}
 		setupLog.Error(err, "failed to load profiles")
 		return err
 	}
+	cfg.StrictNameTemplates = flags.StrictNameTemplates
+	cfg.AdditionalKinds = kindNames(flags.AdditionalTargetKinds)
 	if err := cfg.Validate(flags.DefaultNameTemplate); err != nil {
-		setupLog.Error(err, "failed to validate profiles")
+		setupLog.Error(err, "failed to validate profiles", "invalidProfiles", invalidProfiles(err))
 		return err
 	}
+	for _, warning := range cfg.Warnings() {
+		setupLog.Info("profile configuration warning", "warning", warning)
+	}
 
 	if len(flags.OverriddenValues) > 0 {
 		logger.Info(
@@ -90,20 +113,42 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		)
 	}
 
-	profilesCfg := controller.ProfileConfig{
-		Entries:      cfg.Profiles,
-		Default:      cfg.DefaultProfile,
-		NameTemplate: flags.DefaultNameTemplate,
+	profilesCfg, err := newProfileConfig(cfg, flags)
+	if err != nil {
+		setupLog.Error(err, "invalid default update mode")
+		return err
+	}
+	if profilesCfg.DefaultUpdateMode != "" {
+		setupLog.Info("default update mode for profiles without updateMode", "updateMode", profilesCfg.DefaultUpdateMode)
+	}
+	if flags.ForceUpdateModeOff {
+		setupLog.Info(
+			"forcing update mode on every managed VPA; profile update modes do not apply",
+			"flag", "--force-update-mode-off",
+			"updateMode", vpaautoscaling.UpdateModeOff,
+		)
 	}
 
 	metaCfg := controller.MetaConfig{
-		ProfileKey:   flags.ProfileAnnotation,
-		ManagedLabel: flags.ManagedLabel,
+		ProfileKey:          flags.ProfileAnnotations[0],
+		LegacyProfileKeys:   flags.ProfileAnnotations[1:],
+		ManagedLabel:        flags.ManagedLabel,
+		ManagedLabelValue:   flags.ManagedLabelValue,
+		TrackingAnnotations: flags.TrackingAnnotations,
+		RecommendedLabels:   flags.RecommendedLabels,
+		EmptyMeansDefault:   flags.EmptyMeansDefault,
+		WarnAnnotationTypos: flags.WarnAnnotationTypos,
+	}
+	if flags.NamespaceDefaults {
+		metaCfg.NamespaceProfileKey = controller.NamespaceDefaultProfileAnnotation
 	}
 
 	meta := map[string]string{
 		"Managed": flags.ManagedLabel,
-		"Profile": flags.ProfileAnnotation,
+		"Profile": flags.ProfileAnnotations[0],
+	}
+	for i, key := range flags.ProfileAnnotations[1:] {
+		meta[fmt.Sprintf("LegacyProfile%d", i+1)] = key
 	}
 	if err := utils.ValidateUniqueKeys(meta); err != nil {
 		setupLog.Error(err, "annotation/label keys must be unique")
@@ -111,6 +156,18 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 	}
 	setupLog.Info("configured annotation/label keys", "values", utils.FormatKeys(meta))
 
+	// Render the managed label value once with sample data so broken templates fail at startup.
+	if _, err := utils.RenderLabelValue(flags.ManagedLabelValue, utils.NameTemplateData{
+		WorkloadName: "sample",
+		Namespace:    "default",
+		Kind:         "Deployment",
+		Profile:      cfg.DefaultProfile,
+	}); err != nil {
+		err = fmt.Errorf("managed label value invalid: %w", err)
+		setupLog.Error(err, "invalid managed label value")
+		return err
+	}
+
 	tlsOpts := []func(*tls.Config){}
 	if !flags.EnableHTTP2 {
 		setupLog.Info("disabling HTTP/2 for compatibility")
@@ -123,32 +180,95 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		TLSOpts: tlsOpts,
 	})
 
-	metricsReg := internalmetrics.NewRegistry(crmetrics.Registry)
+	metricsReg := internalmetrics.NewControllerRegistry()
+	metricsReg.SetBuildInfo(version)
+	metricsReg.SetProfileContainerPolicies(cfg.ContainerPolicyCounts())
+
+	tracer, tracerProvider, err := newTracer(ctx, flags.OTelEndpoint, version)
+	if err != nil {
+		setupLog.Error(err, "unable to configure tracing")
+		return err
+	}
+	if tracerProvider != nil {
+		setupLog.Info("exporting reconcile traces", "endpoint", flags.OTelEndpoint)
+	}
 
-	metricsServerOptions := metricsserver.Options{
-		BindAddress: "0", // disabled by default
+	metricsServerOptions, metricsCertWatcher, err := newMetricsServerOptions(flags, tlsOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to configure metrics server")
+		return err
+	}
+	if metricsCertWatcher != nil {
+		setupLog.Info("serving metrics with mounted certificate", "dir", flags.MetricsCertDir)
 	}
-	if flags.EnableMetrics {
-		metricsServerOptions = metricsserver.Options{
-			BindAddress:   flags.MetricsAddr,
-			SecureServing: flags.SecureMetrics,
-			TLSOpts:       tlsOpts,
+
+	var fileNamespaces []string
+	if flags.WatchNamespaceFile != "" {
+		fileNamespaces, err = config.LoadNamespaceFile(flags.WatchNamespaceFile)
+		if err != nil {
+			setupLog.Error(err, "failed to load watched namespace file", "path", flags.WatchNamespaceFile)
+			return err
 		}
-		if flags.SecureMetrics {
-			metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+		for _, namespace := range fileNamespaces {
+			if !slices.Contains(flags.WatchNamespaces, namespace) {
+				flags.WatchNamespaces = append(flags.WatchNamespaces, namespace)
+			}
 		}
 	}
 
-	cacheOpts := utils.ToCacheOptions(flags.WatchNamespaces)
-
 	restCfg, err := ctrl.GetConfig()
 	if err != nil {
 		setupLog.Error(err, "unable to get Kubernetes REST config")
 		return err
 	}
 
+	var namespaceSelector labels.Selector
+	var selectedNamespaces []string
+	if flags.NamespaceSelector != "" {
+		// Validated by flag.ParseArgs.
+		namespaceSelector, _ = labels.Parse(flags.NamespaceSelector)
+
+		reader, err := client.New(restCfg, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create client for namespace lookup")
+			return err
+		}
+		selectedNamespaces, err = selectNamespaces(ctx, reader, namespaceSelector)
+		if err != nil {
+			setupLog.Error(err, "failed to resolve namespace label selector", "selector", flags.NamespaceSelector)
+			return err
+		}
+		if len(selectedNamespaces) == 0 {
+			setupLog.Error(errNoSelectedNamespaces, "refusing to watch the whole cluster", "selector", flags.NamespaceSelector)
+			return errNoSelectedNamespaces
+		}
+		for _, namespace := range selectedNamespaces {
+			if !slices.Contains(flags.WatchNamespaces, namespace) {
+				flags.WatchNamespaces = append(flags.WatchNamespaces, namespace)
+			}
+		}
+	}
+
+	cacheOpts := utils.ToCacheOptions(flags.WatchNamespaces)
+	if flags.ResyncPeriod > 0 {
+		cacheOpts.SyncPeriod = &flags.ResyncPeriod
+		setupLog.Info("periodic resync enabled", "period", flags.ResyncPeriod)
+	}
+
+	vpaGV := schema.GroupVersion{Group: flags.VPAAPIGroup, Version: flags.VPAAPIVersion}
+	controller.SetVPAGroupVersion(vpaGV)
+	setupLog.Info("VPA API", "groupVersion", vpaGV.String())
+
+	setupLog.Info(
+		"effective config",
+		"options", flags.Summary(),
+		"profiles", len(cfg.Profiles),
+		"defaultProfile", cfg.DefaultProfile,
+		"vpaGroupVersionKind", controller.VPAGroupVersionKind().String(),
+	)
+
 	if flags.CRDCheck {
-		if err := utils.EnsureVPAResource(restCfg); err != nil {
+		if err := utils.EnsureVPAResource(restCfg, controller.VPAGroupVersionKind()); err != nil {
 			setupLog.Error(err, "failed to ensure VPA CRD")
 			return err
 		}
@@ -156,78 +276,269 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 
 	reconcilerLog := logger.WithName("reconciler")
 
-	mgr, err := ctrl.NewManager(restCfg, ctrl.Options{
+	mgrOpts := ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		Logger:                 reconcilerLog,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: flags.ProbeAddr,
-		LeaderElection:         flags.LeaderElection,
-		LeaderElectionID:       "fc1fdccd.autovpa.containeroo.ch",
 		Cache:                  cacheOpts,
-	})
+		// Stop handing out new reconciles on shutdown but let in-flight ones finish.
+		GracefulShutdownTimeout: &flags.ShutdownTimeout,
+		// Controller names are unique per process; in-process tests run the operator repeatedly.
+		Controller: ctrlconfig.Controller{SkipNameValidation: &flags.SkipNameValidation},
+	}
+	applyLeaderElectionOptions(&mgrOpts, flags)
+
+	mgr, err := ctrl.NewManager(restCfg, mgrOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to create manager")
 		return err
 	}
 
+	// Profiles asking for in-place updates need a VPA installation supporting them.
+	if !flags.ForceUpdateModeOff {
+		profilesCfg.DowngradeInPlace = checkInPlaceUpdates(
+			ctx,
+			mgr.GetAPIReader(),
+			controller.VPAGroupVersionKind(),
+			inPlaceProfiles(cfg.Profiles, profilesCfg.DefaultUpdateMode),
+			flags.DowngradeUpdateMode,
+			setupLog,
+		)
+	}
+
+	// With reloading enabled, the reconcilers read the profiles from a store
+	// the ConfigReloader replaces.
+	var profileStore *controller.ProfileStore
+	if flags.ConfigReloadInterval > 0 {
+		profileStore = controller.NewProfileStore(profilesCfg)
+	}
+
 	if len(flags.WatchNamespaces) == 0 {
 		setupLog.Info("namespace scope", "mode", "cluster-wide")
 	} else {
 		setupLog.Info("namespace scope", "mode", "namespaced", "namespaces", flags.WatchNamespaces)
 	}
 
-	if err := (&controller.DeploymentReconciler{
-		BaseReconciler: controller.BaseReconciler{
+	// Cluster-wide installs and namespace defaults come with read access to
+	// namespaces; the namespaced Role templates do not grant it.
+	checkNamespacePhase := len(flags.WatchNamespaces) == 0 || flags.NamespaceDefaults
+
+	// Shared by the workload and VPA controllers; lock keys include the kind.
+	workloadLocks := &controller.WorkloadLocks{}
+
+	newBaseReconciler := func(recorderName string) controller.BaseReconciler {
+		return controller.BaseReconciler{
 			Logger:     &reconcilerLog,
 			KubeClient: mgr.GetClient(),
-			Recorder:   mgr.GetEventRecorder("deployment-controller"),
+			Recorder:   newEventRecorder(mgr, recorderName, flags.DisableEvents),
 			Profiles:   profilesCfg,
 			Meta:       metaCfg,
 			Metrics:    metricsReg,
-		},
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create Deployment controller")
-		return err
+
+			ProfileStore:              profileStore,
+			DisableBlockOwnerDeletion: !flags.OwnerBlockDeletion,
+			CreateOnly:                flags.CreateOnly,
+			UseFinalizers:             flags.UseFinalizers,
+			RespectLimitRanges:        flags.RespectLimitRanges,
+			RespectResourceQuota:      flags.RespectResourceQuota,
+			SkipIfHPA:                 flags.SkipIfHPA,
+			SkipIfWithinBounds:        flags.SkipIfWithinBounds,
+			MaxVPAsPerNamespace:       flags.MaxVPAsPerNamespace,
+			FieldManager:              flags.FieldManager,
+			Circuit:                   &controller.ApplyCircuitBreaker{Threshold: flags.ApplyFailureThreshold},
+			Locks:                     workloadLocks,
+			Tracer:                    tracer,
+			CheckNamespacePhase:       checkNamespacePhase,
+			ObsoleteAction:            flags.ObsoleteAction,
+			DisableObsoleteCleanup:    flags.NoObsoleteCleanup,
+			WriteErrorAnnotations:     flags.ErrorAnnotations,
+		}
 	}
 
-	if err := (&controller.StatefulSetReconciler{
-		BaseReconciler: controller.BaseReconciler{
-			Logger:     &reconcilerLog,
+	// Everything reading VPAs is registered once the VPA CRD is served; with
+	// --disable-crd-check that may only happen after the manager started.
+	setupVPAControllers := func() error {
+		workloadKinds, err := setupWorkloadReconcilers(mgr, flags, newBaseReconciler)
+		if err != nil {
+			setupLog.Error(err, "unable to create workload controller")
+			return err
+		}
+
+		for _, gvk := range flags.AdditionalTargetKinds {
+			if err := (&controller.GenericWorkloadReconciler{
+				BaseReconciler: newBaseReconciler(strings.ToLower(gvk.Kind) + "-controller"),
+				GVK:            gvk,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create generic workload controller", "gvk", gvk.String())
+				return err
+			}
+			workloadKinds = append(workloadKinds, gvk.Kind)
+		}
+		setupLog.Info("workload controllers", "kinds", workloadKinds)
+
+		vpaReconciler := &controller.VPAReconciler{
+			Logger:          &reconcilerLog,
+			KubeClient:      mgr.GetClient(),
+			Recorder:        newEventRecorder(mgr, "vpa-controller", flags.DisableEvents),
+			Meta:            metaCfg,
+			Metrics:         metricsReg,
+			AdditionalKinds: flags.AdditionalTargetKinds,
+
+			MirrorRecommendations: flags.MirrorRecommendations,
+			Tracer:                tracer,
+			Locks:                 workloadLocks,
+		}
+		if err := vpaReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create VPA controller")
+			return err
+		}
+
+		if flags.PruneOnStartup {
+			if err := mgr.Add(&controller.VPAStartupPruner{
+				Reconciler: vpaReconciler,
+				Namespaces: flags.WatchNamespaces,
+			}); err != nil {
+				setupLog.Error(err, "unable to add startup VPA prune")
+				return err
+			}
+		}
+
+		if err := mgr.Add(&controller.VPAInventoryReporter{
 			KubeClient: mgr.GetClient(),
-			Recorder:   mgr.GetEventRecorder("statefulset-controller"),
-			Profiles:   profilesCfg,
+			Logger:     &reconcilerLog,
 			Meta:       metaCfg,
-			Metrics:    metricsReg,
-		},
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create StatefulSet controller")
-		return err
+			Namespaces: flags.WatchNamespaces,
+		}); err != nil {
+			setupLog.Error(err, "unable to add managed VPA inventory")
+			return err
+		}
+
+		if flags.UnmanagedInterval > 0 {
+			if err := mgr.Add(&controller.UnmanagedWorkloadsReporter{
+				KubeClient: mgr.GetClient(),
+				Logger:     &reconcilerLog,
+				Metrics:    metricsReg,
+				Meta:       metaCfg,
+				Profiles:   profilesCfg,
+				Interval:   flags.UnmanagedInterval,
+				Kinds:      workloadKinds,
+
+				AdditionalKinds: flags.AdditionalTargetKinds,
+				ProfileStore:    profileStore,
+			}); err != nil {
+				setupLog.Error(err, "unable to add unmanaged workloads reporter")
+				return err
+			}
+		}
+
+		if flags.VPAAgeInterval > 0 {
+			if err := mgr.Add(&controller.ManagedVPAAgeReporter{
+				KubeClient: mgr.GetClient(),
+				Logger:     &reconcilerLog,
+				Metrics:    metricsReg,
+				Meta:       metaCfg,
+				Interval:   flags.VPAAgeInterval,
+			}); err != nil {
+				setupLog.Error(err, "unable to add managed VPA age reporter")
+				return err
+			}
+		}
+
+		return nil
 	}
 
-	if err := (&controller.DaemonSetReconciler{
-		BaseReconciler: controller.BaseReconciler{
-			Logger:     &reconcilerLog,
-			KubeClient: mgr.GetClient(),
-			Recorder:   mgr.GetEventRecorder("daemonset-controller"),
-			Profiles:   profilesCfg,
-			Meta:       metaCfg,
-			Metrics:    metricsReg,
-		},
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create DaemonSet controller")
-		return err
+	vpaAvailable := true
+	if !flags.CRDCheck {
+		vpaAvailable, err = utils.VPAResourceAvailable(restCfg, controller.VPAGroupVersionKind())
+		if err != nil {
+			// Keep the previous behavior of starting right away when discovery fails.
+			setupLog.Error(err, "unable to discover VPA CRD; starting controllers anyway")
+			vpaAvailable = true
+		}
+	}
+	if vpaAvailable {
+		if err := setupVPAControllers(); err != nil {
+			return err
+		}
+	} else {
+		setupLog.Info("VPA CRD not installed; waiting for it before starting controllers", "interval", vpaCRDPollInterval)
+		if err := mgr.Add(&VPACRDWatcher{
+			Available: func() (bool, error) {
+				return utils.VPAResourceAvailable(restCfg, controller.VPAGroupVersionKind())
+			},
+			Interval:    vpaCRDPollInterval,
+			Logger:      setupLog,
+			OnAvailable: setupVPAControllers,
+		}); err != nil {
+			setupLog.Error(err, "unable to add VPA CRD watcher")
+			return err
+		}
 	}
 
-	if err := (&controller.VPAReconciler{
-		Logger:     &reconcilerLog,
-		KubeClient: mgr.GetClient(),
-		Recorder:   mgr.GetEventRecorder("vpa-controller"),
-		Meta:       metaCfg,
-		Metrics:    metricsReg,
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create VPA controller")
-		return err
+	if flags.DefaultingWebhook {
+		mgr.GetWebhookServer().Register(controller.ProfileDefaulterPath, &webhook.Admission{
+			Handler: &controller.ProfileDefaulter{
+				KubeClient: mgr.GetClient(),
+				Logger:     &reconcilerLog,
+				Meta:       metaCfg,
+			},
+		})
+		setupLog.Info("profile defaulting webhook enabled", "path", controller.ProfileDefaulterPath)
+	}
+
+	if metricsCertWatcher != nil {
+		if err := mgr.Add(metricsCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add metrics certificate watcher")
+			return err
+		}
+	}
+
+	if tracerProvider != nil {
+		if err := mgr.Add(&TracerShutdown{Provider: tracerProvider}); err != nil {
+			setupLog.Error(err, "unable to add tracer shutdown")
+			return err
+		}
+	}
+
+	if profileStore != nil {
+		if err := mgr.Add(&ConfigReloader{
+			Flags:    flags,
+			Store:    profileStore,
+			Metrics:  metricsReg,
+			Interval: flags.ConfigReloadInterval,
+			Logger:   setupLog,
+		}); err != nil {
+			setupLog.Error(err, "unable to add profile config reloader")
+			return err
+		}
+		setupLog.Info("profile config reload enabled", "path", flags.ConfigPath, "interval", flags.ConfigReloadInterval)
+	}
+
+	if flags.WatchNamespaceFile != "" {
+		if err := mgr.Add(&NamespaceFileWatcher{
+			Path:       flags.WatchNamespaceFile,
+			Namespaces: fileNamespaces,
+			Interval:   namespaceFilePollInterval,
+			Logger:     setupLog,
+		}); err != nil {
+			setupLog.Error(err, "unable to add watched namespace file watcher")
+			return err
+		}
+	}
+
+	if namespaceSelector != nil {
+		if err := mgr.Add(&NamespaceSelectorWatcher{
+			Reader:     mgr.GetAPIReader(),
+			Selector:   namespaceSelector,
+			Namespaces: selectedNamespaces,
+			Interval:   namespaceSelectorPollInterval,
+			Logger:     setupLog,
+		}); err != nil {
+			setupLog.Error(err, "unable to add namespace label selector watcher")
+			return err
+		}
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -238,6 +549,11 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 		setupLog.Error(err, "failed to set up ready check")
 		return err
 	}
+	cacheSync := startCacheSyncCheck(ctx, mgr.GetCache().WaitForCacheSync, flags.StartupSyncTimeout)
+	if err := mgr.AddReadyzCheck("cache-sync", cacheSync.Check); err != nil {
+		setupLog.Error(err, "failed to set up cache sync check")
+		return err
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
@@ -247,3 +563,52 @@ func Run(ctx context.Context, version string, args []string, stdOut, stdErr io.W
 
 	return nil
 }
+
+// newProfileConfig builds the controller's profile settings from the profiles
+// file and the flags. It fails only on an invalid --default-update-mode.
+// DowngradeInPlace is left to the caller, since deciding it needs the VPA CRD.
+func newProfileConfig(cfg *config.Config, flags flag.Options) (controller.ProfileConfig, error) {
+	profiles := controller.ProfileConfig{
+		Entries:                    cfg.Profiles,
+		Default:                    cfg.DefaultProfile,
+		NameTemplate:               flags.DefaultNameTemplate,
+		DefaultRecommender:         flags.DefaultRecommender,
+		DefaultControlledResources: flags.DefaultControlledResources,
+		DefaultMinAllowed:          flags.DefaultMinAllowed,
+		DefaultMaxAllowed:          flags.DefaultMaxAllowed,
+		ForceUpdateModeOff:         flags.ForceUpdateModeOff,
+		KindDefaults:               cfg.KindDefaults,
+		KindRestrictions:           cfg.ProfileKindRestrictions,
+		ContainerPolicyMode:        flags.ContainerPolicyMode,
+	}
+	if flags.DefaultUpdateMode != "" {
+		mode, err := config.ParseUpdateMode(flags.DefaultUpdateMode)
+		if err != nil {
+			return controller.ProfileConfig{}, err
+		}
+		profiles.DefaultUpdateMode = mode
+	}
+	return profiles, nil
+}
+
+// invalidProfiles returns the sorted, distinct profile names referenced by
+// the validation errors in err, for structured logging.
+func invalidProfiles(err error) []string {
+	var names []string
+	for _, verr := range config.ValidationErrors(err) {
+		if verr.Profile != "" && !slices.Contains(names, verr.Profile) {
+			names = append(names, verr.Profile)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// kindNames returns the kinds of gvks, in order.
+func kindNames(gvks []schema.GroupVersionKind) []string {
+	names := make([]string, 0, len(gvks))
+	for _, gvk := range gvks {
+		names = append(names, gvk.Kind)
+	}
+	return names
+}
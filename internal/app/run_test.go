@@ -20,9 +20,13 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	"github.com/containeroo/autovpa/test/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,12 +37,17 @@ func TestRun(t *testing.T) {
 		defer cancel()
 
 		cfg := writeProfileFile(t)
+		namespaceFile := t.TempDir() + "/namespaces"
+		require.NoError(t, os.WriteFile(namespaceFile, []byte("test-autovpa\nteam-a\n"), 0o644))
 		args := []string{
 			"--leader-elect=false",
 			"--watch-namespace=test-autovpa",
+			"--watch-namespace-file=" + namespaceFile,
 			"--metrics-enabled=false",
 			"--skip-manager-start=true",
 			"--health-probe-bind-address=:0",
+			"--graceful-shutdown-timeout=1s",
+			"--enable-daemonsets=false",
 			"--config=" + cfg,
 		}
 		out := &bytes.Buffer{}
@@ -60,6 +69,41 @@ func TestRun(t *testing.T) {
 		case <-time.After(5 * time.Second):
 			t.Error("Run did not return within the expected time")
 		}
+		assert.Contains(t, out.String(), `"graceful-shutdown-timeout":"1s"`)
+		assert.Contains(t, out.String(), `"msg":"workload controllers","kinds":["Deployment","StatefulSet"]`)
+		assert.Contains(t, out.String(), `"msg":"namespace scope","mode":"namespaced","namespaces":["test-autovpa","team-a"]`)
+	})
+
+	t.Run("Runs again in the same process", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		args := []string{
+			"--leader-elect=false",
+			"--watch-namespace=test-autovpa",
+			"--metrics-enabled=false",
+			"--health-probe-bind-address=:0",
+			"--skip-controller-name-validation=true",
+			"--config=" + writeProfileFile(t),
+		}
+		out := testutils.NewSyncBuffer()
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- Run(ctx, "v0.0.0", args, out, out)
+		}()
+
+		require.Eventually(t, func() bool {
+			return strings.Contains(out.String(), `"msg":"starting manager"`)
+		}, 30*time.Second, 50*time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-errCh:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Run did not return within the expected time")
+		}
 	})
 
 	t.Run("Invalid args", func(t *testing.T) {
@@ -117,6 +161,26 @@ func TestRun(t *testing.T) {
 		assert.Empty(t, errOut.String())
 	})
 
+	t.Run("Invalid watched namespace file", func(t *testing.T) {
+		ctx := t.Context()
+
+		namespaceFile := t.TempDir() + "/namespaces"
+		require.NoError(t, os.WriteFile(namespaceFile, []byte("# empty\n"), 0o644))
+		args := []string{
+			"--config=" + writeProfileFile(t),
+			"--watch-namespace-file=" + namespaceFile,
+			"--leader-elect=false",
+			"--metrics-enabled=false",
+		}
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+
+		err := Run(ctx, "v0.0.0", args, out, errOut)
+
+		require.Error(t, err)
+		assert.EqualError(t, err, "namespace file lists no namespaces")
+	})
+
 	t.Run("Wrong profile file", func(t *testing.T) {
 		ctx := t.Context()
 
@@ -183,6 +247,46 @@ profiles:
 		assert.EqualError(t, err, "default name template invalid: parse template: template: name:1: unclosed action")
 		assert.Empty(t, errOut.String())
 	})
+
+	t.Run("Invalid managed label value", func(t *testing.T) {
+		ctx := t.Context()
+		profilePath := writeProfileFile(t)
+		args := []string{
+			"--config", profilePath,
+			"--managed-label-value", "{{ .WorkloadName }}/{{ .Namespace }}",
+			"--leader-elect=false",
+			"--metrics-enabled=false",
+			"--disable-crd-check",
+		}
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+
+		err := Run(ctx, "v0.0.0", args, out, errOut)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `managed label value invalid: rendered label value "sample/default" is invalid`)
+		assert.Empty(t, errOut.String())
+	})
+
+	t.Run("Invalid default update mode", func(t *testing.T) {
+		ctx := t.Context()
+		profilePath := writeProfileFile(t)
+		args := []string{
+			"--config", profilePath,
+			"--default-update-mode", "Sometimes",
+			"--leader-elect=false",
+			"--metrics-enabled=false",
+			"--disable-crd-check",
+		}
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+
+		err := Run(ctx, "v0.0.0", args, out, errOut)
+
+		require.Error(t, err)
+		assert.EqualError(t, err, `unknown update mode "Sometimes"`)
+		assert.Empty(t, errOut.String())
+	})
 }
 
 func writeProfileFile(t *testing.T) string {
@@ -196,3 +300,31 @@ profiles:
 	require.NoError(t, err)
 	return path
 }
+
+func TestInvalidProfiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Lists distinct profiles", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &config.Config{
+			DefaultProfile: "b",
+			Profiles: map[string]config.Profile{
+				"b": {NameTemplate: "{{ .Invalid }}", TargetAPIVersion: "apps/"},
+				"a": {TargetAPIVersion: "apps/"},
+				"c": {},
+			},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.Equal(t, []string{"a", "b"}, invalidProfiles(err))
+	})
+
+	t.Run("Config-wide errors name no profile", func(t *testing.T) {
+		t.Parallel()
+
+		err := (&config.Config{}).Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.Empty(t, invalidProfiles(err))
+	})
+}
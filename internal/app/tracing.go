@@ -0,0 +1,80 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/controller"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerShutdownTimeout bounds flushing buffered spans on shutdown.
+const tracerShutdownTimeout = 5 * time.Second
+
+// newTracer returns the tracer for reconcile spans and the tracer provider
+// behind it. With an empty endpoint both are no-ops and provider is nil;
+// otherwise spans are batched to the OTLP/gRPC endpoint (plaintext for
+// http://, TLS for https://).
+func newTracer(ctx context.Context, endpoint, version string) (trace.Tracer, *sdktrace.TracerProvider, error) {
+	if endpoint == "" {
+		return noop.NewTracerProvider().Tracer(controller.TracerName), nil, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, nil, fmt.Errorf("create OTLP trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "autovpa"),
+			attribute.String("service.version", version),
+		)),
+	)
+	return provider.Tracer(controller.TracerName), provider, nil
+}
+
+// TracerShutdown flushes and stops the tracer provider when the manager stops.
+type TracerShutdown struct {
+	Provider *sdktrace.TracerProvider
+}
+
+// Start blocks until ctx is done, then shuts the provider down.
+func (t *TracerShutdown) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+	defer cancel()
+	if err := t.Provider.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shut down tracer provider: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection reports false: every replica flushes its own spans.
+func (t *TracerShutdown) NeedLeaderElection() bool {
+	return false
+}
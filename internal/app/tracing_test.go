@@ -0,0 +1,84 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTracer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No-op without endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		tracer, provider, err := newTracer(t.Context(), "", "v0.0.0")
+		require.NoError(t, err)
+		assert.Nil(t, provider)
+
+		_, span := tracer.Start(t.Context(), "test")
+		defer span.End()
+		assert.False(t, span.IsRecording())
+	})
+
+	t.Run("Exports with endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		tracer, provider, err := newTracer(t.Context(), "http://127.0.0.1:4317", "v0.0.0")
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+
+		_, span := tracer.Start(t.Context(), "test")
+		assert.True(t, span.IsRecording())
+		span.End()
+
+		// Nothing listens on the endpoint; shutting down must still return.
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+		_ = provider.Shutdown(ctx)
+	})
+}
+
+func TestTracerShutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Shuts the provider down on stop", func(t *testing.T) {
+		t.Parallel()
+
+		_, provider, err := newTracer(t.Context(), "http://127.0.0.1:4317", "v0.0.0")
+		require.NoError(t, err)
+		shutdown := &TracerShutdown{Provider: provider}
+		assert.False(t, shutdown.NeedLeaderElection())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error, 1)
+		go func() { done <- shutdown.Start(ctx) }()
+		cancel()
+
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(2 * tracerShutdownTimeout):
+			t.Fatal("shutdown did not return")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// vpaCRDPollInterval is how often discovery is queried while the VPA CRD is missing.
+const vpaCRDPollInterval = 30 * time.Second
+
+// VPACRDWatcher polls discovery until the VPA CRD is served, then calls
+// OnAvailable once and stops. With --disable-crd-check the operator may start
+// before the CRD is installed; the watcher registers the VPA controllers as
+// soon as it appears instead of requiring a restart.
+type VPACRDWatcher struct {
+	Available   func() (bool, error) // Reports whether the VPA CRD is served.
+	Interval    time.Duration        // Time between discovery checks.
+	Logger      logr.Logger
+	OnAvailable func() error // Registers the VPA-dependent controllers.
+}
+
+// NeedLeaderElection returns false so every replica registers its controllers;
+// the controllers themselves still wait for leadership.
+func (w *VPACRDWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks discovery every Interval until the CRD is served or ctx is
+// cancelled. Discovery errors are logged and retried; an OnAvailable error is
+// returned and stops the manager.
+func (w *VPACRDWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			available, err := w.Available()
+			if err != nil {
+				w.Logger.Error(err, "unable to discover VPA CRD; retrying")
+				continue
+			}
+			if !available {
+				continue
+			}
+
+			w.Logger.Info("VPA CRD installed; starting controllers")
+			if err := w.OnAvailable(); err != nil {
+				return fmt.Errorf("start VPA controllers: %w", err)
+			}
+			return nil
+		}
+	}
+}
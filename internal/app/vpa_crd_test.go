@@ -0,0 +1,97 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+)
+
+func TestVPACRDWatcher(t *testing.T) {
+	t.Parallel()
+
+	newWatcher := func(available func() (bool, error), onAvailable func() error) *VPACRDWatcher {
+		return &VPACRDWatcher{
+			Available:   available,
+			Interval:    time.Millisecond,
+			Logger:      logr.Discard(),
+			OnAvailable: onAvailable,
+		}
+	}
+
+	t.Run("Starts controllers once the CRD appears", func(t *testing.T) {
+		t.Parallel()
+
+		var checks, started atomic.Int32
+		watcher := newWatcher(func() (bool, error) {
+			switch checks.Add(1) {
+			case 1:
+				return false, nil
+			case 2:
+				return false, errors.New("discovery unavailable")
+			default:
+				return true, nil
+			}
+		}, func() error {
+			started.Add(1)
+			return nil
+		})
+
+		require.NoError(t, watcher.Start(context.Background()))
+		assert.Equal(t, int32(3), checks.Load())
+		assert.Equal(t, int32(1), started.Load())
+	})
+
+	t.Run("Returns setup errors", func(t *testing.T) {
+		t.Parallel()
+
+		watcher := newWatcher(func() (bool, error) {
+			return true, nil
+		}, func() error {
+			return errors.New("boom")
+		})
+
+		assert.EqualError(t, watcher.Start(context.Background()), "start VPA controllers: boom")
+	})
+
+	t.Run("Stops on cancellation while waiting", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		var started atomic.Bool
+		watcher := newWatcher(func() (bool, error) {
+			return false, nil
+		}, func() error {
+			started.Store(true)
+			return nil
+		})
+
+		require.NoError(t, watcher.Start(ctx))
+		assert.False(t, started.Load())
+		assert.False(t, watcher.NeedLeaderElection())
+	})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/containeroo/autovpa/internal/controller"
+	"github.com/containeroo/autovpa/internal/flag"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// setupWorkloadReconcilers registers the controllers for the built-in workload
+// kinds enabled by flags and returns the kinds it registered. Disabled kinds get
+// no controller, so their objects are never watched or cached. Each controller
+// runs the number of workers set by its --<kind>-workers flag, so a backlog of
+// one kind does not starve the others.
+// newBase returns the shared reconciler settings for the given event recorder name.
+func setupWorkloadReconcilers(
+	mgr ctrl.Manager,
+	flags flag.Options,
+	newBase func(recorderName string) controller.BaseReconciler,
+) ([]string, error) {
+	var kinds []string
+
+	if flags.EnableDeployments {
+		base := newBase("deployment-controller")
+		base.MaxConcurrentReconciles = flags.DeploymentWorkers
+		if err := (&controller.DeploymentReconciler{
+			BaseReconciler: base,
+		}).SetupWithManager(mgr); err != nil {
+			return nil, fmt.Errorf("unable to create Deployment controller: %w", err)
+		}
+		kinds = append(kinds, controller.DeploymentGVK.Kind)
+	}
+
+	if flags.EnableStatefulSets {
+		base := newBase("statefulset-controller")
+		base.MaxConcurrentReconciles = flags.StatefulSetWorkers
+		if err := (&controller.StatefulSetReconciler{
+			BaseReconciler: base,
+		}).SetupWithManager(mgr); err != nil {
+			return nil, fmt.Errorf("unable to create StatefulSet controller: %w", err)
+		}
+		kinds = append(kinds, controller.StatefulSetGVK.Kind)
+	}
+
+	if flags.EnableDaemonSets {
+		base := newBase("daemonset-controller")
+		base.MaxConcurrentReconciles = flags.DaemonSetWorkers
+		if err := (&controller.DaemonSetReconciler{
+			BaseReconciler: base,
+		}).SetupWithManager(mgr); err != nil {
+			return nil, fmt.Errorf("unable to create DaemonSet controller: %w", err)
+		}
+		kinds = append(kinds, controller.DaemonSetGVK.Kind)
+	}
+
+	if flags.EnableReplicaSets {
+		base := newBase("replicaset-controller")
+		base.MaxConcurrentReconciles = flags.ReplicaSetWorkers
+		if err := (&controller.ReplicaSetReconciler{
+			BaseReconciler: base,
+		}).SetupWithManager(mgr); err != nil {
+			return nil, fmt.Errorf("unable to create ReplicaSet controller: %w", err)
+		}
+		kinds = append(kinds, controller.ReplicaSetGVK.Kind)
+	}
+
+	return kinds, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/controller"
+	"github.com/containeroo/autovpa/internal/flag"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// workerRecordingManager records the worker count of every controller added to it.
+type workerRecordingManager struct {
+	ctrl.Manager
+	workers map[string]int // Controller name -> MaxConcurrentReconciles.
+}
+
+// Add records the controller's worker count before adding it. Controllers are
+// runnables from an internal controller-runtime package, so the fields are
+// read via reflection.
+func (m *workerRecordingManager) Add(r manager.Runnable) error {
+	if v := reflect.ValueOf(r); v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct {
+		name, workers := v.Elem().FieldByName("Name"), v.Elem().FieldByName("MaxConcurrentReconciles")
+		if name.IsValid() && workers.IsValid() {
+			m.workers[name.String()] = int(workers.Int())
+		}
+	}
+	return m.Manager.Add(r)
+}
+
+func TestSetupWorkloadReconcilers(t *testing.T) {
+	t.Parallel()
+
+	newManager := func(t *testing.T) ctrl.Manager {
+		t.Helper()
+		// Nothing talks to the API server before the manager starts.
+		mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{
+			Scheme:                 scheme,
+			Metrics:                metricsserver.Options{BindAddress: "0"},
+			HealthProbeBindAddress: "0",
+			// Controller names are process-wide; other tests register the same ones.
+			Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+		})
+		require.NoError(t, err)
+		return mgr
+	}
+
+	newBase := func(string) controller.BaseReconciler {
+		logger := logr.Discard()
+		return controller.BaseReconciler{Logger: &logger}
+	}
+
+	t.Run("Registers all kinds by default", func(t *testing.T) {
+		t.Parallel()
+
+		flags, err := flag.ParseArgs(nil, "0.0.0")
+		require.NoError(t, err)
+
+		kinds, err := setupWorkloadReconcilers(newManager(t), flags, newBase)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment", "StatefulSet", "DaemonSet"}, kinds)
+	})
+
+	t.Run("Skips disabled kinds", func(t *testing.T) {
+		t.Parallel()
+
+		flags, err := flag.ParseArgs([]string{"--enable-statefulsets=false", "--enable-daemonsets=false"}, "0.0.0")
+		require.NoError(t, err)
+
+		kinds, err := setupWorkloadReconcilers(newManager(t), flags, newBase)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Deployment"}, kinds)
+	})
+
+	t.Run("Registers ReplicaSets when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		flags, err := flag.ParseArgs([]string{"--enable-deployments=false", "--enable-replicasets=true"}, "0.0.0")
+		require.NoError(t, err)
+
+		kinds, err := setupWorkloadReconcilers(newManager(t), flags, newBase)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"StatefulSet", "DaemonSet", "ReplicaSet"}, kinds)
+	})
+
+	t.Run("Applies the workers per kind", func(t *testing.T) {
+		t.Parallel()
+
+		flags, err := flag.ParseArgs([]string{
+			"--enable-replicasets=true",
+			"--deployment-workers=4",
+			"--daemonset-workers=2",
+			"--replicaset-workers=3",
+		}, "0.0.0")
+		require.NoError(t, err)
+
+		mgr := &workerRecordingManager{Manager: newManager(t), workers: map[string]int{}}
+		_, err = setupWorkloadReconcilers(mgr, flags, newBase)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{
+			"deployment":  4,
+			"statefulset": 1,
+			"daemonset":   2,
+			"replicaset":  3,
+		}, mgr.workers)
+	})
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTrailingData is wrapped by a ParseError when a JSON profiles file holds
+// more than one top-level value.
+var ErrTrailingData = errors.New("unexpected data after the top-level object")
+
+// ParseError reports a profiles document that could not be decoded.
+type ParseError struct {
+	Format Format // Format the document was decoded as.
+	Line   int    // 1-based line of the offending input; 0 when unknown.
+	Column int    // 1-based column of the offending input; 0 when unknown.
+	Err    error  // Underlying decoder error.
+}
+
+// Error implements error.
+func (e *ParseError) Error() string {
+	prefix := "parse profiles"
+	if e.Format == FormatJSON {
+		prefix += " as JSON"
+	}
+	switch {
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %v", prefix, e.Err)
+	case errors.Is(e.Err, ErrTrailingData):
+		return fmt.Sprintf("%s: %v at line %d, column %d", prefix, e.Err, e.Line, e.Column)
+	default:
+		return fmt.Sprintf("%s at line %d, column %d: %v", prefix, e.Line, e.Column, e.Err)
+	}
+}
+
+// Unwrap returns the underlying decoder error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError reports a problem found by Config.Validate.
+type ValidationError struct {
+	Profile string // Profile the problem belongs to; empty for config-wide problems.
+	Field   string // Offending field, e.g. "nameTemplate" or "kindDefaults".
+	Err     error  // Full description of the problem.
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the description, which may wrap a further cause.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalid returns a ValidationError for profile and field.
+func invalid(profile, field string, err error) *ValidationError {
+	return &ValidationError{Profile: profile, Field: field, Err: err}
+}
+
+// ValidationErrors returns every ValidationError in err's tree, including
+// all errors joined by Validate, in order.
+func ValidationErrors(err error) []*ValidationError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *ValidationError:
+		return []*ValidationError{e}
+	case interface{ Unwrap() []error }:
+		var out []*ValidationError
+		for _, inner := range e.Unwrap() {
+			out = append(out, ValidationErrors(inner)...)
+		}
+		return out
+	case interface{ Unwrap() error }:
+		return ValidationErrors(e.Unwrap())
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+)
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	load := func(t *testing.T, name, content string) error {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		_, err := LoadFile(path, FormatAuto)
+		return err
+	}
+
+	t.Run("YAML errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := load(t, "profiles.yaml", "defaultProfile: p1\nunknown: true\n")
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, FormatYAML, parseErr.Format)
+		assert.Zero(t, parseErr.Line)
+		assert.Equal(t, "parse profiles: "+parseErr.Err.Error(), err.Error())
+	})
+
+	t.Run("JSON syntax errors carry the position", func(t *testing.T) {
+		t.Parallel()
+
+		err := load(t, "profiles.json", "{\n  \"profiles\": {,}\n}")
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, FormatJSON, parseErr.Format)
+		assert.Equal(t, 2, parseErr.Line)
+		assert.Equal(t, 16, parseErr.Column)
+		assert.EqualError(t, err, "parse profiles as JSON at line 2, column 16: invalid character ',' looking for beginning of object key string")
+	})
+
+	t.Run("JSON trailing data", func(t *testing.T) {
+		t.Parallel()
+
+		err := load(t, "profiles.json", "{}\n{}")
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.ErrorIs(t, err, ErrTrailingData)
+		assert.Equal(t, 2, parseErr.Line)
+		assert.Equal(t, 1, parseErr.Column)
+		assert.EqualError(t, err, "parse profiles as JSON: unexpected data after the top-level object at line 2, column 1")
+	})
+
+	t.Run("Read errors are not parse errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"), FormatAuto)
+		require.Error(t, err)
+		var parseErr *ParseError
+		assert.False(t, errors.As(err, &parseErr))
+	})
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Config-wide errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := (&Config{}).Validate(flag.DefaultNameTemplate)
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Empty(t, verr.Profile)
+		assert.Equal(t, "profiles", verr.Field)
+		assert.EqualError(t, err, "profiles must be set")
+	})
+
+	t.Run("Collects profile errors", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{
+			DefaultProfile: "missing",
+			Profiles: map[string]Profile{
+				"a": {Spec: ProfileSpec{TargetRef: &autoscalingv1.CrossVersionObjectReference{Name: "x"}}},
+				"b": {NameTemplate: "{{ .Invalid }}"},
+				"c": {TargetAPIVersion: "apps/"},
+			},
+			KindDefaults: map[string]string{"DaemonSet": "gone"},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+
+		verrs := ValidationErrors(fmt.Errorf("reload config: %w", err))
+		require.Len(t, verrs, 5)
+		got := make([][2]string, 0, len(verrs))
+		for _, verr := range verrs {
+			got = append(got, [2]string{verr.Profile, verr.Field})
+		}
+		assert.Equal(t, [][2]string{
+			{"a", "spec"},
+			{"b", "nameTemplate"},
+			{"c", "targetApiVersion"},
+			{"missing", "defaultProfile"},
+			{"gone", "kindDefaults"},
+		}, got)
+
+		assert.EqualError(t, verrs[0], `profile "a" invalid: invalid profile: .targetRef must not be set`)
+		assert.EqualError(t, verrs[3], `defaultProfile "missing" not found in profiles`)
+		assert.EqualError(t, verrs[4], `kindDefaults["DaemonSet"]: profile "gone" not found in profiles`)
+	})
+
+	t.Run("Ignores other errors", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, ValidationErrors(nil))
+		assert.Nil(t, ValidationErrors(errors.New("boom")))
+	})
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	yamlv3 "go.yaml.in/yaml/v3"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -33,6 +37,12 @@ type ProfileSpec vpaautoscaling.VerticalPodAutoscalerSpec
 type Profile struct {
 	// NameTemplate optionally overrides the global VPA name template for this profile.
 	NameTemplate string `yaml:"nameTemplate,omitempty"`
+	// TargetAPIVersion optionally overrides the apiVersion written into the VPA targetRef.
+	TargetAPIVersion string `yaml:"targetApiVersion,omitempty"`
+	// Enabled optionally disables the profile while keeping it defined (nil means enabled).
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// ExcludeContainers optionally lists containers (e.g. sidecars) the VPA must not manage.
+	ExcludeContainers []string `yaml:"excludeContainers,omitempty"`
 	// Spec is the inline VerticalPodAutoscaler spec fragment for this profile.
 	Spec ProfileSpec `yaml:",inline"`
 }
@@ -43,19 +53,75 @@ type Config struct {
 	DefaultProfile string `yaml:"defaultProfile"`
 	// Profiles contains all available profiles keyed by their name.
 	Profiles map[string]Profile `yaml:"profiles"`
+	// KindDefaults optionally overrides DefaultProfile per workload kind
+	// (e.g. "DaemonSet"), for workloads that request "default" or no profile.
+	KindDefaults map[string]string `yaml:"kindDefaults,omitempty"`
+	// ProfileKindRestrictions optionally limits profiles to workload kinds
+	// (e.g. "DaemonSet"); profiles without an entry may be used by any kind.
+	ProfileKindRestrictions map[string][]string `yaml:"profileKindRestrictions,omitempty"`
+	// StrictNameTemplates turns name templates that ignore .Kind into
+	// validation errors instead of warnings. Set from --strict-name-templates.
+	StrictNameTemplates bool `json:"-" yaml:"-"`
+	// AdditionalKinds are the workload kinds added with --additional-target-kind,
+	// which profileKindRestrictions may name besides the built-in kinds.
+	AdditionalKinds []string `json:"-" yaml:"-"`
+
+	warnings []string // Non-fatal findings collected by Validate.
 }
 
+// Format is the encoding of a profiles file.
+type Format string
+
+// Supported profiles file formats.
+const (
+	FormatAuto Format = "auto" // JSON for .json files or documents starting with "{", YAML otherwise.
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
 // LoadFile reads a profiles file from disk and returns the parsed config.
-func LoadFile(filePath string) (*Config, error) {
+// Decoding failures are returned as *ParseError.
+func LoadFile(filePath string, format Format) (*Config, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("read profiles file %q: %w", filePath, err)
 	}
-	return parse(data)
+
+	switch detectFormat(filePath, data, format) {
+	case FormatJSON:
+		return parseJSON(data)
+	default:
+		return parse(data)
+	}
+}
+
+// detectFormat resolves FormatAuto from the file extension, falling back to
+// the first non-blank byte of data.
+func detectFormat(filePath string, data []byte, format Format) Format {
+	if format != FormatAuto && format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// IsEnabled reports whether the profile is enabled. Profiles are enabled
+// unless they set enabled: false.
+func (p Profile) IsEnabled() bool {
+	return p.Enabled == nil || *p.Enabled
 }
 
 // UnmarshalJSON supports inline VPA spec fields and rejects a nested
-// "spec" block. It inlines all keys except nameTemplate into the ProfileSpec.
+// "spec" block. It inlines all keys except nameTemplate, targetApiVersion,
+// enabled and excludeContainers into the ProfileSpec.
 func (p *Profile) UnmarshalJSON(data []byte) error {
 	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -75,6 +141,30 @@ func (p *Profile) UnmarshalJSON(data []byte) error {
 		delete(raw, "nameTemplate")
 	}
 
+	// Parse targetApiVersion.
+	if v, ok := raw["targetApiVersion"]; ok {
+		if err := json.Unmarshal(v, &p.TargetAPIVersion); err != nil {
+			return err
+		}
+		delete(raw, "targetApiVersion")
+	}
+
+	// Parse enabled.
+	if v, ok := raw["enabled"]; ok {
+		if err := json.Unmarshal(v, &p.Enabled); err != nil {
+			return fmt.Errorf("parse enabled: %w", err)
+		}
+		delete(raw, "enabled")
+	}
+
+	// Parse excludeContainers.
+	if v, ok := raw["excludeContainers"]; ok {
+		if err := json.Unmarshal(v, &p.ExcludeContainers); err != nil {
+			return fmt.Errorf("parse excludeContainers: %w", err)
+		}
+		delete(raw, "excludeContainers")
+	}
+
 	if len(raw) == 0 {
 		p.Spec = ProfileSpec{}
 		return nil
@@ -103,6 +193,11 @@ func (p *Profile) UnmarshalJSON(data []byte) error {
 //   - true, "true", "on", and "auto" are normalized to "Recreate".
 //   - false, "false", and "off" are normalized to "Off".
 //
+// Friendly aliases used by other tools are accepted as well:
+//   - "disabled" and "none" are normalized to "Off".
+//   - "recommend" is normalized to "Initial".
+//   - "enforce" is normalized to "Recreate", like "auto".
+//
 // Explicit non-deprecated modes such as "Recreate", "Initial", and
 // "InPlaceOrRecreate" are preserved.
 func (p *ProfileSpec) UnmarshalJSON(data []byte) error {
@@ -135,8 +230,8 @@ func (p *ProfileSpec) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// normalizeUpdateMode maps legacy updateMode aliases to explicit non-deprecated
-// VPA update modes.
+// normalizeUpdateMode maps legacy and friendly updateMode aliases to explicit
+// non-deprecated VPA update modes.
 func normalizeUpdateMode(value any) (string, error) {
 	switch v := value.(type) {
 	case bool:
@@ -147,11 +242,11 @@ func normalizeUpdateMode(value any) (string, error) {
 
 	case string:
 		switch strings.ToLower(strings.TrimSpace(v)) {
-		case "true", "on", "auto":
+		case "true", "on", "auto", "enforce":
 			return string(vpaautoscaling.UpdateModeRecreate), nil
-		case "false", "off":
+		case "false", "off", "disabled", "none":
 			return string(vpaautoscaling.UpdateModeOff), nil
-		case "initial":
+		case "initial", "recommend":
 			return string(vpaautoscaling.UpdateModeInitial), nil
 		case "recreate":
 			return string(vpaautoscaling.UpdateModeRecreate), nil
@@ -166,11 +261,182 @@ func normalizeUpdateMode(value any) (string, error) {
 	}
 }
 
-// parse unmarshals a profiles YAML document into a Config.
+// ParseUpdateMode normalizes an update mode (including the legacy aliases
+// accepted in profiles) and rejects values that are not a known VPA mode.
+func ParseUpdateMode(value string) (vpaautoscaling.UpdateMode, error) {
+	normalized, err := normalizeUpdateMode(value)
+	if err != nil {
+		return "", err
+	}
+
+	mode := vpaautoscaling.UpdateMode(normalized)
+	switch mode {
+	case vpaautoscaling.UpdateModeOff,
+		vpaautoscaling.UpdateModeInitial,
+		vpaautoscaling.UpdateModeRecreate,
+		vpaautoscaling.UpdateModeInPlaceOrRecreate:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown update mode %q", value)
+	}
+}
+
+// parse unmarshals a profiles YAML document into a Config. Anchors, aliases
+// and merge keys are resolved first. Errors are *ParseError.
 func parse(data []byte) (*Config, error) {
+	data, err := resolveAnchors(data)
+	if err != nil {
+		return nil, &ParseError{Format: FormatYAML, Err: err}
+	}
+
 	var cfg Config
 	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parse profiles: %w", err)
+		return nil, &ParseError{Format: FormatYAML, Err: err}
 	}
 	return &cfg, nil
 }
+
+// resolveAnchors expands aliases and "<<" merge keys in a YAML document and
+// re-marshals it, so the strict decoder only ever sees plain mappings. The
+// strict decoder would otherwise reject keys overriding merged values as
+// duplicates. Documents without aliases are returned unchanged, which keeps
+// line numbers in later decoding errors accurate.
+func resolveAnchors(data []byte) ([]byte, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	changed, err := resolveNode(&doc)
+	if err != nil || !changed {
+		return data, err
+	}
+	return yamlv3.Marshal(&doc)
+}
+
+// resolveNode replaces aliases below n with copies of their anchored nodes and
+// folds merge keys into their mappings. It reports whether n was modified.
+func resolveNode(n *yamlv3.Node) (bool, error) {
+	changed := false
+	if n.Kind == yamlv3.AliasNode {
+		*n = *cloneNode(n.Alias)
+		changed = true
+	}
+	n.Anchor = ""
+
+	for _, child := range n.Content {
+		c, err := resolveNode(child)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || c
+	}
+
+	if n.Kind != yamlv3.MappingNode {
+		return changed, nil
+	}
+	merged, err := mergeKeys(n)
+	if err != nil {
+		return false, err
+	}
+	return changed || merged, nil
+}
+
+// mergeKeys folds the "<<" entries of mapping m into m. Keys set on m itself
+// take precedence, followed by earlier merge sources.
+func mergeKeys(m *yamlv3.Node) (bool, error) {
+	var own []*yamlv3.Node
+	var sources []*yamlv3.Node
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key, value := m.Content[i], m.Content[i+1]
+		if key.Kind != yamlv3.ScalarNode || key.Tag != "!!merge" {
+			own = append(own, key, value)
+			continue
+		}
+		switch value.Kind {
+		case yamlv3.MappingNode:
+			sources = append(sources, value)
+		case yamlv3.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind != yamlv3.MappingNode {
+					return false, fmt.Errorf("line %d: merge key values must be mappings", item.Line)
+				}
+				sources = append(sources, item)
+			}
+		default:
+			return false, fmt.Errorf("line %d: merge key value must be a mapping or a sequence of mappings", value.Line)
+		}
+	}
+	if len(own) == len(m.Content) {
+		return false, nil
+	}
+
+	seen := make(map[string]bool, len(own)/2)
+	for i := 0; i < len(own); i += 2 {
+		seen[own[i].Value] = true
+	}
+	for _, src := range sources {
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			if key := src.Content[i]; !seen[key.Value] {
+				seen[key.Value] = true
+				own = append(own, key, src.Content[i+1])
+			}
+		}
+	}
+	m.Content = own
+	return true, nil
+}
+
+// cloneNode returns a deep copy of n, so aliases expanded in several places
+// do not share nodes.
+func cloneNode(n *yamlv3.Node) *yamlv3.Node {
+	out := *n
+	out.Content = make([]*yamlv3.Node, len(n.Content))
+	for i, child := range n.Content {
+		out.Content[i] = cloneNode(child)
+	}
+	return &out
+}
+
+// parseJSON unmarshals a profiles JSON document into a Config, rejecting
+// unknown fields and trailing data like parse. Errors are *ParseError
+// carrying the line and column of the offending input when known.
+func parseJSON(data []byte) (*Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		line, col := jsonErrorPosition(data, err)
+		return nil, &ParseError{Format: FormatJSON, Line: line, Column: col, Err: err}
+	}
+	if dec.More() {
+		line, col := lineColumn(data, dec.InputOffset())
+		return nil, &ParseError{Format: FormatJSON, Line: line, Column: col, Err: ErrTrailingData}
+	}
+	return &cfg, nil
+}
+
+// jsonErrorPosition returns the line and column of JSON errors carrying an
+// input offset, or zeros otherwise.
+func jsonErrorPosition(data []byte, err error) (line, col int) {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset - 1 // Offset is just past the offending byte.
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return 0, 0
+	}
+	return lineColumn(data, offset)
+}
+
+// lineColumn converts a byte offset into data to a 1-based line and column.
+func lineColumn(data []byte, offset int64) (line, col int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	corev1 "k8s.io/api/core/v1"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -45,7 +46,7 @@ profiles:
 `), 0o644)
 		require.NoError(t, err)
 
-		cfg, err := LoadFile(path)
+		cfg, err := LoadFile(path, FormatAuto)
 		require.NoError(t, err)
 		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
 
@@ -54,9 +55,52 @@ profiles:
 		assert.True(t, ok, "expected profile p1")
 	})
 
+	t.Run("Loads kind defaults", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "profiles.yaml")
+		err := os.WriteFile(path, []byte(`
+defaultProfile: p1
+kindDefaults:
+  DaemonSet: observe
+profiles:
+  p1: {}
+  observe:
+    updatePolicy:
+      updateMode: "Off"
+`), 0o644)
+		require.NoError(t, err)
+
+		cfg, err := LoadFile(path, FormatAuto)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		assert.Equal(t, map[string]string{"DaemonSet": "observe"}, cfg.KindDefaults)
+	})
+
+	t.Run("Loads profile kind restrictions", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "profiles.yaml")
+		err := os.WriteFile(path, []byte(`
+defaultProfile: p1
+profileKindRestrictions:
+  p1: [Deployment, StatefulSet]
+profiles:
+  p1: {}
+`), 0o644)
+		require.NoError(t, err)
+
+		cfg, err := LoadFile(path, FormatAuto)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		assert.Equal(t, map[string][]string{"p1": {"Deployment", "StatefulSet"}}, cfg.ProfileKindRestrictions)
+	})
+
 	t.Run("Fails when file missing", func(t *testing.T) {
 		t.Parallel()
-		_, err := LoadFile("/tmp/does-not-exist.yaml")
+		_, err := LoadFile("/tmp/does-not-exist.yaml", FormatAuto)
 		assert.Error(t, err)
 	})
 
@@ -67,11 +111,129 @@ profiles:
 		err := os.WriteFile(path, []byte(`:bad yaml`), 0o644)
 		require.NoError(t, err)
 
-		_, err = LoadFile(path)
+		_, err = LoadFile(path, FormatAuto)
 		assert.Error(t, err)
 	})
 }
 
+func TestConfigLoadFile_JSON(t *testing.T) {
+	t.Parallel()
+
+	const validJSON = `{
+  "defaultProfile": "p1",
+  "kindDefaults": {"DaemonSet": "p2"},
+  "profiles": {
+    "p1": {"updatePolicy": {"updateMode": false}},
+    "p2": {"nameTemplate": "{{ .Namespace }}-{{ .WorkloadName }}-quiet"}
+  }
+}`
+
+	writeFile := func(t *testing.T, name, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("Loads JSON by extension", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.json", validJSON)
+
+		cfg, err := LoadFile(path, FormatAuto)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		assert.Equal(t, "p1", cfg.DefaultProfile)
+		assert.Equal(t, map[string]string{"DaemonSet": "p2"}, cfg.KindDefaults)
+		mode := cfg.Profiles["p1"].Spec.UpdatePolicy.UpdateMode
+		require.NotNil(t, mode)
+		assert.Equal(t, vpaautoscaling.UpdateModeOff, *mode)
+		assert.Equal(t, "{{ .Namespace }}-{{ .WorkloadName }}-quiet", cfg.Profiles["p2"].NameTemplate)
+	})
+
+	t.Run("Detects JSON content without extension", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles", "\n  "+validJSON)
+
+		cfg, err := LoadFile(path, FormatAuto)
+		require.NoError(t, err)
+		assert.Equal(t, "p1", cfg.DefaultProfile)
+	})
+
+	t.Run("Explicit JSON format overrides the extension", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.yaml", validJSON)
+
+		cfg, err := LoadFile(path, FormatJSON)
+		require.NoError(t, err)
+		assert.Equal(t, "p1", cfg.DefaultProfile)
+	})
+
+	t.Run("Explicit JSON format rejects YAML", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.yaml", "defaultProfile: p1\n")
+
+		_, err := LoadFile(path, FormatJSON)
+		require.Error(t, err)
+		assert.EqualError(t, err, "parse profiles as JSON at line 1, column 1: invalid character 'd' looking for beginning of value")
+	})
+
+	t.Run("Explicit YAML format accepts YAML in a .json file", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.json", "defaultProfile: p1\nprofiles:\n  p1: {}\n")
+
+		cfg, err := LoadFile(path, FormatYAML)
+		require.NoError(t, err)
+		assert.Equal(t, "p1", cfg.DefaultProfile)
+	})
+
+	t.Run("Rejects the spec field", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.json", `{"defaultProfile": "p1", "profiles": {"p1": {"spec": {}}}}`)
+
+		_, err := LoadFile(path, FormatAuto)
+		require.Error(t, err)
+		assert.EqualError(t, err, "parse profiles as JSON: profile spec must be provided inline; the spec field is not supported")
+	})
+
+	t.Run("Rejects unknown fields", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.json", `{"defaultProfile": "p1", "profile": {}}`)
+
+		_, err := LoadFile(path, FormatAuto)
+		require.Error(t, err)
+		assert.EqualError(t, err, `parse profiles as JSON: json: unknown field "profile"`)
+	})
+
+	t.Run("Reports the position of syntax errors", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.json", "{\n  \"defaultProfile\": \"p1\",\n  \"profiles\": {,}\n}")
+
+		_, err := LoadFile(path, FormatAuto)
+		require.Error(t, err)
+		assert.EqualError(t, err, "parse profiles as JSON at line 3, column 16: invalid character ',' looking for beginning of object key string")
+	})
+
+	t.Run("Reports the position of type errors", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.json", "{\n  \"defaultProfile\": 1\n}")
+
+		_, err := LoadFile(path, FormatAuto)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse profiles as JSON at line 2, column")
+		assert.Contains(t, err.Error(), "cannot unmarshal number")
+	})
+
+	t.Run("Rejects trailing data", func(t *testing.T) {
+		t.Parallel()
+		path := writeFile(t, "profiles.json", validJSON+"\n{}")
+
+		_, err := LoadFile(path, FormatAuto)
+		require.Error(t, err)
+		assert.EqualError(t, err, "parse profiles as JSON: unexpected data after the top-level object at line 9, column 1")
+	})
+}
+
 func TestConfigParse(t *testing.T) {
 	t.Parallel()
 
@@ -95,6 +257,98 @@ profiles:
 		assert.True(t, ok, "expected profile p1 to be present")
 	})
 
+	t.Run("Parses eviction requirements", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: p1
+profiles:
+  p1:
+    updatePolicy:
+      updateMode: true
+      evictionRequirements:
+        - resources: ["cpu", "memory"]
+          changeRequirement: TargetHigherThanRequests
+        - resources: ["memory"]
+          changeRequirement: TargetLowerThanRequests
+`)
+
+		cfg, err := parse(data)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		policy := cfg.Profiles["p1"].Spec.UpdatePolicy
+		require.NotNil(t, policy)
+		require.NotNil(t, policy.UpdateMode)
+		assert.Equal(t, vpaautoscaling.UpdateModeRecreate, *policy.UpdateMode)
+		assert.Equal(t, []*vpaautoscaling.EvictionRequirement{
+			{
+				Resources:         []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+				ChangeRequirement: vpaautoscaling.TargetHigherThanRequests,
+			},
+			{
+				Resources:         []corev1.ResourceName{corev1.ResourceMemory},
+				ChangeRequirement: vpaautoscaling.TargetLowerThanRequests,
+			},
+		}, policy.EvictionRequirements)
+	})
+
+	t.Run("Parses recommenders", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: p1
+profiles:
+  p1:
+    recommenders:
+      - name: frugal
+`)
+
+		cfg, err := parse(data)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		assert.Equal(t, []*vpaautoscaling.VerticalPodAutoscalerRecommenderSelector{{Name: "frugal"}}, cfg.Profiles["p1"].Spec.Recommenders)
+	})
+
+	t.Run("Rejects empty recommender name", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: p1
+profiles:
+  p1:
+    recommenders:
+      - name: ""
+`)
+
+		cfg, err := parse(data)
+		require.NoError(t, err)
+		err = cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), ".recommenders[0].name must not be empty")
+	})
+
+	t.Run("Rejects unknown eviction change requirement", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: p1
+profiles:
+  p1:
+    updatePolicy:
+      evictionRequirements:
+        - resources: ["cpu"]
+          changeRequirement: Sometimes
+`)
+
+		cfg, err := parse(data)
+		require.NoError(t, err)
+		err = cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `.changeRequirement: unsupported value "Sometimes"`)
+	})
+
 	t.Run("Fails on invalid YAML", func(t *testing.T) {
 		t.Parallel()
 
@@ -104,6 +358,116 @@ profiles:
 		assert.Error(t, err, "expected parse to fail on invalid YAML")
 	})
 
+	t.Run("Resolves anchors and aliases", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: a
+profiles:
+  a: &shared
+    updatePolicy:
+      updateMode: "Recreate"
+    resourcePolicy:
+      containerPolicies:
+        - containerName: "*"
+          controlledResources: ["cpu", "memory"]
+  b: *shared
+`)
+
+		cfg, err := parse(data)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		require.NotNil(t, cfg.Profiles["a"].Spec.ResourcePolicy)
+		assert.Equal(t, cfg.Profiles["a"], cfg.Profiles["b"])
+	})
+
+	t.Run("Resolves merge keys with overrides", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: a
+profiles:
+  a: &shared
+    updatePolicy:
+      updateMode: "Recreate"
+    resourcePolicy:
+      containerPolicies:
+        - containerName: "*"
+          controlledResources: ["cpu", "memory"]
+  b:
+    <<: *shared
+    updatePolicy:
+      updateMode: "Off"
+`)
+
+		cfg, err := parse(data)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		a, b := cfg.Profiles["a"], cfg.Profiles["b"]
+		assert.Equal(t, a.Spec.ResourcePolicy, b.Spec.ResourcePolicy)
+		require.NotNil(t, b.Spec.UpdatePolicy)
+		assert.Equal(t, vpaautoscaling.UpdateModeOff, *b.Spec.UpdatePolicy.UpdateMode)
+		assert.Equal(t, vpaautoscaling.UpdateModeRecreate, *a.Spec.UpdatePolicy.UpdateMode)
+	})
+
+	t.Run("Resolves a sequence of merge keys", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: a
+profiles:
+  a:
+    updatePolicy: &update
+      updateMode: "Initial"
+    recommenders: &recommenders
+      - name: frugal
+  b:
+    <<: [{updatePolicy: *update}, {recommenders: *recommenders}]
+`)
+
+		cfg, err := parse(data)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+		assert.Equal(t, cfg.Profiles["a"], cfg.Profiles["b"])
+	})
+
+	t.Run("Rejects merge keys that are not mappings", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: a
+profiles:
+  a:
+    <<: nope
+`)
+
+		_, err := parse(data)
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.EqualError(t, err, "parse profiles: line 5: merge key value must be a mapping or a sequence of mappings")
+	})
+
+	t.Run("Still rejects duplicate keys", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`---
+defaultProfile: a
+profiles:
+  a:
+    updatePolicy:
+      updateMode: "Recreate"
+    updatePolicy:
+      updateMode: "Off"
+`)
+
+		_, err := parse(data)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already set")
+	})
+
 	t.Run("Parses but leaves semantic validation to Validate", func(t *testing.T) {
 		t.Parallel()
 
@@ -182,6 +546,82 @@ profiles:
 		}
 		assert.ElementsMatch(t, []string{"cpu", "memory"}, gotResources)
 	})
+
+	t.Run("Parses targetApiVersion outside the spec", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`
+targetApiVersion: argoproj.io/v1alpha1
+updatePolicy:
+  updateMode: Off
+`)
+		var p Profile
+		require.NoError(t, yaml.Unmarshal(data, &p))
+
+		assert.Equal(t, "argoproj.io/v1alpha1", p.TargetAPIVersion)
+		require.NotNil(t, p.Spec.UpdatePolicy)
+		assert.Nil(t, p.Spec.TargetRef)
+	})
+
+	t.Run("Parses excludeContainers outside the spec", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`
+excludeContainers: [istio-proxy, linkerd-proxy]
+updatePolicy:
+  updateMode: Off
+`)
+		var p Profile
+		require.NoError(t, yaml.Unmarshal(data, &p))
+
+		assert.Equal(t, []string{"istio-proxy", "linkerd-proxy"}, p.ExcludeContainers)
+		require.NotNil(t, p.Spec.UpdatePolicy)
+	})
+
+	t.Run("Rejects non-list excludeContainers", func(t *testing.T) {
+		t.Parallel()
+
+		var p Profile
+		err := yaml.Unmarshal([]byte("excludeContainers: istio-proxy\n"), &p)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse excludeContainers")
+	})
+
+	t.Run("Parses enabled outside the spec", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`
+enabled: false
+updatePolicy:
+  updateMode: Off
+`)
+		var p Profile
+		require.NoError(t, yaml.Unmarshal(data, &p))
+
+		require.NotNil(t, p.Enabled)
+		assert.False(t, *p.Enabled)
+		assert.False(t, p.IsEnabled())
+		require.NotNil(t, p.Spec.UpdatePolicy)
+	})
+
+	t.Run("Defaults to enabled", func(t *testing.T) {
+		t.Parallel()
+
+		var p Profile
+		require.NoError(t, yaml.Unmarshal([]byte(`updatePolicy: {updateMode: "Off"}`), &p))
+
+		assert.Nil(t, p.Enabled)
+		assert.True(t, p.IsEnabled())
+	})
+
+	t.Run("Rejects non-boolean enabled", func(t *testing.T) {
+		t.Parallel()
+
+		var p Profile
+		err := yaml.Unmarshal([]byte(`enabled: maybe`), &p)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse enabled")
+	})
 }
 
 func TestProfileSpecUnmarshalJSON(t *testing.T) {
@@ -307,3 +747,70 @@ updatePolicy:
 		assert.Equal(t, vpaautoscaling.UpdateModeInPlaceOrRecreate, *mode)
 	})
 }
+
+func TestProfileSpecUnmarshalJSON_Aliases(t *testing.T) {
+	t.Parallel()
+
+	for alias, want := range map[string]vpaautoscaling.UpdateMode{
+		"disabled":  vpaautoscaling.UpdateModeOff,
+		"none":      vpaautoscaling.UpdateModeOff,
+		"recommend": vpaautoscaling.UpdateModeInitial,
+		"enforce":   vpaautoscaling.UpdateModeRecreate,
+		"Disabled":  vpaautoscaling.UpdateModeOff,
+		" ENFORCE ": vpaautoscaling.UpdateModeRecreate,
+	} {
+		t.Run(alias, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := parse([]byte(`
+defaultProfile: p1
+profiles:
+  p1:
+    updatePolicy:
+      updateMode: "` + alias + `"
+`))
+			require.NoError(t, err)
+			require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+
+			mode := cfg.Profiles["p1"].Spec.UpdatePolicy.UpdateMode
+			require.NotNil(t, mode)
+			assert.Equal(t, want, *mode)
+		})
+	}
+}
+
+func TestParseUpdateMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Accepts known modes", func(t *testing.T) {
+		t.Parallel()
+
+		mode, err := ParseUpdateMode("Initial")
+		require.NoError(t, err)
+		assert.Equal(t, vpaautoscaling.UpdateModeInitial, mode)
+	})
+
+	t.Run("Normalizes legacy aliases", func(t *testing.T) {
+		t.Parallel()
+
+		mode, err := ParseUpdateMode("off")
+		require.NoError(t, err)
+		assert.Equal(t, vpaautoscaling.UpdateModeOff, mode)
+
+		mode, err = ParseUpdateMode("Auto")
+		require.NoError(t, err)
+		assert.Equal(t, vpaautoscaling.UpdateModeRecreate, mode)
+
+		mode, err = ParseUpdateMode("recommend")
+		require.NoError(t, err)
+		assert.Equal(t, vpaautoscaling.UpdateModeInitial, mode)
+	})
+
+	t.Run("Rejects unknown modes", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseUpdateMode("Sometimes")
+		require.Error(t, err)
+		assert.EqualError(t, err, `unknown update mode "Sometimes"`)
+	})
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// LoadNamespaceFile reads a list of namespaces to watch, one per line.
+// Blank lines and lines starting with "#" are ignored and duplicates dropped.
+// A file without namespaces is an error, since an empty list would widen the
+// operator's scope to the whole cluster.
+func LoadNamespaceFile(filePath string) ([]string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read namespace file: %w", err)
+	}
+	return parseNamespaces(data)
+}
+
+// parseNamespaces parses the content of a namespace file.
+func parseNamespaces(data []byte) ([]string, error) {
+	var namespaces []string
+	for i, line := range strings.Split(string(data), "\n") {
+		namespace := strings.TrimSpace(line)
+		if namespace == "" || strings.HasPrefix(namespace, "#") {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("namespace file line %d: invalid namespace %q: %s", i+1, namespace, strings.Join(errs, "; "))
+		}
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		return nil, errors.New("namespace file lists no namespaces")
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadNamespaceFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Loads namespaces", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "namespaces")
+		require.NoError(t, os.WriteFile(path, []byte("# tenants\nteam-a\n\n  team-b  \nteam-a\n"), 0o644))
+
+		namespaces, err := LoadNamespaceFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a", "team-b"}, namespaces)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadNamespaceFile(filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read namespace file: ")
+	})
+}
+
+func TestParseNamespaces(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Handles CRLF line endings", func(t *testing.T) {
+		t.Parallel()
+		namespaces, err := parseNamespaces([]byte("team-a\r\nteam-b\r\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a", "team-b"}, namespaces)
+	})
+
+	t.Run("Rejects invalid namespaces", func(t *testing.T) {
+		t.Parallel()
+		_, err := parseNamespaces([]byte("team-a\nTeam_B\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `namespace file line 2: invalid namespace "Team_B": `)
+	})
+
+	t.Run("Rejects files without namespaces", func(t *testing.T) {
+		t.Parallel()
+		_, err := parseNamespaces([]byte("# nothing yet\n\n"))
+		require.Error(t, err)
+		assert.EqualError(t, err, "namespace file lists no namespaces")
+	})
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var quantityType = reflect.TypeFor[resource.Quantity]()
+
+// enumValues lists the allowed values for VPA string enums used in profile specs.
+var enumValues = map[reflect.Type][]string{
+	reflect.TypeFor[vpaautoscaling.ContainerScalingMode](): {
+		string(vpaautoscaling.ContainerScalingModeAuto),
+		string(vpaautoscaling.ContainerScalingModeOff),
+	},
+	reflect.TypeFor[vpaautoscaling.ContainerControlledValues](): {
+		string(vpaautoscaling.ContainerControlledValuesRequestsAndLimits),
+		string(vpaautoscaling.ContainerControlledValuesRequestsOnly),
+	},
+	reflect.TypeFor[vpaautoscaling.EvictionChangeRequirement](): {
+		string(vpaautoscaling.TargetHigherThanRequests),
+		string(vpaautoscaling.TargetLowerThanRequests),
+	},
+}
+
+// Schema returns a JSON Schema describing the profiles file.
+//
+// The profile spec properties are generated from the VPA Go types, so the
+// schema follows the vendored VPA API. The fields AutoVPA rejects (a nested
+// "spec" block and "targetRef") are marked as forbidden.
+func Schema() map[string]any {
+	profile := schemaForType(reflect.TypeFor[ProfileSpec]())
+	props := profile["properties"].(map[string]any)
+
+	props["nameTemplate"] = map[string]any{
+		"type":        "string",
+		"description": "Optional VPA name template overriding the global --vpa-name-template for this profile.",
+	}
+	props["targetApiVersion"] = map[string]any{
+		"type":        "string",
+		"description": "Optional group/version written into the VPA targetRef instead of the workload's apiVersion.",
+	}
+	props["enabled"] = map[string]any{
+		"type":        "boolean",
+		"description": "Set to false to keep the profile defined but inactive. Defaults to true.",
+	}
+	props["excludeContainers"] = map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string", "minLength": 1, "not": map[string]any{"enum": []string{"*", AllContainers}}},
+		"uniqueItems": true,
+		"description": "Containers (e.g. sidecars) the VPA must not manage; each gets a container policy with mode Off.",
+	}
+	props["targetRef"] = map[string]any{
+		"not":         map[string]any{},
+		"description": "Forbidden: AutoVPA sets targetRef from the workload.",
+	}
+	props["spec"] = map[string]any{
+		"not":         map[string]any{},
+		"description": "Forbidden: profile specs are defined inline, not under a nested spec key.",
+	}
+	if up, ok := props["updatePolicy"].(map[string]any); ok {
+		upProps := up["properties"].(map[string]any)
+		upProps["updateMode"] = map[string]any{
+			"type":        []string{"string", "boolean"},
+			"description": "VPA update mode (Off, Initial, Recreate, InPlaceOrRecreate, ...). Booleans are normalized to Recreate/Off; the aliases disabled/none (Off), recommend (Initial) and enforce (Recreate) are accepted.",
+		}
+	}
+	profile["description"] = "A profile is an inline VerticalPodAutoscaler spec fragment plus optional AutoVPA metadata."
+
+	return map[string]any{
+		"$schema":              schemaDraft,
+		"title":                "AutoVPA profiles",
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"defaultProfile", "profiles"},
+		"properties": map[string]any{
+			"defaultProfile": map[string]any{
+				"type":        "string",
+				"description": "Profile used when a workload requests \"default\". Must name an entry in profiles.",
+			},
+			"profiles": map[string]any{
+				"type":                 "object",
+				"description":          "Available profiles keyed by name.",
+				"minProperties":        1,
+				"additionalProperties": profile,
+			},
+			"kindDefaults": map[string]any{
+				"type":                 "object",
+				"description":          "Optional default profile per workload kind (e.g. DaemonSet), used instead of defaultProfile for that kind. Each value must name an entry in profiles.",
+				"additionalProperties": map[string]any{"type": "string"},
+			},
+			"profileKindRestrictions": map[string]any{
+				"type":        "object",
+				"description": "Optional workload kinds allowed to use a profile, keyed by profile name. Other kinds requesting the profile are skipped with a ProfileKindNotAllowed event. Profiles without an entry may be used by any kind.",
+				"additionalProperties": map[string]any{
+					"type":     "array",
+					"minItems": 1,
+					"items":    map[string]any{"type": "string", "minLength": 1},
+				},
+			},
+		},
+	}
+}
+
+// schemaForType derives a JSON Schema fragment from a Go type using its json tags.
+func schemaForType(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return schemaForType(t.Elem())
+	}
+	if t == quantityType {
+		return map[string]any{"type": []string{"string", "number"}}
+	}
+	if values, ok := enumValues[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem())}
+	case reflect.Struct:
+		props := map[string]any{}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			props[name] = schemaForType(field.Type)
+		}
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           props,
+		}
+	default:
+		return map[string]any{}
+	}
+}
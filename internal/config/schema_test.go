@@ -0,0 +1,97 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Marshals to valid JSON", func(t *testing.T) {
+		t.Parallel()
+		out, err := json.Marshal(Schema())
+		require.NoError(t, err)
+
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(out, &decoded))
+		assert.Equal(t, schemaDraft, decoded["$schema"])
+	})
+
+	t.Run("Documents profile fields", func(t *testing.T) {
+		t.Parallel()
+		schema := Schema()
+
+		props := schema["properties"].(map[string]any)
+		require.Contains(t, props, "defaultProfile")
+		require.Contains(t, props, "profiles")
+		require.Contains(t, props, "kindDefaults")
+		require.Contains(t, props, "profileKindRestrictions")
+
+		profile := props["profiles"].(map[string]any)["additionalProperties"].(map[string]any)
+		profileProps := profile["properties"].(map[string]any)
+		assert.Contains(t, profileProps, "nameTemplate")
+		assert.Contains(t, profileProps, "targetApiVersion")
+		assert.Contains(t, profileProps, "enabled")
+		assert.Contains(t, profileProps, "excludeContainers")
+		assert.Contains(t, profileProps, "updatePolicy")
+		assert.Contains(t, profileProps, "resourcePolicy")
+		assert.Contains(t, profileProps, "recommenders")
+		assert.Equal(t, map[string]any{}, profileProps["spec"].(map[string]any)["not"])
+		assert.Equal(t, map[string]any{}, profileProps["targetRef"].(map[string]any)["not"])
+		assert.Equal(t, false, profile["additionalProperties"])
+	})
+
+	t.Run("Generates container policy properties", func(t *testing.T) {
+		t.Parallel()
+		profile := Schema()["properties"].(map[string]any)["profiles"].(map[string]any)["additionalProperties"].(map[string]any)
+		rp := profile["properties"].(map[string]any)["resourcePolicy"].(map[string]any)
+		cp := rp["properties"].(map[string]any)["containerPolicies"].(map[string]any)["items"].(map[string]any)
+		cpProps := cp["properties"].(map[string]any)
+
+		assert.Equal(t, map[string]any{"type": "string"}, cpProps["containerName"])
+		assert.Equal(t, []string{"Auto", "Off"}, cpProps["mode"].(map[string]any)["enum"])
+		minAllowed := cpProps["minAllowed"].(map[string]any)
+		assert.Equal(t, "object", minAllowed["type"])
+		assert.Equal(t, map[string]any{"type": []string{"string", "number"}}, minAllowed["additionalProperties"])
+	})
+}
+
+func TestSchemaForType(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Maps scalar kinds", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, map[string]any{"type": "string"}, schemaForType(reflect.TypeFor[string]()))
+		assert.Equal(t, map[string]any{"type": "boolean"}, schemaForType(reflect.TypeFor[bool]()))
+		assert.Equal(t, map[string]any{"type": "integer"}, schemaForType(reflect.TypeFor[*int32]()))
+		assert.Equal(t, map[string]any{"type": "number"}, schemaForType(reflect.TypeFor[float64]()))
+	})
+
+	t.Run("Maps quantities", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, map[string]any{"type": []string{"string", "number"}}, schemaForType(reflect.TypeFor[*resource.Quantity]()))
+	})
+}
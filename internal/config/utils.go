@@ -17,24 +17,54 @@ limitations under the License.
 package config
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 )
 
+// AllContainers is the container policy name a profile uses for a policy
+// applying to every container of the workload. Depending on
+// --container-policy-mode it renders as one policy per container or as the
+// "*" policy.
+const AllContainers = "__all__"
+
 // copyProfileSpec returns a deep copy of the provided VPA profile spec.
 func copyProfileSpec(spec ProfileSpec) ProfileSpec {
 	typed := vpaautoscaling.VerticalPodAutoscalerSpec(spec)
 	return ProfileSpec(*typed.DeepCopy())
 }
 
-// validateProfileSpec ensures that targetRef is unset in the profile.
+// validateProfileSpec ensures that targetRef is unset in the profile and that
+// its recommenders, eviction requirements and container policies are
+// well-formed.
 func validateProfileSpec(spec *ProfileSpec) error {
 	typed := vpaautoscaling.VerticalPodAutoscalerSpec(*spec)
 
 	if typed.TargetRef != nil {
 		return fmt.Errorf("invalid profile: .targetRef must not be set")
 	}
+	for i, rec := range typed.Recommenders {
+		if rec == nil || strings.TrimSpace(rec.Name) == "" {
+			return fmt.Errorf("invalid profile: .recommenders[%d].name must not be empty", i)
+		}
+	}
+	if typed.UpdatePolicy != nil {
+		for i, req := range typed.UpdatePolicy.EvictionRequirements {
+			if err := validateEvictionRequirement(req); err != nil {
+				return fmt.Errorf("invalid profile: .updatePolicy.evictionRequirements[%d]: %w", i, err)
+			}
+		}
+	}
+
+	if typed.ResourcePolicy != nil {
+		if err := validateContainerPolicies(typed.ResourcePolicy.ContainerPolicies); err != nil {
+			return fmt.Errorf("invalid profile: .resourcePolicy.containerPolicies%w", err)
+		}
+	}
 
 	// Clear targetRef explicitly to avoid accidental reuse.
 	typed.TargetRef = nil
@@ -43,3 +73,77 @@ func validateProfileSpec(spec *ProfileSpec) error {
 
 	return nil
 }
+
+// validateEvictionRequirement checks that req names at least one of cpu or
+// memory and a change requirement the VPA updater understands.
+func validateEvictionRequirement(req *vpaautoscaling.EvictionRequirement) error {
+	if req == nil {
+		return errors.New("must not be null")
+	}
+	if len(req.Resources) == 0 {
+		return errors.New(".resources must not be empty")
+	}
+	for _, name := range req.Resources {
+		if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+			return fmt.Errorf(".resources: unsupported resource %q (must be cpu or memory)", name)
+		}
+	}
+	switch req.ChangeRequirement {
+	case vpaautoscaling.TargetHigherThanRequests, vpaautoscaling.TargetLowerThanRequests:
+		return nil
+	default:
+		return fmt.Errorf(
+			".changeRequirement: unsupported value %q (must be %s or %s)",
+			req.ChangeRequirement, vpaautoscaling.TargetHigherThanRequests, vpaautoscaling.TargetLowerThanRequests,
+		)
+	}
+}
+
+// validateContainerPolicies ensures every container is named once. AllContainers
+// and "*" both cover every container, so a profile may use only one of them.
+func validateContainerPolicies(policies []vpaautoscaling.ContainerResourcePolicy) error {
+	seen := make(map[string]bool, len(policies))
+	for i, policy := range policies {
+		name := policy.ContainerName
+		switch {
+		case seen[name]:
+			return fmt.Errorf("[%d] duplicates container %q", i, name)
+		case name == AllContainers && seen[vpaautoscaling.DefaultContainerResourcePolicy],
+			name == vpaautoscaling.DefaultContainerResourcePolicy && seen[AllContainers]:
+			return fmt.Errorf("[%d] %q and %q must not be combined", i, AllContainers, vpaautoscaling.DefaultContainerResourcePolicy)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// validateAPIVersion ensures the value is a parseable group/version with a version set.
+func validateAPIVersion(apiVersion string) error {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return err
+	}
+	if gv.Version == "" {
+		return errors.New("version must not be empty")
+	}
+	return nil
+}
+
+// validateExcludeContainers ensures every excluded container is named once.
+// The "*" wildcard and AllContainers are rejected: excluding every container
+// disables the VPA.
+func validateExcludeContainers(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		switch {
+		case strings.TrimSpace(name) == "":
+			return fmt.Errorf("[%d] must not be empty", i)
+		case name == vpaautoscaling.DefaultContainerResourcePolicy, name == AllContainers:
+			return fmt.Errorf("[%d] must not be %q", i, name)
+		case seen[name]:
+			return fmt.Errorf("[%d] duplicates container %q", i, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	k8sautoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 )
 
@@ -48,6 +49,85 @@ func TestValidateProfileSpec(t *testing.T) {
 		}
 		assert.Error(t, validateProfileSpec(&spec))
 	})
+
+	t.Run("Allows recommenders", func(t *testing.T) {
+		t.Parallel()
+		spec := ProfileSpec{
+			Recommenders: []*vpaautoscaling.VerticalPodAutoscalerRecommenderSelector{{Name: "frugal"}},
+		}
+		require.NoError(t, validateProfileSpec(&spec))
+	})
+
+	t.Run("Rejects empty recommender names", func(t *testing.T) {
+		t.Parallel()
+		for _, rec := range []*vpaautoscaling.VerticalPodAutoscalerRecommenderSelector{nil, {}, {Name: " "}} {
+			spec := ProfileSpec{
+				Recommenders: []*vpaautoscaling.VerticalPodAutoscalerRecommenderSelector{{Name: "frugal"}, rec},
+			}
+			assert.EqualError(t, validateProfileSpec(&spec), "invalid profile: .recommenders[1].name must not be empty")
+		}
+	})
+
+	t.Run("Allows eviction requirements", func(t *testing.T) {
+		t.Parallel()
+		spec := ProfileSpec{
+			UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+				EvictionRequirements: []*vpaautoscaling.EvictionRequirement{
+					{
+						Resources:         []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+						ChangeRequirement: vpaautoscaling.TargetHigherThanRequests,
+					},
+					{
+						Resources:         []corev1.ResourceName{corev1.ResourceMemory},
+						ChangeRequirement: vpaautoscaling.TargetLowerThanRequests,
+					},
+				},
+			},
+		}
+		require.NoError(t, validateProfileSpec(&spec))
+	})
+
+	t.Run("Rejects invalid eviction requirements", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]struct {
+			req     *vpaautoscaling.EvictionRequirement
+			wantErr string
+		}{
+			"null": {
+				req:     nil,
+				wantErr: "invalid profile: .updatePolicy.evictionRequirements[0]: must not be null",
+			},
+			"no resources": {
+				req:     &vpaautoscaling.EvictionRequirement{ChangeRequirement: vpaautoscaling.TargetHigherThanRequests},
+				wantErr: "invalid profile: .updatePolicy.evictionRequirements[0]: .resources must not be empty",
+			},
+			"unsupported resource": {
+				req: &vpaautoscaling.EvictionRequirement{
+					Resources:         []corev1.ResourceName{corev1.ResourceEphemeralStorage},
+					ChangeRequirement: vpaautoscaling.TargetHigherThanRequests,
+				},
+				wantErr: `invalid profile: .updatePolicy.evictionRequirements[0]: .resources: unsupported resource "ephemeral-storage" (must be cpu or memory)`,
+			},
+			"unknown change requirement": {
+				req: &vpaautoscaling.EvictionRequirement{
+					Resources:         []corev1.ResourceName{corev1.ResourceCPU},
+					ChangeRequirement: "TargetEqualToRequests",
+				},
+				wantErr: `invalid profile: .updatePolicy.evictionRequirements[0]: .changeRequirement: unsupported value "TargetEqualToRequests" (must be TargetHigherThanRequests or TargetLowerThanRequests)`,
+			},
+		}
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				spec := ProfileSpec{
+					UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+						EvictionRequirements: []*vpaautoscaling.EvictionRequirement{tc.req},
+					},
+				}
+				assert.EqualError(t, validateProfileSpec(&spec), tc.wantErr)
+			})
+		}
+	})
 }
 
 func TestCopyProfileSpec(t *testing.T) {
@@ -19,41 +19,64 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
+	"github.com/containeroo/autovpa/internal/flag"
 	"github.com/containeroo/autovpa/internal/utils"
 )
 
 // Validate normalizes profiles, strips targetRef, and ensures defaults exist.
 // It also validates that the provided defaultTemplate and per-profile name templates are valid.
+// All profile errors are collected and returned together as *ValidationError
+// (see ValidationErrors); non-fatal findings are exposed via Warnings.
 func (c *Config) Validate(defaultTemplate string) error {
+	c.warnings = nil
+
 	if len(c.Profiles) == 0 {
-		return errors.New("profiles must be set")
+		return invalid("", "profiles", errors.New("profiles must be set"))
 	}
 	if c.DefaultProfile == "" {
-		return errors.New("defaultProfile must be set")
+		return invalid("", "defaultProfile", errors.New("defaultProfile must be set"))
 	}
 
 	// Example data used for validating name templates.
 	sampleNameData := utils.NameTemplateData{
-		WorkloadName: "workload",
-		Namespace:    "namespace",
-		Kind:         "Deployment",
-		Profile:      "default",
+		WorkloadName:     "workload",
+		Namespace:        "namespace",
+		Kind:             "Deployment",
+		Profile:          "default",
+		RequestedProfile: "default",
 	}
 
 	// Validate the default name template.
 	if _, err := utils.RenderNameTemplate(defaultTemplate, sampleNameData); err != nil {
-		return fmt.Errorf("default name template invalid: %w", err)
+		return invalid("", "nameTemplate", fmt.Errorf("default name template invalid: %w", err))
+	}
+	// The shipped default predates .Kind and is exempt: changing it would
+	// rename every existing VPA.
+	if defaultTemplate != flag.DefaultNameTemplate && ignoresKind(defaultTemplate, sampleNameData) {
+		finding := kindCollisionFinding(defaultTemplate)
+		if c.StrictNameTemplates {
+			return invalid("", "nameTemplate", fmt.Errorf("default name template invalid: %s", finding))
+		}
+		c.warnings = append(c.warnings, "default "+finding)
 	}
 
-	// Validate each profile.
+	// Validate each profile, collecting all problems so they are reported at once.
+	var errs []error
 	parsed := make(map[string]Profile, len(c.Profiles))
-	for name, spec := range c.Profiles {
+	renderedNames := make(map[string][]string, len(c.Profiles)) // sample VPA name -> profiles
+	for _, name := range slices.Sorted(maps.Keys(c.Profiles)) {
+		spec := c.Profiles[name]
 		copied := copyProfileSpec(spec.Spec)
+		valid := true
 
 		// Check if the profile is a valid VerticalPodAutoscaler spec.
 		if err := validateProfileSpec(&copied); err != nil {
-			return fmt.Errorf("profile %q invalid: %w", name, err)
+			errs = append(errs, invalid(name, "spec", fmt.Errorf("profile %q invalid: %w", name, err)))
+			valid = false
 		}
 
 		// Choose effective template: per-profile override or default.
@@ -61,21 +84,175 @@ func (c *Config) Validate(defaultTemplate string) error {
 
 		// Validate the effective name template with sample data.
 		if _, err := utils.RenderNameTemplate(effectiveTemplate, sampleNameData); err != nil {
-			return fmt.Errorf("profile %q name template invalid: %w", name, err)
+			errs = append(errs, invalid(name, "nameTemplate", fmt.Errorf("profile %q name template invalid: %w", name, err)))
+			valid = false
+		} else if spec.NameTemplate != "" && ignoresKind(spec.NameTemplate, sampleNameData) {
+			finding := kindCollisionFinding(spec.NameTemplate)
+			if c.StrictNameTemplates {
+				errs = append(errs, invalid(name, "nameTemplate", fmt.Errorf("profile %q name template invalid: %s", name, finding)))
+				valid = false
+			} else {
+				c.warnings = append(c.warnings, fmt.Sprintf("profile %q %s", name, finding))
+			}
+		}
+
+		// Validate the optional targetRef apiVersion override.
+		if spec.TargetAPIVersion != "" {
+			if err := validateAPIVersion(spec.TargetAPIVersion); err != nil {
+				errs = append(errs, invalid(name, "targetApiVersion", fmt.Errorf("profile %q targetApiVersion invalid: %w", name, err)))
+				valid = false
+			}
+		}
+
+		// Validate the containers excluded from the VPA.
+		if err := validateExcludeContainers(spec.ExcludeContainers); err != nil {
+			errs = append(errs, invalid(name, "excludeContainers", fmt.Errorf("profile %q excludeContainers invalid: %w", name, err)))
+			valid = false
+		}
+
+		if !valid {
+			continue
+		}
+
+		// Render with the actual profile name to detect collisions between the
+		// profiles workloads can actually select.
+		if spec.IsEnabled() {
+			profileNameData := sampleNameData
+			profileNameData.Profile = name
+			profileNameData.RequestedProfile = name
+			if rendered, err := utils.RenderNameTemplate(effectiveTemplate, profileNameData); err == nil {
+				renderedNames[rendered] = append(renderedNames[rendered], name)
+			}
 		}
 
 		// Store the normalized profile.
 		parsed[name] = Profile{
-			NameTemplate: spec.NameTemplate, // keep override as-is; default is applied at use-site
-			Spec:         copied,            // copied & targetRef-stripped
+			NameTemplate:      spec.NameTemplate, // keep override as-is; default is applied at use-site
+			TargetAPIVersion:  spec.TargetAPIVersion,
+			Enabled:           spec.Enabled,
+			ExcludeContainers: spec.ExcludeContainers,
+			Spec:              copied, // copied & targetRef-stripped
 		}
 	}
 
 	// Check if default profile exists.
-	if _, ok := parsed[c.DefaultProfile]; !ok {
-		return fmt.Errorf("defaultProfile %q not found in profiles", c.DefaultProfile)
+	if profile, ok := c.Profiles[c.DefaultProfile]; !ok {
+		errs = append(errs, invalid(c.DefaultProfile, "defaultProfile", fmt.Errorf("defaultProfile %q not found in profiles", c.DefaultProfile)))
+	} else if !profile.IsEnabled() {
+		errs = append(errs, invalid(c.DefaultProfile, "defaultProfile", fmt.Errorf("defaultProfile %q is disabled", c.DefaultProfile)))
+	}
+
+	// Check if the kind defaults exist.
+	for _, kind := range slices.Sorted(maps.Keys(c.KindDefaults)) {
+		name := c.KindDefaults[kind]
+		switch profile, ok := c.Profiles[name]; {
+		case kind == "":
+			errs = append(errs, invalid(name, "kindDefaults", errors.New("kindDefaults must not contain an empty kind")))
+		case !ok:
+			errs = append(errs, invalid(name, "kindDefaults", fmt.Errorf("kindDefaults[%q]: profile %q not found in profiles", kind, name)))
+		case !profile.IsEnabled():
+			errs = append(errs, invalid(name, "kindDefaults", fmt.Errorf("kindDefaults[%q]: profile %q is disabled", kind, name)))
+		}
+	}
+
+	// Check if the kind restrictions name existing profiles and real kinds.
+	for _, name := range slices.Sorted(maps.Keys(c.ProfileKindRestrictions)) {
+		kinds := c.ProfileKindRestrictions[name]
+		switch _, ok := c.Profiles[name]; {
+		case !ok:
+			errs = append(errs, invalid(name, "profileKindRestrictions", fmt.Errorf("profileKindRestrictions[%q]: profile not found in profiles", name)))
+		case len(kinds) == 0:
+			errs = append(errs, invalid(name, "profileKindRestrictions", fmt.Errorf("profileKindRestrictions[%q]: at least one kind must be set", name)))
+		case slices.Contains(kinds, ""):
+			errs = append(errs, invalid(name, "profileKindRestrictions", fmt.Errorf("profileKindRestrictions[%q]: kinds must not be empty", name)))
+		default:
+			if kind, unknown := c.unknownKind(kinds); unknown {
+				errs = append(errs, invalid(name, "profileKindRestrictions", fmt.Errorf("profileKindRestrictions[%q]: unknown kind %q", name, kind)))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
 	}
 
 	c.Profiles = parsed
+	c.warnings = append(c.warnings, nameCollisionWarnings(renderedNames)...)
 	return nil
 }
+
+// builtinWorkloadKinds are the workload kinds with a built-in controller.
+var builtinWorkloadKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"}
+
+// unknownKind returns the first of kinds that is neither a built-in workload
+// kind nor one of AdditionalKinds.
+func (c *Config) unknownKind(kinds []string) (string, bool) {
+	for _, kind := range kinds {
+		if !slices.Contains(builtinWorkloadKinds, kind) && !slices.Contains(c.AdditionalKinds, kind) {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// Warnings returns the non-fatal findings collected by the last Validate call.
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// ContainerPolicyCounts returns the number of container policies per profile.
+func (c *Config) ContainerPolicyCounts() map[string]int {
+	counts := make(map[string]int, len(c.Profiles))
+	for name, profile := range c.Profiles {
+		if profile.Spec.ResourcePolicy != nil {
+			counts[name] = len(profile.Spec.ResourcePolicy.ContainerPolicies)
+			continue
+		}
+		counts[name] = 0
+	}
+	return counts
+}
+
+// ignoresKind reports whether a template renders the same name regardless of
+// the workload kind. VPAs are namespaced, so only .Kind keeps a Deployment and
+// a StatefulSet with the same name and profile from sharing a VPA.
+func ignoresKind(tmpl string, data utils.NameTemplateData) bool {
+	base, err := utils.RenderNameTemplate(tmpl, data)
+	if err != nil {
+		return false
+	}
+
+	otherKind := data
+	otherKind.Kind = "StatefulSet"
+	rendered, err := utils.RenderNameTemplate(tmpl, otherKind)
+	return err == nil && rendered == base
+}
+
+// kindCollisionFinding describes a template that ignores .Kind.
+func kindCollisionFinding(tmpl string) string {
+	return fmt.Sprintf(
+		"name template %q does not reference .Kind; workloads of different kinds with the same name and profile render the same VPA name",
+		tmpl,
+	)
+}
+
+// nameCollisionWarnings reports valid, enabled profiles whose effective
+// templates render the same VPA name for the same workload. Switching a
+// workload between such profiles keeps the VPA name, which usually means the
+// template ignores .Profile.
+func nameCollisionWarnings(renderedNames map[string][]string) []string {
+	var warnings []string
+	for rendered, profiles := range renderedNames {
+		if len(profiles) < 2 {
+			continue
+		}
+		slices.Sort(profiles)
+		warnings = append(warnings, fmt.Sprintf(
+			"profiles %s render the same VPA name %q for the same workload; consider using .Profile in the name template",
+			strings.Join(profiles, ", "),
+			rendered,
+		))
+	}
+	slices.Sort(warnings)
+	return warnings
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/containeroo/autovpa/internal/flag"
@@ -25,6 +26,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/utils/ptr"
 )
 
 func TestConfigValidate(t *testing.T) {
@@ -102,7 +105,7 @@ func TestConfigValidate(t *testing.T) {
 		}
 		err := cfg.Validate("{{ .Invalid }}")
 		require.Error(t, err)
-		assert.EqualError(t, err, "default name template invalid: render template: template: name:1:3: executing \"name\" at <.Invalid>: can't evaluate field Invalid in type utils.NameTemplateData")
+		assert.EqualError(t, err, "default name template invalid: render template: template: name:1:3: executing \"name\" at <.Invalid>: can't evaluate field Invalid in type nametemplate.Data")
 	})
 
 	t.Run("validateProfileSpec errors on targetRef", func(t *testing.T) {
@@ -123,6 +126,436 @@ func TestConfigValidate(t *testing.T) {
 		require.Error(t, err)
 		assert.EqualError(t, err, "profile \"p1\" invalid: invalid profile: .targetRef must not be set")
 	})
+
+	t.Run("Accepts kind defaults", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1":  {},
+				"off": {},
+			},
+			KindDefaults: map[string]string{"DaemonSet": "off"},
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+		assert.Equal(t, map[string]string{"DaemonSet": "off"}, cfg.KindDefaults)
+	})
+
+	t.Run("Rejects kind defaults referencing unknown profiles", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1":     {},
+				"paused": {Enabled: ptr.To(false)},
+			},
+			KindDefaults: map[string]string{
+				"DaemonSet":   "missing",
+				"StatefulSet": "paused",
+				"":            "p1",
+			},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.EqualError(t, err, strings.Join([]string{
+			"kindDefaults must not contain an empty kind",
+			`kindDefaults["DaemonSet"]: profile "missing" not found in profiles`,
+			`kindDefaults["StatefulSet"]: profile "paused" is disabled`,
+		}, "\n"))
+	})
+
+	t.Run("Accepts profile kind restrictions", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1":  {},
+				"off": {},
+			},
+			ProfileKindRestrictions: map[string][]string{"p1": {"Deployment", "StatefulSet"}},
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+		assert.Equal(t, map[string][]string{"p1": {"Deployment", "StatefulSet"}}, cfg.ProfileKindRestrictions)
+	})
+
+	t.Run("Rejects invalid profile kind restrictions", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1":  {},
+				"p2":  {},
+				"off": {},
+			},
+			ProfileKindRestrictions: map[string][]string{
+				"missing": {"DaemonSet"},
+				"off":     {},
+				"p1":      {"Deployment", ""},
+				"p2":      {"Deploymnet"},
+			},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.EqualError(t, err, strings.Join([]string{
+			`profileKindRestrictions["missing"]: profile not found in profiles`,
+			`profileKindRestrictions["off"]: at least one kind must be set`,
+			`profileKindRestrictions["p1"]: kinds must not be empty`,
+			`profileKindRestrictions["p2"]: unknown kind "Deploymnet"`,
+		}, "\n"))
+	})
+
+	t.Run("Accepts additional kinds in profile kind restrictions", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile:          "p1",
+			Profiles:                map[string]Profile{"p1": {}},
+			ProfileKindRestrictions: map[string][]string{"p1": {"Deployment", "Rollout"}},
+			AdditionalKinds:         []string{"Rollout"},
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+	})
+
+	t.Run("Keeps disabled profiles", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {},
+				"p2": {Enabled: ptr.To(false)},
+			},
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+		require.Contains(t, cfg.Profiles, "p2")
+		assert.False(t, cfg.Profiles["p2"].IsEnabled())
+		assert.True(t, cfg.Profiles["p1"].IsEnabled())
+	})
+
+	t.Run("Rejects disabled default profile", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Enabled: ptr.To(false)},
+			},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.EqualError(t, err, "defaultProfile \"p1\" is disabled")
+	})
+
+	t.Run("Accepts valid targetApiVersion", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {TargetAPIVersion: "argoproj.io/v1alpha1"},
+			},
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+		assert.Equal(t, "argoproj.io/v1alpha1", cfg.Profiles["p1"].TargetAPIVersion)
+	})
+
+	t.Run("Rejects unparseable targetApiVersion", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {TargetAPIVersion: "a/b/c"},
+			},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "profile \"p1\" targetApiVersion invalid")
+	})
+
+	t.Run("Rejects targetApiVersion without version", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {TargetAPIVersion: "apps/"},
+			},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+		assert.EqualError(t, err, "profile \"p1\" targetApiVersion invalid: version must not be empty")
+	})
+
+	t.Run("Accepts excludeContainers", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {ExcludeContainers: []string{"istio-proxy"}},
+			},
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+		assert.Equal(t, []string{"istio-proxy"}, cfg.Profiles["p1"].ExcludeContainers)
+	})
+
+	t.Run("Rejects invalid excludeContainers", func(t *testing.T) {
+		t.Parallel()
+		for wantErr, containers := range map[string][]string{
+			`profile "p1" excludeContainers invalid: [1] must not be empty`:                  {"istio-proxy", " "},
+			`profile "p1" excludeContainers invalid: [0] must not be "*"`:                    {"*"},
+			`profile "p1" excludeContainers invalid: [0] must not be "__all__"`:              {"__all__"},
+			`profile "p1" excludeContainers invalid: [1] duplicates container "istio-proxy"`: {"istio-proxy", "istio-proxy"},
+		} {
+			cfg := &Config{
+				DefaultProfile: "p1",
+				Profiles:       map[string]Profile{"p1": {ExcludeContainers: containers}},
+			}
+			err := cfg.Validate(flag.DefaultNameTemplate)
+			require.Error(t, err)
+			assert.EqualError(t, err, wantErr)
+		}
+	})
+
+	t.Run("Accepts an __all__ container policy", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{"p1": {Spec: ProfileSpec{
+				ResourcePolicy: &vpaautoscaling.PodResourcePolicy{ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{
+					{ContainerName: AllContainers},
+					{ContainerName: "app"},
+				}},
+			}}},
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+	})
+
+	t.Run("Rejects invalid container policies", func(t *testing.T) {
+		t.Parallel()
+		for wantErr, containers := range map[string][]string{
+			`profile "p1" invalid: invalid profile: .resourcePolicy.containerPolicies[1] duplicates container "app"`:             {"app", "app"},
+			`profile "p1" invalid: invalid profile: .resourcePolicy.containerPolicies[1] "__all__" and "*" must not be combined`: {"*", "__all__"},
+			`profile "p1" invalid: invalid profile: .resourcePolicy.containerPolicies[2] "__all__" and "*" must not be combined`: {"__all__", "app", "*"},
+		} {
+			policies := make([]vpaautoscaling.ContainerResourcePolicy, 0, len(containers))
+			for _, name := range containers {
+				policies = append(policies, vpaautoscaling.ContainerResourcePolicy{ContainerName: name})
+			}
+			cfg := &Config{
+				DefaultProfile: "p1",
+				Profiles: map[string]Profile{"p1": {Spec: ProfileSpec{
+					ResourcePolicy: &vpaautoscaling.PodResourcePolicy{ContainerPolicies: policies},
+				}}},
+			}
+			err := cfg.Validate(flag.DefaultNameTemplate)
+			require.Error(t, err)
+			assert.EqualError(t, err, wantErr)
+		}
+	})
+
+	t.Run("Reports errors of all profiles together", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "missing",
+			Profiles: map[string]Profile{
+				"bad-template": {NameTemplate: "UPPER"},
+				"bad-version":  {TargetAPIVersion: "apps/"},
+				"good":         {Spec: ProfileSpec{}},
+			},
+		}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+
+		msg := err.Error()
+		assert.Contains(t, msg, "profile \"bad-template\" name template invalid")
+		assert.Contains(t, msg, "profile \"bad-version\" targetApiVersion invalid: version must not be empty")
+		assert.Contains(t, msg, "defaultProfile \"missing\" not found in profiles")
+		assert.NotContains(t, msg, "\"good\"")
+	})
+
+	t.Run("Reports several errors of one profile", func(t *testing.T) {
+		t.Parallel()
+		original := map[string]Profile{
+			"p1": {NameTemplate: "UPPER", TargetAPIVersion: "a/b/c"},
+		}
+		cfg := &Config{DefaultProfile: "p1", Profiles: original}
+		err := cfg.Validate(flag.DefaultNameTemplate)
+		require.Error(t, err)
+
+		lines := strings.Split(err.Error(), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], "profile \"p1\" name template invalid")
+		assert.Contains(t, lines[1], "profile \"p1\" targetApiVersion invalid")
+		assert.Equal(t, original, cfg.Profiles, "profiles must be left untouched on error")
+	})
+}
+
+func TestConfigValidateWarnings(t *testing.T) {
+	t.Parallel()
+
+	const kindTemplate = "{{ .Kind | toLower }}-{{ .WorkloadName }}-{{ .Profile }}"
+
+	t.Run("Warns when profiles render identical names", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Spec: ProfileSpec{}},
+				"p2": {Spec: ProfileSpec{}},
+				"p3": {Spec: ProfileSpec{}, NameTemplate: "{{ .Kind | toLower }}-{{ .WorkloadName }}-{{ .Profile }}"},
+			},
+		}
+		require.NoError(t, cfg.Validate("{{ .Kind | toLower }}-{{ .WorkloadName }}-vpa"))
+		assert.Equal(t, []string{
+			"profiles p1, p2 render the same VPA name \"deployment-workload-vpa\" for the same workload; consider using .Profile in the name template",
+		}, cfg.Warnings())
+	})
+
+	t.Run("No warnings when names differ by requested profile", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Spec: ProfileSpec{}},
+				"p2": {Spec: ProfileSpec{}},
+			},
+		}
+		require.NoError(t, cfg.Validate("{{ .Kind | toLower }}-{{ .WorkloadName }}-{{ .RequestedProfile }}"))
+		assert.Empty(t, cfg.Warnings())
+	})
+
+	t.Run("Ignores disabled profiles for name collisions", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Spec: ProfileSpec{}},
+				"p2": {Spec: ProfileSpec{}, Enabled: ptr.To(false)},
+			},
+		}
+		require.NoError(t, cfg.Validate("{{ .Kind | toLower }}-{{ .WorkloadName }}-vpa"))
+		assert.Empty(t, cfg.Warnings())
+	})
+
+	t.Run("No warnings when names differ by profile", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Spec: ProfileSpec{}},
+				"p2": {Spec: ProfileSpec{}},
+			},
+		}
+		require.NoError(t, cfg.Validate(kindTemplate))
+		assert.Empty(t, cfg.Warnings())
+	})
+
+	t.Run("Resets warnings on revalidation", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Spec: ProfileSpec{}},
+				"p2": {Spec: ProfileSpec{}},
+			},
+		}
+		require.NoError(t, cfg.Validate("{{ .Kind | toLower }}-{{ .WorkloadName }}"))
+		require.Len(t, cfg.Warnings(), 1)
+
+		require.NoError(t, cfg.Validate(kindTemplate))
+		assert.Empty(t, cfg.Warnings())
+	})
+
+	t.Run("Warns when the default template ignores .Kind", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles:       map[string]Profile{"p1": {Spec: ProfileSpec{}}},
+		}
+		require.NoError(t, cfg.Validate("{{ .Namespace }}-{{ .WorkloadName }}-{{ .Profile }}"))
+		assert.Equal(t, []string{
+			"default name template \"{{ .Namespace }}-{{ .WorkloadName }}-{{ .Profile }}\" does not reference .Kind; workloads of different kinds with the same name and profile render the same VPA name",
+		}, cfg.Warnings())
+	})
+
+	t.Run("Exempts the shipped default template", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile:      "p1",
+			Profiles:            map[string]Profile{"p1": {Spec: ProfileSpec{}}},
+			StrictNameTemplates: true,
+		}
+		require.NoError(t, cfg.Validate(flag.DefaultNameTemplate))
+		assert.Empty(t, cfg.Warnings())
+	})
+
+	t.Run("Warns when a profile template ignores .Kind", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Spec: ProfileSpec{}},
+				"p2": {Spec: ProfileSpec{}, NameTemplate: "{{ .WorkloadName }}-{{ .Profile }}"},
+			},
+		}
+		require.NoError(t, cfg.Validate(kindTemplate))
+		require.Len(t, cfg.Warnings(), 1)
+		assert.Contains(t, cfg.Warnings()[0], "profile \"p2\" name template \"{{ .WorkloadName }}-{{ .Profile }}\" does not reference .Kind")
+	})
+
+	t.Run("Strict mode rejects the default template without .Kind", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile:      "p1",
+			Profiles:            map[string]Profile{"p1": {Spec: ProfileSpec{}}},
+			StrictNameTemplates: true,
+		}
+		err := cfg.Validate("{{ .Namespace }}-{{ .WorkloadName }}-{{ .Profile }}")
+		require.Error(t, err)
+		assert.EqualError(t, err, "default name template invalid: name template \"{{ .Namespace }}-{{ .WorkloadName }}-{{ .Profile }}\" does not reference .Kind; workloads of different kinds with the same name and profile render the same VPA name")
+	})
+
+	t.Run("Strict mode rejects profile templates without .Kind", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile: "p1",
+			Profiles: map[string]Profile{
+				"p1": {Spec: ProfileSpec{}},
+				"p2": {Spec: ProfileSpec{}, NameTemplate: "{{ .WorkloadName }}"},
+			},
+			StrictNameTemplates: true,
+		}
+		err := cfg.Validate(kindTemplate)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "profile \"p2\" name template invalid: name template \"{{ .WorkloadName }}\" does not reference .Kind")
+	})
+
+	t.Run("Strict mode accepts templates with .Kind", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			DefaultProfile:      "p1",
+			Profiles:            map[string]Profile{"p1": {Spec: ProfileSpec{}}},
+			StrictNameTemplates: true,
+		}
+		require.NoError(t, cfg.Validate(kindTemplate))
+		assert.Empty(t, cfg.Warnings())
+	})
+}
+
+func TestConfigContainerPolicyCounts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Counts container policies per profile", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{
+			Profiles: map[string]Profile{
+				"multi": {Spec: ProfileSpec{ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+					ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{
+						{ContainerName: "app"},
+						{ContainerName: "sidecar"},
+						{ContainerName: "*"},
+					},
+				}}},
+				"empty": {Spec: ProfileSpec{}},
+			},
+		}
+		assert.Equal(t, map[string]int{"multi": 3, "empty": 0}, cfg.ContainerPolicyCounts())
+	})
 }
 
 func TestRenderNameTemplateValidation(t *testing.T) {
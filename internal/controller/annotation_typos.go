@@ -0,0 +1,65 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	"github.com/containeroo/autovpa/internal/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// profileAnnotationTypoDistance is the largest edit distance at which an
+// annotation key is reported as a likely misspelling of a profile key.
+const profileAnnotationTypoDistance = 2
+
+// warnProfileAnnotationTypos records a Warning event and counts workloads
+// carrying annotation keys that closely resemble, but do not match, a profile
+// annotation key. It only reports; the reconcile outcome is unchanged.
+// Nothing is checked unless Meta.WarnAnnotationTypos is set.
+func (b *BaseReconciler) warnProfileAnnotationTypos(log logr.Logger, obj client.Object, kind string) {
+	if !b.Meta.WarnAnnotationTypos {
+		return
+	}
+
+	keys := b.Meta.profileKeys()
+	typos := utils.NearMisses(obj.GetAnnotations(), keys, profileAnnotationTypoDistance)
+	if len(typos) == 0 {
+		return
+	}
+
+	log.Info(
+		"annotation resembles the profile annotation",
+		"annotations", typos,
+		"expected", strings.Join(keys, ","),
+	)
+
+	b.Recorder.Eventf(
+		obj,
+		nil,
+		corev1.EventTypeWarning,
+		vpaEventPossibleProfileAnnotationTypo,
+		vpaActionCheckAnnotations,
+		"Annotation %q looks like a misspelling of %q",
+		strings.Join(typos, ","),
+		strings.Join(keys, ","),
+	)
+
+	b.Metrics.IncProfileAnnotationTypo(obj.GetNamespace(), kind)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBaseReconciler_warnProfileAnnotationTypos(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, warn bool, dep *appsv1.Deployment) (*BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		rec := events.NewFakeRecorder(10)
+		promReg := prometheus.NewRegistry()
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build(),
+			Logger:     &logger,
+			Recorder:   rec,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:          "vpa/profile",
+				ManagedLabel:        "vpa/managed",
+				WarnAnnotationTypos: warn,
+			},
+			Profiles: ProfileConfig{
+				Default:      "p1",
+				Entries:      map[string]config.Profile{"p1": {}},
+				NameTemplate: "{{ .WorkloadName }}-{{ .Profile }}-vpa",
+			},
+		}, rec, promReg
+	}
+	newDeployment := func(annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			Annotations: annotations,
+		}}
+	}
+	typoCount := func(t *testing.T, promReg *prometheus.Registry) float64 {
+		t.Helper()
+		return mustGetCounterValue(t, promReg, "autovpa_profile_annotation_typos_total", map[string]string{
+			"namespace": "ns1",
+			"kind":      DeploymentGVK.Kind,
+		})
+	}
+
+	t.Run("Warns about a misspelled annotation", func(t *testing.T) {
+		t.Parallel()
+		dep := newDeployment(map[string]string{"vpa/profle": "p1"})
+		r, rec, promReg := newReconciler(t, true, dep)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, rec.Events)
+		assert.Equal(t, `Warning PossibleProfileAnnotationTypo Annotation "vpa/profle" looks like a misspelling of "vpa/profile"`, <-rec.Events)
+		assert.Equal(t, float64(1), typoCount(t, promReg))
+
+		// The outcome is unchanged: the workload is still not opted in.
+		require.NotEmpty(t, rec.Events)
+		assert.Contains(t, <-rec.Events, vpaEventProfileAnnotationMissing)
+	})
+
+	t.Run("Does not change the outcome for opted-in workloads", func(t *testing.T) {
+		t.Parallel()
+		dep := newDeployment(map[string]string{"vpa/profile": "p1", "vpa/profiles": "p1"})
+		r, rec, promReg := newReconciler(t, true, dep)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Contains(t, <-rec.Events, vpaEventPossibleProfileAnnotationTypo)
+		assert.Equal(t, float64(1), typoCount(t, promReg))
+		require.NoError(t, r.KubeClient.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "demo-p1-vpa"}, newVPAObject()))
+	})
+
+	t.Run("Ignores exact and distant keys", func(t *testing.T) {
+		t.Parallel()
+		dep := newDeployment(map[string]string{"vpa/profile": "p1", "app/name": "demo"})
+		r, rec, promReg := newReconciler(t, true, dep)
+
+		r.warnProfileAnnotationTypos(logr.Discard(), dep, DeploymentGVK.Kind)
+
+		assert.Empty(t, rec.Events)
+		count, err := testutil.GatherAndCount(promReg, "autovpa_profile_annotation_typos_total")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Does nothing when disabled", func(t *testing.T) {
+		t.Parallel()
+		dep := newDeployment(map[string]string{"vpa/profle": "p1"})
+		r, rec, promReg := newReconciler(t, false, dep)
+
+		r.warnProfileAnnotationTypos(logr.Discard(), dep, DeploymentGVK.Kind)
+
+		assert.Empty(t, rec.Events)
+		count, err := testutil.GatherAndCount(promReg, "autovpa_profile_annotation_typos_total")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
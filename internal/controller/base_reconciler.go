@@ -18,31 +18,41 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/containeroo/autovpa/internal/config"
 	"github.com/containeroo/autovpa/internal/metrics"
 	"github.com/containeroo/autovpa/internal/utils"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // desiredVPAState is the fully rendered desired state for a workload's VPA.
 type desiredVPAState struct {
-	Name    string            // VPA name rendered from the name template.
-	Profile string            // Selected profile for the workload.
-	Labels  map[string]string // Final labels (managed/profile markers and any additional metadata).
-	Spec    map[string]any    // The VPA "spec" rendered from the selected profile.
+	Name        string            // VPA name rendered from the name template.
+	Profile     string            // Selected profile for the workload.
+	Labels      map[string]string // Final labels (managed/profile markers and any additional metadata).
+	Annotations map[string]string // Tracking annotations propagated from the workload.
+	Spec        map[string]any    // The VPA "spec" rendered from the selected profile.
 }
 
 // BaseReconciler contains the shared logic for Deployment/StatefulSet/DaemonSet reconcilers.
@@ -53,32 +63,140 @@ type BaseReconciler struct {
 	Metrics    *metrics.Registry
 	Meta       MetaConfig
 	Profiles   ProfileConfig
+
+	// ProfileStore, when set, supplies the profile settings instead of
+	// Profiles, so reloaded profiles apply from the next reconcile on.
+	ProfileStore *ProfileStore
+
+	// DisableBlockOwnerDeletion sets blockOwnerDeletion=false on VPA ownerRefs,
+	// for clusters where RBAC does not grant access to the owner's finalizers.
+	DisableBlockOwnerDeletion bool
+
+	// CreateOnly creates missing VPAs but never updates existing ones;
+	// obsolete and opt-out deletions still happen.
+	CreateOnly bool
+
+	// RespectLimitRanges clamps container policy bounds to the namespace's
+	// Container-type LimitRanges.
+	RespectLimitRanges bool
+
+	// RespectResourceQuota clamps container policy bounds to the quota left
+	// in the namespace's ResourceQuotas.
+	RespectResourceQuota bool
+
+	// SkipIfHPA skips creating a VPA when an HPA scales the same workload
+	// on CPU or memory, since both autoscalers would fight over the pods.
+	SkipIfHPA bool
+
+	// SkipIfWithinBounds skips creating or updating a VPA while the
+	// workload's container requests already lie within the profile's bounds.
+	SkipIfWithinBounds bool
+
+	// MaxVPAsPerNamespace refuses to create VPAs in a namespace that already
+	// holds this many managed VPAs (0 means unlimited).
+	MaxVPAsPerNamespace int
+
+	// UseFinalizers adds ManagedFinalizer to managed VPAs; the VPAReconciler
+	// then decrements the managed gauge and removes it on deletion.
+	UseFinalizers bool
+
+	// FieldManager is the server-side apply field manager for VPAs; empty
+	// uses defaultFieldManager.
+	FieldManager string
+
+	// Circuit pauses VPA applies after consecutive apply failures; nil
+	// never pauses.
+	Circuit *ApplyCircuitBreaker
+
+	// Locks serializes concurrent reconciles of the same workload; nil
+	// never serializes.
+	Locks *WorkloadLocks
+
+	// MaxConcurrentReconciles is the number of workers of the controller
+	// embedding this reconciler; 0 keeps the controller-runtime default of 1.
+	MaxConcurrentReconciles int
+
+	// Tracer records a span per reconcile; nil uses a no-op tracer.
+	Tracer trace.Tracer
+
+	// CheckNamespacePhase reads the workload's Namespace to skip workloads in
+	// terminating namespaces up front; it needs get, list and watch on
+	// namespaces. Without it, such workloads are skipped once an apply is
+	// rejected.
+	CheckNamespacePhase bool
+
+	// ObsoleteAction is ObsoleteActionDelete or ObsoleteActionRelease; empty
+	// deletes obsolete VPAs.
+	ObsoleteAction string
+
+	// DisableObsoleteCleanup only logs obsolete VPAs instead of applying
+	// ObsoleteAction, so a workload may end up with several VPAs.
+	DisableObsoleteCleanup bool
+
+	// WriteErrorAnnotations records the latest reconcile error in the
+	// workload's LastErrorAnnotation and removes it once a reconcile succeeds.
+	WriteErrorAnnotations bool
 }
 
-const fieldManager = "autovpa"
+const defaultFieldManager = "autovpa"
+
+// managedFieldsRetryDelay is the base delay before retrying an apply that
+// failed on managedFields; up to the same amount of jitter is added.
+var managedFieldsRetryDelay = 200 * time.Millisecond
+
+// namespaceRetryDelay is the requeue delay for workloads without a profile
+// annotation whose namespace default could not be read.
+const namespaceRetryDelay = time.Minute
 
 // Event reasons.
 const (
-	vpaEventProfileAnnotationMissing = "ProfileAnnotationMissing"
-	vpaEventProfileNotFound          = "ProfileNotFound"
-	vpaEventDeletedManagedVPA        = "DeletedManagedVPA"
-	vpaEventDeletedObsoleteVPA       = "DeletedObsoleteVPA"
-	vpaEventVPACreated               = "VPACreated"
-	vpaEventVPAUpdated               = "VPAUpdated"
+	vpaEventProfileAnnotationMissing  = "ProfileAnnotationMissing"
+	vpaEventProfileNotFound           = "ProfileNotFound"
+	vpaEventDeletedManagedVPA         = "DeletedManagedVPA"
+	vpaEventDeletedObsoleteVPA        = "DeletedObsoleteVPA"
+	vpaEventVPACreated                = "VPACreated"
+	vpaEventVPAUpdated                = "VPAUpdated"
+	vpaEventVPANameConflict           = "VPANameConflict"
+	vpaEventProfileChanged            = "ProfileChanged"
+	vpaEventHPAConflict               = "HPAConflict"
+	vpaEventVPARotated                = "VPARotated"
+	vpaEventInvalidResourceAnnotation = "InvalidResourceAnnotation"
+	vpaEventProfileDisabled           = "ProfileDisabled"
+	vpaEventProfileKindNotAllowed     = "ProfileKindNotAllowed"
+	vpaEventVPACapExceeded            = "VPACapExceeded"
+	vpaEventReleasedObsoleteVPA       = "ReleasedObsoleteVPA"
+	vpaEventInvalidInlineOverride     = "InvalidInlineOverride"
+
+	vpaEventPossibleProfileAnnotationTypo = "PossibleProfileAnnotationTypo"
 )
 
 // Event actions.
 const (
-	vpaActionSkipVPA   = "SkipVPA"
-	vpaActionCreateVPA = "CreateVPA"
-	vpaActionUpdateVPA = "UpdateVPA"
-	vpaActionDeleteVPA = "DeleteVPA"
+	vpaActionSkipVPA       = "SkipVPA"
+	vpaActionCreateVPA     = "CreateVPA"
+	vpaActionUpdateVPA     = "UpdateVPA"
+	vpaActionDeleteVPA     = "DeleteVPA"
+	vpaActionSwitchProfile = "SwitchProfile"
+	vpaActionRotateVPA     = "RotateVPA"
+	vpaActionReleaseVPA    = "ReleaseVPA"
+
+	vpaActionCheckAnnotations = "CheckAnnotations"
 )
 
 // Metric labels.
 const (
-	vpaSkipReasonAnnotationMissing = "annotation_missing"
-	vpaSkipReasonProfileMissing    = "profile_missing"
+	vpaSkipReasonAnnotationMissing         = "annotation_missing"
+	vpaSkipReasonProfileMissing            = "profile_missing"
+	vpaSkipReasonNameConflict              = "name_conflict"
+	vpaSkipReasonUpdateDisabled            = "update_disabled"
+	vpaSkipReasonHPAConflict               = "hpa_conflict"
+	vpaSkipReasonInvalidResourceAnnotation = "invalid_resource_annotation"
+	vpaSkipReasonNamespaceTerminating      = "namespace_terminating"
+	vpaSkipReasonProfileDisabled           = "profile_disabled"
+	vpaSkipReasonWithinBounds              = "within_bounds"
+	vpaSkipReasonProfileKindNotAllowed     = "profile_kind_not_allowed"
+	vpaSkipReasonVPACapExceeded            = "vpa_cap_exceeded"
+	vpaSkipReasonInvalidInlineOverride     = "invalid_inline_override"
 )
 
 // ReconcileWorkload executes the full VPA lifecycle state machine for a workload.
@@ -86,11 +204,19 @@ const (
 // Algorithm overview:
 //  1. Determine whether the workload opts into VPA management (profile annotation).
 //  2. If not opted-in → delete all managed VPAs for this workload.
-//  3. Resolve the profile to use.
-//  4. Render the desired VPA name, labels, and spec.
-//  5. Delete obsolete VPAs (e.g. profile/name-template change).
-//  6. Create the desired VPA if missing.
-//  7. If it exists, merge and apply changes via server-side apply.
+//  3. Skip workloads in a terminating namespace.
+//  4. Resolve the profile(s) to use; the annotation may list several, comma-separated.
+//  5. Render the desired VPA name, labels, and spec for each profile.
+//  6. Delete obsolete VPAs (e.g. profile/name-template change).
+//  7. Create each desired VPA if missing, or recreate it when a rotation was requested.
+//  8. If it exists, merge and apply changes via server-side apply (skipped with CreateOnly).
+//
+// While the apply circuit is open, steps 7 and 8 are skipped and the workload is
+// requeued once the circuit lets applies through again.
+//
+// Every reconcile ends with one "reconcile finished" log line whose outcome is
+// also counted in autovpa_reconcile_outcomes_total; see reportOutcome. With
+// WriteErrorAnnotations the error, if any, is then written onto the workload.
 //
 // This function NEVER requeues on configuration errors (e.g. profile missing) to
 // avoid thrashing. It only returns a non-nil error when an API call fails.
@@ -98,6 +224,24 @@ func (b *BaseReconciler) ReconcileWorkload(
 	ctx context.Context,
 	obj client.Object,
 	targetGVK schema.GroupVersionKind,
+) (ctrl.Result, error) {
+	b = b.withCurrentProfiles()
+	ctx, span := startReconcileSpan(ctx, b.Tracer, "ReconcileWorkload", obj.GetNamespace(), targetGVK.Kind)
+	ctx, outcome := withReconcileOutcome(ctx)
+	result, err := b.reconcileWorkload(ctx, obj, targetGVK)
+	endReconcileSpan(span, result, err)
+	b.reportOutcome(obj, targetGVK, outcome.result(err), err)
+	if b.WriteErrorAnnotations {
+		b.syncErrorAnnotation(ctx, obj, err)
+	}
+	return result, err
+}
+
+// reconcileWorkload implements ReconcileWorkload within its span.
+func (b *BaseReconciler) reconcileWorkload(
+	ctx context.Context,
+	obj client.Object,
+	targetGVK schema.GroupVersionKind,
 ) (ctrl.Result, error) {
 	name, ns := obj.GetName(), obj.GetNamespace()
 	log := b.Logger.WithValues(
@@ -107,13 +251,23 @@ func (b *BaseReconciler) ReconcileWorkload(
 		"controller", targetGVK.Kind,
 	)
 
-	// Check profile annotation (opt-in).
-	annotations := obj.GetAnnotations()
-	profileName, hasProfile := annotations[b.Meta.ProfileKey]
-	if !hasProfile || profileName == "" {
+	unlock := b.Locks.lock(targetGVK.Kind, types.NamespacedName{Namespace: ns, Name: name})
+	defer unlock()
+
+	b.warnProfileAnnotationTypos(log, obj, targetGVK.Kind)
+
+	// Check profile annotation (opt-in), falling back to the namespace default.
+	profileNames, namespaceUnreadable := b.resolveProfileNames(ctx, obj, targetGVK.Kind)
+	setSpanProfile(ctx, strings.Join(profileNames, ","))
+	if namespaceUnreadable {
+		// The namespace default may still apply: keep existing VPAs instead
+		// of treating the workload as opted out, and look again later.
+		return ctrl.Result{RequeueAfter: namespaceRetryDelay}, nil
+	}
+	if len(profileNames) == 0 {
 		log.Info(
 			"profile annotation missing; skipping VPA reconciliation",
-			"annotation", b.Meta.ProfileKey,
+			"annotation", strings.Join(b.Meta.profileKeys(), ","),
 		)
 
 		b.Recorder.Eventf(
@@ -123,7 +277,7 @@ func (b *BaseReconciler) ReconcileWorkload(
 			vpaEventProfileAnnotationMissing,
 			vpaActionSkipVPA,
 			"Annotation %q missing; skipping VPA",
-			b.Meta.ProfileKey,
+			strings.Join(b.Meta.profileKeys(), ","),
 		)
 
 		b.Metrics.IncVPASkipped(
@@ -132,6 +286,7 @@ func (b *BaseReconciler) ReconcileWorkload(
 			targetGVK.Kind,
 			vpaSkipReasonAnnotationMissing,
 		)
+		recordOutcome(ctx, skippedOutcome(vpaSkipReasonAnnotationMissing))
 
 		// User opted out → delete all operator-managed VPAs for this workload.
 		if err := b.DeleteManagedVPAsForOptOut(ctx, obj, targetGVK.Kind); err != nil {
@@ -141,66 +296,359 @@ func (b *BaseReconciler) ReconcileWorkload(
 		return ctrl.Result{}, nil
 	}
 
-	// Resolve profile.
-	selectedProfile := utils.DefaultIfZero(profileName, b.Profiles.Default)
-	profile, found := b.Profiles.Entries[selectedProfile]
-	if !found {
-		// Invalid configuration: profile doesn't exist. This is surfaced as an
-		// Event and metric, but we do not requeue to avoid hot-looping until
-		// someone fixes the profile config.
-		log.Info(
-			"profile not found; skipping VPA reconciliation",
-			"profile", selectedProfile,
-		)
-
-		b.Recorder.Eventf(
-			obj,
-			nil,
-			corev1.EventTypeWarning,
-			vpaEventProfileNotFound,
-			vpaActionSkipVPA,
-			"Profile %q not found",
-			selectedProfile,
-		)
-
-		b.Metrics.IncVPASkipped(
-			ns,
-			name,
-			targetGVK.Kind,
-			vpaSkipReasonProfileMissing,
-		)
+	// A terminating namespace rejects new VPAs; its VPAs go away with it.
+	if b.namespaceTerminating(ctx, ns) {
+		b.skipTerminatingNamespace(ctx, log, obj, targetGVK.Kind)
 
 		// Do not return an error to avoid requeuing the workload.
 		return ctrl.Result{}, nil
 	}
 
-	// Build desired VPA state from the profile and workload.
-	desired, err := b.buildDesiredVPA(obj, targetGVK, selectedProfile, profile)
+	// Resolve all requested profiles before touching any VPA.
+	desiredVPAs := make([]desiredVPAState, 0, len(profileNames))
+	for _, entry := range profileNames {
+		requested, inline, err := parseProfileEntry(entry)
+		if err != nil {
+			// Invalid inline override: retrying cannot help until the
+			// annotation is fixed, which requeues the workload.
+			log.Info(
+				"invalid inline override; skipping VPA reconciliation",
+				"error", err.Error(),
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventInvalidInlineOverride,
+				vpaActionSkipVPA,
+				"Skipping VPA: %s",
+				err.Error(),
+			)
+
+			b.Metrics.IncVPASkipped(
+				ns,
+				name,
+				targetGVK.Kind,
+				vpaSkipReasonInvalidInlineOverride,
+			)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonInvalidInlineOverride))
+
+			// Do not return an error to avoid requeuing the workload.
+			return ctrl.Result{}, nil
+		}
+
+		selectedProfile := b.Profiles.resolve(requested, targetGVK.Kind)
+		profile, found := b.Profiles.Entries[selectedProfile]
+		if !found {
+			// Invalid configuration: profile doesn't exist. This is surfaced as an
+			// Event and metric, but we do not requeue to avoid hot-looping until
+			// someone fixes the profile config.
+			log.Info(
+				"profile not found; skipping VPA reconciliation",
+				"profile", selectedProfile,
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventProfileNotFound,
+				vpaActionSkipVPA,
+				"Profile %q not found",
+				selectedProfile,
+			)
+
+			b.Metrics.IncVPASkipped(
+				ns,
+				name,
+				targetGVK.Kind,
+				vpaSkipReasonProfileMissing,
+			)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonProfileMissing))
+
+			// Do not return an error to avoid requeuing the workload.
+			return ctrl.Result{}, nil
+		}
+		if !profile.IsEnabled() {
+			// Disabled profiles are treated like missing ones until re-enabled.
+			log.Info(
+				"profile disabled; skipping VPA reconciliation",
+				"profile", selectedProfile,
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventProfileDisabled,
+				vpaActionSkipVPA,
+				"Profile %q is disabled",
+				selectedProfile,
+			)
+
+			b.Metrics.IncVPASkipped(
+				ns,
+				name,
+				targetGVK.Kind,
+				vpaSkipReasonProfileDisabled,
+			)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonProfileDisabled))
+
+			// Do not return an error to avoid requeuing the workload.
+			return ctrl.Result{}, nil
+		}
+		if !b.Profiles.allowsKind(selectedProfile, targetGVK.Kind) {
+			// Restricted profiles are treated like missing ones for other kinds.
+			log.Info(
+				"profile not allowed for workload kind; skipping VPA reconciliation",
+				"profile", selectedProfile,
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventProfileKindNotAllowed,
+				vpaActionSkipVPA,
+				"Profile %q is not allowed for %s workloads",
+				selectedProfile,
+				targetGVK.Kind,
+			)
+
+			b.Metrics.IncVPASkipped(
+				ns,
+				name,
+				targetGVK.Kind,
+				vpaSkipReasonProfileKindNotAllowed,
+			)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonProfileKindNotAllowed))
+
+			// Do not return an error to avoid requeuing the workload.
+			return ctrl.Result{}, nil
+		}
+
+		// Build desired VPA state from the profile and workload.
+		desired, err := b.buildDesiredVPA(ctx, obj, targetGVK, requested, selectedProfile, profile, inline)
+		if errors.Is(err, errInvalidResourceAnnotation) {
+			// Invalid workload annotation: retrying cannot help until the
+			// annotation is fixed, which requeues the workload.
+			log.Info(
+				"invalid resource annotation; skipping VPA reconciliation",
+				"error", err.Error(),
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventInvalidResourceAnnotation,
+				vpaActionSkipVPA,
+				"Skipping VPA: %s",
+				err.Error(),
+			)
+
+			b.Metrics.IncVPASkipped(
+				ns,
+				name,
+				targetGVK.Kind,
+				vpaSkipReasonInvalidResourceAnnotation,
+			)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonInvalidResourceAnnotation))
+
+			// Do not return an error to avoid requeuing the workload.
+			return ctrl.Result{}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		desiredVPAs = append(desiredVPAs, desired)
+	}
+
+	// Each profile must render its own VPA; otherwise they would overwrite each other.
+	keepNames := make([]string, 0, len(desiredVPAs))
+	for _, desired := range desiredVPAs {
+		if slices.Contains(keepNames, desired.Name) {
+			log.Info(
+				"multiple profiles render the same VPA name; skipping VPA reconciliation",
+				"vpa", desired.Name,
+				"profiles", profileNames,
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventVPANameConflict,
+				vpaActionSkipVPA,
+				"Profiles %s render the same VPA name %s",
+				strings.Join(profileNames, ","),
+				desired.Name,
+			)
+
+			b.Metrics.IncVPASkipped(
+				ns,
+				name,
+				targetGVK.Kind,
+				vpaSkipReasonNameConflict,
+			)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonNameConflict))
+
+			// Do not return an error to avoid requeuing the workload.
+			return ctrl.Result{}, nil
+		}
+		keepNames = append(keepNames, desired.Name)
+	}
+
+	// Remember the profiles of the current VPAs to report a profile switch.
+	previousProfiles, err := b.activeProfiles(ctx, obj)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Delete obsolete VPAs (e.g. name template/profile changed).
-	if err := b.DeleteObsoleteManagedVPAs(ctx, obj, targetGVK.Kind, desired.Name); err != nil {
+	// Delete obsolete VPAs (e.g. name template/profile changed or profile removed from the list).
+	if err := b.DeleteObsoleteManagedVPAs(ctx, obj, targetGVK.Kind, keepNames...); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Circuit open: recent applies kept failing, so back off instead of
+	// adding load to a struggling API server.
+	if wait := b.Circuit.openFor(); wait > 0 {
+		log.Info("circuit open; skipping VPA apply", "requeueAfter", wait)
+		recordOutcome(ctx, skippedOutcome(outcomeSkipReasonCircuitOpen))
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	for _, desired := range desiredVPAs {
+		err := b.reconcileDesiredVPA(ctx, log, obj, targetGVK, desired)
+		if isNamespaceTerminatingError(err) {
+			b.skipTerminatingNamespace(ctx, log, obj, targetGVK.Kind)
+			return ctrl.Result{}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	b.recordProfileChange(log, obj, targetGVK.Kind, previousProfiles, desiredVPAs)
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileDesiredVPA creates the desired VPA if missing, or merges and applies
+// changes to the existing one.
+func (b *BaseReconciler) reconcileDesiredVPA(
+	ctx context.Context,
+	log logr.Logger,
+	obj client.Object,
+	targetGVK schema.GroupVersionKind,
+	desired desiredVPAState,
+) error {
+	name, ns := obj.GetName(), obj.GetNamespace()
+
 	// Fetch or create the current VPA instance.
 	existing, err := b.fetchExistingVPA(ctx, types.NamespacedName{Name: desired.Name, Namespace: ns})
 	if err != nil {
-		return ctrl.Result{}, err
+		return err
+	}
+
+	// Rotation requested: delete the VPA and recreate it from scratch.
+	if existing != nil && rotationRequested(obj, existing) {
+		recreate, err := b.rotateVPA(ctx, log, obj, targetGVK.Kind, existing)
+		if err != nil || !recreate {
+			return err
+		}
+		existing = nil
+	}
+
+	// Requests already within the profile's bounds: leave the VPA as it is.
+	if b.SkipIfWithinBounds {
+		within, err := requestsWithinBounds(obj, desired.Spec)
+		if err != nil {
+			return err
+		}
+		if within {
+			log.V(1).Info(
+				"container requests within profile bounds; skipping VPA reconciliation",
+				"vpa", desired.Name,
+				"profile", desired.Profile,
+			)
+			b.Metrics.IncVPASkipped(ns, name, targetGVK.Kind, vpaSkipReasonWithinBounds)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonWithinBounds))
+			return nil
+		}
 	}
 
 	// Create a new VPA when none exists yet.
 	if existing == nil {
-		if err := b.createVPA(ctx, obj, desired.Name, desired.Labels, desired.Spec); err != nil {
-			return ctrl.Result{}, err
+		hpa, err := b.conflictingHPA(ctx, obj, targetGVK)
+		if err != nil {
+			return err
+		}
+		if hpa != "" {
+			log.Info(
+				"HPA scales workload on CPU or memory; skipping VPA creation",
+				"hpa", hpa,
+				"vpa", desired.Name,
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventHPAConflict,
+				vpaActionSkipVPA,
+				"HPA %s scales on CPU or memory; skipping VPA %s",
+				hpa,
+				desired.Name,
+			)
+
+			b.Metrics.IncVPASkipped(ns, name, targetGVK.Kind, vpaSkipReasonHPAConflict)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonHPAConflict))
+			return nil
+		}
+
+		capReached, count, err := b.vpaCapReached(ctx, ns)
+		if err != nil {
+			return err
+		}
+		if capReached {
+			// Existing VPAs keep being updated; new ones wait until the
+			// namespace drops below the cap and the workload is reconciled again.
+			log.Info(
+				"namespace reached the managed VPA cap; skipping VPA creation",
+				"vpa", desired.Name,
+				"count", count,
+				"max", b.MaxVPAsPerNamespace,
+			)
+
+			b.Recorder.Eventf(
+				obj,
+				nil,
+				corev1.EventTypeWarning,
+				vpaEventVPACapExceeded,
+				vpaActionSkipVPA,
+				"Namespace already has %d managed VPAs (max %d); skipping VPA %s",
+				count,
+				b.MaxVPAsPerNamespace,
+				desired.Name,
+			)
+
+			b.Metrics.IncVPASkipped(ns, name, targetGVK.Kind, vpaSkipReasonVPACapExceeded)
+			recordOutcome(ctx, skippedOutcome(vpaSkipReasonVPACapExceeded))
+			return nil
+		}
+
+		err = b.createVPA(ctx, obj, desired)
+		b.recordApply(log, targetGVK.Kind, err)
+		if err != nil {
+			return err
 		}
 
 		log.Info(
 			"created VPA",
 			"vpa", desired.Name,
-			"profile", selectedProfile,
+			"profile", desired.Profile,
 		)
 
 		b.Recorder.Eventf(
@@ -211,33 +659,60 @@ func (b *BaseReconciler) ReconcileWorkload(
 			vpaActionCreateVPA,
 			"Created VPA %s with profile %s",
 			desired.Name,
-			selectedProfile,
+			desired.Profile,
 		)
 
-		b.Metrics.IncVPACreated(ns, name, targetGVK.Kind, selectedProfile)
-		b.Metrics.IncVPAManaged(ns, selectedProfile)
-		return ctrl.Result{}, nil
+		b.Metrics.IncVPACreated(ns, name, targetGVK.Kind, desired.Profile)
+		recordOutcome(ctx, outcomeCreated)
+		b.Metrics.IncVPAManaged(ns, desired.Profile)
+		return nil
 	}
 
 	// Merge desired state into the existing VPA and apply any changes.
 	updated, err := b.mergeVPA(existing, desired, obj)
 	if err != nil {
-		return ctrl.Result{}, err
+		return err
 	}
 
 	// Short-circuit if nothing changed to avoid unnecessary API updates.
 	if !vpaNeedsUpdate(existing, updated) {
-		return ctrl.Result{}, nil
+		return nil
 	}
 
-	if err := b.updateVPA(ctx, updated); err != nil {
-		return ctrl.Result{}, err
+	// Create-only mode: existing VPAs are left under manual control.
+	if b.CreateOnly {
+		log.V(1).Info(
+			"VPA differs from profile but updates are disabled; skipping",
+			"vpa", desired.Name,
+			"profile", desired.Profile,
+		)
+		b.Metrics.IncVPASkipped(ns, name, targetGVK.Kind, vpaSkipReasonUpdateDisabled)
+		recordOutcome(ctx, skippedOutcome(vpaSkipReasonUpdateDisabled))
+		return nil
+	}
+
+	err = b.updateVPA(ctx, updated)
+	b.recordApply(log, targetGVK.Kind, err)
+	if err != nil {
+		return err
+	}
+
+	// The apply can still leave the VPA unchanged server-side (e.g. fields the
+	// API server defaults); an unchanged resourceVersion means nothing was updated.
+	if updated.GetResourceVersion() == existing.GetResourceVersion() {
+		log.V(1).Info(
+			"VPA apply changed nothing",
+			"vpa", desired.Name,
+			"profile", desired.Profile,
+		)
+		b.Metrics.IncVPAApplyNoop(ns, targetGVK.Kind)
+		return nil
 	}
 
 	log.Info(
 		"updated VPA",
 		"vpa", desired.Name,
-		"profile", selectedProfile,
+		"profile", desired.Profile,
 	)
 
 	b.Recorder.Eventf(
@@ -248,20 +723,24 @@ func (b *BaseReconciler) ReconcileWorkload(
 		vpaActionUpdateVPA,
 		"Updated VPA %s to profile %s",
 		desired.Name,
-		selectedProfile,
+		desired.Profile,
 	)
 
-	b.Metrics.IncVPAUpdated(ns, name, targetGVK.Kind, selectedProfile)
-	return ctrl.Result{}, nil
+	b.Metrics.IncVPAUpdated(ns, name, targetGVK.Kind, desired.Profile)
+	recordOutcome(ctx, outcomeUpdated)
+	return nil
 }
 
 // DeleteObsoleteManagedVPAs deletes all managed VPAs owned by `owner` except
-// the one named keepName. This handles profile/name-template changes.
+// the ones named in keepNames. This handles profile/name-template changes and
+// profiles removed from a multi-profile annotation. With ObsoleteActionRelease
+// the VPAs are released instead of deleted. With DisableObsoleteCleanup they
+// are only logged and left in place.
 func (b *BaseReconciler) DeleteObsoleteManagedVPAs(
 	ctx context.Context,
 	owner client.Object,
 	workloadKind string,
-	keepName string,
+	keepNames ...string,
 ) error {
 	vpas, err := b.listManagedVPAs(ctx, owner.GetNamespace())
 	if err != nil {
@@ -269,7 +748,7 @@ func (b *BaseReconciler) DeleteObsoleteManagedVPAs(
 	}
 
 	for _, vpa := range vpas {
-		if vpa.GetName() == keepName {
+		if slices.Contains(keepNames, vpa.GetName()) {
 			continue
 		}
 		// Only consider VPAs actually owned by this workload.
@@ -280,23 +759,29 @@ func (b *BaseReconciler) DeleteObsoleteManagedVPAs(
 		// When here, we know that the VPA is owned by the workload and the VPA name
 		// has changed. Most likely the profile or name template changed, so the VPA
 		// is obsolete and should be removed.
-		if err := b.KubeClient.Delete(ctx, vpa); err != nil {
-			if apierrors.IsNotFound(err) {
-				continue
+		if b.DisableObsoleteCleanup {
+			b.Logger.Info(
+				"keeping obsolete VPA, cleanup disabled",
+				"vpa", vpa.GetName(),
+				"namespace", owner.GetNamespace(),
+				"workload", owner.GetName(),
+				"kind", workloadKind,
+			)
+			continue
+		}
+		if b.ObsoleteAction == ObsoleteActionRelease {
+			if err := b.releaseObsoleteVPA(ctx, owner, vpa, workloadKind); err != nil {
+				return err
 			}
-			return fmt.Errorf("delete obsolete VPA %s: %w", vpa.GetName(), err)
+			continue
+		}
+		deleted, err := b.deleteVPA(ctx, owner, vpa, deleteReasonObsolete, workloadKind)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			continue
 		}
-
-		b.Logger.Info(
-			"deleted obsolete VPA",
-			"vpa", vpa.GetName(),
-			"namespace", owner.GetNamespace(),
-			"workload", owner.GetName(),
-		)
-
-		profile := profileFromLabels(vpa.GetLabels(), b.Meta.ProfileKey)
-		b.Metrics.IncVPADeletedObsolete(owner.GetNamespace(), workloadKind)
-		b.Metrics.DecVPAManaged(owner.GetNamespace(), profile)
 
 		b.Recorder.Eventf(
 			owner,
@@ -312,16 +797,66 @@ func (b *BaseReconciler) DeleteObsoleteManagedVPAs(
 	return nil
 }
 
+// releaseObsoleteVPA keeps an obsolete VPA but hands it over to the user: the
+// managed label, the owner's ownerRef and ManagedFinalizer are removed in one
+// patch, so the VPAReconciler never sees a managed VPA without an owner and
+// ignores it from then on.
+//
+// The patch uses an optimistic lock so labels, ownerRefs and finalizers added
+// concurrently by others are never dropped.
+func (b *BaseReconciler) releaseObsoleteVPA(
+	ctx context.Context,
+	owner client.Object,
+	vpa *unstructured.Unstructured,
+	workloadKind string,
+) error {
+	patchBase := client.MergeFromWithOptions(vpa.DeepCopy(), client.MergeFromWithOptimisticLock{})
+
+	labels := vpa.GetLabels()
+	delete(labels, b.Meta.ManagedLabel)
+	vpa.SetLabels(labels)
+	vpa.SetOwnerReferences(slices.DeleteFunc(vpa.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+		return ref.UID == owner.GetUID()
+	}))
+	controllerutil.RemoveFinalizer(vpa, ManagedFinalizer)
+
+	if err := b.KubeClient.Patch(ctx, vpa, patchBase); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("release obsolete VPA %s: %w", vpa.GetName(), err)
+	}
+
+	b.Logger.Info(
+		"released obsolete VPA",
+		"vpa", vpa.GetName(),
+		"namespace", owner.GetNamespace(),
+		"workload", owner.GetName(),
+	)
+
+	// The VPA is no longer managed, and its finalizer (if any) is gone.
+	b.Metrics.IncVPAReleasedObsolete(owner.GetNamespace(), workloadKind)
+	b.Metrics.DecVPAManaged(owner.GetNamespace(), profileFromLabels(vpa.GetLabels(), b.Meta.ProfileKey))
+
+	b.Recorder.Eventf(
+		owner,
+		vpa,
+		corev1.EventTypeNormal,
+		vpaEventReleasedObsoleteVPA,
+		vpaActionReleaseVPA,
+		"Released obsolete VPA %s",
+		vpa.GetName(),
+	)
+	return nil
+}
+
 // DeleteManagedVPAsForOptOut deletes managed VPAs when a workload opts out.
 func (b *BaseReconciler) DeleteManagedVPAsForOptOut(
 	ctx context.Context,
 	owner client.Object,
 	workloadKind string,
 ) error {
-	return b.deleteManagedVPAs(ctx, owner, workloadKind, func(ns, profile string) {
-		b.Metrics.IncVPADeletedOptOut(ns, workloadKind)
-		b.Metrics.DecVPAManaged(ns, profile)
-	})
+	return b.deleteManagedVPAs(ctx, owner, workloadKind, deleteReasonOptOut)
 }
 
 // DeleteManagedVPAsForGoneWorkload deletes managed VPAs when the workload was removed.
@@ -330,18 +865,16 @@ func (b *BaseReconciler) DeleteManagedVPAsForGoneWorkload(
 	owner client.Object,
 	workloadKind string,
 ) error {
-	return b.deleteManagedVPAs(ctx, owner, workloadKind, func(ns, profile string) {
-		b.Metrics.IncVPADeletedWorkloadGone(ns, workloadKind)
-		b.Metrics.DecVPAManaged(ns, profile)
-	})
+	return b.deleteManagedVPAs(ctx, owner, workloadKind, deleteReasonWorkloadGone)
 }
 
-// deleteManagedVPAs removes all managed VPAs for an owner and runs the provided callback.
+// deleteManagedVPAs removes all managed VPAs for an owner, recording each
+// deletion under reason.
 func (b *BaseReconciler) deleteManagedVPAs(
 	ctx context.Context,
 	owner client.Object,
 	workloadKind string,
-	onDelete func(namespace, profile string),
+	reason string,
 ) error {
 	vpas, err := b.listManagedVPAs(ctx, owner.GetNamespace())
 	if err != nil {
@@ -350,27 +883,30 @@ func (b *BaseReconciler) deleteManagedVPAs(
 
 	for _, vpa := range vpas {
 		for _, ref := range vpa.GetOwnerReferences() {
-			if ref.Kind != workloadKind || ref.Name != owner.GetName() {
+			matches, uidMismatch := ownerRefMatches(ref, owner, workloadKind)
+			if uidMismatch {
+				// Owned by a previous workload with the same name; the garbage
+				// collector removes it, since its real owner is gone.
+				b.Logger.Info(
+					"managed VPA references a previous workload with the same name; skipping",
+					"vpa", vpa.GetName(),
+					"namespace", owner.GetNamespace(),
+					"workload", owner.GetName(),
+					"ownerUID", ref.UID,
+					"workloadUID", owner.GetUID(),
+				)
+				b.Metrics.IncVPAOwnerUIDMismatch(owner.GetNamespace(), workloadKind)
+			}
+			if !matches {
 				continue
 			}
 
-			if err := b.KubeClient.Delete(ctx, vpa); err != nil {
-				if apierrors.IsNotFound(err) {
-					continue
-				}
-				return fmt.Errorf("delete VPA %s: %w", vpa.GetName(), err)
+			deleted, err := b.deleteVPA(ctx, owner, vpa, reason, workloadKind)
+			if err != nil {
+				return err
 			}
-
-			b.Logger.Info(
-				"deleted managed VPA for workload",
-				"vpa", vpa.GetName(),
-				"namespace", owner.GetNamespace(),
-				"workload", owner.GetName(),
-			)
-
-			if onDelete != nil {
-				profile := profileFromLabels(vpa.GetLabels(), b.Meta.ProfileKey)
-				onDelete(owner.GetNamespace(), profile)
+			if !deleted {
+				continue
 			}
 
 			b.Recorder.Eventf(
@@ -391,40 +927,99 @@ func (b *BaseReconciler) deleteManagedVPAs(
 
 // buildDesiredVPA resolves the target VPA name, labels, and spec
 // according to the selected profile and operator configuration.
+// requestedProfile is the profile name as requested by the workload, before
+// resolution to selectedProfile; templates see it as .RequestedProfile.
+// inline holds the overrides from the profile annotation entry, if any.
 func (b *BaseReconciler) buildDesiredVPA(
+	ctx context.Context,
 	obj client.Object,
 	targetGVK schema.GroupVersionKind,
+	requestedProfile string,
 	selectedProfile string,
 	profile config.Profile,
+	inline *inlineOverrides,
 ) (desiredVPAState, error) {
-	// Select the name template: profile override or global default.
-	templateStr := utils.DefaultIfZero(profile.NameTemplate, b.Profiles.NameTemplate)
+	nameData := vpaNameData(obj, targetGVK, requestedProfile, selectedProfile)
+	if utils.UsesNamespaceLabels(b.Profiles.nameTemplate(profile)) || utils.UsesNamespaceLabels(b.Meta.ManagedLabelValue) {
+		namespaceLabels, err := b.namespaceLabels(ctx, obj.GetNamespace())
+		if err != nil {
+			return desiredVPAState{}, err
+		}
+		nameData.NamespaceLabels = namespaceLabels
+	}
 
-	vpaName, err := RenderVPAName(templateStr, utils.NameTemplateData{
-		WorkloadName: obj.GetName(),
-		Namespace:    obj.GetNamespace(),
-		Kind:         targetGVK.Kind,
-		Profile:      selectedProfile,
-	})
+	limits := b.namespaceContainerLimits(ctx, obj.GetNamespace())
+	quota := b.namespaceQuotaLimits(ctx, obj.GetNamespace())
+
+	return b.renderDesiredVPA(obj, targetGVK, nameData, profile, inline, limits, quota)
+}
+
+// renderDesiredVPA renders the desired VPA state from data already read from
+// the cluster: the name template data and the namespace's LimitRange and
+// ResourceQuota bounds (nil when not respected). Inline overrides apply on top
+// of the profile, but --force-update-mode-off still wins.
+func (b *BaseReconciler) renderDesiredVPA(
+	obj client.Object,
+	targetGVK schema.GroupVersionKind,
+	nameData utils.NameTemplateData,
+	profile config.Profile,
+	inline *inlineOverrides,
+	limits *containerLimits,
+	quota *containerLimits,
+) (desiredVPAState, error) {
+	vpaName, err := RenderVPAName(b.Profiles.nameTemplate(profile), nameData)
 	if err != nil {
 		return desiredVPAState{}, err
 	}
 
-	spec, err := buildVPASpec(profile.Spec, targetGVK, obj.GetName())
+	managedValue, err := b.Meta.managedLabelValue(nameData)
 	if err != nil {
 		return desiredVPAState{}, err
 	}
 
-	labels := map[string]string{
-		b.Meta.ManagedLabel: "true",
-		b.Meta.ProfileKey:   selectedProfile,
+	overrides, err := workloadResourceOverrides(obj.GetAnnotations())
+	if err != nil {
+		return desiredVPAState{}, err
+	}
+
+	var containers []string
+	if b.Profiles.expandsAllContainers(profile) {
+		podContainers, err := workloadContainers(obj)
+		if err != nil {
+			return desiredVPAState{}, err
+		}
+		for _, container := range podContainers {
+			containers = append(containers, container.Name)
+		}
 	}
 
+	spec, err := b.Profiles.renderSpec(vpaSpecInput{
+		Profile:      profile,
+		Overrides:    overrides,
+		Limits:       limits,
+		Quota:        quota,
+		TargetGVK:    targetGVK,
+		WorkloadName: obj.GetName(),
+		Containers:   containers,
+	}, inline)
+	if err != nil {
+		return desiredVPAState{}, err
+	}
+
+	// Operator labels win over propagated recommended labels.
+	labels := map[string]string{}
+	if b.Meta.RecommendedLabels {
+		maps.Copy(labels, recommendedLabels(obj.GetLabels()))
+	}
+	labels[b.Meta.ManagedLabel] = managedValue
+	labels[b.Meta.ProfileKey] = nameData.Profile
+
 	return desiredVPAState{
-		Name:    vpaName,
-		Profile: selectedProfile,
-		Labels:  labels,
-		Spec:    spec,
+		Name:        vpaName,
+		Profile:     nameData.Profile,
+		Labels:      labels,
+		Annotations: rotationAnnotations(obj, trackingAnnotations(obj.GetAnnotations(), b.Meta.TrackingAnnotations)),
+		Spec:        spec,
 	}, nil
 }
 
@@ -454,50 +1049,169 @@ func (b *BaseReconciler) mergeVPA(
 	updated.SetName(existing.GetName())
 	updated.SetNamespace(existing.GetNamespace())
 
-	// Merge existing labels with desired operator labels.
-	updated.SetLabels(utils.MergeMaps(existing.GetLabels(), desired.Labels))
+	// Merge existing labels with desired operator labels. Propagated
+	// recommended labels are replaced, so labels dropped from the workload
+	// are dropped from the VPA.
+	labels := existing.GetLabels()
+	if b.Meta.RecommendedLabels {
+		labels = maps.Clone(labels)
+		maps.DeleteFunc(labels, func(key, _ string) bool {
+			return strings.HasPrefix(key, recommendedLabelPrefix)
+		})
+	}
+	updated.SetLabels(utils.MergeMaps(labels, desired.Labels))
 
-	// Desired spec is fully owned by the operator.
+	// Replace propagated tracking annotations; keep all other annotations.
+	annotations := maps.Clone(existing.GetAnnotations())
+	for _, key := range b.Meta.TrackingAnnotations {
+		delete(annotations, key)
+	}
+	updated.SetAnnotations(utils.MergeMaps(annotations, desired.Annotations))
+
+	// Desired spec is fully owned by the operator, unless the VPA was marked
+	// as spec-authoritative; then the hand-tuned spec is kept as-is.
 	updated.Object["spec"] = desired.Spec
+	if isSpecAuthoritative(existing) {
+		updated.Object["spec"] = existing.Object["spec"]
+	}
+
+	// Finalizers are a set in server-side apply; only the operator's own entry
+	// is declared, so finalizers added by others are left untouched.
+	if b.UseFinalizers {
+		updated.SetFinalizers([]string{ManagedFinalizer})
+	}
 
-	if err := ctrl.SetControllerReference(owner, updated, b.KubeClient.Scheme()); err != nil {
+	if err := b.setControllerReference(owner, updated); err != nil {
 		return nil, err
 	}
 	return updated, nil
 }
 
+// controllerOptions returns the options of the controller embedding this
+// reconciler, so each workload kind runs its own number of workers.
+func (b *BaseReconciler) controllerOptions() ctrlcontroller.Options {
+	return ctrlcontroller.Options{MaxConcurrentReconciles: b.MaxConcurrentReconciles}
+}
+
+// setControllerReference marks the workload as controller owner of the VPA,
+// honoring DisableBlockOwnerDeletion.
+func (b *BaseReconciler) setControllerReference(owner client.Object, vpa *unstructured.Unstructured) error {
+	return ctrl.SetControllerReference(
+		owner,
+		vpa,
+		b.KubeClient.Scheme(),
+		controllerutil.WithBlockOwnerDeletion(!b.DisableBlockOwnerDeletion),
+	)
+}
+
 // applyVPA applies a VPA via server-side apply.
 // managedFields must be stripped before sending the object, otherwise the API
 // server rejects the request.
+// If the API server rejects the apply because of inconsistent managedFields
+// (e.g. after an operator upgrade), the live object's managedFields are reset
+// and the apply is retried once after a short jittered delay.
 func (b *BaseReconciler) applyVPA(
 	ctx context.Context,
 	vpa *unstructured.Unstructured,
+) error {
+	err := b.patchVPA(ctx, vpa)
+	if !isManagedFieldsError(err) {
+		return err
+	}
+
+	b.Logger.V(1).Info("VPA apply failed on managedFields; resetting and retrying",
+		"namespace", vpa.GetNamespace(),
+		"vpa", vpa.GetName(),
+		"error", err.Error(),
+	)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait.Jitter(managedFieldsRetryDelay, 1.0)):
+	}
+
+	if err := b.resetManagedFields(ctx, vpa); err != nil {
+		return err
+	}
+
+	return b.patchVPA(ctx, vpa)
+}
+
+// patchVPA sends a single server-side apply for the VPA.
+// The apply is attempted without force first so that fields owned by another
+// field manager surface as a conflict, which is counted before force-applying.
+func (b *BaseReconciler) patchVPA(
+	ctx context.Context,
+	vpa *unstructured.Unstructured,
 ) error {
 	// Avoid sending stale managedFields back to the API server on Apply.
 	vpa.SetManagedFields(nil)
 
+	err := b.KubeClient.Patch(ctx, vpa, client.Apply, &client.PatchOptions{
+		FieldManager: b.fieldManager(),
+	})
+	if !apierrors.IsConflict(err) {
+		return err
+	}
+
+	kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+	b.Metrics.IncVPAApplyConflicts(vpa.GetNamespace(), kind)
+	b.Logger.V(1).Info("VPA apply conflicted with another field manager; forcing ownership",
+		"namespace", vpa.GetNamespace(),
+		"vpa", vpa.GetName(),
+		"error", err.Error(),
+	)
+
 	return b.KubeClient.Patch(ctx, vpa, client.Apply, &client.PatchOptions{
-		FieldManager: fieldManager,
+		FieldManager: b.fieldManager(),
 		Force:        ptr.To(true),
 	})
 }
 
+// fieldManager returns the configured field manager or the default.
+func (b *BaseReconciler) fieldManager() string {
+	return utils.DefaultIfZero(b.FieldManager, defaultFieldManager)
+}
+
+// resetManagedFields re-fetches the live VPA and clears its managedFields so
+// the next apply rebuilds field ownership from scratch. A VPA that no longer
+// exists needs no reset.
+func (b *BaseReconciler) resetManagedFields(ctx context.Context, vpa *unstructured.Unstructured) error {
+	live := newVPAObject()
+	if err := b.KubeClient.Get(ctx, client.ObjectKeyFromObject(vpa), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("fetch VPA %s/%s: %w", vpa.GetNamespace(), vpa.GetName(), err)
+	}
+
+	// A single empty entry is how the API server is told to drop all managedFields.
+	reset := client.RawPatch(types.MergePatchType, []byte(`{"metadata":{"managedFields":[{}]}}`))
+	if err := b.KubeClient.Patch(ctx, live, reset); err != nil {
+		return fmt.Errorf("reset managedFields on VPA %s/%s: %w", vpa.GetNamespace(), vpa.GetName(), err)
+	}
+	return nil
+}
+
 // createVPA builds and creates a new VPA owned by the workload.
 func (b *BaseReconciler) createVPA(
 	ctx context.Context,
 	owner client.Object,
-	name string,
-	labels map[string]string,
-	spec map[string]any,
+	desired desiredVPAState,
 ) error {
 	vpa := newVPAObject()
-	vpa.SetName(name)
+	vpa.SetName(desired.Name)
 	vpa.SetNamespace(owner.GetNamespace())
-	vpa.SetLabels(labels)
-	vpa.Object["spec"] = spec
+	vpa.SetLabels(desired.Labels)
+	vpa.SetAnnotations(desired.Annotations)
+	vpa.Object["spec"] = desired.Spec
+	if b.UseFinalizers {
+		vpa.SetFinalizers([]string{ManagedFinalizer})
+	}
 
 	// Ensure the workload owns the VPA for garbage collection and intent tracking.
-	if err := ctrl.SetControllerReference(owner, vpa, b.KubeClient.Scheme()); err != nil {
+	if err := b.setControllerReference(owner, vpa); err != nil {
 		return err
 	}
 
@@ -513,7 +1227,8 @@ func (b *BaseReconciler) updateVPA(
 }
 
 // listManagedVPAs returns all VPA resources in the namespace that carry the
-// operator's managed label. This is the basis for cleanup logic.
+// operator's managed label. This is the basis for cleanup logic. With a
+// templated label value, any VPA carrying the label key is considered managed.
 func (b *BaseReconciler) listManagedVPAs(
 	ctx context.Context,
 	namespace string,
@@ -525,7 +1240,7 @@ func (b *BaseReconciler) listManagedVPAs(
 		ctx,
 		list,
 		client.InNamespace(namespace),
-		client.MatchingLabels{b.Meta.ManagedLabel: "true"},
+		b.Meta.managedSelector(),
 	); err != nil {
 		return nil, fmt.Errorf("list managed VPAs: %w", err)
 	}
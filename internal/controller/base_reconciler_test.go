@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/containeroo/autovpa/internal/config"
@@ -31,7 +32,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	io_prometheus_client "github.com/prometheus/client_model/go"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,7 +43,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func mustGetCounterValue(t *testing.T, g prometheus.Gatherer, metricName string, wantLabels map[string]string) float64 {
@@ -194,6 +203,63 @@ func TestBaseReconciler_ReconcileWorkload(t *testing.T) {
 		assert.Equal(t, float64(1), got)
 	})
 
+	t.Run("Skips VPA when profile disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		scheme := newScheme(t)
+		client := fake.NewClientBuilder().WithScheme(scheme).Build()
+		rec := events.NewFakeRecorder(10)
+		logger := logr.Discard()
+
+		promReg := prometheus.NewRegistry()
+		metricsReg := internalmetrics.NewRegistry(promReg)
+
+		reconciler := BaseReconciler{
+			KubeClient: client,
+			Logger:     &logger,
+			Recorder:   rec,
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1":     {Spec: config.ProfileSpec{}},
+					"paused": {Enabled: ptr.To(false)},
+				},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			Metrics: metricsReg,
+		}
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "paused"})
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		require.NoError(t, err)
+
+		got := mustGetCounterValue(
+			t, promReg,
+			"autovpa_vpa_skipped_total",
+			map[string]string{
+				"namespace": "ns1",
+				"name":      "demo",
+				"kind":      "Deployment",
+				"reason":    vpaSkipReasonProfileDisabled,
+			},
+		)
+		assert.Equal(t, float64(1), got)
+		assert.Equal(t, `Warning ProfileDisabled Profile "paused" is disabled`, <-rec.Events)
+
+		vpas := &unstructured.UnstructuredList{}
+		vpas.SetGroupVersionKind(vpaListGVK)
+		require.NoError(t, client.List(ctx, vpas))
+		assert.Empty(t, vpas.Items)
+	})
+
 	t.Run("Creates VPA", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
@@ -338,6 +404,66 @@ func TestBaseReconciler_ReconcileWorkload(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Keeps obsolete managed VPA when cleanup is disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		scheme := newScheme(t)
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p2"})
+
+		existing := newVPAObject()
+		existing.SetNamespace("ns1")
+		existing.SetName("legacy-demo")
+		existing.SetLabels(map[string]string{"vpa/managed": "true"})
+		require.NoError(t, controllerutil.SetControllerReference(dep, existing, scheme))
+
+		deletes := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing, dep).WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deletes++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).Build()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+
+		reconciler := BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1": {NameTemplate: "legacy-{{ .WorkloadName }}"},
+					"p2": {},
+				},
+				Default:      "p2",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			DisableObsoleteCleanup: true,
+		}
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		require.NoError(t, err)
+
+		assert.Zero(t, deletes)
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: "legacy-demo", Namespace: "ns1"}, newVPAObject()))
+
+		newVPAName := renderDeploymentVPAName(t, "ns1", dep.GetName(), "p2")
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: newVPAName, Namespace: "ns1"}, newVPAObject()))
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_created_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+		}))
+	})
+
 	t.Run("Updates VPA", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
@@ -420,6 +546,91 @@ func TestBaseReconciler_ReconcileWorkload(t *testing.T) {
 		assert.Equal(t, float64(1), got)
 	})
 
+	t.Run("Preserves spec of authoritative VPA and restores it once annotation is removed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		scheme := newScheme(t)
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+
+		managed := true
+		vpaName := renderDeploymentVPAName(t, "ns1", dep.GetName(), "p1")
+		existing := newVPAObject()
+		existing.SetNamespace("ns1")
+		existing.SetName(vpaName)
+		existing.SetAnnotations(map[string]string{SpecAuthoritativeAnnotation: "true"})
+		existing.SetOwnerReferences([]metav1.OwnerReference{
+			{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       dep.GetName(),
+				UID:        dep.GetUID(),
+				Controller: &managed,
+			},
+		})
+		existing.Object["spec"] = map[string]any{
+			"targetRef": map[string]any{
+				"apiVersion": appsv1.SchemeGroupVersion.String(),
+				"kind":       "Deployment",
+				"name":       "demo",
+			},
+			"updatePolicy": map[string]any{"updateMode": "Off"},
+		}
+
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+		logger := logr.Discard()
+
+		reconciler := BaseReconciler{
+			KubeClient: client,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1": {
+						Spec: config.ProfileSpec{
+							UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+								UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeRecreate),
+							},
+						},
+					},
+				},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, client.Get(ctx, types.NamespacedName{Name: vpaName, Namespace: "ns1"}, vpa))
+		assert.Equal(t, "true", vpa.GetLabels()["vpa/managed"])
+		assert.Equal(t, "p1", vpa.GetLabels()["vpa/profile"])
+		updatePolicy := vpa.Object["spec"].(map[string]any)["updatePolicy"].(map[string]any)
+		assert.Equal(t, "Off", updatePolicy["updateMode"])
+
+		// Removing the annotation hands the spec back to the operator.
+		vpa.SetAnnotations(nil)
+		require.NoError(t, client.Update(ctx, vpa))
+
+		_, err = reconciler.ReconcileWorkload(ctx, dep, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		require.NoError(t, err)
+
+		vpa = newVPAObject()
+		require.NoError(t, client.Get(ctx, types.NamespacedName{Name: vpaName, Namespace: "ns1"}, vpa))
+		updatePolicy = vpa.Object["spec"].(map[string]any)["updatePolicy"].(map[string]any)
+		assert.Equal(t, "Recreate", updatePolicy["updateMode"])
+	})
+
 	t.Run("Cleans managed VPAs when annotation is removed", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
@@ -496,97 +707,1486 @@ func TestBaseReconciler_ReconcileWorkload(t *testing.T) {
 	})
 }
 
-func TestBaseReconciler_buildDesiredVPA(t *testing.T) {
+func TestBaseReconciler_ReconcileWorkload_MultiProfile(t *testing.T) {
 	t.Parallel()
 
-	scheme := newScheme(t)
-	logger := logr.Discard()
-	br := BaseReconciler{
-		KubeClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
-		Logger:     &logger,
-		Meta: MetaConfig{
-			ProfileKey:   "vpa/profile",
-			ManagedLabel: "vpa/managed",
-		},
-		Profiles: ProfileConfig{
-			NameTemplate: flag.DefaultNameTemplate,
-		},
-	}
+	newReconciler := func(t *testing.T, objs ...client.Object) (BaseReconciler, client.Client, *prometheus.Registry) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
 
-	dep := &appsv1.Deployment{}
-	dep.SetNamespace("ns1")
-	dep.SetName("demo")
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"memory": {Spec: config.ProfileSpec{
+						UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+							UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeRecreate),
+						},
+					}},
+					"dashboard": {Spec: config.ProfileSpec{
+						UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+							UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeOff),
+						},
+					}},
+					"static": {Spec: config.ProfileSpec{}, NameTemplate: "{{ .WorkloadName }}-vpa"},
+					"other":  {Spec: config.ProfileSpec{}, NameTemplate: "{{ .WorkloadName }}-vpa"},
+				},
+				Default:      "memory",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, kubeClient, promReg
+	}
 
-	profile := config.Profile{
-		Spec: config.ProfileSpec{},
+	newDeployment := func(profiles string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": profiles})
+		return dep
 	}
 
-	targetGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+	t.Run("Creates one VPA per listed profile", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("memory, dashboard")
+		reconciler, kubeClient, promReg := newReconciler(t, dep)
 
-	desired, err := br.buildDesiredVPA(dep, targetGVK, "p1", profile)
-	require.NoError(t, err)
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
 
-	expectedName := renderDeploymentVPAName(t, "ns1", "demo", "p1")
-	assert.Equal(t, expectedName, desired.Name)
-	assert.Equal(t, "p1", desired.Profile)
+		for profile, mode := range map[string]string{"memory": "Recreate", "dashboard": "Off"} {
+			vpa := newVPAObject()
+			key := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", profile), Namespace: "ns1"}
+			require.NoError(t, kubeClient.Get(ctx, key, vpa))
+			assert.Equal(t, profile, vpa.GetLabels()["vpa/profile"])
 
-	assert.Equal(t, map[string]string{
-		"vpa/managed": "true",
-		"vpa/profile": "p1",
-	}, desired.Labels)
+			gotMode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+			assert.Equal(t, mode, gotMode)
 
-	spec := desired.Spec
-	targetRef, ok := spec["targetRef"].(map[string]any)
-	require.True(t, ok)
-	assert.Equal(t, "demo", targetRef["name"])
-	assert.Equal(t, "Deployment", targetRef["kind"])
-}
+			got := mustGetCounterValue(t, promReg, "autovpa_vpa_created_total", map[string]string{
+				"namespace": "ns1",
+				"name":      "demo",
+				"kind":      "Deployment",
+				"profile":   profile,
+			})
+			assert.Equal(t, float64(1), got)
+		}
+	})
 
-func TestBaseReconciler_fetchExistingVPA(t *testing.T) {
-	t.Parallel()
+	t.Run("Deletes VPA of a profile removed from the list", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("memory,dashboard")
+		reconciler, kubeClient, promReg := newReconciler(t, dep)
 
-	ctx := context.Background()
-	scheme := newScheme(t)
-	logger := logr.Discard()
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
 
-	client := fake.NewClientBuilder().WithScheme(scheme).Build()
-	br := BaseReconciler{
-		KubeClient: client,
-		Logger:     &logger,
-	}
+		dep.SetAnnotations(map[string]string{"vpa/profile": "memory"})
+		_, err = reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
 
-	obj, err := br.fetchExistingVPA(ctx, types.NamespacedName{Name: "missing", Namespace: "ns1"})
-	require.NoError(t, err)
-	assert.Nil(t, obj)
+		kept := newVPAObject()
+		keptKey := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "memory"), Namespace: "ns1"}
+		require.NoError(t, kubeClient.Get(ctx, keptKey, kept))
 
-	existing := newVPAObject()
-	existing.SetNamespace("ns1")
-	existing.SetName("present")
-	existing.Object["spec"] = map[string]any{"foo": "bar"}
+		removed := newVPAObject()
+		removedKey := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "dashboard"), Namespace: "ns1"}
+		err = kubeClient.Get(ctx, removedKey, removed)
+		assert.True(t, apierrors.IsNotFound(err))
 
-	client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
-	br.KubeClient = client
+		got := mustGetCounterValue(t, promReg, "autovpa_vpa_deleted_obsolete_total", map[string]string{
+			"namespace": "ns1",
+			"kind":      "Deployment",
+		})
+		assert.Equal(t, float64(1), got)
+	})
 
-	obj, err = br.fetchExistingVPA(ctx, types.NamespacedName{Name: "present", Namespace: "ns1"})
-	require.NoError(t, err)
-	require.NotNil(t, obj)
-	assert.Equal(t, "present", obj.GetName())
-}
+	t.Run("Keeps all desired VPAs during obsolete cleanup", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("memory,dashboard")
+
+		controller := true
+		legacy := newVPAObject()
+		legacy.SetNamespace("ns1")
+		legacy.SetName("legacy-demo")
+		legacy.SetLabels(map[string]string{"vpa/managed": "true", "vpa/profile": "memory"})
+		legacy.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Name:       dep.GetName(),
+			UID:        dep.GetUID(),
+			Controller: &controller,
+		}})
+
+		reconciler, kubeClient, _ := newReconciler(t, dep, legacy)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
 
-func TestBaseReconciler_mergeVPA(t *testing.T) {
-	t.Parallel()
+		err = kubeClient.Get(ctx, types.NamespacedName{Name: "legacy-demo", Namespace: "ns1"}, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
 
-	scheme := newScheme(t)
-	client := fake.NewClientBuilder().WithScheme(scheme).Build()
-	logger := logr.Discard()
+		for _, profile := range []string{"memory", "dashboard"} {
+			key := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", profile), Namespace: "ns1"}
+			require.NoError(t, kubeClient.Get(ctx, key, newVPAObject()))
+		}
+	})
 
-	br := BaseReconciler{
-		KubeClient: client,
-		Logger:     &logger,
-	}
+	t.Run("Skips when listed profiles render the same name", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("static,other")
+		reconciler, kubeClient, promReg := newReconciler(t, dep)
 
-	existing := newVPAObject()
-	existing.SetNamespace("ns1")
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		err = kubeClient.Get(ctx, types.NamespacedName{Name: "demo-vpa", Namespace: "ns1"}, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+
+		got := mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"reason":    vpaSkipReasonNameConflict,
+		})
+		assert.Equal(t, float64(1), got)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_TrackingAnnotations(t *testing.T) {
+	t.Parallel()
+
+	const (
+		argoKey      = "argocd.argoproj.io/tracking-id"
+		fluxNameKey  = "kustomize.toolkit.fluxcd.io/name"
+		fluxNSKey    = "kustomize.toolkit.fluxcd.io/namespace"
+		unrelatedKey = "team"
+	)
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:          "vpa/profile",
+				ManagedLabel:        "vpa/managed",
+				TrackingAnnotations: []string{argoKey, fluxNameKey, fluxNSKey},
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {Spec: config.ProfileSpec{}}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, kubeClient
+	}
+
+	t.Run("Copies every present tracking annotation", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{
+			"vpa/profile": "p1",
+			argoKey:       "apps:apps/Deployment:ns1/demo",
+			fluxNameKey:   "apps",
+			unrelatedKey:  "platform",
+		})
+		reconciler, kubeClient := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}, vpa))
+		assert.Equal(t, map[string]string{
+			argoKey:     "apps:apps/Deployment:ns1/demo",
+			fluxNameKey: "apps",
+		}, vpa.GetAnnotations())
+	})
+
+	t.Run("Updates and removes tracking annotations on existing VPA", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{
+			"vpa/profile": "p1",
+			argoKey:       "old",
+			fluxNSKey:     "flux-system",
+		})
+		reconciler, kubeClient := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		dep.SetAnnotations(map[string]string{
+			"vpa/profile": "p1",
+			argoKey:       "new",
+		})
+		_, err = reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}, vpa))
+		assert.Equal(t, map[string]string{argoKey: "new"}, vpa.GetAnnotations())
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_RecommendedLabels(t *testing.T) {
+	t.Parallel()
+
+	const (
+		nameKey      = "app.kubernetes.io/name"
+		instanceKey  = "app.kubernetes.io/instance"
+		partOfKey    = "app.kubernetes.io/part-of"
+		managedByKey = "app.kubernetes.io/managed-by"
+	)
+
+	newReconciler := func(t *testing.T, propagate bool, managedLabel string, objs ...client.Object) (BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:        "vpa/profile",
+				ManagedLabel:      managedLabel,
+				RecommendedLabels: propagate,
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {Spec: config.ProfileSpec{}}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, kubeClient
+	}
+
+	newDeployment := func(labels map[string]string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetLabels(labels)
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		return dep
+	}
+
+	vpaLabels := func(t *testing.T, c client.Client) map[string]string {
+		t.Helper()
+		vpa := newVPAObject()
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}, vpa))
+		return vpa.GetLabels()
+	}
+
+	t.Run("Copies present recommended labels", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(map[string]string{
+			nameKey:     "demo",
+			instanceKey: "demo-prod",
+			partOfKey:   "shop",
+			"team":      "platform",
+		})
+		reconciler, kubeClient := newReconciler(t, true, "vpa/managed", dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			"vpa/managed": "true",
+			"vpa/profile": "p1",
+			nameKey:       "demo",
+			instanceKey:   "demo-prod",
+			partOfKey:     "shop",
+		}, vpaLabels(t, kubeClient))
+	})
+
+	t.Run("Adds nothing without recommended labels", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(map[string]string{"team": "platform"})
+		reconciler, kubeClient := newReconciler(t, true, "vpa/managed", dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"vpa/managed": "true", "vpa/profile": "p1"}, vpaLabels(t, kubeClient))
+	})
+
+	t.Run("Copies nothing when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(map[string]string{nameKey: "demo"})
+		reconciler, kubeClient := newReconciler(t, false, "vpa/managed", dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"vpa/managed": "true", "vpa/profile": "p1"}, vpaLabels(t, kubeClient))
+	})
+
+	t.Run("Managed label takes precedence", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(map[string]string{nameKey: "demo", managedByKey: "Helm"})
+		reconciler, kubeClient := newReconciler(t, true, managedByKey, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			managedByKey:  "true",
+			"vpa/profile": "p1",
+			nameKey:       "demo",
+		}, vpaLabels(t, kubeClient))
+	})
+
+	t.Run("Follows label changes on existing VPA", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		dep := newDeployment(map[string]string{nameKey: "demo", partOfKey: "shop"})
+		reconciler, kubeClient := newReconciler(t, true, "vpa/managed", dep)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		dep.SetLabels(map[string]string{nameKey: "demo-v2"})
+		_, err = reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			"vpa/managed": "true",
+			"vpa/profile": "p1",
+			nameKey:       "demo-v2",
+		}, vpaLabels(t, kubeClient))
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_EmptyAnnotation(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, emptyMeansDefault bool, objs ...client.Object) (BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:        "vpa/profile",
+				ManagedLabel:      "vpa/managed",
+				EmptyMeansDefault: emptyMeansDefault,
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {Spec: config.ProfileSpec{}}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, kubeClient
+	}
+
+	newDeployment := func(annotations map[string]string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(annotations)
+		return dep
+	}
+
+	t.Run("Empty annotation opts out by default", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		dep := newDeployment(map[string]string{"vpa/profile": ""})
+		reconciler, kubeClient := newReconciler(t, false, dep)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpas := &unstructured.UnstructuredList{}
+		vpas.SetGroupVersionKind(vpaListGVK)
+		require.NoError(t, kubeClient.List(ctx, vpas, client.InNamespace("ns1")))
+		assert.Empty(t, vpas.Items)
+	})
+
+	t.Run("Empty annotation selects the default profile when enabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		dep := newDeployment(map[string]string{"vpa/profile": ""})
+		reconciler, kubeClient := newReconciler(t, true, dep)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}, vpa))
+		assert.Equal(t, "p1", vpa.GetLabels()["vpa/profile"])
+	})
+
+	t.Run("Missing annotation still opts out when enabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		dep := newDeployment(nil)
+		reconciler, kubeClient := newReconciler(t, true, dep)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpas := &unstructured.UnstructuredList{}
+		vpas.SetGroupVersionKind(vpaListGVK)
+		require.NoError(t, kubeClient.List(ctx, vpas, client.InNamespace("ns1")))
+		assert.Empty(t, vpas.Items)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_LegacyProfileKeys(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (BaseReconciler, client.Client, *events.FakeRecorder) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:        "vpa/profile",
+				LegacyProfileKeys: []string{"legacy/profile"},
+				ManagedLabel:      "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1": {Spec: config.ProfileSpec{}},
+					"p2": {Spec: config.ProfileSpec{}},
+				},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, kubeClient, recorder
+	}
+
+	newDeployment := func(annotations map[string]string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(annotations)
+		return dep
+	}
+
+	listProfiles := func(t *testing.T, kubeClient client.Client) []string {
+		t.Helper()
+		vpas := &unstructured.UnstructuredList{}
+		vpas.SetGroupVersionKind(vpaListGVK)
+		require.NoError(t, kubeClient.List(context.Background(), vpas, client.InNamespace("ns1")))
+
+		var profiles []string
+		for _, vpa := range vpas.Items {
+			profiles = append(profiles, vpa.GetLabels()["vpa/profile"])
+		}
+		return profiles
+	}
+
+	t.Run("Legacy key alone opts in and labels with the primary key", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(map[string]string{"legacy/profile": "p2"})
+		reconciler, kubeClient, _ := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p2"}, listProfiles(t, kubeClient))
+	})
+
+	t.Run("Primary key takes priority over the legacy key", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(map[string]string{"vpa/profile": "p1", "legacy/profile": "p2"})
+		reconciler, kubeClient, _ := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p1"}, listProfiles(t, kubeClient))
+	})
+
+	t.Run("Migrating keys switches to the primary profile", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		dep := newDeployment(map[string]string{"legacy/profile": "p2"})
+		reconciler, kubeClient, _ := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p2"}, listProfiles(t, kubeClient))
+
+		// Adding the new key with the same profile keeps the VPA.
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p2", "legacy/profile": "p2"})
+		_, err = reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p2"}, listProfiles(t, kubeClient))
+
+		// The new key wins once it names another profile.
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1", "legacy/profile": "p2"})
+		_, err = reconciler.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p1"}, listProfiles(t, kubeClient))
+	})
+
+	t.Run("Missing annotation lists all keys", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(nil)
+		reconciler, kubeClient, recorder := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Empty(t, listProfiles(t, kubeClient))
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, `Warning ProfileAnnotationMissing Annotation "vpa/profile,legacy/profile" missing; skipping VPA`, <-recorder.Events)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_CreateOnly(t *testing.T) {
+	t.Parallel()
+
+	profileWithMode := func(mode vpaautoscaling.UpdateMode) config.Profile {
+		return config.Profile{Spec: config.ProfileSpec{
+			UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{UpdateMode: &mode},
+		}}
+	}
+
+	newReconciler := func(t *testing.T, promReg *prometheus.Registry, objs ...client.Object) (*BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1": profileWithMode(vpaautoscaling.UpdateModeOff),
+					"p2": profileWithMode(vpaautoscaling.UpdateModeOff),
+				},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			CreateOnly: true,
+		}, kubeClient
+	}
+
+	newDeployment := func(profile string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		if profile != "" {
+			dep.SetAnnotations(map[string]string{"vpa/profile": profile})
+		}
+		return dep
+	}
+
+	getVPA := func(t *testing.T, c client.Client, profile string) (*unstructured.Unstructured, error) {
+		t.Helper()
+		vpa := newVPAObject()
+		err := c.Get(context.Background(), types.NamespacedName{
+			Name:      renderDeploymentVPAName(t, "ns1", "demo", profile),
+			Namespace: "ns1",
+		}, vpa)
+		return vpa, err
+	}
+
+	t.Run("Creates a missing VPA", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1")
+		reconciler, kubeClient := newReconciler(t, prometheus.NewRegistry(), dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		_, err = getVPA(t, kubeClient, "p1")
+		require.NoError(t, err)
+	})
+
+	t.Run("Does not update an existing VPA", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1")
+		promReg := prometheus.NewRegistry()
+		reconciler, kubeClient := newReconciler(t, promReg, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		reconciler.Profiles.Entries = map[string]config.Profile{"p1": profileWithMode(vpaautoscaling.UpdateModeInitial)}
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa, err := getVPA(t, kubeClient, "p1")
+		require.NoError(t, err)
+		mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+		assert.Equal(t, string(vpaautoscaling.UpdateModeOff), mode)
+
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"reason":    vpaSkipReasonUpdateDisabled,
+		}))
+	})
+
+	t.Run("Still deletes obsolete VPAs", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1")
+		reconciler, kubeClient := newReconciler(t, prometheus.NewRegistry(), dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p2"})
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		_, err = getVPA(t, kubeClient, "p1")
+		assert.True(t, apierrors.IsNotFound(err))
+		_, err = getVPA(t, kubeClient, "p2")
+		require.NoError(t, err)
+	})
+
+	t.Run("Still deletes VPAs on opt-out", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1")
+		reconciler, kubeClient := newReconciler(t, prometheus.NewRegistry(), dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		dep.SetAnnotations(nil)
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		_, err = getVPA(t, kubeClient, "p1")
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_ApplyNoop(t *testing.T) {
+	t.Parallel()
+
+	profileWithMode := func(mode vpaautoscaling.UpdateMode) config.Profile {
+		return config.Profile{Spec: config.ProfileSpec{
+			UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{UpdateMode: &mode},
+		}}
+	}
+
+	// newReconciler returns a reconciler whose applies after the first (the
+	// creation) either reach the fake API server or, with noop, only read back
+	// the live VPA, like an apply the API server accepts without changes.
+	newReconciler := func(t *testing.T, promReg *prometheus.Registry, noop bool, objs ...client.Object) (*BaseReconciler, *events.FakeRecorder) {
+		t.Helper()
+		applies := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				applies++
+				if noop && applies > 1 {
+					return c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": profileWithMode(vpaautoscaling.UpdateModeOff)},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, recorder
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		return dep
+	}
+
+	// reconcileChanged creates the VPA, then changes the profile and reconciles again.
+	reconcileChanged := func(t *testing.T, reconciler *BaseReconciler, recorder *events.FakeRecorder, dep *appsv1.Deployment) {
+		t.Helper()
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		require.Len(t, recorder.Events, 1)
+		<-recorder.Events
+
+		reconciler.Profiles.Entries = map[string]config.Profile{"p1": profileWithMode(vpaautoscaling.UpdateModeInitial)}
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+	}
+
+	t.Run("Does not count an apply that changes nothing", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		promReg := prometheus.NewRegistry()
+		reconciler, recorder := newReconciler(t, promReg, true, dep)
+		reconcileChanged(t, reconciler, recorder, dep)
+
+		assert.Empty(t, recorder.Events, "no VPAUpdated event expected")
+		mfs, err := promReg.Gather()
+		require.NoError(t, err)
+		for _, mf := range mfs {
+			assert.NotEqual(t, "autovpa_vpa_updated_total", mf.GetName())
+		}
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_apply_noop_total", map[string]string{
+			"namespace": "ns1",
+			"kind":      "Deployment",
+		}))
+	})
+
+	t.Run("Counts an apply that changes the VPA", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		promReg := prometheus.NewRegistry()
+		reconciler, recorder := newReconciler(t, promReg, false, dep)
+		reconcileChanged(t, reconciler, recorder, dep)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Normal VPAUpdated")
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_updated_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"profile":   "p1",
+		}))
+		mfs, err := promReg.Gather()
+		require.NoError(t, err)
+		for _, mf := range mfs {
+			assert.NotEqual(t, "autovpa_vpa_apply_noop_total", mf.GetName())
+		}
+	})
+}
+
+func TestBaseReconciler_buildDesiredVPA(t *testing.T) {
+	t.Parallel()
+
+	scheme := newScheme(t)
+	logger := logr.Discard()
+	br := BaseReconciler{
+		KubeClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Logger:     &logger,
+		Meta: MetaConfig{
+			ProfileKey:   "vpa/profile",
+			ManagedLabel: "vpa/managed",
+		},
+		Profiles: ProfileConfig{
+			NameTemplate: flag.DefaultNameTemplate,
+		},
+	}
+
+	dep := &appsv1.Deployment{}
+	dep.SetNamespace("ns1")
+	dep.SetName("demo")
+
+	profile := config.Profile{
+		Spec: config.ProfileSpec{},
+	}
+
+	targetGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+	desired, err := br.buildDesiredVPA(context.Background(), dep, targetGVK, "p1", "p1", profile, nil)
+	require.NoError(t, err)
+
+	expectedName := renderDeploymentVPAName(t, "ns1", "demo", "p1")
+	assert.Equal(t, expectedName, desired.Name)
+	assert.Equal(t, "p1", desired.Profile)
+
+	assert.Equal(t, map[string]string{
+		"vpa/managed": "true",
+		"vpa/profile": "p1",
+	}, desired.Labels)
+
+	spec := desired.Spec
+	targetRef, ok := spec["targetRef"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "demo", targetRef["name"])
+	assert.Equal(t, "Deployment", targetRef["kind"])
+}
+
+func TestBaseReconciler_buildDesiredVPA_TemplatedManagedLabel(t *testing.T) {
+	t.Parallel()
+
+	logger := logr.Discard()
+	newReconciler := func(t *testing.T, value string) BaseReconciler {
+		t.Helper()
+		return BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).Build(),
+			Logger:     &logger,
+			Meta: MetaConfig{
+				ProfileKey:        "vpa/profile",
+				ManagedLabel:      "vpa/managed",
+				ManagedLabelValue: value,
+			},
+			Profiles: ProfileConfig{NameTemplate: flag.DefaultNameTemplate},
+		}
+	}
+
+	dep := &appsv1.Deployment{}
+	dep.SetNamespace("ns1")
+	dep.SetName("demo")
+	dep.SetLabels(map[string]string{"team": "payments"})
+
+	targetGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+	t.Run("Renders the value from workload labels", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, `{{ index .Labels "team" }}-{{ .Profile }}`)
+		desired, err := br.buildDesiredVPA(context.Background(), dep, targetGVK, "p1", "p1", config.Profile{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"vpa/managed": "payments-p1",
+			"vpa/profile": "p1",
+		}, desired.Labels)
+	})
+
+	t.Run("Uses a static value as is", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, "yes")
+		desired, err := br.buildDesiredVPA(context.Background(), dep, targetGVK, "p1", "p1", config.Profile{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "yes", desired.Labels["vpa/managed"])
+	})
+
+	t.Run("Fails on an invalid rendered value", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, `{{ .Namespace }}/{{ index .Labels "team" }}`)
+		_, err := br.buildDesiredVPA(context.Background(), dep, targetGVK, "p1", "p1", config.Profile{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `render managed label value: rendered label value "ns1/payments" is invalid`)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_KindDefaults(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) *BaseReconciler {
+		t.Helper()
+		logger := logr.Discard()
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:        "vpa/profile",
+				ManagedLabel:      "vpa/managed",
+				EmptyMeansDefault: true,
+			},
+			Profiles: ProfileConfig{
+				Default: "default",
+				Entries: map[string]config.Profile{
+					"default": {},
+					"observe": {},
+				},
+				KindDefaults: map[string]string{"DaemonSet": "observe"},
+				NameTemplate: "{{ .WorkloadName }}-{{ .Profile }}-vpa",
+			},
+		}
+	}
+	getVPA := func(t *testing.T, r *BaseReconciler, name string) error {
+		t.Helper()
+		return r.KubeClient.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: name}, newVPAObject())
+	}
+
+	t.Run("DaemonSet requesting default gets the kind default", func(t *testing.T) {
+		t.Parallel()
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "agent",
+			Annotations: map[string]string{"vpa/profile": "default"},
+		}}
+		r := newReconciler(t, ds)
+
+		_, err := r.ReconcileWorkload(context.Background(), ds, DaemonSetGVK)
+		require.NoError(t, err)
+
+		require.NoError(t, getVPA(t, r, "agent-observe-vpa"))
+		assert.True(t, apierrors.IsNotFound(getVPA(t, r, "agent-default-vpa")))
+	})
+
+	t.Run("DaemonSet with an empty annotation gets the kind default", func(t *testing.T) {
+		t.Parallel()
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "agent",
+			Annotations: map[string]string{"vpa/profile": ""},
+		}}
+		r := newReconciler(t, ds)
+
+		_, err := r.ReconcileWorkload(context.Background(), ds, DaemonSetGVK)
+		require.NoError(t, err)
+
+		require.NoError(t, getVPA(t, r, "agent-observe-vpa"))
+	})
+
+	t.Run("Name template can use the requested profile", func(t *testing.T) {
+		t.Parallel()
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "agent",
+			Annotations: map[string]string{"vpa/profile": "default"},
+		}}
+		r := newReconciler(t, ds)
+		r.Profiles.NameTemplate = "{{ .WorkloadName }}-{{ .RequestedProfile }}-vpa"
+
+		_, err := r.ReconcileWorkload(context.Background(), ds, DaemonSetGVK)
+		require.NoError(t, err)
+
+		// The name keeps the literal "default" while the VPA uses the kind default.
+		vpa := newVPAObject()
+		require.NoError(t, r.KubeClient.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "agent-default-vpa"}, vpa))
+		assert.Equal(t, "observe", vpa.GetLabels()["vpa/profile"])
+	})
+
+	t.Run("Deployment requesting default keeps the global default", func(t *testing.T) {
+		t.Parallel()
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			Annotations: map[string]string{"vpa/profile": "default"},
+		}}
+		r := newReconciler(t, dep)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		require.NoError(t, getVPA(t, r, "demo-default-vpa"))
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_KindRestrictions(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (*BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+		promReg := prometheus.NewRegistry()
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Default: "default",
+				Entries: map[string]config.Profile{
+					"default":    {},
+					"aggressive": {},
+				},
+				KindRestrictions: map[string][]string{"aggressive": {"Deployment", "StatefulSet"}},
+				NameTemplate:     "{{ .WorkloadName }}-{{ .Profile }}-vpa",
+			},
+		}, recorder, promReg
+	}
+	getVPA := func(t *testing.T, r *BaseReconciler, name string) error {
+		t.Helper()
+		return r.KubeClient.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: name}, newVPAObject())
+	}
+
+	t.Run("Skips a restricted profile for other kinds", func(t *testing.T) {
+		t.Parallel()
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "agent",
+			Annotations: map[string]string{"vpa/profile": "aggressive"},
+		}}
+		r, rec, promReg := newReconciler(t, ds)
+
+		_, err := r.ReconcileWorkload(context.Background(), ds, DaemonSetGVK)
+		require.NoError(t, err)
+
+		assert.True(t, apierrors.IsNotFound(getVPA(t, r, "agent-aggressive-vpa")))
+		require.Len(t, rec.Events, 1)
+		assert.Equal(t, `Warning ProfileKindNotAllowed Profile "aggressive" is not allowed for DaemonSet workloads`, <-rec.Events)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "agent",
+			"kind":      "DaemonSet",
+			"reason":    vpaSkipReasonProfileKindNotAllowed,
+		}))
+	})
+
+	t.Run("Allows a restricted profile for listed kinds", func(t *testing.T) {
+		t.Parallel()
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			Annotations: map[string]string{"vpa/profile": "aggressive"},
+		}}
+		r, _, _ := newReconciler(t, dep)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		require.NoError(t, getVPA(t, r, "demo-aggressive-vpa"))
+	})
+
+	t.Run("Allows unrestricted profiles for every kind", func(t *testing.T) {
+		t.Parallel()
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "agent",
+			Annotations: map[string]string{"vpa/profile": "default"},
+		}}
+		r, _, _ := newReconciler(t, ds)
+
+		_, err := r.ReconcileWorkload(context.Background(), ds, DaemonSetGVK)
+		require.NoError(t, err)
+
+		require.NoError(t, getVPA(t, r, "agent-default-vpa"))
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_ForceUpdateModeOff(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (*BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"recreate": {Spec: config.ProfileSpec{
+						UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+							UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeRecreate),
+						},
+					}},
+					"unset": {},
+				},
+				Default:            "unset",
+				NameTemplate:       flag.DefaultNameTemplate,
+				DefaultUpdateMode:  vpaautoscaling.UpdateModeInitial,
+				ForceUpdateModeOff: true,
+			},
+		}, kubeClient
+	}
+
+	for _, profile := range []string{"recreate", "unset"} {
+		t.Run("Forces Off for profile "+profile, func(t *testing.T) {
+			t.Parallel()
+
+			dep := &appsv1.Deployment{}
+			dep.SetNamespace("ns1")
+			dep.SetName("demo")
+			dep.SetAnnotations(map[string]string{"vpa/profile": profile})
+			reconciler, kubeClient := newReconciler(t, dep)
+
+			_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+			require.NoError(t, err)
+
+			vpa := newVPAObject()
+			key := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", profile), Namespace: "ns1"}
+			require.NoError(t, kubeClient.Get(context.Background(), key, vpa))
+			mode, found, err := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, string(vpaautoscaling.UpdateModeOff), mode)
+		})
+	}
+
+	t.Run("Does not mutate the shared profile", func(t *testing.T) {
+		t.Parallel()
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		reconciler, _ := newReconciler(t)
+
+		_, err := reconciler.buildDesiredVPA(context.Background(), dep, DeploymentGVK, "recreate", "recreate", reconciler.Profiles.Entries["recreate"], nil)
+		require.NoError(t, err)
+		assert.Equal(t, vpaautoscaling.UpdateModeRecreate, *reconciler.Profiles.Entries["recreate"].Spec.UpdatePolicy.UpdateMode)
+	})
+}
+
+func TestBaseReconciler_buildDesiredVPA_DowngradeInPlace(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, downgrade bool) *BaseReconciler {
+		t.Helper()
+		logger := logr.Discard()
+
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).Build(),
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"inplace": {Spec: config.ProfileSpec{
+						UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+							UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeInPlaceOrRecreate),
+						},
+					}},
+					"initial": {Spec: config.ProfileSpec{
+						UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+							UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeInitial),
+						},
+					}},
+					"unset": {},
+				},
+				Default:           "unset",
+				NameTemplate:      flag.DefaultNameTemplate,
+				DefaultUpdateMode: vpaautoscaling.UpdateModeInPlaceOrRecreate,
+				DowngradeInPlace:  downgrade,
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		profile   string
+		downgrade bool
+		want      vpaautoscaling.UpdateMode
+	}{
+		{name: "Downgrades a profile update mode", profile: "inplace", downgrade: true, want: vpaautoscaling.UpdateModeRecreate},
+		{name: "Downgrades the default update mode", profile: "unset", downgrade: true, want: vpaautoscaling.UpdateModeRecreate},
+		{name: "Keeps other update modes", profile: "initial", downgrade: true, want: vpaautoscaling.UpdateModeInitial},
+		{name: "Keeps InPlaceOrRecreate without downgrade", profile: "inplace", downgrade: false, want: vpaautoscaling.UpdateModeInPlaceOrRecreate},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dep := &appsv1.Deployment{}
+			dep.SetNamespace("ns1")
+			dep.SetName("demo")
+			reconciler := newReconciler(t, tc.downgrade)
+
+			desired, err := reconciler.buildDesiredVPA(context.Background(), dep, DeploymentGVK, tc.profile, tc.profile, reconciler.Profiles.Entries[tc.profile], nil)
+			require.NoError(t, err)
+			mode, _, err := unstructured.NestedString(desired.Spec, "updatePolicy", "updateMode")
+			require.NoError(t, err)
+			assert.Equal(t, string(tc.want), mode)
+		})
+	}
+
+	t.Run("Does not mutate the shared profile", func(t *testing.T) {
+		t.Parallel()
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		reconciler := newReconciler(t, true)
+
+		_, err := reconciler.buildDesiredVPA(context.Background(), dep, DeploymentGVK, "inplace", "inplace", reconciler.Profiles.Entries["inplace"], nil)
+		require.NoError(t, err)
+		assert.Equal(t, vpaautoscaling.UpdateModeInPlaceOrRecreate, *reconciler.Profiles.Entries["inplace"].Spec.UpdatePolicy.UpdateMode)
+	})
+
+	t.Run("Downgrades an inline update mode override", func(t *testing.T) {
+		t.Parallel()
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		_, inline, err := parseProfileEntry("initial;updateMode=InPlaceOrRecreate")
+		require.NoError(t, err)
+
+		for downgrade, want := range map[bool]vpaautoscaling.UpdateMode{
+			true:  vpaautoscaling.UpdateModeRecreate,
+			false: vpaautoscaling.UpdateModeInPlaceOrRecreate,
+		} {
+			reconciler := newReconciler(t, downgrade)
+			desired, err := reconciler.buildDesiredVPA(context.Background(), dep, DeploymentGVK, "initial", "initial", reconciler.Profiles.Entries["initial"], inline)
+			require.NoError(t, err)
+			mode, _, err := unstructured.NestedString(desired.Spec, "updatePolicy", "updateMode")
+			require.NoError(t, err)
+			assert.Equal(t, string(want), mode, "downgrade=%t", downgrade)
+		}
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_TemplatedManagedLabel(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (*BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:        "vpa/profile",
+				ManagedLabel:      "vpa/managed",
+				ManagedLabelValue: `{{ index .Labels "team" }}`,
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1": {},
+					"p2": {},
+				},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, kubeClient
+	}
+
+	newDeployment := func(profile, team string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": profile})
+		dep.SetLabels(map[string]string{"team": team})
+		return dep
+	}
+
+	listVPAs := func(t *testing.T, c client.Client) []unstructured.Unstructured {
+		t.Helper()
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(vpaListGVK)
+		require.NoError(t, c.List(context.Background(), list, client.InNamespace("ns1")))
+		return list.Items
+	}
+
+	t.Run("Labels the VPA with the rendered value", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1", "payments")
+		reconciler, kubeClient := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpas := listVPAs(t, kubeClient)
+		require.Len(t, vpas, 1)
+		assert.Equal(t, "payments", vpas[0].GetLabels()["vpa/managed"])
+	})
+
+	t.Run("Updates the value when the workload label changes", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1", "payments")
+		reconciler, kubeClient := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		dep.SetLabels(map[string]string{"team": "search"})
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpas := listVPAs(t, kubeClient)
+		require.Len(t, vpas, 1)
+		assert.Equal(t, "search", vpas[0].GetLabels()["vpa/managed"])
+	})
+
+	t.Run("Deletes obsolete VPAs regardless of their value", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1", "payments")
+		reconciler, kubeClient := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		dep = newDeployment("p2", "search")
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpas := listVPAs(t, kubeClient)
+		require.Len(t, vpas, 1)
+		assert.Equal(t, renderDeploymentVPAName(t, "ns1", "demo", "p2"), vpas[0].GetName())
+		assert.Equal(t, "search", vpas[0].GetLabels()["vpa/managed"])
+	})
+}
+
+func TestBaseReconciler_buildDesiredVPA_ContainerResources(t *testing.T) {
+	t.Parallel()
+
+	logger := logr.Discard()
+	br := BaseReconciler{
+		KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).Build(),
+		Logger:     &logger,
+		Meta: MetaConfig{
+			ProfileKey:   "vpa/profile",
+			ManagedLabel: "vpa/managed",
+		},
+		Profiles: ProfileConfig{
+			NameTemplate: flag.DefaultNameTemplate,
+		},
+	}
+
+	dep := &appsv1.Deployment{}
+	dep.SetNamespace("ns1")
+	dep.SetName("demo")
+	dep.Spec.Template.Spec.Containers = []corev1.Container{
+		{Name: "app", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("50m"),
+		}}},
+		{Name: "sidecar", Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		}}},
+	}
+
+	profile := config.Profile{
+		Spec: config.ProfileSpec{
+			ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+				ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+					ContainerName: vpaautoscaling.DefaultContainerResourcePolicy,
+					MinAllowed:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20m")},
+				}},
+			},
+		},
+	}
+
+	desired, err := br.buildDesiredVPA(context.Background(), dep, DeploymentGVK, "p1", "p1", profile, nil)
+	require.NoError(t, err)
+
+	// The wildcard policy applies to every container of the target; the
+	// workload's own requests and limits must not leak into the VPA spec.
+	assert.Equal(t, map[string]any{
+		"containerPolicies": []any{
+			map[string]any{
+				"containerName": "*",
+				"minAllowed":    map[string]any{"cpu": "20m"},
+			},
+		},
+	}, desired.Spec["resourcePolicy"])
+}
+
+func TestBaseReconciler_fetchExistingVPA(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	scheme := newScheme(t)
+	logger := logr.Discard()
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	br := BaseReconciler{
+		KubeClient: client,
+		Logger:     &logger,
+	}
+
+	obj, err := br.fetchExistingVPA(ctx, types.NamespacedName{Name: "missing", Namespace: "ns1"})
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+
+	existing := newVPAObject()
+	existing.SetNamespace("ns1")
+	existing.SetName("present")
+	existing.Object["spec"] = map[string]any{"foo": "bar"}
+
+	client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+	br.KubeClient = client
+
+	obj, err = br.fetchExistingVPA(ctx, types.NamespacedName{Name: "present", Namespace: "ns1"})
+	require.NoError(t, err)
+	require.NotNil(t, obj)
+	assert.Equal(t, "present", obj.GetName())
+}
+
+func TestBaseReconciler_mergeVPA(t *testing.T) {
+	t.Parallel()
+
+	scheme := newScheme(t)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	logger := logr.Discard()
+
+	br := BaseReconciler{
+		KubeClient: client,
+		Logger:     &logger,
+	}
+
+	existing := newVPAObject()
+	existing.SetNamespace("ns1")
 	existing.SetName("demo-vpa")
 	existing.SetLabels(map[string]string{
 		"keep":     "yes",
@@ -627,14 +2227,100 @@ func TestBaseReconciler_mergeVPA(t *testing.T) {
 	assert.Equal(t, "true", gotLabels["vpa/managed"])
 	assert.Equal(t, "p1", gotLabels["vpa/profile"])
 
-	gotSpec := updated.Object["spec"].(map[string]any)
-	assert.Equal(t, "bar", gotSpec["foo"])
+	gotSpec := updated.Object["spec"].(map[string]any)
+	assert.Equal(t, "bar", gotSpec["foo"])
+
+	owners := updated.GetOwnerReferences()
+	require.Len(t, owners, 1)
+	assert.Equal(t, "demo", owners[0].Name)
+	require.NotNil(t, owners[0].Controller)
+	assert.True(t, *owners[0].Controller)
+}
+
+func TestBaseReconciler_mergeVPA_SpecAuthoritative(t *testing.T) {
+	t.Parallel()
+
+	scheme := newScheme(t)
+	logger := logr.Discard()
+	br := BaseReconciler{
+		KubeClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Logger:     &logger,
+	}
+
+	owner := &appsv1.Deployment{}
+	owner.SetNamespace("ns1")
+	owner.SetName("demo")
+	owner.SetUID("uid1")
+
+	desired := desiredVPAState{
+		Name:    "demo-vpa",
+		Profile: "p1",
+		Labels:  map[string]string{"vpa/managed": "true", "vpa/profile": "p1"},
+		Spec:    map[string]any{"targetRef": map[string]any{"name": "demo"}, "foo": "operator"},
+	}
+
+	t.Run("Preserves existing spec when annotated", func(t *testing.T) {
+		t.Parallel()
+		existing := newVPAObject()
+		existing.SetNamespace("ns1")
+		existing.SetName("demo-vpa")
+		existing.SetAnnotations(map[string]string{SpecAuthoritativeAnnotation: "true"})
+		existing.Object["spec"] = map[string]any{"targetRef": map[string]any{"name": "demo"}, "foo": "manual"}
+
+		updated, err := br.mergeVPA(existing, desired, owner)
+		require.NoError(t, err)
+
+		assert.Equal(t, "manual", updated.Object["spec"].(map[string]any)["foo"])
+		assert.Equal(t, "true", updated.GetLabels()["vpa/managed"])
+		assert.Equal(t, "p1", updated.GetLabels()["vpa/profile"])
+		require.Len(t, updated.GetOwnerReferences(), 1)
+		assert.Equal(t, "demo", updated.GetOwnerReferences()[0].Name)
+	})
+
+	t.Run("Uses desired spec when annotation is not true", func(t *testing.T) {
+		t.Parallel()
+		existing := newVPAObject()
+		existing.SetNamespace("ns1")
+		existing.SetName("demo-vpa")
+		existing.SetAnnotations(map[string]string{SpecAuthoritativeAnnotation: "false"})
+		existing.Object["spec"] = map[string]any{"targetRef": map[string]any{"name": "demo"}, "foo": "manual"}
+
+		updated, err := br.mergeVPA(existing, desired, owner)
+		require.NoError(t, err)
+
+		assert.Equal(t, "operator", updated.Object["spec"].(map[string]any)["foo"])
+	})
+}
+
+func TestBaseReconciler_mergeVPA_TrackingAnnotations(t *testing.T) {
+	t.Parallel()
+
+	scheme := newScheme(t)
+	logger := logr.Discard()
+	br := BaseReconciler{
+		KubeClient: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Logger:     &logger,
+		Meta:       MetaConfig{TrackingAnnotations: []string{"a", "b"}},
+	}
+
+	existing := newVPAObject()
+	existing.SetNamespace("ns1")
+	existing.SetName("demo-vpa")
+	existing.SetAnnotations(map[string]string{"a": "old", "b": "gone", "other": "keep"})
+
+	owner := &appsv1.Deployment{}
+	owner.SetNamespace("ns1")
+	owner.SetName("demo")
+	owner.SetUID("uid1")
 
-	owners := updated.GetOwnerReferences()
-	require.Len(t, owners, 1)
-	assert.Equal(t, "demo", owners[0].Name)
-	require.NotNil(t, owners[0].Controller)
-	assert.True(t, *owners[0].Controller)
+	updated, err := br.mergeVPA(existing, desiredVPAState{
+		Name:        "demo-vpa",
+		Annotations: map[string]string{"a": "new"},
+	}, owner)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"a": "new", "other": "keep"}, updated.GetAnnotations())
+	assert.True(t, vpaNeedsUpdate(existing, updated))
 }
 
 func TestBaseReconciler_applyVPA(t *testing.T) {
@@ -654,6 +2340,7 @@ func TestBaseReconciler_applyVPA(t *testing.T) {
 	br := BaseReconciler{
 		KubeClient: client,
 		Logger:     &logger,
+		Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
 	}
 
 	toApply := existing.DeepCopy()
@@ -675,6 +2362,196 @@ func TestBaseReconciler_applyVPA(t *testing.T) {
 	assert.Equal(t, "new", spec["field"])
 }
 
+func TestBaseReconciler_applyVPA_Conflict(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := logr.Discard()
+
+	var forced []bool
+	kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			po := &client.PatchOptions{}
+			po.ApplyOptions(opts)
+			forced = append(forced, po.Force != nil && *po.Force)
+			if len(forced) == 1 {
+				return apierrors.NewConflict(
+					schema.GroupResource{Group: "autoscaling.k8s.io", Resource: "verticalpodautoscalers"},
+					obj.GetName(),
+					errors.New(`conflict with "kubectl": .spec.updatePolicy.updateMode`),
+				)
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+
+	promReg := prometheus.NewRegistry()
+	br := BaseReconciler{
+		KubeClient: kubeClient,
+		Logger:     &logger,
+		Metrics:    internalmetrics.NewRegistry(promReg),
+	}
+
+	vpa := newVPAObject()
+	vpa.SetNamespace("ns1")
+	vpa.SetName("demo-vpa")
+	vpa.Object["spec"] = map[string]any{
+		"targetRef": map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "name": "demo"},
+	}
+
+	require.NoError(t, br.applyVPA(ctx, vpa))
+	assert.Equal(t, []bool{false, true}, forced, "expected a plain apply followed by a forced retry")
+
+	got := newVPAObject()
+	require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: "demo-vpa", Namespace: "ns1"}, got))
+
+	assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_apply_conflicts_total", map[string]string{
+		"namespace": "ns1",
+		"kind":      "Deployment",
+	}))
+}
+
+func TestBaseReconciler_applyVPA_FieldManager(t *testing.T) {
+	t.Parallel()
+
+	apply := func(t *testing.T, fieldManager string) []string {
+		t.Helper()
+		logger := logr.Discard()
+
+		var managers []string
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				po := &client.PatchOptions{}
+				po.ApplyOptions(opts)
+				managers = append(managers, po.FieldManager)
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+		br := BaseReconciler{
+			KubeClient:   kubeClient,
+			Logger:       &logger,
+			Metrics:      internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			FieldManager: fieldManager,
+		}
+
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName("demo-vpa")
+		vpa.Object["spec"] = map[string]any{"field": "new"}
+		require.NoError(t, br.applyVPA(context.Background(), vpa))
+		return managers
+	}
+
+	t.Run("Uses the configured field manager", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"autovpa-team-a"}, apply(t, "autovpa-team-a"))
+	})
+
+	t.Run("Defaults to autovpa", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"autovpa"}, apply(t, ""))
+	})
+}
+
+func TestBaseReconciler_applyVPA_ManagedFieldsRetry(t *testing.T) {
+	t.Parallel()
+
+	newVPA := func() *unstructured.Unstructured {
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName("demo-vpa")
+		vpa.Object["spec"] = map[string]any{
+			"targetRef": map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "name": "demo"},
+		}
+		return vpa
+	}
+
+	t.Run("Resets managedFields and retries once", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		logger := logr.Discard()
+
+		var patchTypes []types.PatchType
+		applies := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(newVPA()).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patchTypes = append(patchTypes, patch.Type())
+				if patch.Type() != types.ApplyPatchType {
+					return nil
+				}
+				applies++
+				if applies == 1 {
+					return newManagedFieldsError()
+				}
+				// Force so the seeded object does not trigger a field manager conflict.
+				return c.Patch(ctx, obj, patch, append(opts, client.ForceOwnership)...)
+			},
+		}).Build()
+
+		br := BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		}
+
+		require.NoError(t, br.applyVPA(ctx, newVPA()))
+		assert.Equal(t, []types.PatchType{types.ApplyPatchType, types.MergePatchType, types.ApplyPatchType}, patchTypes)
+	})
+
+	t.Run("Does not retry other errors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		logger := logr.Discard()
+
+		calls := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				calls++
+				return errors.New("boom")
+			},
+		}).Build()
+
+		br := BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		}
+
+		err := br.applyVPA(ctx, newVPA())
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Retry failure is returned", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		logger := logr.Discard()
+
+		calls := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				calls++
+				return newManagedFieldsError()
+			},
+		}).Build()
+
+		br := BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		}
+
+		err := br.applyVPA(ctx, newVPA())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "managedFields")
+		assert.Equal(t, 2, calls, "expected exactly one retry; the VPA does not exist so no reset is sent")
+	})
+}
+
 func TestBaseReconciler_createVPA(t *testing.T) {
 	t.Parallel()
 
@@ -693,13 +2570,11 @@ func TestBaseReconciler_createVPA(t *testing.T) {
 	owner.SetName("demo")
 	owner.SetUID("uid1")
 
-	err := br.createVPA(
-		ctx,
-		owner,
-		"demo-vpa",
-		map[string]string{"vpa/managed": "true"},
-		map[string]any{"foo": "bar"},
-	)
+	err := br.createVPA(ctx, owner, desiredVPAState{
+		Name:   "demo-vpa",
+		Labels: map[string]string{"vpa/managed": "true"},
+		Spec:   map[string]any{"foo": "bar"},
+	})
 	require.NoError(t, err)
 
 	got := newVPAObject()
@@ -717,6 +2592,299 @@ func TestBaseReconciler_createVPA(t *testing.T) {
 	assert.Equal(t, "demo", owners[0].Name)
 }
 
+func TestBaseReconciler_Finalizers(t *testing.T) {
+	t.Parallel()
+
+	owner := &appsv1.Deployment{}
+	owner.SetNamespace("ns1")
+	owner.SetName("demo")
+	owner.SetUID("uid1")
+
+	newReconciler := func(t *testing.T, useFinalizers bool, objs ...client.Object) (BaseReconciler, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+		return BaseReconciler{
+			KubeClient:    fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			Logger:        &logger,
+			Recorder:      events.NewFakeRecorder(10),
+			Metrics:       internalmetrics.NewRegistry(promReg),
+			Meta:          MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"},
+			UseFinalizers: useFinalizers,
+		}, promReg
+	}
+
+	t.Run("Adds finalizer on create when enabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		br, _ := newReconciler(t, true)
+
+		require.NoError(t, br.createVPA(ctx, owner, desiredVPAState{Name: "demo-vpa"}))
+
+		got := newVPAObject()
+		require.NoError(t, br.KubeClient.Get(ctx, types.NamespacedName{Name: "demo-vpa", Namespace: "ns1"}, got))
+		assert.Equal(t, []string{ManagedFinalizer}, got.GetFinalizers())
+	})
+
+	t.Run("No finalizer on create when disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		br, _ := newReconciler(t, false)
+
+		require.NoError(t, br.createVPA(ctx, owner, desiredVPAState{Name: "demo-vpa"}))
+
+		got := newVPAObject()
+		require.NoError(t, br.KubeClient.Get(ctx, types.NamespacedName{Name: "demo-vpa", Namespace: "ns1"}, got))
+		assert.Empty(t, got.GetFinalizers())
+	})
+
+	t.Run("Merge adds finalizer to existing VPA", func(t *testing.T) {
+		t.Parallel()
+		br, _ := newReconciler(t, true)
+
+		existing := newVPAObject()
+		existing.SetNamespace("ns1")
+		existing.SetName("demo-vpa")
+
+		merged, err := br.mergeVPA(existing, desiredVPAState{Name: "demo-vpa"}, owner)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ManagedFinalizer}, merged.GetFinalizers())
+	})
+
+	t.Run("Opt-out deletion leaves gauge to the finalizer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName("demo-vpa")
+		vpa.SetLabels(map[string]string{"vpa/managed": "true", "vpa/profile": "p1"})
+		vpa.SetFinalizers([]string{ManagedFinalizer})
+		require.NoError(t, controllerutil.SetControllerReference(owner, vpa, newScheme(t)))
+
+		br, promReg := newReconciler(t, true, vpa)
+		br.Metrics.IncVPAManaged("ns1", "p1")
+
+		require.NoError(t, br.DeleteManagedVPAsForOptOut(ctx, owner, "Deployment"))
+
+		got := newVPAObject()
+		require.NoError(t, br.KubeClient.Get(ctx, client.ObjectKeyFromObject(vpa), got))
+		assert.False(t, got.GetDeletionTimestamp().IsZero())
+		assert.Equal(t, float64(1), gaugeValue(t, promReg, "autovpa_managed_vpa", map[string]string{
+			"namespace": "ns1",
+			"profile":   "p1",
+		}))
+	})
+}
+
+func TestBaseReconciler_DeleteObsoleteManagedVPAs(t *testing.T) {
+	t.Parallel()
+
+	owner := &appsv1.Deployment{}
+	owner.SetNamespace("ns1")
+	owner.SetName("demo")
+	owner.SetUID("uid1")
+
+	newOwnedVPA := func(t *testing.T, name string) *unstructured.Unstructured {
+		t.Helper()
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName(name)
+		vpa.SetLabels(map[string]string{"vpa/managed": "true", "vpa/profile": "p1", "team": "payments"})
+		vpa.SetFinalizers([]string{ManagedFinalizer})
+		require.NoError(t, controllerutil.SetControllerReference(owner, vpa, newScheme(t)))
+		return vpa
+	}
+
+	newReconciler := func(t *testing.T, action string, objs ...client.Object) (BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+		rec := events.NewFakeRecorder(10)
+		return BaseReconciler{
+			KubeClient:     fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			Logger:         &logger,
+			Recorder:       rec,
+			Metrics:        internalmetrics.NewRegistry(promReg),
+			Meta:           MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"},
+			ObsoleteAction: action,
+		}, rec, promReg
+	}
+
+	t.Run("Deletes obsolete VPAs by default", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		obsolete := newOwnedVPA(t, "old-vpa")
+		obsolete.SetFinalizers(nil)
+		kept := newOwnedVPA(t, "new-vpa")
+
+		br, rec, promReg := newReconciler(t, "", obsolete, kept)
+
+		require.NoError(t, br.DeleteObsoleteManagedVPAs(ctx, owner, "Deployment", "new-vpa"))
+
+		err := br.KubeClient.Get(ctx, client.ObjectKeyFromObject(obsolete), newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+		require.NoError(t, br.KubeClient.Get(ctx, client.ObjectKeyFromObject(kept), newVPAObject()))
+
+		assert.Equal(t, "Normal DeletedObsoleteVPA Deleted obsolete VPA old-vpa", <-rec.Events)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_deleted_obsolete_total", map[string]string{
+			"namespace": "ns1",
+			"kind":      "Deployment",
+		}))
+	})
+
+	t.Run("Deletes obsolete VPAs with the delete action", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		obsolete := newOwnedVPA(t, "old-vpa")
+		obsolete.SetFinalizers(nil)
+
+		br, _, _ := newReconciler(t, ObsoleteActionDelete, obsolete)
+
+		require.NoError(t, br.DeleteObsoleteManagedVPAs(ctx, owner, "Deployment"))
+
+		err := br.KubeClient.Get(ctx, client.ObjectKeyFromObject(obsolete), newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("Releases obsolete VPAs with the release action", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		obsolete := newOwnedVPA(t, "old-vpa")
+		kept := newOwnedVPA(t, "new-vpa")
+
+		br, rec, promReg := newReconciler(t, ObsoleteActionRelease, obsolete, kept)
+		br.Metrics.IncVPAManaged("ns1", "p1")
+		br.Metrics.IncVPAManaged("ns1", "p1")
+
+		require.NoError(t, br.DeleteObsoleteManagedVPAs(ctx, owner, "Deployment", "new-vpa"))
+
+		released := newVPAObject()
+		require.NoError(t, br.KubeClient.Get(ctx, client.ObjectKeyFromObject(obsolete), released))
+		assert.Equal(t, map[string]string{"vpa/profile": "p1", "team": "payments"}, released.GetLabels())
+		assert.Empty(t, released.GetOwnerReferences())
+		assert.Empty(t, released.GetFinalizers())
+		assert.True(t, released.GetDeletionTimestamp().IsZero())
+
+		stillManaged := newVPAObject()
+		require.NoError(t, br.KubeClient.Get(ctx, client.ObjectKeyFromObject(kept), stillManaged))
+		assert.Equal(t, "true", stillManaged.GetLabels()["vpa/managed"])
+		assert.Len(t, stillManaged.GetOwnerReferences(), 1)
+
+		assert.Equal(t, "Normal ReleasedObsoleteVPA Released obsolete VPA old-vpa", <-rec.Events)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_released_obsolete_total", map[string]string{
+			"namespace": "ns1",
+			"kind":      "Deployment",
+		}))
+		assert.Equal(t, float64(1), gaugeValue(t, promReg, "autovpa_managed_vpa", map[string]string{
+			"namespace": "ns1",
+			"profile":   "p1",
+		}))
+
+		// The VPAReconciler ignores the released VPA instead of deleting it as an orphan.
+		vr := newTestVPAReconciler(t, released)
+		vr.Meta = MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"}
+		_, err := vr.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(released)})
+		require.NoError(t, err)
+		require.NoError(t, vr.KubeClient.Get(ctx, client.ObjectKeyFromObject(released), newVPAObject()))
+	})
+
+	t.Run("Keeps ownerRefs of other owners when releasing", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		obsolete := newOwnedVPA(t, "old-vpa")
+		obsolete.SetOwnerReferences(append(obsolete.GetOwnerReferences(), metav1.OwnerReference{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       "keep",
+			UID:        "uid2",
+		}))
+
+		br, _, _ := newReconciler(t, ObsoleteActionRelease, obsolete)
+
+		require.NoError(t, br.DeleteObsoleteManagedVPAs(ctx, owner, "Deployment"))
+
+		released := newVPAObject()
+		require.NoError(t, br.KubeClient.Get(ctx, client.ObjectKeyFromObject(obsolete), released))
+		require.Len(t, released.GetOwnerReferences(), 1)
+		assert.Equal(t, "keep", released.GetOwnerReferences()[0].Name)
+	})
+
+	t.Run("Returns release errors", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		obsolete := newOwnedVPA(t, "old-vpa")
+		logger := logr.Discard()
+
+		br := BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(obsolete).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+					return errors.New("boom")
+				},
+			}).Build(),
+			Logger:         &logger,
+			Recorder:       events.NewFakeRecorder(10),
+			Metrics:        internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta:           MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"},
+			ObsoleteAction: ObsoleteActionRelease,
+		}
+
+		err := br.DeleteObsoleteManagedVPAs(ctx, owner, "Deployment")
+		require.Error(t, err)
+		assert.EqualError(t, err, "release obsolete VPA old-vpa: boom")
+	})
+}
+
+func TestBaseReconciler_setControllerReference(t *testing.T) {
+	t.Parallel()
+
+	owner := &appsv1.Deployment{}
+	owner.SetNamespace("ns1")
+	owner.SetName("demo")
+	owner.SetUID("uid1")
+
+	for _, tc := range []struct {
+		name      string
+		disable   bool
+		wantBlock bool
+	}{
+		{name: "Blocks owner deletion by default", disable: false, wantBlock: true},
+		{name: "Does not block owner deletion when disabled", disable: true, wantBlock: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+			logger := logr.Discard()
+			br := BaseReconciler{
+				KubeClient:                kubeClient,
+				Logger:                    &logger,
+				DisableBlockOwnerDeletion: tc.disable,
+			}
+
+			// Created VPA.
+			require.NoError(t, br.createVPA(ctx, owner, desiredVPAState{Name: "demo-vpa"}))
+			created := newVPAObject()
+			require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Name: "demo-vpa", Namespace: "ns1"}, created))
+
+			// Merged VPA.
+			merged, err := br.mergeVPA(created, desiredVPAState{Name: "demo-vpa"}, owner)
+			require.NoError(t, err)
+
+			for _, vpa := range []*unstructured.Unstructured{created, merged} {
+				ref := metav1.GetControllerOf(vpa)
+				require.NotNil(t, ref)
+				assert.Equal(t, "Deployment", ref.Kind)
+				assert.Equal(t, "demo", ref.Name)
+				require.NotNil(t, ref.Controller)
+				assert.True(t, *ref.Controller)
+				require.NotNil(t, ref.BlockOwnerDeletion)
+				assert.Equal(t, tc.wantBlock, *ref.BlockOwnerDeletion)
+			}
+		})
+	}
+}
+
 func TestBaseReconciler_updateVPA(t *testing.T) {
 	t.Parallel()
 
@@ -734,6 +2902,7 @@ func TestBaseReconciler_updateVPA(t *testing.T) {
 	br := BaseReconciler{
 		KubeClient: client,
 		Logger:     &logger,
+		Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
 	}
 
 	updated := existing.DeepCopy()
@@ -792,11 +2961,51 @@ func TestBaseReconciler_listManagedVPAs(t *testing.T) {
 	assert.Equal(t, "vpa-managed-1", list[0].GetName())
 }
 
+func TestBaseReconciler_listManagedVPAs_TemplatedValue(t *testing.T) {
+	t.Parallel()
+
+	newVPA := func(name string, labels map[string]string) *unstructured.Unstructured {
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName(name)
+		vpa.SetLabels(labels)
+		return vpa
+	}
+
+	logger := logr.Discard()
+	br := BaseReconciler{
+		KubeClient: fake.NewClientBuilder().
+			WithScheme(newScheme(t)).
+			WithObjects(
+				newVPA("vpa-payments", map[string]string{"vpa/managed": "payments"}),
+				newVPA("vpa-search", map[string]string{"vpa/managed": "search"}),
+				newVPA("vpa-unmanaged", map[string]string{"other": "label"}),
+			).
+			Build(),
+		Logger: &logger,
+		Meta: MetaConfig{
+			ManagedLabel:      "vpa/managed",
+			ManagedLabelValue: `{{ index .Labels "team" }}`,
+		},
+	}
+
+	list, err := br.listManagedVPAs(context.Background(), "ns1")
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(list))
+	for _, vpa := range list {
+		names = append(names, vpa.GetName())
+	}
+	assert.ElementsMatch(t, []string{"vpa-payments", "vpa-search"}, names)
+}
+
 func newScheme(t *testing.T) *runtime.Scheme {
 	t.Helper()
 	s := runtime.NewScheme()
 	err := appsv1.AddToScheme(s)
 	require.NoError(t, err)
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, autoscalingv2.AddToScheme(s))
 
 	s.AddKnownTypeWithName(vpaGVK, &unstructured.Unstructured{})
 	s.AddKnownTypeWithName(schema.GroupVersionKind{
@@ -823,3 +3032,77 @@ func renderDeploymentVPAName(t *testing.T, namespace, workloadName, profile stri
 	require.NoError(t, err)
 	return vpaName
 }
+
+func TestBaseReconciler_DeleteManagedVPAs_OwnerUID(t *testing.T) {
+	t.Parallel()
+
+	newOwnedVPA := func(uid types.UID) *unstructured.Unstructured {
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName("demo-vpa")
+		vpa.SetLabels(map[string]string{"vpa/managed": "true", "vpa/profile": "p1"})
+		vpa.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "demo",
+			UID:        uid,
+			Controller: ptr.To(true),
+		}})
+		return vpa
+	}
+
+	newReconciler := func(t *testing.T, promReg *prometheus.Registry, objs ...client.Object) (BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta:       MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"},
+		}, kubeClient
+	}
+
+	owner := &appsv1.Deployment{}
+	owner.SetNamespace("ns1")
+	owner.SetName("demo")
+	owner.SetUID("uid-new")
+
+	t.Run("Deletes VPA owned by the same UID", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		br, kubeClient := newReconciler(t, prometheus.NewRegistry(), newOwnedVPA("uid-new"))
+		require.NoError(t, br.DeleteManagedVPAsForOptOut(ctx, owner, "Deployment"))
+
+		err := kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "demo-vpa"}, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("Deletes VPA whose ownerRef has no UID", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		br, kubeClient := newReconciler(t, prometheus.NewRegistry(), newOwnedVPA(""))
+		require.NoError(t, br.DeleteManagedVPAsForOptOut(ctx, owner, "Deployment"))
+
+		err := kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "demo-vpa"}, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("Skips and counts VPA owned by a previous UID", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		promReg := prometheus.NewRegistry()
+		br, kubeClient := newReconciler(t, promReg, newOwnedVPA("uid-old"))
+		require.NoError(t, br.DeleteManagedVPAsForOptOut(ctx, owner, "Deployment"))
+
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "demo-vpa"}, newVPAObject()))
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_owner_uid_mismatch_total", map[string]string{
+			"namespace": "ns1",
+			"kind":      "Deployment",
+		}))
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// circuitOpenRequeueAfter is how long an open circuit pauses VPA applies
+// before the next apply is tried again.
+const circuitOpenRequeueAfter = 5 * time.Minute
+
+// ApplyCircuitBreaker pauses a controller's VPA applies after Threshold
+// consecutive apply failures, so a failing API server is not hammered. While
+// open, reconciles requeue after circuitOpenRequeueAfter without applying;
+// afterwards the next apply is let through, and its success closes the circuit.
+// A nil breaker or a Threshold of 0 never opens. Each controller gets its own.
+type ApplyCircuitBreaker struct {
+	Threshold int // Consecutive apply failures that open the circuit.
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	now       func() time.Time // Overridden in tests; nil uses time.Now.
+}
+
+// openFor returns how long the circuit stays open, or 0 when it is closed.
+func (c *ApplyCircuitBreaker) openFor() time.Duration {
+	if c == nil || c.Threshold <= 0 {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(c.openUntil.Sub(c.clock()), 0)
+}
+
+// record counts an apply outcome. It reports whether a failure opened the
+// circuit and whether a success closed a circuit that had opened.
+func (c *ApplyCircuitBreaker) record(err error) (opened, closed bool) {
+	if c == nil || c.Threshold <= 0 {
+		return false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		closed = c.failures >= c.Threshold
+		c.failures = 0
+		c.openUntil = time.Time{}
+		return false, closed
+	}
+
+	c.failures++
+	if c.failures < c.Threshold {
+		return false, false
+	}
+	c.openUntil = c.clock().Add(circuitOpenRequeueAfter)
+	return true, false
+}
+
+// clock returns the current time.
+func (c *ApplyCircuitBreaker) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// recordApply feeds an apply outcome to the circuit breaker, logging and
+// updating the circuit gauge when the circuit opens or closes.
+// Only transient failures count: a rejected write (e.g. Forbidden, Invalid or
+// a terminating namespace) is specific to one workload and says nothing about
+// the API server's health.
+func (b *BaseReconciler) recordApply(log logr.Logger, kind string, err error) {
+	if err != nil && !isTransientApplyError(err) {
+		return
+	}
+	opened, closed := b.Circuit.record(err)
+	switch {
+	case opened:
+		log.Error(
+			err,
+			"circuit open; pausing VPA applies after consecutive failures",
+			"threshold", b.Circuit.Threshold,
+			"retryAfter", circuitOpenRequeueAfter,
+		)
+		b.Metrics.SetCircuitOpen(kind, true)
+	case closed:
+		log.Info("circuit closed; resuming VPA applies")
+		b.Metrics.SetCircuitOpen(kind, false)
+	}
+}
+
+// isTransientApplyError reports whether err points at an overloaded or
+// unreachable API server: server-side timeouts, throttling, internal errors,
+// an unavailable service, or a transport error without an API status.
+func isTransientApplyError(err error) bool {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return true
+	}
+	return apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err)
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestApplyCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	errApply := errors.New("apply failed")
+
+	newBreaker := func(threshold int) (*ApplyCircuitBreaker, *time.Time) {
+		now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+		return &ApplyCircuitBreaker{Threshold: threshold, now: func() time.Time { return now }}, &now
+	}
+
+	t.Run("Opens after threshold consecutive failures", func(t *testing.T) {
+		t.Parallel()
+		breaker, _ := newBreaker(3)
+
+		for range 2 {
+			opened, _ := breaker.record(errApply)
+			assert.False(t, opened)
+			assert.Zero(t, breaker.openFor())
+		}
+		opened, _ := breaker.record(errApply)
+		assert.True(t, opened)
+		assert.Equal(t, circuitOpenRequeueAfter, breaker.openFor())
+	})
+
+	t.Run("Success resets the failure count", func(t *testing.T) {
+		t.Parallel()
+		breaker, _ := newBreaker(2)
+
+		breaker.record(errApply)
+		_, closed := breaker.record(nil)
+		assert.False(t, closed, "a circuit that never opened is not reported as closed")
+
+		opened, _ := breaker.record(errApply)
+		assert.False(t, opened)
+	})
+
+	t.Run("Lets an apply through after the cooldown", func(t *testing.T) {
+		t.Parallel()
+		breaker, now := newBreaker(1)
+
+		breaker.record(errApply)
+		*now = now.Add(time.Minute)
+		assert.Equal(t, circuitOpenRequeueAfter-time.Minute, breaker.openFor())
+
+		*now = now.Add(circuitOpenRequeueAfter)
+		assert.Zero(t, breaker.openFor())
+
+		opened, _ := breaker.record(errApply)
+		assert.True(t, opened, "a failure after the cooldown reopens the circuit")
+
+		*now = now.Add(circuitOpenRequeueAfter)
+		_, closed := breaker.record(nil)
+		assert.True(t, closed)
+		assert.Zero(t, breaker.openFor())
+	})
+
+	t.Run("Disabled breaker never opens", func(t *testing.T) {
+		t.Parallel()
+		var nilBreaker *ApplyCircuitBreaker
+		opened, closed := nilBreaker.record(errApply)
+		assert.False(t, opened)
+		assert.False(t, closed)
+		assert.Zero(t, nilBreaker.openFor())
+
+		breaker, _ := newBreaker(0)
+		for range 5 {
+			opened, _ := breaker.record(errApply)
+			assert.False(t, opened)
+		}
+		assert.Zero(t, breaker.openFor())
+	})
+}
+
+func TestIsTransientApplyError(t *testing.T) {
+	t.Parallel()
+
+	gr := schema.GroupResource{Group: "autoscaling.k8s.io", Resource: "verticalpodautoscalers"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Transport error", err: errors.New("connection refused"), want: true},
+		{name: "Server timeout", err: apierrors.NewServerTimeout(gr, "patch", 1), want: true},
+		{name: "Timeout", err: apierrors.NewTimeoutError("timed out", 1), want: true},
+		{name: "Too many requests", err: apierrors.NewTooManyRequests("slow down", 1), want: true},
+		{name: "Internal error", err: apierrors.NewInternalError(errors.New("etcd")), want: true},
+		{name: "Service unavailable", err: apierrors.NewServiceUnavailable("down"), want: true},
+		{name: "Forbidden", err: apierrors.NewForbidden(gr, "demo", errors.New("denied")), want: false},
+		{name: "Invalid", err: apierrors.NewInvalid(schema.GroupKind{Group: gr.Group, Kind: "VerticalPodAutoscaler"}, "demo", nil), want: false},
+		{name: "Already exists", err: apierrors.NewAlreadyExists(gr, "demo"), want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, isTransientApplyError(tc.err))
+		})
+	}
+}
+
+func TestBaseReconciler_ReconcileWorkload_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	// newReconciler returns a reconciler whose VPA applies fail with applyErr
+	// while *failing is true, along with the apply count and the clock.
+	newReconciler := func(t *testing.T, applyErr error) (*BaseReconciler, client.Client, *prometheus.Registry, *bool, *int, *time.Time) {
+		t.Helper()
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+
+		now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+		failing := true
+		applies := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				applies++
+				if failing {
+					return applyErr
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+		reconciler := &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			Circuit: &ApplyCircuitBreaker{Threshold: 3, now: func() time.Time { return now }},
+		}
+		return reconciler, kubeClient, promReg, &failing, &applies, &now
+	}
+
+	dep := &appsv1.Deployment{}
+	dep.SetNamespace("ns1")
+	dep.SetName("demo")
+	dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+
+	t.Run("Transient failures open and close the circuit", func(t *testing.T) {
+		t.Parallel()
+
+		reconciler, kubeClient, promReg, failing, applies, now := newReconciler(t, apierrors.NewInternalError(errors.New("etcdserver: request timed out")))
+		circuitOpen := func() float64 {
+			return gaugeValue(t, promReg, "autovpa_circuit_open", map[string]string{"controller": "Deployment"})
+		}
+
+		// Consecutive apply failures surface as errors until the circuit opens.
+		for range 3 {
+			_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+			require.Error(t, err)
+		}
+		assert.Equal(t, 3, *applies)
+		assert.Equal(t, float64(1), circuitOpen())
+
+		// Open circuit: back off without applying.
+		res, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, circuitOpenRequeueAfter, res.RequeueAfter)
+		assert.Equal(t, 3, *applies)
+
+		// After the cooldown the next apply goes through and closes the circuit.
+		*now = now.Add(circuitOpenRequeueAfter)
+		*failing = false
+		res, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Zero(t, res)
+		assert.Equal(t, 4, *applies)
+		assert.Equal(t, float64(0), circuitOpen())
+
+		vpaKey := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}
+		require.NoError(t, kubeClient.Get(context.Background(), vpaKey, newVPAObject()))
+	})
+
+	t.Run("Forbidden applies do not open the circuit", func(t *testing.T) {
+		t.Parallel()
+
+		gr := schema.GroupResource{Group: "autoscaling.k8s.io", Resource: "verticalpodautoscalers"}
+		reconciler, _, _, _, applies, _ := newReconciler(t, apierrors.NewForbidden(gr, "demo", errors.New("denied by policy")))
+
+		for range 5 {
+			_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+			require.Error(t, err)
+			assert.True(t, apierrors.IsForbidden(err))
+		}
+		assert.Equal(t, 5, *applies, "every reconcile still applies")
+		assert.Zero(t, reconciler.Circuit.openFor())
+	})
+}
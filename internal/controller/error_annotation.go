@@ -0,0 +1,63 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncErrorAnnotation writes reconcileErr into the workload's
+// LastErrorAnnotation, or removes the annotation when reconcileErr is nil.
+// The workload is only patched when the annotation changes. Patch failures
+// are logged but do not fail the reconcile, so they never hide reconcileErr.
+func (b *BaseReconciler) syncErrorAnnotation(ctx context.Context, obj client.Object, reconcileErr error) {
+	current, present := obj.GetAnnotations()[LastErrorAnnotation]
+	if reconcileErr == nil && !present {
+		return
+	}
+	if reconcileErr != nil && present && current == reconcileErr.Error() {
+		return
+	}
+
+	patchBase := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := maps.Clone(obj.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if reconcileErr == nil {
+		delete(annotations, LastErrorAnnotation)
+	} else {
+		annotations[LastErrorAnnotation] = reconcileErr.Error()
+	}
+	obj.SetAnnotations(annotations)
+
+	if err := b.KubeClient.Patch(ctx, obj, patchBase); err != nil {
+		if apierrors.IsNotFound(err) {
+			return
+		}
+		b.Logger.Info(
+			"unable to update last-error annotation",
+			"namespace", obj.GetNamespace(),
+			"workload", obj.GetName(),
+			"error", err.Error(),
+		)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestBaseReconciler_ReconcileWorkload_ErrorAnnotations(t *testing.T) {
+	t.Parallel()
+
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			Annotations: map[string]string{"vpa/profile": "p1"},
+		}}
+	}
+
+	newReconciler := func(t *testing.T, enabled bool, kubeClient client.Client) *BaseReconciler {
+		t.Helper()
+		logger := logr.Discard()
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				// Renders an invalid VPA name until the template is fixed.
+				Entries:      map[string]config.Profile{"p1": {NameTemplate: "Invalid_{{ .WorkloadName }}"}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			WriteErrorAnnotations: enabled,
+		}
+	}
+
+	getDeployment := func(t *testing.T, kubeClient client.Client) *appsv1.Deployment {
+		t.Helper()
+		dep := &appsv1.Deployment{}
+		require.NoError(t, kubeClient.Get(context.Background(), client.ObjectKey{Namespace: "ns1", Name: "demo"}, dep))
+		return dep
+	}
+
+	t.Run("Sets the annotation on failure and clears it on success", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(newDeployment()).Build()
+		r := newReconciler(t, true, kubeClient)
+
+		_, err := r.ReconcileWorkload(ctx, getDeployment(t, kubeClient), DeploymentGVK)
+		require.Error(t, err)
+
+		failed := getDeployment(t, kubeClient)
+		assert.Equal(t, err.Error(), failed.GetAnnotations()[LastErrorAnnotation])
+		assert.Equal(t, "p1", failed.GetAnnotations()["vpa/profile"])
+
+		r.Profiles.Entries = map[string]config.Profile{"p1": {}}
+		_, err = r.ReconcileWorkload(ctx, failed, DeploymentGVK)
+		require.NoError(t, err)
+
+		fixed := getDeployment(t, kubeClient)
+		assert.NotContains(t, fixed.GetAnnotations(), LastErrorAnnotation)
+		assert.Equal(t, "p1", fixed.GetAnnotations()["vpa/profile"])
+	})
+
+	t.Run("Does not patch an unchanged error again", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		patches := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(newDeployment()).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patches++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+		r := newReconciler(t, true, kubeClient)
+
+		_, err := r.ReconcileWorkload(ctx, getDeployment(t, kubeClient), DeploymentGVK)
+		require.Error(t, err)
+		_, err = r.ReconcileWorkload(ctx, getDeployment(t, kubeClient), DeploymentGVK)
+		require.Error(t, err)
+
+		assert.Equal(t, 1, patches)
+	})
+
+	t.Run("Leaves workloads without errors untouched", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		patches := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(newDeployment()).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if _, ok := obj.(*appsv1.Deployment); ok {
+					patches++
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+		r := newReconciler(t, true, kubeClient)
+		r.Profiles.Entries = map[string]config.Profile{"p1": {}}
+
+		_, err := r.ReconcileWorkload(ctx, getDeployment(t, kubeClient), DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Zero(t, patches)
+	})
+
+	t.Run("Does nothing when disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(newDeployment()).Build()
+		r := newReconciler(t, false, kubeClient)
+
+		_, err := r.ReconcileWorkload(ctx, getDeployment(t, kubeClient), DeploymentGVK)
+		require.Error(t, err)
+
+		assert.NotContains(t, getDeployment(t, kubeClient).GetAnnotations(), LastErrorAnnotation)
+	})
+
+	t.Run("Keeps the reconcile error when the patch fails", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(newDeployment()).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+				return errors.New("forbidden")
+			},
+		}).Build()
+		r := newReconciler(t, true, kubeClient)
+
+		_, err := r.ReconcileWorkload(ctx, getDeployment(t, kubeClient), DeploymentGVK)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "forbidden")
+	})
+
+	t.Run("Writing the annotation does not requeue the workload", func(t *testing.T) {
+		t.Parallel()
+		r := newReconciler(t, true, nil)
+		oldDep := newDeployment()
+		newDep := oldDep.DeepCopy()
+		newDep.Annotations[LastErrorAnnotation] = "boom"
+
+		assert.False(t, r.workloadPredicate().Update(event.UpdateEvent{ObjectOld: oldDep, ObjectNew: newDep}))
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/containeroo/autovpa/internal/predicates"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// GenericWorkloadReconciler reconciles an arbitrary workload kind (e.g. a CRD
+// whose controller owns pods directly) and manages its VPAs.
+//
+// The workload is handled as an unstructured object; the VPA targetRef points
+// at the custom object itself. All VPA logic is shared with the built-in
+// workload reconcilers through BaseReconciler.ReconcileWorkload.
+type GenericWorkloadReconciler struct {
+	BaseReconciler
+	GVK schema.GroupVersionKind // Workload kind reconciled by this controller.
+}
+
+// Reconcile ensures that the workload's opted-in state (profile annotation)
+// is reflected in its managed VPAs.
+//
+// High-level flow:
+//
+//  1. Try to load the workload.
+//     - If it does not exist anymore, proactively delete any managed VPAs
+//     that still point at this workload (best-effort cleanup).
+//  2. If it exists, delegate to ReconcileWorkload to create/update/delete
+//     the associated VPA based on the selected profile.
+func (r *GenericWorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	obj := r.newWorkloadObject()
+	if err := r.KubeClient.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The workload has been deleted. We may still have managed VPAs
+			// with an ownerRef pointing at this name/namespace; clean them up.
+			logger.Info(r.GVK.Kind + " not found; cleaning managed VPAs if any")
+
+			gone := r.newWorkloadObject()
+			gone.SetNamespace(req.Namespace)
+			gone.SetName(req.Name)
+			if err := r.DeleteManagedVPAsForGoneWorkload(ctx, gone, r.GVK.Kind); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+
+		// Any non-NotFound error should be retried by controller-runtime.
+		return ctrl.Result{}, fmt.Errorf("failed to fetch %s: %w", r.GVK.Kind, err)
+	}
+
+	// Workload exists: reconcile its VPA according to the selected profile.
+	return r.ReconcileWorkload(ctx, obj, r.GVK)
+}
+
+// SetupWithManager wires the generic workload controller into the manager.
+//
+// Predicates match the built-in workload controllers. The controller is named
+// after kind and group so several additional kinds can coexist.
+func (r *GenericWorkloadReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	vpa := newVPAObject()
+
+	name := genericControllerName(r.GVK)
+
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(r.newWorkloadObject(), builder.WithPredicates(
+			countedPredicate(r.Metrics, r.workloadPredicate(), name, r.GVK.Kind),
+		)).
+		Owns(vpa, builder.WithPredicates(
+			countedPredicate(r.Metrics,
+				predicates.ManagedVPALifecycle(r.Meta.ManagedLabel, r.Meta.managedMatchValue(), r.Meta.ProfileKey, r.Meta.vpaAnnotationKeys()...),
+				name, vpaResource),
+		))
+
+	return r.watchNamespaceDefaults(bldr, r.newWorkloadList).
+		Complete(r)
+}
+
+// newWorkloadObject returns an empty unstructured workload with the configured GVK.
+func (r *GenericWorkloadReconciler) newWorkloadObject() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(r.GVK)
+	return obj
+}
+
+// newWorkloadList returns an empty unstructured list of the configured GVK.
+func (r *GenericWorkloadReconciler) newWorkloadList() client.ObjectList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(r.GVK.GroupVersion().WithKind(r.GVK.Kind + "List"))
+	return list
+}
+
+// genericControllerName returns a unique controller name for the GVK, e.g. "rollout.argoproj.io".
+func genericControllerName(gvk schema.GroupVersionKind) string {
+	name := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		name += "." + gvk.Group
+	}
+	return name
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var podSetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "PodSet"}
+
+func TestGenericWorkloadReconciler_SetupWithManager(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.New(ctrl.GetConfigOrDie(), manager.Options{})
+	assert.NoError(t, err, "Failed to create manager")
+
+	reconciler := &GenericWorkloadReconciler{
+		BaseReconciler: BaseReconciler{
+			KubeClient: fake.NewClientBuilder().Build(),
+			Logger:     &logr.Logger{},
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		},
+		GVK: podSetGVK,
+	}
+
+	err = reconciler.SetupWithManager(mgr)
+	assert.NoError(t, err, "SetupWithManager should not return an error")
+}
+
+func TestGenericWorkloadReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (*GenericWorkloadReconciler, client.Client) {
+		t.Helper()
+
+		scheme := newScheme(t)
+		scheme.AddKnownTypeWithName(podSetGVK, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(podSetGVK.GroupVersion().WithKind("PodSetList"), &unstructured.UnstructuredList{})
+
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return &GenericWorkloadReconciler{
+			BaseReconciler: BaseReconciler{
+				KubeClient: kubeClient,
+				Logger:     &logger,
+				Recorder:   events.NewFakeRecorder(10),
+				Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+				Meta: MetaConfig{
+					ProfileKey:   "vpa/profile",
+					ManagedLabel: "vpa/managed",
+				},
+				Profiles: ProfileConfig{
+					Entries:      map[string]config.Profile{"p1": {Spec: config.ProfileSpec{}}},
+					Default:      "p1",
+					NameTemplate: flag.DefaultNameTemplate,
+				},
+			},
+			GVK: podSetGVK,
+		}, kubeClient
+	}
+
+	t.Run("Creates VPA targeting the custom resource", func(t *testing.T) {
+		t.Parallel()
+
+		podSet := &unstructured.Unstructured{}
+		podSet.SetGroupVersionKind(podSetGVK)
+		podSet.SetNamespace("ns1")
+		podSet.SetName("workers")
+		podSet.SetUID("uid1")
+		podSet.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+
+		reconciler, kubeClient := newReconciler(t, podSet)
+
+		result, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "workers"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(t.Context(), types.NamespacedName{Namespace: "ns1", Name: "workers-p1-vpa"}, vpa))
+
+		target, _, _ := unstructured.NestedStringMap(vpa.Object, "spec", "targetRef")
+		assert.Equal(t, map[string]string{
+			"apiVersion": "example.com/v1",
+			"kind":       "PodSet",
+			"name":       "workers",
+		}, target)
+
+		owner := metav1.GetControllerOf(vpa)
+		require.NotNil(t, owner)
+		assert.Equal(t, "PodSet", owner.Kind)
+		assert.Equal(t, "workers", owner.Name)
+	})
+
+	t.Run("Deletes managed VPAs when the custom resource is gone", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName("workers-p1-vpa")
+		vpa.SetLabels(map[string]string{"vpa/managed": "true", "vpa/profile": "p1"})
+		vpa.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: podSetGVK.GroupVersion().String(),
+			Kind:       podSetGVK.Kind,
+			Name:       "workers",
+			UID:        "uid1",
+			Controller: ptr.To(true),
+		}})
+
+		reconciler, kubeClient := newReconciler(t, vpa)
+
+		_, err := reconciler.Reconcile(t.Context(), ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "workers"},
+		})
+		require.NoError(t, err)
+
+		err = kubeClient.Get(t.Context(), types.NamespacedName{Namespace: "ns1", Name: "workers-p1-vpa"}, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestGenericControllerName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Includes the group", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "podset.example.com", genericControllerName(podSetGVK))
+	})
+
+	t.Run("Uses the kind for the core group", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "pod", genericControllerName(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}))
+	})
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// conflictingHPA returns the HPA scaling obj on CPU or memory, or "" if none.
+func (b *BaseReconciler) conflictingHPA(
+	ctx context.Context,
+	obj client.Object,
+	targetGVK schema.GroupVersionKind,
+) (string, error) {
+	if !b.SkipIfHPA {
+		return "", nil
+	}
+
+	list := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := b.KubeClient.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return "", fmt.Errorf("list HPAs in namespace %q: %w", obj.GetNamespace(), err)
+	}
+
+	for i := range list.Items {
+		hpa := &list.Items[i]
+		if hpaTargets(hpa, targetGVK, obj.GetName()) && hpaScalesOnResources(hpa) {
+			return hpa.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+// hpaTargets reports whether the HPA's scaleTargetRef points at the workload.
+func hpaTargets(hpa *autoscalingv2.HorizontalPodAutoscaler, targetGVK schema.GroupVersionKind, name string) bool {
+	ref := hpa.Spec.ScaleTargetRef
+	if ref.Kind != targetGVK.Kind || ref.Name != name {
+		return false
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == targetGVK.Group
+}
+
+// hpaScalesOnResources reports whether the HPA scales on CPU or memory. An HPA
+// without metrics defaults to CPU utilization and therefore counts as well.
+func hpaScalesOnResources(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	if len(hpa.Spec.Metrics) == 0 {
+		return true
+	}
+	for _, metric := range hpa.Spec.Metrics {
+		var name corev1.ResourceName
+		switch {
+		case metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil:
+			name = metric.Resource.Name
+		case metric.Type == autoscalingv2.ContainerResourceMetricSourceType && metric.ContainerResource != nil:
+			name = metric.ContainerResource.Name
+		default:
+			continue
+		}
+		if name == corev1.ResourceCPU || name == corev1.ResourceMemory {
+			return true
+		}
+	}
+	return false
+}
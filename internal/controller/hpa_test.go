@@ -0,0 +1,221 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestHPA returns an HPA in ns1 scaling the given workload on the given metrics.
+func newTestHPA(name, apiVersion, kind, target string, metrics ...autoscalingv2.MetricSpec) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: apiVersion,
+				Kind:       kind,
+				Name:       target,
+			},
+			Metrics: metrics,
+		},
+	}
+}
+
+func resourceMetric(name corev1.ResourceName) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type:     autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{Name: name},
+	}
+}
+
+func TestBaseReconciler_ReconcileWorkload_SkipIfHPA(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, skipIfHPA bool, objs ...client.Object) (*BaseReconciler, client.Client, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+		promReg := prometheus.NewRegistry()
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			SkipIfHPA: skipIfHPA,
+		}, kubeClient, recorder, promReg
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		return dep
+	}
+
+	getVPA := func(t *testing.T, c client.Client) error {
+		t.Helper()
+		return c.Get(context.Background(), types.NamespacedName{
+			Name:      renderDeploymentVPAName(t, "ns1", "demo", "p1"),
+			Namespace: "ns1",
+		}, newVPAObject())
+	}
+
+	t.Run("Skips VPA creation for a CPU HPA", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		hpa := newTestHPA("demo", "apps/v1", "Deployment", "demo", resourceMetric(corev1.ResourceCPU))
+		reconciler, kubeClient, recorder, promReg := newReconciler(t, true, dep, hpa)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.True(t, apierrors.IsNotFound(getVPA(t, kubeClient)))
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning HPAConflict HPA demo scales on CPU or memory; skipping VPA "+renderDeploymentVPAName(t, "ns1", "demo", "p1"), <-recorder.Events)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"reason":    "hpa_conflict",
+		}))
+	})
+
+	t.Run("Creates VPA without a conflicting HPA", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		objs := []client.Object{
+			dep,
+			newTestHPA("custom", "apps/v1", "Deployment", "demo", autoscalingv2.MetricSpec{
+				Type: autoscalingv2.ExternalMetricSourceType,
+			}),
+			newTestHPA("other", "apps/v1", "Deployment", "other", resourceMetric(corev1.ResourceCPU)),
+			newTestHPA("sts", "apps/v1", "StatefulSet", "demo", resourceMetric(corev1.ResourceCPU)),
+		}
+		reconciler, kubeClient, _, _ := newReconciler(t, true, objs...)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		require.NoError(t, getVPA(t, kubeClient))
+	})
+
+	t.Run("Ignores HPAs when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		hpa := newTestHPA("demo", "apps/v1", "Deployment", "demo", resourceMetric(corev1.ResourceMemory))
+		reconciler, kubeClient, _, _ := newReconciler(t, false, dep, hpa)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		require.NoError(t, getVPA(t, kubeClient))
+	})
+}
+
+func TestControllerHPATargets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Matches group, kind and name", func(t *testing.T) {
+		t.Parallel()
+		hpa := newTestHPA("h", "apps/v1", "Deployment", "demo")
+		assert.True(t, hpaTargets(hpa, DeploymentGVK, "demo"))
+	})
+
+	t.Run("Ignores the version", func(t *testing.T) {
+		t.Parallel()
+		hpa := newTestHPA("h", "apps/v1beta2", "Deployment", "demo")
+		assert.True(t, hpaTargets(hpa, DeploymentGVK, "demo"))
+	})
+
+	t.Run("Rejects other groups, kinds and names", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, hpaTargets(newTestHPA("h", "argoproj.io/v1alpha1", "Deployment", "demo"), DeploymentGVK, "demo"))
+		assert.False(t, hpaTargets(newTestHPA("h", "apps/v1", "StatefulSet", "demo"), DeploymentGVK, "demo"))
+		assert.False(t, hpaTargets(newTestHPA("h", "apps/v1", "Deployment", "other"), DeploymentGVK, "demo"))
+	})
+
+	t.Run("Rejects an invalid apiVersion", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, hpaTargets(newTestHPA("h", "a/b/c", "Deployment", "demo"), DeploymentGVK, "demo"))
+	})
+}
+
+func TestControllerHPAScalesOnResources(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		metrics []autoscalingv2.MetricSpec
+		want    bool
+	}{
+		"no metrics defaults to CPU": {want: true},
+		"cpu resource":               {metrics: []autoscalingv2.MetricSpec{resourceMetric(corev1.ResourceCPU)}, want: true},
+		"memory resource":            {metrics: []autoscalingv2.MetricSpec{resourceMetric(corev1.ResourceMemory)}, want: true},
+		"container memory resource": {
+			metrics: []autoscalingv2.MetricSpec{{
+				Type:              autoscalingv2.ContainerResourceMetricSourceType,
+				ContainerResource: &autoscalingv2.ContainerResourceMetricSource{Name: corev1.ResourceMemory, Container: "app"},
+			}},
+			want: true,
+		},
+		"ephemeral storage only": {
+			metrics: []autoscalingv2.MetricSpec{resourceMetric(corev1.ResourceEphemeralStorage)},
+		},
+		"pods metric only": {
+			metrics: []autoscalingv2.MetricSpec{{Type: autoscalingv2.PodsMetricSourceType}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			hpa := newTestHPA("h", "apps/v1", "Deployment", "demo", tc.metrics...)
+			assert.Equal(t, tc.want, hpaScalesOnResources(hpa))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+// errInvalidInlineOverride marks a profile annotation entry whose inline
+// overrides cannot be applied.
+var errInvalidInlineOverride = errors.New("invalid inline override")
+
+// Keys accepted as inline overrides in a profile annotation entry.
+const (
+	inlineOverrideUpdateMode  = "updateMode"
+	inlineOverrideMinReplicas = "minReplicas"
+)
+
+// inlineOverrides are the spec tweaks a profile annotation entry carries after
+// its profile name, e.g. "p1;updateMode=Off;minReplicas=2".
+type inlineOverrides struct {
+	UpdateMode  *vpaautoscaling.UpdateMode
+	MinReplicas *int32
+}
+
+// parseProfileEntry splits a profile annotation entry into its profile name
+// and inline overrides. Entries without overrides return nil overrides.
+// Errors wrap errInvalidInlineOverride.
+func parseProfileEntry(entry string) (string, *inlineOverrides, error) {
+	name, rest, found := strings.Cut(entry, ";")
+	name = strings.TrimSpace(name)
+	if !found {
+		return name, nil, nil
+	}
+	if name == "" {
+		return "", nil, fmt.Errorf("%w %q: profile name missing", errInvalidInlineOverride, entry)
+	}
+
+	overrides := &inlineOverrides{}
+	seen := map[string]bool{}
+	for part := range strings.SplitSeq(rest, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok {
+			return "", nil, fmt.Errorf("%w %q: expected key=value", errInvalidInlineOverride, part)
+		}
+		if seen[key] {
+			return "", nil, fmt.Errorf("%w %q: %s set more than once", errInvalidInlineOverride, part, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case inlineOverrideUpdateMode:
+			mode, err := config.ParseUpdateMode(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w %q: %w", errInvalidInlineOverride, part, err)
+			}
+			overrides.UpdateMode = &mode
+		case inlineOverrideMinReplicas:
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || n < 1 {
+				return "", nil, fmt.Errorf("%w %q: must be a positive integer", errInvalidInlineOverride, part)
+			}
+			replicas := int32(n)
+			overrides.MinReplicas = &replicas
+		default:
+			return "", nil, fmt.Errorf("%w %q: unknown key %q (supported: %s, %s)",
+				errInvalidInlineOverride, part, key, inlineOverrideUpdateMode, inlineOverrideMinReplicas)
+		}
+	}
+	return name, overrides, nil
+}
+
+// apply sets the overrides on a rendered VPA spec. A nil receiver leaves the
+// spec untouched.
+func (o *inlineOverrides) apply(spec map[string]any) error {
+	if o == nil {
+		return nil
+	}
+	if o.UpdateMode != nil {
+		if err := unstructured.SetNestedField(spec, string(*o.UpdateMode), "updatePolicy", "updateMode"); err != nil {
+			return fmt.Errorf("set inline updateMode: %w", err)
+		}
+	}
+	if o.MinReplicas != nil {
+		if err := unstructured.SetNestedField(spec, int64(*o.MinReplicas), "updatePolicy", "minReplicas"); err != nil {
+			return fmt.Errorf("set inline minReplicas: %w", err)
+		}
+	}
+	return nil
+}
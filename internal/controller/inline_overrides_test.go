@@ -0,0 +1,214 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseProfileEntry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Plain profile name", func(t *testing.T) {
+		t.Parallel()
+		name, overrides, err := parseProfileEntry("p1")
+		require.NoError(t, err)
+		assert.Equal(t, "p1", name)
+		assert.Nil(t, overrides)
+	})
+
+	t.Run("Parses all overrides", func(t *testing.T) {
+		t.Parallel()
+		name, overrides, err := parseProfileEntry(" p1 ; updateMode=off; minReplicas = 2 ;")
+		require.NoError(t, err)
+		assert.Equal(t, "p1", name)
+		assert.Equal(t, &inlineOverrides{
+			UpdateMode:  ptr.To(vpaautoscaling.UpdateModeOff),
+			MinReplicas: ptr.To(int32(2)),
+		}, overrides)
+	})
+
+	t.Run("Accepts update mode aliases", func(t *testing.T) {
+		t.Parallel()
+		_, overrides, err := parseProfileEntry("p1;updateMode=recommend")
+		require.NoError(t, err)
+		assert.Equal(t, ptr.To(vpaautoscaling.UpdateModeInitial), overrides.UpdateMode)
+	})
+
+	invalid := map[string]string{
+		";updateMode=Off":                `invalid inline override ";updateMode=Off": profile name missing`,
+		"p1;updateMode":                  `invalid inline override "updateMode": expected key=value`,
+		"p1;updateMode=Sometimes":        `invalid inline override "updateMode=Sometimes": unknown update mode "Sometimes"`,
+		"p1;minReplicas=0":               `invalid inline override "minReplicas=0": must be a positive integer`,
+		"p1;minReplicas=two":             `invalid inline override "minReplicas=two": must be a positive integer`,
+		"p1;mode=Off":                    `invalid inline override "mode=Off": unknown key "mode" (supported: updateMode, minReplicas)`,
+		"p1;minReplicas=1;minReplicas=2": `invalid inline override "minReplicas=2": minReplicas set more than once`,
+	}
+	for entry, want := range invalid {
+		t.Run("Rejects "+entry, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := parseProfileEntry(entry)
+			require.ErrorIs(t, err, errInvalidInlineOverride)
+			assert.EqualError(t, err, want)
+		})
+	}
+}
+
+func TestInlineOverridesApply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Nil overrides leave the spec untouched", func(t *testing.T) {
+		t.Parallel()
+		spec := map[string]any{"updatePolicy": map[string]any{"updateMode": "Recreate"}}
+		var overrides *inlineOverrides
+		require.NoError(t, overrides.apply(spec))
+		assert.Equal(t, map[string]any{"updatePolicy": map[string]any{"updateMode": "Recreate"}}, spec)
+	})
+
+	t.Run("Sets update mode and min replicas", func(t *testing.T) {
+		t.Parallel()
+		spec := map[string]any{"updatePolicy": map[string]any{"updateMode": "Recreate", "minReplicas": int64(3)}}
+		overrides := &inlineOverrides{
+			UpdateMode:  ptr.To(vpaautoscaling.UpdateModeOff),
+			MinReplicas: ptr.To(int32(1)),
+		}
+		require.NoError(t, overrides.apply(spec))
+		assert.Equal(t, map[string]any{"updatePolicy": map[string]any{"updateMode": "Off", "minReplicas": int64(1)}}, spec)
+	})
+
+	t.Run("Creates the update policy", func(t *testing.T) {
+		t.Parallel()
+		spec := map[string]any{}
+		overrides := &inlineOverrides{MinReplicas: ptr.To(int32(2))}
+		require.NoError(t, overrides.apply(spec))
+		assert.Equal(t, map[string]any{"updatePolicy": map[string]any{"minReplicas": int64(2)}}, spec)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_InlineOverrides(t *testing.T) {
+	t.Parallel()
+
+	profile := config.Profile{Spec: config.ProfileSpec{
+		UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+			UpdateMode: ptr.To(vpaautoscaling.UpdateModeRecreate),
+		},
+	}}
+
+	newReconciler := func(t *testing.T, dep *appsv1.Deployment) (*BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+		promReg := prometheus.NewRegistry()
+
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build(),
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": profile},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, recorder, promReg
+	}
+
+	newDeployment := func(annotation string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetAnnotations(map[string]string{"vpa/profile": annotation})
+		return dep
+	}
+
+	vpaKey := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}
+
+	t.Run("Applies inline overrides to the VPA", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1;updateMode=Off;minReplicas=2")
+		reconciler, _, _ := newReconciler(t, dep)
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, reconciler.KubeClient.Get(context.Background(), vpaKey, vpa))
+		policy, _, err := unstructured.NestedMap(vpa.Object, "spec", "updatePolicy")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"updateMode": "Off", "minReplicas": int64(2)}, policy)
+		assert.Equal(t, "p1", vpa.GetLabels()["vpa/profile"])
+	})
+
+	t.Run("Force update mode off still wins", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1;updateMode=Recreate")
+		reconciler, _, _ := newReconciler(t, dep)
+		reconciler.Profiles.ForceUpdateModeOff = true
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, reconciler.KubeClient.Get(context.Background(), vpaKey, vpa))
+		mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+		assert.Equal(t, "Off", mode)
+	})
+
+	t.Run("Skips the workload on an invalid override", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("p1;minReplicas=none")
+		reconciler, recorder, promReg := newReconciler(t, dep)
+		res, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Zero(t, res)
+
+		err = reconciler.KubeClient.Get(context.Background(), vpaKey, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, `Warning InvalidInlineOverride Skipping VPA: invalid inline override "minReplicas=none": must be a positive integer`)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"reason":    "invalid_inline_override",
+		}))
+	})
+}
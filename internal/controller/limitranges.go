@@ -0,0 +1,176 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// containerLimits are per-resource container bounds: the combined Container-type
+// bounds of a namespace's LimitRanges, or the configured default bounds.
+type containerLimits struct {
+	Min corev1.ResourceList // Per-resource lower bounds (for LimitRanges, the largest min).
+	Max corev1.ResourceList // Per-resource upper bounds (for LimitRanges, the smallest max).
+}
+
+// namespaceContainerLimits returns the Container-type bounds of the namespace's
+// LimitRanges, or nil when LimitRanges are not respected, none set bounds or
+// they cannot be listed.
+func (b *BaseReconciler) namespaceContainerLimits(ctx context.Context, namespace string) *containerLimits {
+	if !b.RespectLimitRanges {
+		return nil
+	}
+
+	list := &corev1.LimitRangeList{}
+	if err := b.KubeClient.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		b.namespaceLookupFailed(namespace, "limitranges", err)
+		return nil
+	}
+
+	limits := &containerLimits{Min: corev1.ResourceList{}, Max: corev1.ResourceList{}}
+	for _, lr := range list.Items {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for name, q := range item.Min {
+				if cur, ok := limits.Min[name]; !ok || q.Cmp(cur) > 0 {
+					limits.Min[name] = q
+				}
+			}
+			for name, q := range item.Max {
+				if cur, ok := limits.Max[name]; !ok || q.Cmp(cur) < 0 {
+					limits.Max[name] = q
+				}
+			}
+		}
+	}
+
+	if len(limits.Min) == 0 && len(limits.Max) == 0 {
+		return nil
+	}
+	return limits
+}
+
+// clampContainerPolicies keeps every container policy's minAllowed/maxAllowed
+// within limits, filling bounds the policy leaves unset. Without container
+// policies a wildcard policy carrying the bounds is added.
+// The spec's resource policy must not be shared with the profile.
+func clampContainerPolicies(spec *vpaautoscaling.VerticalPodAutoscalerSpec, limits *containerLimits) {
+	if limits == nil {
+		return
+	}
+	if spec.ResourcePolicy == nil {
+		spec.ResourcePolicy = &vpaautoscaling.PodResourcePolicy{}
+	}
+	if len(spec.ResourcePolicy.ContainerPolicies) == 0 {
+		spec.ResourcePolicy.ContainerPolicies = []vpaautoscaling.ContainerResourcePolicy{
+			{ContainerName: vpaautoscaling.DefaultContainerResourcePolicy},
+		}
+	}
+
+	for i := range spec.ResourcePolicy.ContainerPolicies {
+		policy := &spec.ResourcePolicy.ContainerPolicies[i]
+		policy.MinAllowed = clampResources(policy.MinAllowed, limits, true)
+		policy.MaxAllowed = clampResources(policy.MaxAllowed, limits, false)
+	}
+}
+
+// clampResources returns a copy of list with every value kept within limits.
+// Missing values are filled from limits.Min (isMin) or limits.Max (!isMin).
+func clampResources(list corev1.ResourceList, limits *containerLimits, isMin bool) corev1.ResourceList {
+	out := list.DeepCopy()
+	if out == nil {
+		out = corev1.ResourceList{}
+	}
+
+	fill := limits.Max
+	if isMin {
+		fill = limits.Min
+	}
+	for name, q := range fill {
+		if _, ok := out[name]; !ok {
+			out[name] = q
+		}
+	}
+
+	for name, q := range out {
+		if lo, ok := limits.Min[name]; ok && q.Cmp(lo) < 0 {
+			out[name] = lo
+		}
+		if hi, ok := limits.Max[name]; ok && q.Cmp(hi) > 0 {
+			out[name] = hi
+		}
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// applyDefaultBounds fills the minAllowed/maxAllowed resources container
+// policies leave unset from defaults, keeping explicit bounds. A default that
+// would cross the policy's explicit opposite bound is skipped. Without
+// container policies a wildcard policy carrying the defaults is added.
+// The spec's resource policy must not be shared with the profile.
+func applyDefaultBounds(spec *vpaautoscaling.VerticalPodAutoscalerSpec, defaults *containerLimits) {
+	if defaults == nil {
+		return
+	}
+	if spec.ResourcePolicy == nil {
+		spec.ResourcePolicy = &vpaautoscaling.PodResourcePolicy{}
+	}
+	if len(spec.ResourcePolicy.ContainerPolicies) == 0 {
+		spec.ResourcePolicy.ContainerPolicies = []vpaautoscaling.ContainerResourcePolicy{
+			{ContainerName: vpaautoscaling.DefaultContainerResourcePolicy},
+		}
+	}
+
+	for i := range spec.ResourcePolicy.ContainerPolicies {
+		policy := &spec.ResourcePolicy.ContainerPolicies[i]
+		minAllowed := withDefaultResources(policy.MinAllowed, defaults.Min, policy.MaxAllowed, true)
+		maxAllowed := withDefaultResources(policy.MaxAllowed, defaults.Max, policy.MinAllowed, false)
+		policy.MinAllowed, policy.MaxAllowed = minAllowed, maxAllowed
+	}
+}
+
+// withDefaultResources returns a copy of list with resources missing from it
+// taken from defaults, unless a default lies beyond the opposite bound (above
+// it for a min, below it for a max).
+func withDefaultResources(list, defaults, opposite corev1.ResourceList, isMin bool) corev1.ResourceList {
+	out := list.DeepCopy()
+	for name, q := range defaults {
+		if _, ok := out[name]; ok {
+			continue
+		}
+		if bound, ok := opposite[name]; ok {
+			if cmp := q.Cmp(bound); (isMin && cmp > 0) || (!isMin && cmp < 0) {
+				continue
+			}
+		}
+		if out == nil {
+			out = corev1.ResourceList{}
+		}
+		out[name] = q
+	}
+	return out
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newLimitRange returns a LimitRange with a single limit item.
+func newLimitRange(namespace, name string, limitType corev1.LimitType, lo, hi corev1.ResourceList) *corev1.LimitRange {
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{{Type: limitType, Min: lo, Max: hi}},
+		},
+	}
+}
+
+func resources(cpu, memory string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	if cpu != "" {
+		list[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		list[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return list
+}
+
+func TestBaseReconciler_namespaceContainerLimits(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, respect bool, objs ...client.Object) BaseReconciler {
+		t.Helper()
+		return BaseReconciler{
+			KubeClient:         fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			RespectLimitRanges: respect,
+		}
+	}
+
+	t.Run("Returns nil when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		lr := newLimitRange("ns1", "lr", corev1.LimitTypeContainer, resources("100m", ""), resources("2", ""))
+		br := newReconciler(t, false, lr)
+
+		limits := br.namespaceContainerLimits(context.Background(), "ns1")
+		assert.Nil(t, limits)
+	})
+
+	t.Run("Returns nil when LimitRanges cannot be listed", func(t *testing.T) {
+		t.Parallel()
+
+		logger := logr.Discard()
+		lr := newLimitRange("ns1", "lr", corev1.LimitTypeContainer, resources("100m", ""), resources("2", ""))
+		br := BaseReconciler{
+			KubeClient:         fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(lr).WithInterceptorFuncs(namespaceLookupsFail).Build(),
+			Logger:             &logger,
+			RespectLimitRanges: true,
+		}
+
+		assert.Nil(t, br.namespaceContainerLimits(context.Background(), "ns1"))
+	})
+
+	t.Run("Returns nil without LimitRanges", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true)
+
+		limits := br.namespaceContainerLimits(context.Background(), "ns1")
+		assert.Nil(t, limits)
+	})
+
+	t.Run("Ignores non-container limits and other namespaces", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true,
+			newLimitRange("ns1", "pod", corev1.LimitTypePod, resources("100m", ""), resources("2", "")),
+			newLimitRange("ns2", "other", corev1.LimitTypeContainer, resources("100m", ""), resources("2", "")),
+		)
+
+		limits := br.namespaceContainerLimits(context.Background(), "ns1")
+		assert.Nil(t, limits)
+	})
+
+	t.Run("Combines the tightest bounds", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true,
+			newLimitRange("ns1", "a", corev1.LimitTypeContainer, resources("100m", "64Mi"), resources("4", "4Gi")),
+			newLimitRange("ns1", "b", corev1.LimitTypeContainer, resources("200m", ""), resources("2", "8Gi")),
+		)
+
+		limits := br.namespaceContainerLimits(context.Background(), "ns1")
+		require.NotNil(t, limits)
+		assert.True(t, resource.MustParse("200m").Equal(*limits.Min.Cpu()))
+		assert.True(t, resource.MustParse("64Mi").Equal(*limits.Min.Memory()))
+		assert.True(t, resource.MustParse("2").Equal(*limits.Max.Cpu()))
+		assert.True(t, resource.MustParse("4Gi").Equal(*limits.Max.Memory()))
+	})
+}
+
+func TestControllerClampContainerPolicies(t *testing.T) {
+	t.Parallel()
+
+	limits := &containerLimits{Min: resources("100m", "64Mi"), Max: resources("2", "2Gi")}
+
+	t.Run("Leaves spec untouched without limits", func(t *testing.T) {
+		t.Parallel()
+
+		spec := vpaautoscaling.VerticalPodAutoscalerSpec{}
+		clampContainerPolicies(&spec, nil)
+		assert.Nil(t, spec.ResourcePolicy)
+	})
+
+	t.Run("Adds a wildcard policy when none exists", func(t *testing.T) {
+		t.Parallel()
+
+		spec := vpaautoscaling.VerticalPodAutoscalerSpec{}
+		clampContainerPolicies(&spec, limits)
+
+		require.Len(t, spec.ResourcePolicy.ContainerPolicies, 1)
+		policy := spec.ResourcePolicy.ContainerPolicies[0]
+		assert.Equal(t, vpaautoscaling.DefaultContainerResourcePolicy, policy.ContainerName)
+		assert.Equal(t, "100m", policy.MinAllowed.Cpu().String())
+		assert.Equal(t, "2Gi", policy.MaxAllowed.Memory().String())
+	})
+
+	t.Run("Clamps bounds outside the limits", func(t *testing.T) {
+		t.Parallel()
+
+		spec := vpaautoscaling.VerticalPodAutoscalerSpec{
+			ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+				ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+					ContainerName: "app",
+					MinAllowed:    resources("10m", "1Gi"),
+					MaxAllowed:    resources("8", "1Mi"),
+				}},
+			},
+		}
+		clampContainerPolicies(&spec, limits)
+
+		policy := spec.ResourcePolicy.ContainerPolicies[0]
+		assert.Equal(t, "100m", policy.MinAllowed.Cpu().String(), "min raised to the LimitRange min")
+		assert.Equal(t, "1Gi", policy.MinAllowed.Memory().String(), "min within bounds is kept")
+		assert.Equal(t, "2", policy.MaxAllowed.Cpu().String(), "max lowered to the LimitRange max")
+		assert.Equal(t, "64Mi", policy.MaxAllowed.Memory().String(), "max raised to the LimitRange min")
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_LimitRanges(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{"p1": {Spec: config.ProfileSpec{
+					ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+						ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+							ContainerName: "*",
+							MaxAllowed:    resources("8", ""),
+						}},
+					},
+				}}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			RespectLimitRanges: true,
+		}, kubeClient
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		return dep
+	}
+
+	containerPolicy := func(t *testing.T, c client.Client) map[string]any {
+		t.Helper()
+		vpa := newVPAObject()
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{
+			Name:      renderDeploymentVPAName(t, "ns1", "demo", "p1"),
+			Namespace: "ns1",
+		}, vpa))
+		policies := vpa.Object["spec"].(map[string]any)["resourcePolicy"].(map[string]any)["containerPolicies"].([]any)
+		require.Len(t, policies, 1)
+		return policies[0].(map[string]any)
+	}
+
+	t.Run("Clamps to the namespace LimitRange", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		lr := newLimitRange("ns1", "lr", corev1.LimitTypeContainer, resources("50m", ""), resources("2", "1Gi"))
+		reconciler, kubeClient := newReconciler(t, dep, lr)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		policy := containerPolicy(t, kubeClient)
+		assert.Equal(t, map[string]any{"cpu": "2", "memory": "1Gi"}, policy["maxAllowed"])
+		assert.Equal(t, map[string]any{"cpu": "50m"}, policy["minAllowed"])
+		assert.Equal(t, "8", reconciler.Profiles.Entries["p1"].Spec.ResourcePolicy.ContainerPolicies[0].MaxAllowed.Cpu().String(),
+			"shared profile must not be mutated")
+	})
+
+	t.Run("Leaves spec untouched without LimitRange", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		reconciler, kubeClient := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		policy := containerPolicy(t, kubeClient)
+		assert.Equal(t, map[string]any{"cpu": "8"}, policy["maxAllowed"])
+		assert.NotContains(t, policy, "minAllowed")
+	})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/containeroo/autovpa/internal/predicates"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// resolveProfileNames returns the profiles requested for a workload of kind.
+// The workload's own annotation always wins; when it is missing and namespace
+// defaults are enabled, the namespace's default-profile annotation is used.
+//
+// A namespace that cannot be read is reported as unreadable instead of
+// falling back to no profile: that fallback would opt the workload out and
+// delete its VPAs on every transient read error. Callers keep the existing
+// VPAs and retry.
+func (b *BaseReconciler) resolveProfileNames(ctx context.Context, obj client.Object, kind string) (names []string, unreadable bool) {
+	if names := workloadProfileNames(obj.GetAnnotations(), b.Meta, b.Profiles.defaultFor(kind)); len(names) > 0 {
+		return names, false
+	}
+	if b.Meta.NamespaceProfileKey == "" {
+		return nil, false
+	}
+
+	ns := &corev1.Namespace{}
+	if err := b.KubeClient.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false
+		}
+		b.Logger.V(1).Info(
+			"namespace lookup failed; keeping existing VPAs",
+			"namespace", obj.GetNamespace(),
+			"lookup", "default profile",
+			"error", err.Error(),
+			"requeueAfter", namespaceRetryDelay,
+		)
+		return nil, true
+	}
+	return parseProfileNames(ns.GetAnnotations()[b.Meta.NamespaceProfileKey]), false
+}
+
+// namespaceLookupFailed logs, at V(1), a namespace-level lookup that failed;
+// the caller continues with the global defaults. The namespace default profile
+// is the exception, see resolveProfileNames.
+func (b *BaseReconciler) namespaceLookupFailed(namespace, lookup string, err error) {
+	b.Logger.V(1).Info(
+		"namespace lookup failed; using global defaults",
+		"namespace", namespace,
+		"lookup", lookup,
+		"error", err.Error(),
+	)
+}
+
+// workloadPredicate returns the event filter for the primary workload resource.
+// With namespace defaults enabled, newly created workloads are always
+// reconciled because their namespace may opt them in. With EmptyMeansDefault,
+// workloads with an empty profile annotation are treated as opted in. A
+// templated managed label value may read workload labels, so label changes
+// then requeue the workload too. Opted-in workloads whose container set
+// changes are requeued as well. With WarnAnnotationTypos, workloads gaining a
+// misspelled profile annotation are reconciled so the typo can be reported.
+// Updates changing only LastErrorAnnotation are ignored.
+func (b *BaseReconciler) workloadPredicate() predicate.Predicate {
+	extraKeys := append(slices.Clone(b.Meta.TrackingAnnotations), RotateAnnotation)
+	for _, override := range resourceOverrideAnnotations {
+		extraKeys = append(extraKeys, override.key)
+	}
+	lifecycle := predicate.Or(
+		predicates.ProfileAnnotationLifecycle(b.Meta.profileKeys(), extraKeys...),
+		predicates.ContainerSetChanged(b.Meta.profileKeys()...),
+	)
+	if b.Meta.EmptyMeansDefault {
+		lifecycle = predicate.Or(lifecycle, predicates.AnnotationPresent(b.Meta.profileKeys()...))
+	}
+	if b.Meta.WarnAnnotationTypos {
+		lifecycle = predicate.Or(lifecycle, predicates.AnnotationKeyNearMiss(b.Meta.profileKeys(), profileAnnotationTypoDistance))
+	}
+	if b.Meta.managedMatchValue() == "" {
+		lifecycle = predicate.Or(lifecycle, predicates.LabelsChanged())
+	}
+	if b.Meta.NamespaceProfileKey != "" {
+		lifecycle = predicate.Or(lifecycle, predicates.AnyCreate())
+	}
+	// The operator writes LastErrorAnnotation itself; that must not requeue.
+	return predicate.And(lifecycle, predicates.IgnoreAnnotationOnlyUpdates(LastErrorAnnotation))
+}
+
+// watchNamespaceDefaults requeues every workload of a namespace when its
+// default-profile annotation changes. It is a no-op when namespace defaults
+// are disabled. newList must return an empty list of the reconciled kind.
+func (b *BaseReconciler) watchNamespaceDefaults(
+	bldr *builder.Builder,
+	newList func() client.ObjectList,
+) *builder.Builder {
+	if b.Meta.NamespaceProfileKey == "" {
+		return bldr
+	}
+
+	return bldr.Watches(
+		&corev1.Namespace{},
+		handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, ns client.Object) []reconcile.Request {
+			return b.namespaceWorkloadRequests(ctx, ns.GetName(), newList())
+		}),
+		builder.WithPredicates(predicates.AnnotationValueChanged(b.Meta.NamespaceProfileKey)),
+	)
+}
+
+// namespaceWorkloadRequests lists the workloads in namespace and returns a
+// reconcile request for each. Listing errors are logged and yield no requests.
+func (b *BaseReconciler) namespaceWorkloadRequests(
+	ctx context.Context,
+	namespace string,
+	list client.ObjectList,
+) []reconcile.Request {
+	if err := b.KubeClient.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		b.Logger.Error(err, "failed to list workloads for namespace default profile", "namespace", namespace)
+		return nil
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		b.Logger.Error(err, "failed to extract workloads for namespace default profile", "namespace", namespace)
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+	}
+	return reqs
+}
@@ -0,0 +1,368 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// namespaceLookupsFail fails every Namespace read and every LimitRange and
+// ResourceQuota list, i.e. all namespace-level lookups.
+var namespaceLookupsFail = interceptor.Funcs{
+	Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+		if _, ok := obj.(*corev1.Namespace); ok {
+			return errors.New("namespaces are forbidden")
+		}
+		return c.Get(ctx, key, obj, opts...)
+	},
+	List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+		switch list.(type) {
+		case *corev1.LimitRangeList, *corev1.ResourceQuotaList:
+			return errors.New("list is forbidden")
+		}
+		return c.List(ctx, list, opts...)
+	},
+}
+
+func newNamespace(name string, annotations map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+}
+
+func newNamespaceDefaultsReconciler(t *testing.T, kubeClient client.Client, enabled bool) BaseReconciler {
+	t.Helper()
+	logger := logr.Discard()
+	meta := MetaConfig{
+		ProfileKey:   "vpa/profile",
+		ManagedLabel: "vpa/managed",
+	}
+	if enabled {
+		meta.NamespaceProfileKey = NamespaceDefaultProfileAnnotation
+	}
+	return BaseReconciler{
+		KubeClient: kubeClient,
+		Logger:     &logger,
+		Recorder:   events.NewFakeRecorder(10),
+		Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		Meta:       meta,
+		Profiles: ProfileConfig{
+			Default:      "p1",
+			NameTemplate: flag.DefaultNameTemplate,
+			Entries: map[string]config.Profile{
+				"p1":   {Spec: config.ProfileSpec{}},
+				"team": {Spec: config.ProfileSpec{}},
+			},
+		},
+	}
+}
+
+func TestBaseReconciler_resolveProfileNames(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ns := newNamespace("ns1", map[string]string{NamespaceDefaultProfileAnnotation: "team"})
+
+	newWorkload := func(annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "demo", Annotations: annotations}}
+	}
+
+	t.Run("Workload annotation wins over namespace default", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		names, unreadable := br.resolveProfileNames(ctx, newWorkload(map[string]string{"vpa/profile": "p1"}), "Deployment")
+		assert.False(t, unreadable)
+		assert.Equal(t, []string{"p1"}, names)
+	})
+
+	t.Run("Falls back to namespace default", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		names, unreadable := br.resolveProfileNames(ctx, newWorkload(nil), "Deployment")
+		assert.False(t, unreadable)
+		assert.Equal(t, []string{"team"}, names)
+	})
+
+	t.Run("Ignores namespace default when disabled", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, false)
+
+		names, unreadable := br.resolveProfileNames(ctx, newWorkload(nil), "Deployment")
+		assert.False(t, unreadable)
+		assert.Empty(t, names)
+	})
+
+	t.Run("Missing namespace yields no profile", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		names, unreadable := br.resolveProfileNames(ctx, newWorkload(nil), "Deployment")
+		assert.False(t, unreadable)
+		assert.Empty(t, names)
+	})
+
+	t.Run("Reports unreadable namespaces", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return errors.New("boom")
+			},
+		}).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		names, unreadable := br.resolveProfileNames(ctx, newWorkload(nil), "Deployment")
+		assert.True(t, unreadable)
+		assert.Empty(t, names)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_NamespaceDefaults(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Creates VPA from namespace default profile", func(t *testing.T) {
+		t.Parallel()
+		ns := newNamespace("ns1", map[string]string{NamespaceDefaultProfileAnnotation: "team"})
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "demo", UID: "uid1"}}
+		_, err := br.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "demo-team-vpa"}, vpa))
+		assert.Equal(t, "team", vpa.GetLabels()["vpa/profile"])
+	})
+
+	t.Run("Workload annotation overrides namespace default", func(t *testing.T) {
+		t.Parallel()
+		ns := newNamespace("ns1", map[string]string{NamespaceDefaultProfileAnnotation: "team"})
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			UID:         "uid1",
+			Annotations: map[string]string{"vpa/profile": "p1"},
+		}}
+		_, err := br.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpas, err := br.listManagedVPAs(ctx, "ns1")
+		require.NoError(t, err)
+		require.Len(t, vpas, 1)
+		assert.Equal(t, "demo-p1-vpa", vpas[0].GetName())
+	})
+
+	t.Run("Creates VPA with global defaults when namespace lookups fail", func(t *testing.T) {
+		t.Parallel()
+		lr := newLimitRange("ns1", "lr", corev1.LimitTypeContainer, resources("100m", ""), resources("2", ""))
+		rq := newResourceQuota("ns1", "rq", resources("4", ""), nil)
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			UID:         "uid1",
+			Annotations: map[string]string{"vpa/profile": "p1"},
+		}}
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).
+			WithObjects(newNamespace("ns1", nil), lr, rq, dep).
+			WithInterceptorFuncs(namespaceLookupsFail).
+			Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+		br.CheckNamespacePhase = true
+		br.RespectLimitRanges = true
+		br.RespectResourceQuota = true
+
+		res, err := br.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Zero(t, res)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "demo-p1-vpa"}, vpa))
+		_, clamped, err := unstructured.NestedMap(vpa.Object, "spec", "resourcePolicy")
+		require.NoError(t, err)
+		assert.False(t, clamped, "LimitRanges and quotas must not apply when they cannot be listed")
+	})
+
+	t.Run("Keeps VPAs when the namespace default cannot be read", func(t *testing.T) {
+		t.Parallel()
+		var fail atomic.Bool
+		ns := newNamespace("ns1", map[string]string{NamespaceDefaultProfileAnnotation: "team"})
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if fail.Load() {
+						return namespaceLookupsFail.Get(ctx, c, key, obj, opts...)
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "demo", UID: "uid1"}}
+		_, err := br.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		fail.Store(true)
+		res, err := br.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, namespaceRetryDelay, res.RequeueAfter)
+
+		vpas, err := br.listManagedVPAs(ctx, "ns1")
+		require.NoError(t, err)
+		require.Len(t, vpas, 1)
+		assert.Equal(t, "demo-team-vpa", vpas[0].GetName())
+	})
+
+	t.Run("Skips workload when namespace has no default", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(newNamespace("ns1", nil)).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "demo", UID: "uid1"}}
+		_, err := br.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpas, err := br.listManagedVPAs(ctx, "ns1")
+		require.NoError(t, err)
+		assert.Empty(t, vpas)
+	})
+}
+
+func TestBaseReconciler_workloadPredicate(t *testing.T) {
+	t.Parallel()
+
+	unannotated := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "demo"}}
+
+	t.Run("Ignores unannotated creates when disabled", func(t *testing.T) {
+		t.Parallel()
+		br := newNamespaceDefaultsReconciler(t, nil, false)
+		assert.False(t, br.workloadPredicate().Create(event.CreateEvent{Object: unannotated}))
+	})
+
+	t.Run("Accepts unannotated creates when enabled", func(t *testing.T) {
+		t.Parallel()
+		br := newNamespaceDefaultsReconciler(t, nil, true)
+		assert.True(t, br.workloadPredicate().Create(event.CreateEvent{Object: unannotated}))
+	})
+	t.Run("Accepts rotate annotation changes", func(t *testing.T) {
+		t.Parallel()
+		br := newNamespaceDefaultsReconciler(t, nil, false)
+		oldObj := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns1",
+			Name:            "demo",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{br.Meta.ProfileKey: "p1"},
+		}}
+		newObj := oldObj.DeepCopy()
+		newObj.ResourceVersion = "2"
+		newObj.Annotations[RotateAnnotation] = "2026-10-17T10:00:00Z"
+		assert.True(t, br.workloadPredicate().Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}))
+	})
+	t.Run("Accepts resource annotation changes", func(t *testing.T) {
+		t.Parallel()
+		br := newNamespaceDefaultsReconciler(t, nil, false)
+		oldObj := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns1",
+			Name:            "demo",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{br.Meta.ProfileKey: "p1"},
+		}}
+		newObj := oldObj.DeepCopy()
+		newObj.ResourceVersion = "2"
+		newObj.Annotations[MaxMemoryAnnotation] = "2Gi"
+		assert.True(t, br.workloadPredicate().Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}))
+	})
+	t.Run("Accepts misspelled profile annotations only when typo warnings are enabled", func(t *testing.T) {
+		t.Parallel()
+		br := newNamespaceDefaultsReconciler(t, nil, false)
+		typo := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			Annotations: map[string]string{br.Meta.ProfileKey + "s": "p1"},
+		}}
+		assert.False(t, br.workloadPredicate().Create(event.CreateEvent{Object: typo}))
+
+		br.Meta.WarnAnnotationTypos = true
+		assert.True(t, br.workloadPredicate().Create(event.CreateEvent{Object: typo}))
+	})
+}
+
+func TestBaseReconciler_namespaceWorkloadRequests(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Lists workloads of the namespace", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "a"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "b"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "c"}},
+		).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		reqs := br.namespaceWorkloadRequests(ctx, "ns1", &appsv1.DeploymentList{})
+		assert.ElementsMatch(t, []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "a"}},
+			{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "b"}},
+		}, reqs)
+	})
+
+	t.Run("Returns nothing on list errors", func(t *testing.T) {
+		t.Parallel()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return errors.New("boom")
+			},
+		}).Build()
+		br := newNamespaceDefaultsReconciler(t, kubeClient, true)
+
+		assert.Empty(t, br.namespaceWorkloadRequests(ctx, "ns1", &appsv1.DeploymentList{}))
+	})
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// namespaceLabels returns the labels of the namespace for name templates that
+// read .NamespaceLabels. A missing namespace has no labels. Unlike the other
+// namespace lookups, read errors are returned: rendering without the labels
+// would rename the VPA.
+func (b *BaseReconciler) namespaceLabels(ctx context.Context, namespace string) (map[string]string, error) {
+	ns := &corev1.Namespace{}
+	if err := b.KubeClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetch namespace %q: %w", namespace, err)
+	}
+	return ns.GetLabels(), nil
+}
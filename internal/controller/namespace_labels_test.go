@@ -0,0 +1,155 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// namespaceGetFails fails every Namespace read, to catch unexpected lookups.
+var namespaceGetFails = interceptor.Funcs{
+	Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+		if _, ok := obj.(*corev1.Namespace); ok {
+			return errors.New("namespaces are forbidden")
+		}
+		return c.Get(ctx, key, obj, opts...)
+	},
+}
+
+func TestBaseReconciler_namespaceLabels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Returns namespace labels", func(t *testing.T) {
+		t.Parallel()
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"team": "payments"}}}
+		b := &BaseReconciler{KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns).Build()}
+
+		got, err := b.namespaceLabels(context.Background(), "ns1")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "payments"}, got)
+	})
+
+	t.Run("Missing namespace has no labels", func(t *testing.T) {
+		t.Parallel()
+		b := &BaseReconciler{KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).Build()}
+
+		got, err := b.namespaceLabels(context.Background(), "ns1")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("Returns read errors", func(t *testing.T) {
+		t.Parallel()
+		b := &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(namespaceGetFails).Build(),
+		}
+
+		_, err := b.namespaceLabels(context.Background(), "ns1")
+		require.Error(t, err)
+		assert.EqualError(t, err, `fetch namespace "ns1": namespaces are forbidden`)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_NamespaceLabels(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, kubeClient client.Client, nameTemplate string) *BaseReconciler {
+		t.Helper()
+		logger := logr.Discard()
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Default:      "p1",
+				Entries:      map[string]config.Profile{"p1": {}},
+				NameTemplate: nameTemplate,
+			},
+			Metrics: internalmetrics.NewRegistry(prometheus.NewRegistry()),
+		}
+	}
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "demo",
+			Annotations: map[string]string{"vpa/profile": "p1"},
+		}}
+	}
+
+	t.Run("Renders the VPA name from a namespace label", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"team": "payments"}}}
+		dep := newDeployment()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns, dep).Build()
+		r := newReconciler(t, kubeClient, `{{ .WorkloadName }}-{{ index .NamespaceLabels "team" }}-vpa`)
+
+		_, err := r.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "demo-payments-vpa"}, vpa))
+		assert.Equal(t, "p1", vpa.GetLabels()["vpa/profile"])
+	})
+
+	t.Run("Does not read the namespace for other templates", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).WithInterceptorFuncs(namespaceGetFails).Build()
+		r := newReconciler(t, kubeClient, `{{ .WorkloadName }}-{{ .Profile }}-vpa`)
+
+		_, err := r.ReconcileWorkload(ctx, dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "demo-p1-vpa"}, vpa))
+	})
+
+	t.Run("Returns namespace read errors", func(t *testing.T) {
+		t.Parallel()
+		dep := newDeployment()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).WithInterceptorFuncs(namespaceGetFails).Build()
+		r := newReconciler(t, kubeClient, `{{ .WorkloadName }}-{{ index .NamespaceLabels "team" }}-vpa`)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `fetch namespace "ns1"`)
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namespaceTerminating reports whether the namespace is being deleted. The API
+// server rejects new objects in such a namespace, so VPAs cannot be created.
+// A missing or unreadable namespace is not reported as terminating; a write
+// rejected by the API server is still caught by isNamespaceTerminatingError.
+// Nothing is read unless CheckNamespacePhase is set.
+func (b *BaseReconciler) namespaceTerminating(ctx context.Context, namespace string) bool {
+	if !b.CheckNamespacePhase {
+		return false
+	}
+
+	ns := &corev1.Namespace{}
+	if err := b.KubeClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if !apierrors.IsNotFound(err) {
+			b.namespaceLookupFailed(namespace, "phase", err)
+		}
+		return false
+	}
+	return ns.Status.Phase == corev1.NamespaceTerminating || !ns.DeletionTimestamp.IsZero()
+}
+
+// isNamespaceTerminatingError reports whether the API server rejected a write
+// because the namespace is being deleted. It catches terminating namespaces
+// when CheckNamespacePhase is off or the cached phase is stale.
+func isNamespaceTerminatingError(err error) bool {
+	return apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause)
+}
+
+// skipTerminatingNamespace logs and counts a workload skipped because its
+// namespace is being deleted. No event is recorded, as the namespace rejects
+// new events as well.
+func (b *BaseReconciler) skipTerminatingNamespace(ctx context.Context, log logr.Logger, obj client.Object, kind string) {
+	log.Info("namespace terminating; skipping VPA reconciliation")
+
+	b.Metrics.IncVPASkipped(
+		obj.GetNamespace(),
+		obj.GetName(),
+		kind,
+		vpaSkipReasonNamespaceTerminating,
+	)
+	recordOutcome(ctx, skippedOutcome(vpaSkipReasonNamespaceTerminating))
+}
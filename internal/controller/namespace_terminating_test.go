@@ -0,0 +1,207 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// namespaceTerminatingError mimics the API server rejecting a create in a
+// terminating namespace.
+func namespaceTerminatingError(namespace string) error {
+	err := apierrors.NewForbidden(
+		schema.GroupResource{Group: "autoscaling.k8s.io", Resource: "verticalpodautoscalers"},
+		"demo-vpa",
+		errors.New("unable to create new content in namespace "+namespace+" because it is being terminated"),
+	)
+	err.ErrStatus.Details.Causes = []metav1.StatusCause{{
+		Type:    corev1.NamespaceTerminatingCause,
+		Message: "namespace " + namespace + " is being terminated",
+		Field:   "metadata.namespace",
+	}}
+	return err
+}
+
+func TestBaseReconciler_namespaceTerminating(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, check bool, objs ...client.Object) *BaseReconciler {
+		t.Helper()
+		return &BaseReconciler{
+			KubeClient:          fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			CheckNamespacePhase: check,
+		}
+	}
+	terminating := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns1"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+
+	t.Run("Terminating phase", func(t *testing.T) {
+		t.Parallel()
+		got := newReconciler(t, true, terminating).namespaceTerminating(context.Background(), "ns1")
+		assert.True(t, got)
+	})
+
+	t.Run("Active namespace", func(t *testing.T) {
+		t.Parallel()
+		active := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ns1"},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		}
+		got := newReconciler(t, true, active).namespaceTerminating(context.Background(), "ns1")
+		assert.False(t, got)
+	})
+
+	t.Run("Missing namespace", func(t *testing.T) {
+		t.Parallel()
+		got := newReconciler(t, true).namespaceTerminating(context.Background(), "ns1")
+		assert.False(t, got)
+	})
+
+	t.Run("Unreadable namespace", func(t *testing.T) {
+		t.Parallel()
+		logger := logr.Discard()
+		br := &BaseReconciler{
+			KubeClient:          fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(terminating).WithInterceptorFuncs(namespaceLookupsFail).Build(),
+			Logger:              &logger,
+			CheckNamespacePhase: true,
+		}
+		assert.False(t, br.namespaceTerminating(context.Background(), "ns1"))
+	})
+
+	t.Run("Check disabled", func(t *testing.T) {
+		t.Parallel()
+		got := newReconciler(t, false, terminating).namespaceTerminating(context.Background(), "ns1")
+		assert.False(t, got)
+	})
+}
+
+func TestIsNamespaceTerminatingError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isNamespaceTerminatingError(namespaceTerminatingError("ns1")))
+	assert.False(t, isNamespaceTerminatingError(apierrors.NewInternalError(errors.New("boom"))))
+	assert.False(t, isNamespaceTerminatingError(nil))
+}
+
+func TestBaseReconciler_ReconcileWorkload_NamespaceTerminating(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, kubeClient client.Client, check bool) (*BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+		promReg := prometheus.NewRegistry()
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			Circuit:             &ApplyCircuitBreaker{Threshold: 1},
+			CheckNamespacePhase: check,
+		}, recorder, promReg
+	}
+
+	dep := &appsv1.Deployment{}
+	dep.SetNamespace("ns1")
+	dep.SetName("demo")
+	dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+
+	skipped := map[string]string{
+		"namespace": "ns1",
+		"name":      "demo",
+		"kind":      "Deployment",
+		"reason":    "namespace_terminating",
+	}
+
+	t.Run("Skips a workload in a terminating namespace", func(t *testing.T) {
+		t.Parallel()
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ns1"},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+		}
+		applies := 0
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(ns, dep).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				applies++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+		reconciler, recorder, promReg := newReconciler(t, kubeClient, true)
+
+		res, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Zero(t, res)
+		assert.Zero(t, applies)
+		assert.Empty(t, recorder.Events)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", skipped))
+
+		vpaKey := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}
+		assert.True(t, apierrors.IsNotFound(kubeClient.Get(context.Background(), vpaKey, newVPAObject())))
+	})
+
+	t.Run("Skips a workload whose VPA create is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+				return namespaceTerminatingError("ns1")
+			},
+		}).Build()
+		reconciler, recorder, promReg := newReconciler(t, kubeClient, false)
+
+		res, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Zero(t, res)
+		assert.Empty(t, recorder.Events)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", skipped))
+		assert.Zero(t, reconciler.Circuit.openFor(), "rejections by a terminating namespace must not open the circuit")
+	})
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/containeroo/autovpa/internal/metrics"
+	"github.com/containeroo/autovpa/internal/predicates"
+
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// vpaResource is the resource label used when counting VPA watch events.
+const vpaResource = "VerticalPodAutoscaler"
+
+// countedPredicate wraps p so its admitted and filtered events are counted in
+// reg. Without a registry p is returned as is.
+func countedPredicate(reg *metrics.Registry, p predicate.Predicate, controller, resource string) predicate.Predicate {
+	if reg == nil {
+		return p
+	}
+	return predicates.Counted(p, reg, controller, resource)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+func TestCountedPredicate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Counts workload predicate decisions", func(t *testing.T) {
+		t.Parallel()
+
+		promReg := prometheus.NewRegistry()
+		b := &BaseReconciler{
+			Metrics: internalmetrics.NewRegistry(promReg),
+			Meta:    MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"},
+		}
+		pred := countedPredicate(b.Metrics, b.workloadPredicate(), "deployment", "Deployment")
+
+		optedIn := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "a", Namespace: "ns", Annotations: map[string]string{"vpa/profile": "p1"},
+		}}
+		plain := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns"}}
+
+		assert.True(t, pred.Create(event.CreateEvent{Object: optedIn}))
+		assert.False(t, pred.Create(event.CreateEvent{Object: plain}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: optedIn}))
+
+		labels := func(event, result string) map[string]string {
+			return map[string]string{"controller": "deployment", "resource": "Deployment", "event": event, "result": result}
+		}
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_predicate_events_total", labels("create", "admitted")))
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_predicate_events_total", labels("create", "filtered")))
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_predicate_events_total", labels("generic", "filtered")))
+	})
+
+	t.Run("Nil registry returns predicate unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		inner := predicate.GenerationChangedPredicate{}
+		assert.Equal(t, inner, countedPredicate(nil, inner, "deployment", "Deployment"))
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// activeProfiles returns the sorted profiles of the managed VPAs controlled by
+// owner, as recorded in their profile label. Together with the desired profiles
+// it tells whether a reconcile switches the workload to other profiles.
+func (b *BaseReconciler) activeProfiles(ctx context.Context, owner client.Object) ([]string, error) {
+	vpas, err := b.listManagedVPAs(ctx, owner.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []string
+	for _, vpa := range vpas {
+		if !metav1.IsControlledBy(vpa, owner) {
+			continue
+		}
+		profile := vpa.GetLabels()[b.Meta.ProfileKey]
+		if profile == "" || slices.Contains(profiles, profile) {
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	slices.Sort(profiles)
+	return profiles, nil
+}
+
+// recordProfileChange emits a ProfileChanged event and counts the switch when
+// the workload already had managed VPAs for a different set of profiles.
+// Newly opted-in workloads are not reported.
+func (b *BaseReconciler) recordProfileChange(
+	log logr.Logger,
+	obj client.Object,
+	kind string,
+	previous []string,
+	desired []desiredVPAState,
+) {
+	if len(previous) == 0 {
+		return
+	}
+
+	current := make([]string, 0, len(desired))
+	for _, d := range desired {
+		current = append(current, d.Profile)
+	}
+	slices.Sort(current)
+	if slices.Equal(previous, current) {
+		return
+	}
+
+	from, to := strings.Join(previous, ","), strings.Join(current, ",")
+	log.Info("profile changed", "from", from, "to", to)
+
+	b.Recorder.Eventf(
+		obj,
+		nil,
+		corev1.EventTypeNormal,
+		vpaEventProfileChanged,
+		vpaActionSwitchProfile,
+		"Profile changed from %s to %s",
+		from,
+		to,
+	)
+
+	b.Metrics.IncProfileChanges(obj.GetNamespace(), kind, from, to)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBaseReconciler_ReconcileWorkload_ProfileChanged(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, dep *appsv1.Deployment) (*BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(20)
+		promReg := prometheus.NewRegistry()
+
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build(),
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1": {},
+					"p2": {},
+					"p3": {},
+				},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, recorder, promReg
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		return dep
+	}
+
+	// profileChangedEvents drains the recorder and returns the ProfileChanged events.
+	profileChangedEvents := func(recorder *events.FakeRecorder) []string {
+		var out []string
+		for {
+			select {
+			case e := <-recorder.Events:
+				if strings.HasPrefix(e, "Normal ProfileChanged ") {
+					out = append(out, e)
+				}
+			default:
+				return out
+			}
+		}
+	}
+
+	t.Run("Reports a profile switch", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		reconciler, recorder, promReg := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Empty(t, profileChangedEvents(recorder), "first opt-in is not a profile change")
+
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p2"})
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Normal ProfileChanged Profile changed from p1 to p2"}, profileChangedEvents(recorder))
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_profile_changes_total", map[string]string{
+			"namespace":   "ns1",
+			"kind":        "Deployment",
+			"old_profile": "p1",
+			"new_profile": "p2",
+		}))
+	})
+
+	t.Run("Reports changes to the profile list", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		reconciler, recorder, _ := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p3,p1"})
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Normal ProfileChanged Profile changed from p1 to p1,p3"}, profileChangedEvents(recorder))
+	})
+
+	t.Run("Ignores reconciles without a switch", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		reconciler, recorder, promReg := newReconciler(t, dep)
+
+		for range 2 {
+			_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+			require.NoError(t, err)
+		}
+
+		assert.Empty(t, profileChangedEvents(recorder))
+		mfs, err := promReg.Gather()
+		require.NoError(t, err)
+		for _, mf := range mfs {
+			assert.NotEqual(t, "autovpa_profile_changes_total", mf.GetName())
+		}
+	})
+}
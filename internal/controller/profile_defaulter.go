@@ -0,0 +1,114 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ProfileDefaulterPath is the webhook server path serving ProfileDefaulter.
+const ProfileDefaulterPath string = "/mutate-autovpa-profile"
+
+// profileDefaultingEnabled is the ProfileDefaultingLabel value opting a namespace in.
+const profileDefaultingEnabled string = "enabled"
+
+// ProfileDefaulter is a mutating admission handler that stamps the profile
+// annotation with "default" onto newly created Deployments, StatefulSets and
+// DaemonSets in namespaces labelled with ProfileDefaultingLabel=enabled. The
+// keyword resolves to the default profile (or kind default) at reconcile
+// time, so later config reloads apply without rewriting workloads.
+// Workloads already carrying a profile annotation are left untouched.
+type ProfileDefaulter struct {
+	KubeClient client.Client // Reads namespaces for the opt-in label.
+	Logger     *logr.Logger  // Logger for admission decisions.
+	Meta       MetaConfig    // Profile annotation keys.
+}
+
+// Handle implements admission.Handler.
+func (d *ProfileDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create || !defaultableKind(req.Kind) {
+		return admission.Allowed("not a workload creation")
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(req.Object.Raw, &obj.Object); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if _, present := d.Meta.profileAnnotation(obj.GetAnnotations()); present {
+		return admission.Allowed("profile annotation already set")
+	}
+
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	ns := &corev1.Namespace{}
+	if err := d.KubeClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return admission.Allowed("namespace not found")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if ns.GetLabels()[ProfileDefaultingLabel] != profileDefaultingEnabled {
+		return admission.Allowed("namespace not opted in")
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[d.Meta.ProfileKey] = defaultProfileKeyword
+	obj.SetAnnotations(annotations)
+
+	mutated, err := json.Marshal(obj.Object)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	d.Logger.V(1).Info(
+		"defaulting profile annotation",
+		"namespace", namespace,
+		"kind", req.Kind.Kind,
+		"name", obj.GetName(),
+	)
+	return admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+}
+
+// defaultableKind reports whether kind is a built-in workload the defaulting
+// webhook annotates.
+func defaultableKind(kind metav1.GroupVersionKind) bool {
+	if kind.Group != DeploymentGVK.Group {
+		return false
+	}
+	switch kind.Kind {
+	case DeploymentGVK.Kind, StatefulSetGVK.Kind, DaemonSetGVK.Kind:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestProfileDefaulter_Handle(t *testing.T) {
+	t.Parallel()
+
+	optedIn := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "ns1",
+		Labels: map[string]string{ProfileDefaultingLabel: "enabled"},
+	}}
+	optedOut := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}
+
+	newDefaulter := func(t *testing.T, funcs interceptor.Funcs) *ProfileDefaulter {
+		t.Helper()
+		logger := logr.Discard()
+		return &ProfileDefaulter{
+			KubeClient: fake.NewClientBuilder().
+				WithScheme(newScheme(t)).
+				WithObjects(optedIn, optedOut).
+				WithInterceptorFuncs(funcs).
+				Build(),
+			Logger: &logger,
+			Meta: MetaConfig{
+				ProfileKey:        "vpa/profile",
+				LegacyProfileKeys: []string{"old/profile"},
+				ManagedLabel:      "vpa/managed",
+			},
+		}
+	}
+
+	newRequest := func(t *testing.T, op admissionv1.Operation, kind string, obj runtime.Object) admission.Request {
+		t.Helper()
+		raw, err := json.Marshal(obj)
+		require.NoError(t, err)
+		meta := obj.(client.Object)
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: kind},
+			Namespace: meta.GetNamespace(),
+			Name:      meta.GetName(),
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	newDeployment := func(namespace string, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "demo", Annotations: annotations},
+		}
+	}
+
+	t.Run("Adds annotations map with default profile", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDefaulter(t, interceptor.Funcs{})
+		resp := d.Handle(context.Background(), newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns1", nil)))
+
+		require.True(t, resp.Allowed)
+		assert.Equal(t, []jsonpatch.JsonPatchOperation{{
+			Operation: "add",
+			Path:      "/metadata/annotations",
+			Value:     map[string]any{"vpa/profile": "default"},
+		}}, resp.Patches)
+	})
+
+	t.Run("Adds annotation next to existing ones", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDefaulter(t, interceptor.Funcs{})
+		for _, kind := range []string{"StatefulSet", "DaemonSet"} {
+			obj := &appsv1.StatefulSet{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: kind},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "demo", Annotations: map[string]string{"team": "a"}},
+			}
+			resp := d.Handle(context.Background(), newRequest(t, admissionv1.Create, kind, obj))
+
+			require.True(t, resp.Allowed, kind)
+			assert.Equal(t, []jsonpatch.JsonPatchOperation{{
+				Operation: "add",
+				Path:      "/metadata/annotations/vpa~1profile",
+				Value:     "default",
+			}}, resp.Patches, kind)
+		}
+	})
+
+	t.Run("Leaves workloads alone", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDefaulter(t, interceptor.Funcs{})
+		cases := map[string]admission.Request{
+			"annotated":         newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns1", map[string]string{"vpa/profile": "p1"})),
+			"opted out":         newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns1", map[string]string{"vpa/profile": ""})),
+			"legacy key":        newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns1", map[string]string{"old/profile": "p1"})),
+			"update":            newRequest(t, admissionv1.Update, "Deployment", newDeployment("ns1", nil)),
+			"other kind":        newRequest(t, admissionv1.Create, "ReplicaSet", newDeployment("ns1", nil)),
+			"not opted in":      newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns2", nil)),
+			"missing namespace": newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns3", nil)),
+		}
+		for name, req := range cases {
+			resp := d.Handle(context.Background(), req)
+			assert.True(t, resp.Allowed, name)
+			assert.Empty(t, resp.Patches, name)
+		}
+	})
+
+	t.Run("Errors when namespace lookup fails", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDefaulter(t, interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return assert.AnError
+			},
+		})
+		resp := d.Handle(context.Background(), newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns1", nil)))
+
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, int32(http.StatusInternalServerError), resp.Result.Code)
+	})
+
+	t.Run("Rejects malformed objects", func(t *testing.T) {
+		t.Parallel()
+
+		d := newDefaulter(t, interceptor.Funcs{})
+		req := newRequest(t, admissionv1.Create, "Deployment", newDeployment("ns1", nil))
+		req.Object.Raw = []byte("{")
+		resp := d.Handle(context.Background(), req)
+
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, int32(http.StatusBadRequest), resp.Result.Code)
+	})
+}
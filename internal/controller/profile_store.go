@@ -0,0 +1,54 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import "sync/atomic"
+
+// ProfileStore holds the profile settings shared by the workload reconcilers
+// and the unmanaged workloads reporter, so a reloaded profiles file applies
+// without a restart. Readers take a snapshot per reconcile or recount.
+type ProfileStore struct {
+	current atomic.Pointer[ProfileConfig]
+}
+
+// NewProfileStore returns a store holding profiles.
+func NewProfileStore(profiles ProfileConfig) *ProfileStore {
+	s := &ProfileStore{}
+	s.Store(profiles)
+	return s
+}
+
+// Load returns the current profile settings.
+func (s *ProfileStore) Load() ProfileConfig {
+	return *s.current.Load()
+}
+
+// Store replaces the profile settings.
+func (s *ProfileStore) Store(profiles ProfileConfig) {
+	s.current.Store(&profiles)
+}
+
+// withCurrentProfiles returns b with Profiles taken from its ProfileStore, so
+// a reconcile sees one profile configuration even while it is reloaded.
+func (b *BaseReconciler) withCurrentProfiles() *BaseReconciler {
+	if b.ProfileStore == nil {
+		return b
+	}
+	snapshot := *b
+	snapshot.Profiles = b.ProfileStore.Load()
+	return &snapshot
+}
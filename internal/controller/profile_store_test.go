@@ -0,0 +1,108 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProfileStore(t *testing.T) {
+	t.Parallel()
+
+	withMode := func(mode vpaautoscaling.UpdateMode) ProfileConfig {
+		return ProfileConfig{
+			Entries: map[string]config.Profile{"p1": {Spec: config.ProfileSpec{
+				UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{UpdateMode: ptr.To(mode)},
+			}}},
+			Default:      "p1",
+			NameTemplate: flag.DefaultNameTemplate,
+		}
+	}
+
+	t.Run("Load returns the stored profiles", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewProfileStore(withMode(vpaautoscaling.UpdateModeOff))
+		assert.Equal(t, withMode(vpaautoscaling.UpdateModeOff), store.Load())
+
+		store.Store(withMode(vpaautoscaling.UpdateModeRecreate))
+		assert.Equal(t, withMode(vpaautoscaling.UpdateModeRecreate), store.Load())
+	})
+
+	t.Run("Reconciles with the profiles stored last", func(t *testing.T) {
+		t.Parallel()
+
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build()
+		logger := logr.Discard()
+		store := NewProfileStore(withMode(vpaautoscaling.UpdateModeOff))
+		reconciler := &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			ProfileStore: store,
+		}
+
+		updateMode := func(t *testing.T) string {
+			t.Helper()
+			vpa := newVPAObject()
+			require.NoError(t, kubeClient.Get(context.Background(), types.NamespacedName{
+				Namespace: "ns1",
+				Name:      renderDeploymentVPAName(t, "ns1", "demo", "p1"),
+			}, vpa))
+			mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+			return mode
+		}
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, "Off", updateMode(t))
+
+		store.Store(withMode(vpaautoscaling.UpdateModeRecreate))
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Equal(t, "Recreate", updateMode(t))
+		assert.Empty(t, reconciler.Profiles.Entries, "the reconciler's own profiles stay untouched")
+	})
+}
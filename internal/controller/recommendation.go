@@ -0,0 +1,97 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// mirrorRecommendation copies the VPA's target recommendation onto the owner
+// workload's RecommendationAnnotation.
+//
+// VPAs without a recommendation are skipped, leaving any previous value in
+// place. The workload is only patched when the summary changed; the workload
+// reconcilers do not react to this annotation, so mirroring cannot loop.
+func (r *VPAReconciler) mirrorRecommendation(
+	ctx context.Context,
+	log logr.Logger,
+	vpa *unstructured.Unstructured,
+	owner *unstructured.Unstructured,
+) error {
+	summary, err := recommendationSummary(vpa)
+	if err != nil {
+		return err
+	}
+	if summary == "" || owner.GetAnnotations()[RecommendationAnnotation] == summary {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(owner.DeepCopy())
+	annotations := owner.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[RecommendationAnnotation] = summary
+	owner.SetAnnotations(annotations)
+
+	if err := r.KubeClient.Patch(ctx, owner, patchBase); err != nil {
+		return fmt.Errorf("mirror recommendation onto %s %s/%s: %w",
+			owner.GetKind(), owner.GetNamespace(), owner.GetName(), err)
+	}
+
+	log.V(1).Info("mirrored VPA recommendation onto workload", "recommendation", summary)
+	return nil
+}
+
+// recommendationSummary renders the target recommendation of every container
+// as compact JSON, e.g. {"app":{"cpu":"100m","memory":"128Mi"}}.
+// It returns "" if the VPA has no recommendation yet.
+func recommendationSummary(vpa *unstructured.Unstructured) (string, error) {
+	raw, found, err := unstructured.NestedMap(vpa.Object, "status", "recommendation")
+	if err != nil || !found {
+		return "", err
+	}
+
+	var rec vpaautoscaling.RecommendedPodResources
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &rec); err != nil {
+		return "", fmt.Errorf("convert VPA recommendation: %w", err)
+	}
+	if len(rec.ContainerRecommendations) == 0 {
+		return "", nil
+	}
+
+	summary := make(map[string]corev1.ResourceList, len(rec.ContainerRecommendations))
+	for _, c := range rec.ContainerRecommendations {
+		summary[c.ContainerName] = c.Target
+	}
+
+	// Map keys are marshaled in sorted order, so the output is stable.
+	out, err := json.Marshal(summary)
+	if err != nil {
+		return "", fmt.Errorf("marshal VPA recommendation: %w", err)
+	}
+	return string(out), nil
+}
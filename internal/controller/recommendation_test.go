@@ -0,0 +1,181 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// setRecommendation sets a status recommendation with the given container targets.
+func setRecommendation(vpa *unstructured.Unstructured, targets map[string]map[string]any) {
+	var recs []any
+	for name, target := range targets {
+		recs = append(recs, map[string]any{
+			"containerName": name,
+			"target":        target,
+		})
+	}
+	vpa.Object["status"] = map[string]any{
+		"recommendation": map[string]any{"containerRecommendations": recs},
+	}
+}
+
+func TestRecommendationSummary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Renders targets keyed by container", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newVPAObject()
+		setRecommendation(vpa, map[string]map[string]any{
+			"app":     {"cpu": "100m", "memory": "128Mi"},
+			"sidecar": {"cpu": "10m"},
+		})
+
+		summary, err := recommendationSummary(vpa)
+		require.NoError(t, err)
+		assert.Equal(t, `{"app":{"cpu":"100m","memory":"128Mi"},"sidecar":{"cpu":"10m"}}`, summary)
+	})
+
+	t.Run("Returns empty without status", func(t *testing.T) {
+		t.Parallel()
+
+		summary, err := recommendationSummary(newVPAObject())
+		require.NoError(t, err)
+		assert.Empty(t, summary)
+	})
+
+	t.Run("Returns empty without container recommendations", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newVPAObject()
+		vpa.Object["status"] = map[string]any{"recommendation": map[string]any{}}
+
+		summary, err := recommendationSummary(vpa)
+		require.NoError(t, err)
+		assert.Empty(t, summary)
+	})
+
+	t.Run("Fails on malformed recommendation", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newVPAObject()
+		vpa.Object["status"] = map[string]any{"recommendation": map[string]any{"containerRecommendations": "nope"}}
+
+		_, err := recommendationSummary(vpa)
+		assert.ErrorContains(t, err, "convert VPA recommendation")
+	})
+}
+
+func TestVPAReconciler_Reconcile_MirrorRecommendations(t *testing.T) {
+	t.Parallel()
+
+	const namespace = "default"
+	const ownerName = "demo"
+	const vpaName = "demo-vpa"
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: vpaName, Namespace: namespace}}
+
+	newVPA := func() *unstructured.Unstructured {
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef(ownerName)})
+		return vpa
+	}
+
+	t.Run("Mirrors recommendation onto the owner", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newVPA()
+		setRecommendation(vpa, map[string]map[string]any{"app": {"cpu": "100m", "memory": "128Mi"}})
+
+		r := newTestVPAReconciler(t, newOwnerUnstructuredDeployment(t, namespace, ownerName), vpa)
+		r.MirrorRecommendations = true
+
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		require.NoError(t, r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(owner), owner))
+		assert.Equal(t, `{"app":{"cpu":"100m","memory":"128Mi"}}`, owner.GetAnnotations()[RecommendationAnnotation])
+	})
+
+	t.Run("Skips patch when summary is unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newVPA()
+		setRecommendation(vpa, map[string]map[string]any{"app": {"cpu": "100m"}})
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		owner.SetAnnotations(map[string]string{RecommendationAnnotation: `{"app":{"cpu":"100m"}}`})
+
+		r := newTestVPAReconciler(t, owner, vpa)
+		r.MirrorRecommendations = true
+
+		patches := 0
+		r.KubeClient = interceptor.NewClient(r.KubeClient.(client.WithWatch), interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patches++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Zero(t, patches)
+	})
+
+	t.Run("Leaves owner untouched without status", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t, newOwnerUnstructuredDeployment(t, namespace, ownerName), newVPA())
+		r.MirrorRecommendations = true
+
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		require.NoError(t, r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(owner), owner))
+		assert.NotContains(t, owner.GetAnnotations(), RecommendationAnnotation)
+	})
+
+	t.Run("Does nothing when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newVPA()
+		setRecommendation(vpa, map[string]map[string]any{"app": {"cpu": "100m"}})
+
+		r := newTestVPAReconciler(t, newOwnerUnstructuredDeployment(t, namespace, ownerName), vpa)
+
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		require.NoError(t, r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(owner), owner))
+		assert.NotContains(t, owner.GetAnnotations(), RecommendationAnnotation)
+	})
+}
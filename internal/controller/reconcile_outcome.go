@@ -0,0 +1,123 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Workload reconcile outcomes, reported in the terminal reconcile log line and
+// autovpa_reconcile_outcomes_total. Skips are reported as "skipped:<reason>".
+const (
+	outcomeCreated = "created"
+	outcomeUpdated = "updated"
+	outcomeDeleted = "deleted"
+	outcomeNoop    = "noop"
+	outcomeError   = "error"
+
+	outcomeSkippedPrefix = "skipped:"
+
+	// outcomeSkipReasonCircuitOpen is the skip reason while the apply circuit
+	// is open; it is not counted in autovpa_vpa_skipped_total.
+	outcomeSkipReasonCircuitOpen = "circuit_open"
+)
+
+// skippedOutcome returns the outcome of a reconcile skipped for reason.
+func skippedOutcome(reason string) string {
+	return outcomeSkippedPrefix + reason
+}
+
+// outcomeRank orders outcomes so the most significant one of a reconcile
+// wins: any change beats a skip, and a skip beats doing nothing.
+func outcomeRank(outcome string) int {
+	switch outcome {
+	case outcomeCreated:
+		return 4
+	case outcomeUpdated:
+		return 3
+	case outcomeDeleted:
+		return 2
+	case outcomeNoop, "":
+		return 0
+	default:
+		return 1
+	}
+}
+
+// reconcileOutcome collects the outcome of one workload reconcile.
+type reconcileOutcome struct {
+	value string
+}
+
+type reconcileOutcomeKey struct{}
+
+// withReconcileOutcome returns a context carrying a new reconcileOutcome.
+func withReconcileOutcome(ctx context.Context) (context.Context, *reconcileOutcome) {
+	o := &reconcileOutcome{}
+	return context.WithValue(ctx, reconcileOutcomeKey{}, o), o
+}
+
+// recordOutcome records outcome on the reconcile in ctx unless a more
+// significant outcome was already recorded. Contexts without a
+// reconcileOutcome are ignored.
+func recordOutcome(ctx context.Context, outcome string) {
+	o, ok := ctx.Value(reconcileOutcomeKey{}).(*reconcileOutcome)
+	if !ok || o == nil {
+		return
+	}
+	if outcomeRank(outcome) > outcomeRank(o.value) {
+		o.value = outcome
+	}
+}
+
+// result returns the final outcome, given the error the reconcile returned.
+func (o *reconcileOutcome) result(err error) string {
+	switch {
+	case err != nil:
+		return outcomeError
+	case o.value == "":
+		return outcomeNoop
+	default:
+		return o.value
+	}
+}
+
+// reportOutcome writes the terminal log line of a workload reconcile and
+// counts its outcome. Changes and errors log at Info; no-ops and skips, which
+// repeat on every resync and already log their own details, log at V(1).
+func (b *BaseReconciler) reportOutcome(obj client.Object, targetGVK schema.GroupVersionKind, outcome string, err error) {
+	log := b.Logger.WithValues(
+		"namespace", obj.GetNamespace(),
+		"workload", obj.GetName(),
+		"kind", targetGVK.Kind,
+		"outcome", outcome,
+	)
+
+	switch outcome {
+	case outcomeCreated, outcomeUpdated, outcomeDeleted:
+		log.Info("reconcile finished")
+	case outcomeError:
+		log.Info("reconcile finished", "error", err.Error())
+	default:
+		log.V(1).Info("reconcile finished")
+	}
+
+	b.Metrics.IncReconcileOutcome(targetGVK.Kind, outcome)
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestBaseReconciler_ReconcileWorkload_Outcomes(t *testing.T) {
+	t.Parallel()
+
+	deploymentGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+	newDeployment := func(profile string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		if profile != "" {
+			dep.SetAnnotations(map[string]string{"vpa/profile": profile})
+		}
+		return dep
+	}
+
+	newReconciler := func(t *testing.T, kubeClient client.Client) (BaseReconciler, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, promReg
+	}
+
+	newOwnedVPA := func(t *testing.T, dep *appsv1.Deployment) *unstructured.Unstructured {
+		t.Helper()
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName(renderDeploymentVPAName(t, "ns1", "demo", "p1"))
+		vpa.SetLabels(map[string]string{"vpa/managed": "true", "vpa/profile": "p1"})
+		require.NoError(t, controllerutil.SetControllerReference(dep, vpa, newScheme(t)))
+		return vpa
+	}
+
+	outcomeCount := func(t *testing.T, promReg *prometheus.Registry, outcome string) float64 {
+		t.Helper()
+		return mustGetCounterValue(t, promReg, "autovpa_reconcile_outcomes_total", map[string]string{
+			"kind":    "Deployment",
+			"outcome": outcome,
+		})
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("p1")
+		br, promReg := newReconciler(t, fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build())
+
+		_, err := br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, outcomeCreated))
+	})
+
+	t.Run("Noop", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("p1")
+		br, promReg := newReconciler(t, fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build())
+
+		_, err := br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.NoError(t, err)
+		_, err = br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, outcomeCreated))
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, outcomeNoop))
+	})
+
+	t.Run("Updated", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("p1")
+		existing := newOwnedVPA(t, dep)
+		existing.SetLabels(map[string]string{"old": "label"})
+		br, promReg := newReconciler(t, fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep, existing).Build())
+
+		_, err := br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, outcomeUpdated))
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("")
+		existing := newOwnedVPA(t, dep)
+		br, promReg := newReconciler(t, fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep, existing).Build())
+
+		_, err := br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, outcomeDeleted))
+	})
+
+	t.Run("Skipped without annotation", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("")
+		br, promReg := newReconciler(t, fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build())
+
+		_, err := br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, "skipped:annotation_missing"))
+	})
+
+	t.Run("Skipped for missing profile", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("unknown")
+		br, promReg := newReconciler(t, fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build())
+
+		_, err := br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, "skipped:profile_missing"))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dep := newDeployment("p1")
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return errors.New("boom")
+			},
+		}).Build()
+		br, promReg := newReconciler(t, kubeClient)
+
+		_, err := br.ReconcileWorkload(ctx, dep, deploymentGVK)
+		require.Error(t, err)
+
+		assert.Equal(t, float64(1), outcomeCount(t, promReg, outcomeError))
+	})
+}
+
+func TestRecordOutcome(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Keeps the most significant outcome", func(t *testing.T) {
+		t.Parallel()
+		ctx, outcome := withReconcileOutcome(context.Background())
+
+		recordOutcome(ctx, skippedOutcome(vpaSkipReasonAnnotationMissing))
+		assert.Equal(t, "skipped:annotation_missing", outcome.result(nil))
+
+		recordOutcome(ctx, outcomeDeleted)
+		recordOutcome(ctx, skippedOutcome(vpaSkipReasonHPAConflict))
+		assert.Equal(t, outcomeDeleted, outcome.result(nil))
+
+		recordOutcome(ctx, outcomeCreated)
+		recordOutcome(ctx, outcomeUpdated)
+		assert.Equal(t, outcomeCreated, outcome.result(nil))
+	})
+
+	t.Run("Defaults to noop", func(t *testing.T) {
+		t.Parallel()
+		_, outcome := withReconcileOutcome(context.Background())
+		assert.Equal(t, outcomeNoop, outcome.result(nil))
+	})
+
+	t.Run("Errors win", func(t *testing.T) {
+		t.Parallel()
+		ctx, outcome := withReconcileOutcome(context.Background())
+		recordOutcome(ctx, outcomeCreated)
+		assert.Equal(t, outcomeError, outcome.result(errors.New("boom")))
+	})
+
+	t.Run("Ignores contexts without an outcome", func(t *testing.T) {
+		t.Parallel()
+		assert.NotPanics(t, func() { recordOutcome(context.Background(), outcomeCreated) })
+	})
+}
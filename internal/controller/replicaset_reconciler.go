@@ -0,0 +1,151 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containeroo/autovpa/internal/predicates"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ReplicaSetReconciler reconciles standalone ReplicaSets and manages their VPAs.
+//
+// Its responsibilities are:
+//
+//   - Drive the desired VPA state for ReplicaSets without a controlling
+//     Deployment by delegating to BaseReconciler.ReconcileWorkload.
+//   - Perform cleanup of managed VPAs when a ReplicaSet is deleted or adopted
+//     by a Deployment.
+//
+// ReplicaSets controlled by a Deployment are left alone: their VPAs belong to
+// the Deployment, and managing both would double-manage the same pods.
+type ReplicaSetReconciler struct {
+	BaseReconciler
+}
+
+// Reconcile ensures that the standalone ReplicaSet's opted-in state (profile
+// annotation) is reflected in its managed VPAs.
+//
+// High-level flow:
+//
+//  1. Try to load the ReplicaSet.
+//     - If it does not exist anymore, proactively delete any managed VPAs
+//     that still point at this ReplicaSet (best-effort cleanup).
+//  2. If it is controlled by a Deployment, delete any managed VPAs left from
+//     when it was standalone and stop.
+//  3. Otherwise, delegate to ReconcileWorkload to create/update/delete
+//     the associated VPA based on the selected profile.
+func (r *ReplicaSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Fetch the current ReplicaSet object from the cache/API server.
+	rs := &appsv1.ReplicaSet{}
+	if err := r.KubeClient.Get(ctx, req.NamespacedName, rs); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The ReplicaSet has been deleted. We may still have managed VPAs
+			// with an ownerRef pointing at this name/namespace; clean them up.
+			logger.Info("ReplicaSet not found; cleaning managed VPAs if any")
+
+			if err := r.DeleteManagedVPAsForGoneWorkload(
+				ctx,
+				&appsv1.ReplicaSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: req.Namespace,
+						Name:      req.Name,
+					},
+				},
+				ReplicaSetGVK.Kind,
+			); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+
+		// Any non-NotFound error should be retried by controller-runtime.
+		return ctrl.Result{}, fmt.Errorf("failed to fetch ReplicaSet: %w", err)
+	}
+
+	// The Deployment owns this ReplicaSet's VPAs; drop any we created before
+	// it was adopted.
+	if controlledByDeployment(rs) {
+		logger.V(1).Info("ReplicaSet is controlled by a Deployment; cleaning managed VPAs if any")
+
+		if err := r.DeleteManagedVPAsForOptOut(ctx, rs, ReplicaSetGVK.Kind); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Standalone ReplicaSet: reconcile its VPA according to the selected profile.
+	return r.ReconcileWorkload(ctx, rs, ReplicaSetGVK)
+}
+
+// SetupWithManager wires the ReplicaSet controller into the manager.
+//
+//   - ReplicaSet events are filtered by the profile annotation lifecycle and
+//     limited to ReplicaSets not controlled by a Deployment. Changes of the
+//     controller ownerRef are let through, so an adopted ReplicaSet has its
+//     managed VPAs cleaned up.
+//   - Owned VPA events are filtered by ManagedVPALifecycle, so spec/label drift
+//     requeues the owning ReplicaSet ("snap back" behavior) while still ignoring
+//     status churn.
+//   - With namespace defaults enabled, a change to a Namespace's default-profile
+//     annotation requeues all ReplicaSets in that namespace.
+func (r *ReplicaSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	vpa := newVPAObject()
+
+	filter := predicate.Or(
+		predicate.And(predicates.NotControlledBy(DeploymentGVK.Kind), r.workloadPredicate()),
+		predicates.ControllerOwnerChanged(),
+	)
+
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(r.controllerOptions()).
+		// Primary resource: only standalone ReplicaSets whose profile annotation is added/removed/present.
+		For(&appsv1.ReplicaSet{}, builder.WithPredicates(
+			countedPredicate(r.Metrics, filter, "replicaset", "ReplicaSet"),
+		)).
+		// Secondary resource: any change to a managed VPA should requeue the owner.
+		// We use a label-based predicate here so only VPAs with the managed label
+		// generate events for this controller.
+		Owns(vpa, builder.WithPredicates(
+			countedPredicate(r.Metrics,
+				predicates.ManagedVPALifecycle(r.Meta.ManagedLabel, r.Meta.managedMatchValue(), r.Meta.ProfileKey, r.Meta.vpaAnnotationKeys()...),
+				"replicaset", vpaResource),
+		))
+
+	// Namespace default-profile changes requeue the namespace's workloads.
+	return r.watchNamespaceDefaults(bldr, func() client.ObjectList { return &appsv1.ReplicaSetList{} }).
+		Complete(r)
+}
+
+// controlledByDeployment reports whether the ReplicaSet's controller ownerRef
+// points at a Deployment.
+func controlledByDeployment(rs *appsv1.ReplicaSet) bool {
+	ref := metav1.GetControllerOf(rs)
+	return ref != nil && ref.Kind == DeploymentGVK.Kind
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func TestReplicaSetReconciler_SetupWithManager(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.New(ctrl.GetConfigOrDie(), manager.Options{})
+	assert.NoError(t, err, "Failed to create manager")
+
+	reconciler := &ReplicaSetReconciler{
+		BaseReconciler: BaseReconciler{
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).Build(),
+			Logger:     &logr.Logger{},
+			Recorder:   events.NewFakeRecorder(10),
+		},
+	}
+
+	err = reconciler.SetupWithManager(mgr)
+	assert.NoError(t, err, "SetupWithManager should not return an error")
+}
+
+func TestReplicaSetReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) *ReplicaSetReconciler {
+		t.Helper()
+		logger := logr.Discard()
+		return &ReplicaSetReconciler{
+			BaseReconciler: BaseReconciler{
+				KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+				Logger:     &logger,
+				Recorder:   events.NewFakeRecorder(10),
+				Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+				Meta: MetaConfig{
+					ProfileKey:   "vpa/profile",
+					ManagedLabel: "vpa/managed",
+				},
+				Profiles: ProfileConfig{
+					Entries:      map[string]config.Profile{"p1": {}},
+					Default:      "p1",
+					NameTemplate: flag.DefaultNameTemplate,
+				},
+			},
+		}
+	}
+
+	newReplicaSet := func(owner *metav1.OwnerReference) *appsv1.ReplicaSet {
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "legacy",
+			UID:         "rs-uid",
+			Annotations: map[string]string{"vpa/profile": "p1"},
+		}}
+		if owner != nil {
+			rs.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return rs
+	}
+
+	listVPAs := func(t *testing.T, r *ReplicaSetReconciler) []unstructured.Unstructured {
+		t.Helper()
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(vpaListGVK)
+		require.NoError(t, r.KubeClient.List(t.Context(), list, client.InNamespace("ns1")))
+		return list.Items
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "legacy"}}
+
+	t.Run("ReplicaSet not found", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(t)
+
+		result, err := r.Reconcile(t.Context(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+
+	t.Run("Creates a VPA for a standalone ReplicaSet", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(t, newReplicaSet(nil))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		vpas := listVPAs(t, r)
+		require.Len(t, vpas, 1)
+		owner := metav1.GetControllerOf(&vpas[0])
+		require.NotNil(t, owner)
+		assert.Equal(t, ReplicaSetGVK.Kind, owner.Kind)
+		assert.Equal(t, "legacy", owner.Name)
+
+		kind, _, _ := unstructured.NestedString(vpas[0].Object, "spec", "targetRef", "kind")
+		assert.Equal(t, ReplicaSetGVK.Kind, kind)
+	})
+
+	t.Run("Skips a ReplicaSet whose requests are within bounds", func(t *testing.T) {
+		t.Parallel()
+
+		rs := newReplicaSet(nil)
+		rs.Spec.Template.Spec.Containers = []corev1.Container{{
+			Name:      "app",
+			Resources: corev1.ResourceRequirements{Requests: resources("100m", "128Mi")},
+		}}
+		r := newReconciler(t, rs)
+		r.SkipIfWithinBounds = true
+		r.Profiles.Entries = map[string]config.Profile{"p1": {Spec: config.ProfileSpec{
+			ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+				ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+					ContainerName: vpaautoscaling.DefaultContainerResourcePolicy,
+					MinAllowed:    resources("50m", "64Mi"),
+					MaxAllowed:    resources("500m", "512Mi"),
+				}},
+			},
+		}}}
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		assert.Empty(t, listVPAs(t, r))
+	})
+
+	t.Run("Leaves a ReplicaSet controlled by a Deployment alone", func(t *testing.T) {
+		t.Parallel()
+
+		r := newReconciler(t, newReplicaSet(&metav1.OwnerReference{
+			APIVersion: DeploymentGVK.GroupVersion().String(),
+			Kind:       DeploymentGVK.Kind,
+			Name:       "app",
+			Controller: ptr.To(true),
+		}))
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		assert.Empty(t, listVPAs(t, r))
+	})
+
+	t.Run("Deletes the VPA once a Deployment adopts the ReplicaSet", func(t *testing.T) {
+		t.Parallel()
+
+		rs := newReplicaSet(nil)
+		r := newReconciler(t, rs)
+
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		require.Len(t, listVPAs(t, r), 1)
+
+		require.NoError(t, r.KubeClient.Get(t.Context(), req.NamespacedName, rs))
+		rs.OwnerReferences = append(rs.OwnerReferences, metav1.OwnerReference{
+			APIVersion: DeploymentGVK.GroupVersion().String(),
+			Kind:       DeploymentGVK.Kind,
+			Name:       "app",
+			UID:        "dep-uid",
+			Controller: ptr.To(true),
+		})
+		require.NoError(t, r.KubeClient.Update(t.Context(), rs))
+
+		_, err = r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+
+		assert.Empty(t, listVPAs(t, r))
+	})
+}
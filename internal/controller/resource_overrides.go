@@ -0,0 +1,127 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+// errInvalidResourceAnnotation marks a workload resource override annotation
+// that cannot be applied.
+var errInvalidResourceAnnotation = errors.New("invalid resource annotation")
+
+// resourceOverrideAnnotations lists the workload annotations overriding
+// container policy bounds, with the resource and bound each one sets.
+var resourceOverrideAnnotations = []struct {
+	key      string
+	resource corev1.ResourceName
+	isMin    bool
+}{
+	{key: MinCPUAnnotation, resource: corev1.ResourceCPU, isMin: true},
+	{key: MinMemoryAnnotation, resource: corev1.ResourceMemory, isMin: true},
+	{key: MaxCPUAnnotation, resource: corev1.ResourceCPU},
+	{key: MaxMemoryAnnotation, resource: corev1.ResourceMemory},
+}
+
+// workloadResourceOverrides parses the workload's resource override
+// annotations, or returns nil when none is set. Errors wrap
+// errInvalidResourceAnnotation.
+func workloadResourceOverrides(annotations map[string]string) (*containerLimits, error) {
+	var overrides *containerLimits
+	for _, override := range resourceOverrideAnnotations {
+		value, ok := annotations[override.key]
+		if !ok {
+			continue
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s %q: %w", errInvalidResourceAnnotation, override.key, value, err)
+		}
+		if q.Sign() < 0 {
+			return nil, fmt.Errorf("%w %s %q: must not be negative", errInvalidResourceAnnotation, override.key, value)
+		}
+		if overrides == nil {
+			overrides = &containerLimits{Min: corev1.ResourceList{}, Max: corev1.ResourceList{}}
+		}
+		if override.isMin {
+			overrides.Min[override.resource] = q
+		} else {
+			overrides.Max[override.resource] = q
+		}
+	}
+	if overrides == nil {
+		return nil, nil
+	}
+
+	for name, lo := range overrides.Min {
+		if hi, ok := overrides.Max[name]; ok && lo.Cmp(hi) > 0 {
+			return nil, fmt.Errorf("%w: min %s %s exceeds max %s %s", errInvalidResourceAnnotation, name, lo.String(), name, hi.String())
+		}
+	}
+	return overrides, nil
+}
+
+// applyResourceOverrides sets the overridden minAllowed/maxAllowed resources
+// on every container policy, replacing the profile's values. An opposite bound
+// the override would cross is moved onto the override so the policy stays
+// valid. Without container policies a wildcard policy carrying the overrides
+// is added. The spec's resource policy must not be shared with the profile.
+func applyResourceOverrides(spec *vpaautoscaling.VerticalPodAutoscalerSpec, overrides *containerLimits) {
+	if overrides == nil {
+		return
+	}
+	if spec.ResourcePolicy == nil {
+		spec.ResourcePolicy = &vpaautoscaling.PodResourcePolicy{}
+	}
+	if len(spec.ResourcePolicy.ContainerPolicies) == 0 {
+		spec.ResourcePolicy.ContainerPolicies = []vpaautoscaling.ContainerResourcePolicy{
+			{ContainerName: vpaautoscaling.DefaultContainerResourcePolicy},
+		}
+	}
+
+	for i := range spec.ResourcePolicy.ContainerPolicies {
+		policy := &spec.ResourcePolicy.ContainerPolicies[i]
+		minAllowed, maxAllowed := policy.MinAllowed.DeepCopy(), policy.MaxAllowed.DeepCopy()
+		for name, q := range overrides.Min {
+			minAllowed = withResource(minAllowed, name, q)
+			if hi, ok := maxAllowed[name]; ok && q.Cmp(hi) > 0 {
+				maxAllowed[name] = q
+			}
+		}
+		for name, q := range overrides.Max {
+			maxAllowed = withResource(maxAllowed, name, q)
+			if lo, ok := minAllowed[name]; ok && q.Cmp(lo) < 0 {
+				minAllowed[name] = q
+			}
+		}
+		policy.MinAllowed, policy.MaxAllowed = minAllowed, maxAllowed
+	}
+}
+
+// withResource sets name to q in list, allocating the list when it is nil.
+func withResource(list corev1.ResourceList, name corev1.ResourceName, q resource.Quantity) corev1.ResourceList {
+	if list == nil {
+		list = corev1.ResourceList{}
+	}
+	list[name] = q
+	return list
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWorkloadResourceOverrides(t *testing.T) {
+	t.Parallel()
+
+	t.Run("No annotations", func(t *testing.T) {
+		t.Parallel()
+		overrides, err := workloadResourceOverrides(map[string]string{"other": "1Gi"})
+		require.NoError(t, err)
+		assert.Nil(t, overrides)
+	})
+
+	t.Run("Parses all bounds", func(t *testing.T) {
+		t.Parallel()
+		overrides, err := workloadResourceOverrides(map[string]string{
+			MinCPUAnnotation:    "100m",
+			MinMemoryAnnotation: "64Mi",
+			MaxCPUAnnotation:    "2",
+			MaxMemoryAnnotation: "2Gi",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &containerLimits{
+			Min: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Max: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		}, overrides)
+	})
+
+	t.Run("Invalid quantity", func(t *testing.T) {
+		t.Parallel()
+		_, err := workloadResourceOverrides(map[string]string{MaxMemoryAnnotation: "lots"})
+		require.ErrorIs(t, err, errInvalidResourceAnnotation)
+		assert.ErrorContains(t, err, `invalid resource annotation autovpa.containeroo.ch/max-memory "lots": `)
+	})
+
+	t.Run("Negative quantity", func(t *testing.T) {
+		t.Parallel()
+		_, err := workloadResourceOverrides(map[string]string{MaxCPUAnnotation: "-1"})
+		require.ErrorIs(t, err, errInvalidResourceAnnotation)
+		assert.EqualError(t, err, `invalid resource annotation autovpa.containeroo.ch/max-cpu "-1": must not be negative`)
+	})
+
+	t.Run("Min exceeds max", func(t *testing.T) {
+		t.Parallel()
+		_, err := workloadResourceOverrides(map[string]string{
+			MinMemoryAnnotation: "4Gi",
+			MaxMemoryAnnotation: "2Gi",
+		})
+		require.ErrorIs(t, err, errInvalidResourceAnnotation)
+		assert.EqualError(t, err, "invalid resource annotation: min memory 4Gi exceeds max memory 2Gi")
+	})
+}
+
+func TestApplyResourceOverrides(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Nil overrides leave the spec untouched", func(t *testing.T) {
+		t.Parallel()
+		spec := vpaautoscaling.VerticalPodAutoscalerSpec{}
+		applyResourceOverrides(&spec, nil)
+		assert.Nil(t, spec.ResourcePolicy)
+	})
+
+	t.Run("Adds a wildcard policy without container policies", func(t *testing.T) {
+		t.Parallel()
+		spec := vpaautoscaling.VerticalPodAutoscalerSpec{}
+		applyResourceOverrides(&spec, &containerLimits{
+			Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		})
+		require.NotNil(t, spec.ResourcePolicy)
+		assert.Equal(t, []vpaautoscaling.ContainerResourcePolicy{{
+			ContainerName: vpaautoscaling.DefaultContainerResourcePolicy,
+			MaxAllowed:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}}, spec.ResourcePolicy.ContainerPolicies)
+	})
+
+	t.Run("Replaces bounds on every policy", func(t *testing.T) {
+		t.Parallel()
+		spec := vpaautoscaling.VerticalPodAutoscalerSpec{
+			ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+				ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{
+					{
+						ContainerName: "app",
+						MaxAllowed: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+					},
+					{ContainerName: "sidecar"},
+				},
+			},
+		}
+		applyResourceOverrides(&spec, &containerLimits{
+			Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		})
+		assert.Equal(t, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}, spec.ResourcePolicy.ContainerPolicies[0].MaxAllowed)
+		assert.Equal(t, corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}, spec.ResourcePolicy.ContainerPolicies[1].MaxAllowed)
+	})
+
+	t.Run("Moves a crossed opposite bound", func(t *testing.T) {
+		t.Parallel()
+		spec := vpaautoscaling.VerticalPodAutoscalerSpec{
+			ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+				ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+					ContainerName: "app",
+					MinAllowed:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					MaxAllowed:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				}},
+			},
+		}
+		applyResourceOverrides(&spec, &containerLimits{
+			Min: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			Max: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		})
+		policy := spec.ResourcePolicy.ContainerPolicies[0]
+		assert.Equal(t, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}, policy.MinAllowed)
+		assert.Equal(t, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}, policy.MaxAllowed)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_ResourceOverrides(t *testing.T) {
+	t.Parallel()
+
+	profile := config.Profile{Spec: config.ProfileSpec{
+		ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+			ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+				ContainerName: "app",
+				MaxAllowed:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+			}},
+		},
+	}}
+
+	newReconciler := func(t *testing.T, dep *appsv1.Deployment) (*BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+		promReg := prometheus.NewRegistry()
+
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build(),
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": profile},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+		}, recorder, promReg
+	}
+
+	newDeployment := func(maxMemory string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetAnnotations(map[string]string{
+			"vpa/profile":       "p1",
+			MaxMemoryAnnotation: maxMemory,
+		})
+		return dep
+	}
+
+	vpaKey := types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}
+
+	t.Run("Applies the annotation to the VPA", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("2Gi")
+		reconciler, _, _ := newReconciler(t, dep)
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, reconciler.KubeClient.Get(context.Background(), vpaKey, vpa))
+		policies, ok := vpa.Object["spec"].(map[string]any)["resourcePolicy"].(map[string]any)["containerPolicies"].([]any)
+		require.True(t, ok)
+		require.Len(t, policies, 1)
+		assert.Equal(t, map[string]any{"memory": "2Gi"}, policies[0].(map[string]any)["maxAllowed"])
+	})
+
+	t.Run("Skips the workload on an invalid annotation", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment("lots")
+		reconciler, recorder, promReg := newReconciler(t, dep)
+		res, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		assert.Zero(t, res)
+
+		err = reconciler.KubeClient.Get(context.Background(), vpaKey, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, `Warning InvalidResourceAnnotation Skipping VPA: invalid resource annotation autovpa.containeroo.ch/max-memory "lots"`)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"reason":    "invalid_resource_annotation",
+		}))
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// quotaRequestResources maps ResourceQuota entries bounding pod requests to
+// the container resource they bound. VPA bounds apply to requests, so limits.*
+// entries are not considered.
+var quotaRequestResources = map[corev1.ResourceName]corev1.ResourceName{
+	corev1.ResourceCPU:            corev1.ResourceCPU,
+	corev1.ResourceRequestsCPU:    corev1.ResourceCPU,
+	corev1.ResourceMemory:         corev1.ResourceMemory,
+	corev1.ResourceRequestsMemory: corev1.ResourceMemory,
+}
+
+// namespaceQuotaLimits returns the smallest remaining quota (hard minus used)
+// per resource across the namespace's ResourceQuotas as upper bounds, or nil
+// when quotas are not respected, none bound CPU or memory requests or they
+// cannot be listed.
+//
+// Quotas whose status has not been populated yet count as unused. Exhausted
+// quotas are skipped: a zero maxAllowed would cap recommendations below what
+// the pods already request, and the quota admission rejects new pods anyway.
+func (b *BaseReconciler) namespaceQuotaLimits(ctx context.Context, namespace string) *containerLimits {
+	if !b.RespectResourceQuota {
+		return nil
+	}
+
+	list := &corev1.ResourceQuotaList{}
+	if err := b.KubeClient.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		b.namespaceLookupFailed(namespace, "resourcequotas", err)
+		return nil
+	}
+
+	limits := &containerLimits{Max: corev1.ResourceList{}}
+	for _, rq := range list.Items {
+		for quotaName, hard := range rq.Spec.Hard {
+			name, ok := quotaRequestResources[quotaName]
+			if !ok {
+				continue
+			}
+			left := hard.DeepCopy()
+			if used, ok := rq.Status.Used[quotaName]; ok {
+				left.Sub(used)
+			}
+			if left.Sign() <= 0 {
+				continue
+			}
+			if cur, ok := limits.Max[name]; !ok || left.Cmp(cur) < 0 {
+				limits.Max[name] = left
+			}
+		}
+	}
+
+	if len(limits.Max) == 0 {
+		return nil
+	}
+	return limits
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newResourceQuota returns a ResourceQuota with the given hard and used amounts.
+func newResourceQuota(namespace, name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestBaseReconciler_namespaceQuotaLimits(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, respect bool, objs ...client.Object) BaseReconciler {
+		t.Helper()
+		return BaseReconciler{
+			KubeClient:           fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			RespectResourceQuota: respect,
+		}
+	}
+
+	t.Run("Returns nil when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		rq := newResourceQuota("ns1", "rq", resources("2", ""), nil)
+		br := newReconciler(t, false, rq)
+
+		limits := br.namespaceQuotaLimits(context.Background(), "ns1")
+		assert.Nil(t, limits)
+	})
+
+	t.Run("Returns nil when ResourceQuotas cannot be listed", func(t *testing.T) {
+		t.Parallel()
+
+		logger := logr.Discard()
+		rq := newResourceQuota("ns1", "rq", resources("2", ""), nil)
+		br := BaseReconciler{
+			KubeClient:           fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(rq).WithInterceptorFuncs(namespaceLookupsFail).Build(),
+			Logger:               &logger,
+			RespectResourceQuota: true,
+		}
+
+		assert.Nil(t, br.namespaceQuotaLimits(context.Background(), "ns1"))
+	})
+
+	t.Run("Returns nil without ResourceQuotas", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true)
+
+		limits := br.namespaceQuotaLimits(context.Background(), "ns1")
+		assert.Nil(t, limits)
+	})
+
+	t.Run("Ignores unrelated resources and other namespaces", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true,
+			newResourceQuota("ns1", "pods", corev1.ResourceList{
+				corev1.ResourcePods:         resource.MustParse("10"),
+				corev1.ResourceLimitsMemory: resource.MustParse("4Gi"),
+			}, nil),
+			newResourceQuota("ns2", "other", resources("2", "2Gi"), nil),
+		)
+
+		limits := br.namespaceQuotaLimits(context.Background(), "ns1")
+		assert.Nil(t, limits)
+	})
+
+	t.Run("Takes the smallest remaining quota", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true,
+			newResourceQuota("ns1", "a", resources("4", "8Gi"), resources("1", "7Gi")),
+			newResourceQuota("ns1", "b", corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("2"),
+			}, corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("500m"),
+			}),
+		)
+
+		limits := br.namespaceQuotaLimits(context.Background(), "ns1")
+		require.NotNil(t, limits)
+		assert.Empty(t, limits.Min)
+		assert.True(t, resource.MustParse("1500m").Equal(*limits.Max.Cpu()))
+		assert.True(t, resource.MustParse("1Gi").Equal(*limits.Max.Memory()))
+	})
+
+	t.Run("Treats a quota without status as unused", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true, newResourceQuota("ns1", "rq", resources("2", ""), nil))
+
+		limits := br.namespaceQuotaLimits(context.Background(), "ns1")
+		require.NotNil(t, limits)
+		assert.True(t, resource.MustParse("2").Equal(*limits.Max.Cpu()))
+	})
+
+	t.Run("Skips exhausted quotas", func(t *testing.T) {
+		t.Parallel()
+
+		br := newReconciler(t, true,
+			newResourceQuota("ns1", "full", resources("2", "1Gi"), resources("2", "2Gi")),
+			newResourceQuota("ns1", "rq", resources("", "4Gi"), resources("", "1Gi")),
+		)
+
+		limits := br.namespaceQuotaLimits(context.Background(), "ns1")
+		require.NotNil(t, limits)
+		assert.NotContains(t, limits.Max, corev1.ResourceCPU)
+		assert.True(t, resource.MustParse("3Gi").Equal(*limits.Max.Memory()))
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_ResourceQuota(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, objs ...client.Object) (BaseReconciler, client.Client) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+
+		return BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{"p1": {Spec: config.ProfileSpec{
+					ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+						ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+							ContainerName: "*",
+							MinAllowed:    resources("100m", ""),
+							MaxAllowed:    resources("8", ""),
+						}},
+					},
+				}}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			RespectLimitRanges:   true,
+			RespectResourceQuota: true,
+		}, kubeClient
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		return dep
+	}
+
+	containerPolicy := func(t *testing.T, c client.Client) map[string]any {
+		t.Helper()
+		vpa := newVPAObject()
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{
+			Name:      renderDeploymentVPAName(t, "ns1", "demo", "p1"),
+			Namespace: "ns1",
+		}, vpa))
+		policies := vpa.Object["spec"].(map[string]any)["resourcePolicy"].(map[string]any)["containerPolicies"].([]any)
+		require.Len(t, policies, 1)
+		return policies[0].(map[string]any)
+	}
+
+	t.Run("Clamps to the remaining quota", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		rq := newResourceQuota("ns1", "rq", resources("2", "2Gi"), resources("1500m", "512Mi"))
+		reconciler, kubeClient := newReconciler(t, dep, rq)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		policy := containerPolicy(t, kubeClient)
+		assert.Equal(t, map[string]any{"cpu": "500m", "memory": "1536Mi"}, policy["maxAllowed"])
+		assert.Equal(t, map[string]any{"cpu": "100m"}, policy["minAllowed"])
+		assert.Equal(t, "8", reconciler.Profiles.Entries["p1"].Spec.ResourcePolicy.ContainerPolicies[0].MaxAllowed.Cpu().String(),
+			"shared profile must not be mutated")
+	})
+
+	t.Run("Applies the tighter of LimitRange and quota", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		lr := newLimitRange("ns1", "lr", corev1.LimitTypeContainer, nil, resources("1", "4Gi"))
+		rq := newResourceQuota("ns1", "rq", resources("4", "1Gi"), nil)
+		reconciler, kubeClient := newReconciler(t, dep, lr, rq)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		policy := containerPolicy(t, kubeClient)
+		assert.Equal(t, map[string]any{"cpu": "1", "memory": "1Gi"}, policy["maxAllowed"])
+	})
+
+	t.Run("Leaves spec untouched without ResourceQuota", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		reconciler, kubeClient := newReconciler(t, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		policy := containerPolicy(t, kubeClient)
+		assert.Equal(t, map[string]any{"cpu": "8"}, policy["maxAllowed"])
+		assert.Equal(t, map[string]any{"cpu": "100m"}, policy["minAllowed"])
+	})
+}
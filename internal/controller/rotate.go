@@ -0,0 +1,92 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rotationRequested reports whether the workload's rotate annotation holds a
+// value the VPA has not been recreated for yet.
+func rotationRequested(obj client.Object, vpa client.Object) bool {
+	value := obj.GetAnnotations()[RotateAnnotation]
+	return value != "" && vpa.GetAnnotations()[RotatedAnnotation] != value
+}
+
+// rotationAnnotations returns annotations with the processed rotate value of
+// the workload recorded, so a recreated VPA is not rotated again.
+func rotationAnnotations(obj client.Object, annotations map[string]string) map[string]string {
+	value := obj.GetAnnotations()[RotateAnnotation]
+	if value == "" {
+		return annotations
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[RotatedAnnotation] = value
+	return annotations
+}
+
+// rotateVPA deletes the existing VPA so it is recreated fresh, clearing its
+// status and recommendations. It reports whether the VPA is gone and can be
+// recreated right away; a VPA held by a finalizer is recreated once its
+// deletion requeues the workload.
+func (b *BaseReconciler) rotateVPA(
+	ctx context.Context,
+	log logr.Logger,
+	obj client.Object,
+	kind string,
+	existing *unstructured.Unstructured,
+) (bool, error) {
+	if _, err := b.deleteVPA(ctx, obj, existing, deleteReasonRotation, kind); err != nil {
+		return false, err
+	}
+
+	rotation := obj.GetAnnotations()[RotateAnnotation]
+	log.Info(
+		"rotated VPA",
+		"vpa", existing.GetName(),
+		"rotation", rotation,
+	)
+
+	b.Recorder.Eventf(
+		obj,
+		existing,
+		corev1.EventTypeNormal,
+		vpaEventVPARotated,
+		vpaActionRotateVPA,
+		"Deleted VPA %s for rotation %s",
+		existing.GetName(),
+		rotation,
+	)
+
+	current, err := b.fetchExistingVPA(ctx, types.NamespacedName{Name: existing.GetName(), Namespace: existing.GetNamespace()})
+	if err != nil {
+		return false, err
+	}
+	if current != nil {
+		log.V(1).Info("rotated VPA is still terminating; recreating after deletion", "vpa", existing.GetName())
+		return false, nil
+	}
+	return true, nil
+}
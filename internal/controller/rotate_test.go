@@ -0,0 +1,207 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBaseReconciler_ReconcileWorkload_Rotate(t *testing.T) {
+	t.Parallel()
+
+	const rotation = "2026-10-17T10:00:00Z"
+
+	newReconciler := func(t *testing.T, useFinalizers bool, objs ...client.Object) (*BaseReconciler, client.Client, *events.FakeRecorder) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			UseFinalizers: useFinalizers,
+		}, kubeClient, recorder
+	}
+
+	newDeployment := func(annotations map[string]string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		for key, value := range annotations {
+			dep.Annotations[key] = value
+		}
+		return dep
+	}
+
+	vpaKey := func(t *testing.T) types.NamespacedName {
+		t.Helper()
+		return types.NamespacedName{Name: renderDeploymentVPAName(t, "ns1", "demo", "p1"), Namespace: "ns1"}
+	}
+
+	// setStatus simulates a recommendation written by the VPA recommender.
+	setStatus := func(t *testing.T, c client.Client) {
+		t.Helper()
+		vpa := newVPAObject()
+		require.NoError(t, c.Get(context.Background(), vpaKey(t), vpa))
+		vpa.Object["status"] = map[string]any{"conditions": []any{map[string]any{"type": "RecommendationProvided", "status": "True"}}}
+		require.NoError(t, c.Update(context.Background(), vpa))
+	}
+
+	drain := func(recorder *events.FakeRecorder) []string {
+		var out []string
+		for len(recorder.Events) > 0 {
+			out = append(out, <-recorder.Events)
+		}
+		return out
+	}
+
+	t.Run("Recreates the VPA when the rotate value changes", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(nil)
+		reconciler, kubeClient, recorder := newReconciler(t, false, dep)
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		setStatus(t, kubeClient)
+		drain(recorder)
+
+		rotated := newDeployment(map[string]string{RotateAnnotation: rotation})
+		_, err = reconciler.ReconcileWorkload(context.Background(), rotated, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(context.Background(), vpaKey(t), vpa))
+		_, hasStatus := vpa.Object["status"]
+		assert.False(t, hasStatus, "rotation must clear the VPA status")
+		assert.Equal(t, rotation, vpa.GetAnnotations()[RotatedAnnotation])
+
+		name := vpaKey(t).Name
+		assert.Equal(t, []string{
+			"Normal VPARotated Deleted VPA " + name + " for rotation " + rotation,
+			"Normal VPACreated Created VPA " + name + " with profile p1",
+		}, drain(recorder))
+	})
+
+	t.Run("Rotates only once per value", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(map[string]string{RotateAnnotation: rotation})
+		reconciler, kubeClient, recorder := newReconciler(t, false, dep)
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		setStatus(t, kubeClient)
+		drain(recorder)
+
+		_, err = reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(context.Background(), vpaKey(t), vpa))
+		_, hasStatus := vpa.Object["status"]
+		assert.True(t, hasStatus, "a processed rotation must not recreate the VPA")
+		assert.Empty(t, drain(recorder))
+	})
+
+	t.Run("Waits for a finalizer before recreating", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment(nil)
+		reconciler, kubeClient, recorder := newReconciler(t, true, dep)
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		drain(recorder)
+
+		rotated := newDeployment(map[string]string{RotateAnnotation: rotation})
+		_, err = reconciler.ReconcileWorkload(context.Background(), rotated, DeploymentGVK)
+		require.NoError(t, err)
+
+		vpa := newVPAObject()
+		require.NoError(t, kubeClient.Get(context.Background(), vpaKey(t), vpa))
+		assert.NotNil(t, vpa.GetDeletionTimestamp(), "VPA must be terminating")
+		assert.Empty(t, vpa.GetAnnotations()[RotatedAnnotation])
+		assert.Equal(t, []string{
+			"Normal VPARotated Deleted VPA " + vpaKey(t).Name + " for rotation " + rotation,
+		}, drain(recorder))
+	})
+}
+
+func TestRotationRequested(t *testing.T) {
+	t.Parallel()
+
+	newVPA := func(annotations map[string]string) *unstructured.Unstructured {
+		vpa := newVPAObject()
+		vpa.SetAnnotations(annotations)
+		return vpa
+	}
+	newWorkload := func(annotations map[string]string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetAnnotations(annotations)
+		return dep
+	}
+
+	t.Run("No rotate annotation", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, rotationRequested(newWorkload(nil), newVPA(nil)))
+	})
+
+	t.Run("Empty rotate annotation", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, rotationRequested(newWorkload(map[string]string{RotateAnnotation: ""}), newVPA(nil)))
+	})
+
+	t.Run("New rotate value", func(t *testing.T) {
+		t.Parallel()
+		workload := newWorkload(map[string]string{RotateAnnotation: "2"})
+		assert.True(t, rotationRequested(workload, newVPA(map[string]string{RotatedAnnotation: "1"})))
+	})
+
+	t.Run("Processed rotate value", func(t *testing.T) {
+		t.Parallel()
+		workload := newWorkload(map[string]string{RotateAnnotation: "1"})
+		assert.False(t, rotationRequested(workload, newVPA(map[string]string{RotatedAnnotation: "1"})))
+	})
+}
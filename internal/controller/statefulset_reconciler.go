@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -95,19 +96,27 @@ func (r *StatefulSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 //   - Owned VPA events are filtered by ManagedVPALifecycle, so spec/label drift
 //     requeues the owning StatefulSet ("snap back" behavior) while still ignoring
 //     status churn.
+//   - With namespace defaults enabled, a change to a Namespace's default-profile
+//     annotation requeues all StatefulSets in that namespace.
 func (r *StatefulSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	vpa := newVPAObject()
 
-	return ctrl.NewControllerManagedBy(mgr).
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(r.controllerOptions()).
 		// Primary resource: only react when the profile annotation is added/removed/present.
 		For(&appsv1.StatefulSet{}, builder.WithPredicates(
-			predicates.ProfileAnnotationLifecycle(r.Meta.ProfileKey),
+			countedPredicate(r.Metrics, r.workloadPredicate(), "statefulset", "StatefulSet"),
 		)).
 		// Secondary resource: any change to a managed VPA should requeue the owner.
 		// We use a label-based predicate here so only VPAs with the managed label
 		// generate events for this controller.
 		Owns(vpa, builder.WithPredicates(
-			predicates.ManagedVPALifecycle(r.Meta.ManagedLabel, r.Meta.ProfileKey),
-		)).
+			countedPredicate(r.Metrics,
+				predicates.ManagedVPALifecycle(r.Meta.ManagedLabel, r.Meta.managedMatchValue(), r.Meta.ProfileKey, r.Meta.vpaAnnotationKeys()...),
+				"statefulset", vpaResource),
+		))
+
+	// Namespace default-profile changes requeue the namespace's workloads.
+	return r.watchNamespaceDefaults(bldr, func() client.ObjectList { return &appsv1.StatefulSetList{} }).
 		Complete(r)
 }
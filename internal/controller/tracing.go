@@ -0,0 +1,83 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// TracerName is the instrumentation name of the reconcile tracer.
+const TracerName = "github.com/containeroo/autovpa/internal/controller"
+
+// Span attribute keys.
+const (
+	spanAttrNamespace = attribute.Key("k8s.namespace.name")
+	spanAttrKind      = attribute.Key("autovpa.kind")
+	spanAttrProfile   = attribute.Key("autovpa.profile")
+	spanAttrOutcome   = attribute.Key("autovpa.outcome")
+)
+
+// Reconcile span outcomes.
+const (
+	spanOutcomeSuccess = "success"
+	spanOutcomeRequeue = "requeue"
+	spanOutcomeError   = "error"
+)
+
+// startReconcileSpan starts a span for a reconcile of kind in namespace. A nil
+// tracer uses a no-op one. Attributes are only set on recording spans, so
+// disabled tracing skips building them.
+func startReconcileSpan(ctx context.Context, tracer trace.Tracer, name, namespace, kind string) (context.Context, trace.Span) {
+	if tracer == nil {
+		tracer = noop.Tracer{}
+	}
+	ctx, span := tracer.Start(ctx, name)
+	if span.IsRecording() {
+		span.SetAttributes(spanAttrNamespace.String(namespace), spanAttrKind.String(kind))
+	}
+	return ctx, span
+}
+
+// setSpanProfile records the profile on the reconcile span in ctx.
+func setSpanProfile(ctx context.Context, profile string) {
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		span.SetAttributes(spanAttrProfile.String(profile))
+	}
+}
+
+// endReconcileSpan records the reconcile outcome and ends span.
+func endReconcileSpan(span trace.Span, result ctrl.Result, err error) {
+	if span.IsRecording() {
+		switch {
+		case err != nil:
+			span.SetAttributes(spanAttrOutcome.String(spanOutcomeError))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case !result.IsZero():
+			span.SetAttributes(spanAttrOutcome.String(spanOutcomeRequeue))
+		default:
+			span.SetAttributes(spanAttrOutcome.String(spanOutcomeSuccess))
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newSpanRecorder returns an in-memory span recorder and a tracer feeding it.
+func newSpanRecorder(t *testing.T) (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return recorder, provider
+}
+
+// spanAttributes returns the attributes of span keyed by name.
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]string {
+	attrs := map[attribute.Key]string{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	return attrs
+}
+
+func TestReconcileSpans(t *testing.T) {
+	t.Parallel()
+
+	newWorkloadReconciler := func(t *testing.T, provider *sdktrace.TracerProvider, funcs interceptor.Funcs, objs ...client.Object) *BaseReconciler {
+		t.Helper()
+		logger := logr.Discard()
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).WithInterceptorFuncs(funcs).Build(),
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			Tracer: provider.Tracer(TracerName),
+		}
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+		return dep
+	}
+
+	t.Run("Records a workload reconcile", func(t *testing.T) {
+		t.Parallel()
+
+		recorder, provider := newSpanRecorder(t)
+		dep := newDeployment()
+		reconciler := newWorkloadReconciler(t, provider, interceptor.Funcs{}, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "ReconcileWorkload", spans[0].Name())
+		assert.Equal(t, map[attribute.Key]string{
+			spanAttrNamespace: "ns1",
+			spanAttrKind:      "Deployment",
+			spanAttrProfile:   "p1",
+			spanAttrOutcome:   spanOutcomeSuccess,
+		}, spanAttributes(spans[0]))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+
+	t.Run("Records a failed workload reconcile", func(t *testing.T) {
+		t.Parallel()
+
+		recorder, provider := newSpanRecorder(t)
+		dep := newDeployment()
+		reconciler := newWorkloadReconciler(t, provider, interceptor.Funcs{
+			Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+				return errors.New("apply failed")
+			},
+		}, dep)
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.Error(t, err)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, spanOutcomeError, spanAttributes(spans[0])[spanAttrOutcome])
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		require.NotEmpty(t, spans[0].Events(), "error must be recorded on the span")
+	})
+
+	t.Run("Records a VPA reconcile", func(t *testing.T) {
+		t.Parallel()
+
+		recorder, provider := newSpanRecorder(t)
+		vpa := newManagedVPA(t, "ns1", "demo-p1-vpa", "p1")
+		reconciler := newTestVPAReconciler(t, vpa)
+		reconciler.Tracer = provider.Tracer(TracerName)
+
+		_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "demo-p1-vpa"},
+		})
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "VPAReconciler.Reconcile", spans[0].Name())
+		assert.Equal(t, map[attribute.Key]string{
+			spanAttrNamespace: "ns1",
+			spanAttrKind:      "VerticalPodAutoscaler",
+			spanAttrProfile:   "p1",
+			spanAttrOutcome:   spanOutcomeSuccess,
+		}, spanAttributes(spans[0]))
+	})
+
+	t.Run("Records a requeue", func(t *testing.T) {
+		t.Parallel()
+
+		recorder, provider := newSpanRecorder(t)
+		_, span := startReconcileSpan(context.Background(), provider.Tracer(TracerName), "test", "ns1", "Deployment")
+		endReconcileSpan(span, ctrl.Result{RequeueAfter: 1}, nil)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, spanOutcomeRequeue, spanAttributes(spans[0])[spanAttrOutcome])
+	})
+
+	t.Run("Uses a no-op tracer without one", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, span := startReconcileSpan(context.Background(), nil, "test", "ns1", "Deployment")
+		assert.False(t, span.IsRecording())
+		setSpanProfile(ctx, "p1")
+		endReconcileSpan(span, ctrl.Result{}, errors.New("boom"))
+	})
+}
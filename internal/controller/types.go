@@ -17,27 +17,272 @@ limitations under the License.
 package controller
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/utils"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SpecAuthoritativeAnnotation marks a managed VPA whose spec is maintained by hand.
+// When set to "true", the operator keeps labels and ownership in sync but
+// preserves the existing VPA spec.
+const SpecAuthoritativeAnnotation string = "autovpa.containeroo.ch/spec-authoritative"
+
+// ManagedFinalizer is added to managed VPAs when finalizers are enabled, so
+// out-of-band deletions are observed and the managed gauge stays accurate.
+const ManagedFinalizer string = "autovpa.containeroo.ch/managed"
+
+// NamespaceDefaultProfileAnnotation on a Namespace selects the profile(s) for
+// workloads in that namespace that carry no profile annotation themselves.
+const NamespaceDefaultProfileAnnotation string = "autovpa.containeroo.ch/default-profile"
+
+// ProfileDefaultingLabel set to "enabled" on a Namespace opts it into the
+// defaulting webhook, which annotates new workloads with the default profile.
+const ProfileDefaultingLabel string = "autovpa.containeroo.ch/profile-defaulting"
+
+// RecommendationAnnotation on a workload mirrors the target recommendation of
+// its managed VPA as compact JSON, keyed by container name.
+const RecommendationAnnotation string = "autovpa.containeroo.ch/recommendation"
+
+// LastErrorAnnotation on a workload holds the error of its latest failed
+// reconcile when error annotations are enabled; it is removed again once a
+// reconcile succeeds.
+const LastErrorAnnotation string = "autovpa.containeroo.ch/last-error"
+
+// RotateAnnotation on a workload requests a one-shot rotation of its managed
+// VPAs: whenever the value (e.g. a timestamp) changes, the VPAs are deleted and
+// recreated fresh.
+const RotateAnnotation string = "autovpa.containeroo.ch/rotate"
+
+// RotatedAnnotation on a managed VPA records the rotate value it was created
+// for, so each rotation is processed only once.
+const RotatedAnnotation string = "autovpa.containeroo.ch/rotated"
+
+// Workload annotations overriding the minAllowed/maxAllowed of every container
+// policy in the workload's VPAs with a resource quantity (e.g. "2Gi").
+const (
+	MinCPUAnnotation    string = "autovpa.containeroo.ch/min-cpu"
+	MinMemoryAnnotation string = "autovpa.containeroo.ch/min-memory"
+	MaxCPUAnnotation    string = "autovpa.containeroo.ch/max-cpu"
+	MaxMemoryAnnotation string = "autovpa.containeroo.ch/max-memory"
+)
+
+// Actions taken on obsolete managed VPAs, selected by BaseReconciler.ObsoleteAction.
+const (
+	// ObsoleteActionDelete deletes obsolete VPAs.
+	ObsoleteActionDelete string = "delete"
+	// ObsoleteActionRelease keeps obsolete VPAs but removes the managed label,
+	// the workload's ownerRef and ManagedFinalizer, so the operator lets go of them.
+	ObsoleteActionRelease string = "release"
+)
+
+// Renderings of a config.AllContainers container policy, selected by
+// ProfileConfig.ContainerPolicyMode.
+const (
+	// ContainerPolicyModeExplicit expands the policy into one policy per
+	// workload container.
+	ContainerPolicyModeExplicit string = "explicit"
+	// ContainerPolicyModeWildcard renders the policy as the "*" policy.
+	ContainerPolicyModeWildcard string = "wildcard"
 )
 
 // MetaConfig holds annotation/label settings shared across reconcilers.
 // It controls how workloads opt into profiles and how managed VPAs are marked.
 type MetaConfig struct {
-	ProfileKey   string // Workload annotation key used to pick a VPA profile.
-	ManagedLabel string // Label key applied to VPAs managed by this operator.
+	ProfileKey          string   // Workload annotation key used to pick a VPA profile; also labels managed VPAs.
+	LegacyProfileKeys   []string // Lower-priority workload annotation keys honored while migrating to ProfileKey.
+	ManagedLabel        string   // Label key applied to VPAs managed by this operator.
+	ManagedLabelValue   string   // Managed label value or name template rendered per workload (empty means "true").
+	TrackingAnnotations []string // Workload annotation keys copied onto managed VPAs (e.g. GitOps tracking ids).
+	RecommendedLabels   bool     // Copy the workload's app.kubernetes.io/* labels onto managed VPAs.
+	NamespaceProfileKey string   // Namespace annotation key providing a fallback profile (empty disables).
+	EmptyMeansDefault   bool     // Treat a present-but-empty profile annotation as opting into the default profile.
+	WarnAnnotationTypos bool     // Warn about workload annotation keys resembling a profile key.
+}
+
+// profileKeys returns the workload profile annotation keys in priority order.
+func (m MetaConfig) profileKeys() []string {
+	return append([]string{m.ProfileKey}, m.LegacyProfileKeys...)
+}
+
+// profileAnnotation returns the value of the first profile annotation key set
+// in annotations and whether any key was set at all.
+func (m MetaConfig) profileAnnotation(annotations map[string]string) (value string, present bool) {
+	for _, key := range m.profileKeys() {
+		if value, ok := annotations[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// managedMatchValue returns the managed label value that marks a managed VPA,
+// or "" when the value is rendered per workload and only the key can be matched.
+func (m MetaConfig) managedMatchValue() string {
+	if utils.IsTemplate(m.ManagedLabelValue) {
+		return ""
+	}
+	return utils.DefaultIfZero(m.ManagedLabelValue, "true")
+}
+
+// managedSelector returns the list option selecting managed VPAs.
+func (m MetaConfig) managedSelector() client.ListOption {
+	if value := m.managedMatchValue(); value != "" {
+		return client.MatchingLabels{m.ManagedLabel: value}
+	}
+	return client.HasLabels{m.ManagedLabel}
+}
+
+// isManaged reports whether labels mark a VPA as managed.
+func (m MetaConfig) isManaged(labels map[string]string) bool {
+	value, ok := labels[m.ManagedLabel]
+	if match := m.managedMatchValue(); match != "" {
+		return value == match
+	}
+	return ok
+}
+
+// managedLabelValue returns the managed label value for a workload's VPA.
+func (m MetaConfig) managedLabelValue(data utils.NameTemplateData) (string, error) {
+	if value := m.managedMatchValue(); value != "" {
+		return value, nil
+	}
+	value, err := utils.RenderLabelValue(m.ManagedLabelValue, data)
+	if err != nil {
+		return "", fmt.Errorf("render managed label value: %w", err)
+	}
+	return value, nil
+}
+
+// vpaAnnotationKeys returns the VPA annotation keys whose changes must requeue the owning workload.
+func (m MetaConfig) vpaAnnotationKeys() []string {
+	return append([]string{SpecAuthoritativeAnnotation}, m.TrackingAnnotations...)
 }
 
 // ProfileConfig wraps profile data shared across reconcilers.
 // It supplies the available profiles, default profile, and default name template.
 type ProfileConfig struct {
-	NameTemplate string                    // Default VPA name template when a profile does not override.
-	Default      string                    // Default profile name to use when annotation selects "default".
-	Entries      map[string]config.Profile // All available profiles keyed by name.
+	NameTemplate               string                    // Default VPA name template when a profile does not override.
+	Default                    string                    // Default profile name to use when annotation selects "default".
+	Entries                    map[string]config.Profile // All available profiles keyed by name.
+	DefaultUpdateMode          vpaautoscaling.UpdateMode // Update mode injected when a profile does not set one (empty keeps the VPA default).
+	DefaultRecommender         string                    // Recommender injected when a profile does not set one (empty keeps the VPA default).
+	DefaultControlledResources []corev1.ResourceName     // controlledResources injected into container policies that leave them unset.
+	DefaultMinAllowed          corev1.ResourceList       // minAllowed resources injected into container policies that leave them unset.
+	DefaultMaxAllowed          corev1.ResourceList       // maxAllowed resources injected into container policies that leave them unset.
+	ForceUpdateModeOff         bool                      // Render every VPA with updateMode Off, overriding profiles and DefaultUpdateMode.
+	DowngradeInPlace           bool                      // Render updateMode InPlaceOrRecreate as Recreate, for VPAs without in-place updates.
+	KindDefaults               map[string]string         // Default profile per workload kind, overriding Default for that kind.
+	KindRestrictions           map[string][]string       // Workload kinds allowed per profile; profiles without an entry allow any kind.
+	ContainerPolicyMode        string                    // How config.AllContainers policies render (empty means ContainerPolicyModeExplicit).
+}
+
+// defaultProfileKeyword is the profile annotation value requesting the default profile.
+const defaultProfileKeyword = "default"
+
+// defaultFor returns the default profile for workloads of kind.
+func (p ProfileConfig) defaultFor(kind string) string {
+	return utils.DefaultIfZero(p.KindDefaults[kind], p.Default)
+}
+
+// resolve returns the profile selected by a requested profile name for a
+// workload of kind. An empty request selects the default profile; with a
+// kind default configured, requesting "default" selects it too.
+func (p ProfileConfig) resolve(requested, kind string) string {
+	if requested == "" {
+		return p.defaultFor(kind)
+	}
+	if kindDefault, ok := p.KindDefaults[kind]; ok && requested == defaultProfileKeyword {
+		return kindDefault
+	}
+	return requested
+}
+
+// allowsKind reports whether workloads of kind may use profile.
+func (p ProfileConfig) allowsKind(profile, kind string) bool {
+	kinds, restricted := p.KindRestrictions[profile]
+	return !restricted || slices.Contains(kinds, kind)
+}
+
+// nameTemplate returns the profile's name template override or the global default.
+func (p ProfileConfig) nameTemplate(profile config.Profile) string {
+	return utils.DefaultIfZero(profile.NameTemplate, p.NameTemplate)
+}
+
+// expandsAllContainers reports whether profile has a config.AllContainers
+// policy that renders per container and so needs the workload's containers.
+func (p ProfileConfig) expandsAllContainers(profile config.Profile) bool {
+	return p.ContainerPolicyMode != ContainerPolicyModeWildcard &&
+		profile.Spec.ResourcePolicy != nil &&
+		allContainersIndex(profile.Spec.ResourcePolicy.ContainerPolicies) >= 0
+}
+
+// defaultBounds returns the default container policy bounds, or nil when none are set.
+func (p ProfileConfig) defaultBounds() *containerLimits {
+	if len(p.DefaultMinAllowed) == 0 && len(p.DefaultMaxAllowed) == 0 {
+		return nil
+	}
+	return &containerLimits{Min: p.DefaultMinAllowed, Max: p.DefaultMaxAllowed}
+}
+
+// renderSpec builds the VPA spec for in with the profile defaults filled in,
+// then applies inline overrides, the in-place downgrade and
+// --force-update-mode-off, in that order.
+func (p ProfileConfig) renderSpec(in vpaSpecInput, inline *inlineOverrides) (map[string]any, error) {
+	in.DefaultUpdateMode = p.DefaultUpdateMode
+	in.DefaultRecommender = p.DefaultRecommender
+	in.DefaultControlled = p.DefaultControlledResources
+	in.DefaultBounds = p.defaultBounds()
+	in.ContainerPolicyMode = p.ContainerPolicyMode
+
+	spec, err := buildVPASpec(in)
+	if err != nil {
+		return nil, err
+	}
+	if err := inline.apply(spec); err != nil {
+		return nil, err
+	}
+	if p.DowngradeInPlace {
+		// The VPA installation cannot resize pods in place; evict instead.
+		mode, _, _ := unstructured.NestedString(spec, "updatePolicy", "updateMode")
+		if mode == string(vpaautoscaling.UpdateModeInPlaceOrRecreate) {
+			if err := unstructured.SetNestedField(spec, string(vpaautoscaling.UpdateModeRecreate), "updatePolicy", "updateMode"); err != nil {
+				return nil, fmt.Errorf("downgrade VPA update mode to Recreate: %w", err)
+			}
+		}
+	}
+	if p.ForceUpdateModeOff {
+		// Recommendation-only rollout: no profile may evict or resize pods.
+		if err := unstructured.SetNestedField(spec, string(vpaautoscaling.UpdateModeOff), "updatePolicy", "updateMode"); err != nil {
+			return nil, fmt.Errorf("force VPA update mode Off: %w", err)
+		}
+	}
+	return spec, nil
+}
+
+// EffectiveSpec returns the VPA spec profile renders to before any workload,
+// namespace or annotation input is applied. The targetRef is omitted since it
+// is set per workload, and a config.AllContainers policy renders as "*" since
+// the containers are not known.
+func (p ProfileConfig) EffectiveSpec(profile config.Profile) (map[string]any, error) {
+	spec, err := p.renderSpec(vpaSpecInput{Profile: profile}, nil)
+	if err != nil {
+		return nil, err
+	}
+	delete(spec, "targetRef")
+	return spec, nil
 }
 
+// vpaGVK and vpaListGVK default to the upstream VPA API and may be overridden
+// once at startup via SetVPAGroupVersion, before any controller is set up.
 var (
 	vpaGVK = schema.GroupVersionKind{
 		Group:   "autoscaling.k8s.io",
@@ -52,4 +297,19 @@ var (
 	DeploymentGVK  = appsv1.SchemeGroupVersion.WithKind("Deployment")
 	StatefulSetGVK = appsv1.SchemeGroupVersion.WithKind("StatefulSet")
 	DaemonSetGVK   = appsv1.SchemeGroupVersion.WithKind("DaemonSet")
+	ReplicaSetGVK  = appsv1.SchemeGroupVersion.WithKind("ReplicaSet")
 )
+
+// SetVPAGroupVersion overrides the API group/version used for all VPA objects,
+// for distributions that serve the VPA under a different group or version.
+// It is not safe for concurrent use and must be called before the controllers
+// are set up.
+func SetVPAGroupVersion(gv schema.GroupVersion) {
+	vpaGVK = gv.WithKind("VerticalPodAutoscaler")
+	vpaListGVK = gv.WithKind("VerticalPodAutoscalerList")
+}
+
+// VPAGroupVersionKind returns the GVK used for VPA objects.
+func VPAGroupVersionKind() schema.GroupVersionKind {
+	return vpaGVK
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/metrics"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UnmanagedWorkloadsReporter periodically relists workloads and publishes how
+// many of them currently do not get a VPA, grouped by skip reason.
+//
+// It complements the skipped counter, which only tracks reconcile events, with
+// a point-in-time view. It runs as a manager Runnable on the leader only.
+type UnmanagedWorkloadsReporter struct {
+	KubeClient client.Client
+	Logger     *logr.Logger
+	Metrics    *metrics.Registry
+	Meta       MetaConfig
+	Profiles   ProfileConfig
+	Interval   time.Duration // Time between recomputations.
+	Kinds      []string      // Workload kinds with a running controller; empty counts Deployments, StatefulSets and DaemonSets.
+
+	// AdditionalKinds lists the extra workload kinds, listed as unstructured
+	// objects when their kind is counted.
+	AdditionalKinds []schema.GroupVersionKind
+
+	// ProfileStore, when set, supplies the profile settings instead of
+	// Profiles, read once per recount.
+	ProfileStore *ProfileStore
+}
+
+// Start recomputes the gauge immediately and then on every interval until ctx is done.
+func (r *UnmanagedWorkloadsReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.recompute(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// recompute refreshes the gauge; failures are logged and retried on the next tick.
+func (r *UnmanagedWorkloadsReporter) recompute(ctx context.Context) {
+	if r.ProfileStore != nil {
+		snapshot := *r
+		snapshot.Profiles = r.ProfileStore.Load()
+		r = &snapshot
+	}
+	counts, err := r.countUnmanagedWorkloads(ctx)
+	if err != nil {
+		r.Logger.Error(err, "failed to recompute unmanaged workloads")
+		return
+	}
+	r.Metrics.SetWorkloadsUnmanaged(counts)
+}
+
+// countUnmanagedWorkloads lists all supported workloads and counts those that
+// would be skipped by ReconcileWorkload, keyed by skip reason. Every known
+// reason is present so stale series drop to zero.
+func (r *UnmanagedWorkloadsReporter) countUnmanagedWorkloads(ctx context.Context) (map[string]int, error) {
+	counts := map[string]int{
+		vpaSkipReasonAnnotationMissing:     0,
+		vpaSkipReasonProfileMissing:        0,
+		vpaSkipReasonProfileDisabled:       0,
+		vpaSkipReasonProfileKindNotAllowed: 0,
+		vpaSkipReasonInvalidInlineOverride: 0,
+	}
+
+	namespaceNames, err := r.namespaceProfileNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	count := func(kind string, obj client.Object) {
+		if reason := r.unmanagedReason(kind, obj.GetAnnotations(), namespaceNames[obj.GetNamespace()]); reason != "" {
+			counts[reason]++
+		}
+	}
+
+	if r.countsKind(DeploymentGVK.Kind) {
+		deployments := &appsv1.DeploymentList{}
+		if err := r.KubeClient.List(ctx, deployments); err != nil {
+			return nil, fmt.Errorf("list deployments: %w", err)
+		}
+		for i := range deployments.Items {
+			count(DeploymentGVK.Kind, &deployments.Items[i])
+		}
+	}
+
+	if r.countsKind(StatefulSetGVK.Kind) {
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := r.KubeClient.List(ctx, statefulSets); err != nil {
+			return nil, fmt.Errorf("list statefulsets: %w", err)
+		}
+		for i := range statefulSets.Items {
+			count(StatefulSetGVK.Kind, &statefulSets.Items[i])
+		}
+	}
+
+	if r.countsKind(DaemonSetGVK.Kind) {
+		daemonSets := &appsv1.DaemonSetList{}
+		if err := r.KubeClient.List(ctx, daemonSets); err != nil {
+			return nil, fmt.Errorf("list daemonsets: %w", err)
+		}
+		for i := range daemonSets.Items {
+			count(DaemonSetGVK.Kind, &daemonSets.Items[i])
+		}
+	}
+
+	// ReplicaSets are opt-in, so they are only counted when their controller runs.
+	if slices.Contains(r.Kinds, ReplicaSetGVK.Kind) {
+		replicaSets := &appsv1.ReplicaSetList{}
+		if err := r.KubeClient.List(ctx, replicaSets); err != nil {
+			return nil, fmt.Errorf("list replicasets: %w", err)
+		}
+		for i := range replicaSets.Items {
+			// The Deployment's VPA covers the ReplicaSets it rolls out.
+			if controlledByDeployment(&replicaSets.Items[i]) {
+				continue
+			}
+			count(ReplicaSetGVK.Kind, &replicaSets.Items[i])
+		}
+	}
+
+	for _, gvk := range r.AdditionalKinds {
+		if !r.countsKind(gvk.Kind) {
+			continue
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.KubeClient.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("list %s: %w", gvk.Kind, err)
+		}
+		for i := range list.Items {
+			count(gvk.Kind, &list.Items[i])
+		}
+	}
+
+	return counts, nil
+}
+
+// countsKind reports whether workloads of kind are counted. Listing a disabled
+// kind would start an informer for it and defeat disabling its controller.
+func (r *UnmanagedWorkloadsReporter) countsKind(kind string) bool {
+	return len(r.Kinds) == 0 || slices.Contains(r.Kinds, kind)
+}
+
+// namespaceProfileNames returns the namespace default profiles keyed by
+// namespace, or nil when namespace defaults are disabled.
+func (r *UnmanagedWorkloadsReporter) namespaceProfileNames(ctx context.Context) (map[string][]string, error) {
+	if r.Meta.NamespaceProfileKey == "" {
+		return nil, nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := r.KubeClient.List(ctx, namespaces); err != nil {
+		return nil, fmt.Errorf("list namespaces: %w", err)
+	}
+	names := make(map[string][]string, len(namespaces.Items))
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if value := ns.GetAnnotations()[r.Meta.NamespaceProfileKey]; value != "" {
+			names[ns.GetName()] = parseProfileNames(value)
+		}
+	}
+	return names, nil
+}
+
+// unmanagedReason returns the skip reason for the annotations of a workload of
+// kind, or "" when the workload gets its VPAs. namespaceNames are the default
+// profiles of the workload's namespace, used when the workload requests none.
+func (r *UnmanagedWorkloadsReporter) unmanagedReason(kind string, annotations map[string]string, namespaceNames []string) string {
+	profileNames := workloadProfileNames(annotations, r.Meta, r.Profiles.defaultFor(kind))
+	if len(profileNames) == 0 {
+		profileNames = namespaceNames
+	}
+	if len(profileNames) == 0 {
+		return vpaSkipReasonAnnotationMissing
+	}
+	for _, entry := range profileNames {
+		name, _, err := parseProfileEntry(entry)
+		if err != nil {
+			return vpaSkipReasonInvalidInlineOverride
+		}
+		selected := r.Profiles.resolve(name, kind)
+		profile, found := r.Profiles.Entries[selected]
+		if !found {
+			return vpaSkipReasonProfileMissing
+		}
+		if !profile.IsEnabled() {
+			return vpaSkipReasonProfileDisabled
+		}
+		if !r.Profiles.allowsKind(selected, kind) {
+			return vpaSkipReasonProfileKindNotAllowed
+		}
+	}
+	return ""
+}
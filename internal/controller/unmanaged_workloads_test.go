@@ -0,0 +1,271 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/config"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestUnmanagedWorkloadsReporter_countUnmanagedWorkloads(t *testing.T) {
+	t.Parallel()
+
+	newReporter := func(t *testing.T, kubeClient client.Client) (*UnmanagedWorkloadsReporter, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+		return &UnmanagedWorkloadsReporter{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta:       MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"},
+			Profiles: ProfileConfig{
+				Default: "p1",
+				Entries: map[string]config.Profile{
+					"p1":  {Spec: config.ProfileSpec{}},
+					"p2":  {Spec: config.ProfileSpec{}},
+					"off": {Enabled: ptr.To(false)},
+				},
+				KindRestrictions: map[string][]string{"p2": {"Deployment"}},
+			},
+			Interval: time.Minute,
+		}, promReg
+	}
+
+	objectMeta := func(name string, annotations map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: "ns1", Name: name, Annotations: annotations}
+	}
+
+	t.Run("Counts mixed workloads by reason", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(
+			&appsv1.Deployment{ObjectMeta: objectMeta("managed", map[string]string{"vpa/profile": "p1"})},
+			&appsv1.Deployment{ObjectMeta: objectMeta("multi", map[string]string{"vpa/profile": "p1,p2"})},
+			&appsv1.Deployment{ObjectMeta: objectMeta("plain", nil)},
+			&appsv1.StatefulSet{ObjectMeta: objectMeta("empty", map[string]string{"vpa/profile": ""})},
+			&appsv1.StatefulSet{ObjectMeta: objectMeta("unknown", map[string]string{"vpa/profile": "nope"})},
+			&appsv1.DaemonSet{ObjectMeta: objectMeta("partial", map[string]string{"vpa/profile": "p1,nope"})},
+			&appsv1.DaemonSet{ObjectMeta: objectMeta("agent", map[string]string{"other": "x"})},
+			&appsv1.DaemonSet{ObjectMeta: objectMeta("paused", map[string]string{"vpa/profile": "off"})},
+			&appsv1.DaemonSet{ObjectMeta: objectMeta("restricted", map[string]string{"vpa/profile": "p2"})},
+			&appsv1.Deployment{ObjectMeta: objectMeta("tweaked", map[string]string{"vpa/profile": "p1;updateMode=Off"})},
+			&appsv1.Deployment{ObjectMeta: objectMeta("typo", map[string]string{"vpa/profile": "p1;mode=Off"})},
+		).Build()
+		reporter, _ := newReporter(t, kubeClient)
+
+		counts, err := reporter.countUnmanagedWorkloads(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{
+			vpaSkipReasonAnnotationMissing:     3,
+			vpaSkipReasonProfileMissing:        2,
+			vpaSkipReasonProfileDisabled:       1,
+			vpaSkipReasonProfileKindNotAllowed: 1,
+			vpaSkipReasonInvalidInlineOverride: 1,
+		}, counts)
+	})
+
+	t.Run("Reports zero for every reason when all workloads are managed", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(
+			&appsv1.Deployment{ObjectMeta: objectMeta("managed", map[string]string{"vpa/profile": "p2"})},
+		).Build()
+		reporter, _ := newReporter(t, kubeClient)
+
+		counts, err := reporter.countUnmanagedWorkloads(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{
+			vpaSkipReasonAnnotationMissing:     0,
+			vpaSkipReasonProfileMissing:        0,
+			vpaSkipReasonProfileDisabled:       0,
+			vpaSkipReasonProfileKindNotAllowed: 0,
+			vpaSkipReasonInvalidInlineOverride: 0,
+		}, counts)
+	})
+
+	t.Run("Counts workloads opted in by their namespace as managed", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Annotations: map[string]string{"vpa/default-profile": "p1"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2", Annotations: map[string]string{"vpa/default-profile": "nope"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns3"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "inherited"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "unknown"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns3", Name: "plain"}},
+		).Build()
+		reporter, _ := newReporter(t, kubeClient)
+		reporter.Meta.NamespaceProfileKey = "vpa/default-profile"
+
+		counts, err := reporter.countUnmanagedWorkloads(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{
+			vpaSkipReasonAnnotationMissing:     1,
+			vpaSkipReasonProfileMissing:        1,
+			vpaSkipReasonProfileDisabled:       0,
+			vpaSkipReasonProfileKindNotAllowed: 0,
+			vpaSkipReasonInvalidInlineOverride: 0,
+		}, counts)
+	})
+
+	t.Run("Skips kinds without a running controller", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(
+			&appsv1.Deployment{ObjectMeta: objectMeta("plain", nil)},
+		).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*appsv1.DeploymentList); !ok {
+					return errors.New("unexpected list")
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+		reporter, _ := newReporter(t, kubeClient)
+		reporter.Kinds = []string{"Deployment"}
+
+		counts, err := reporter.countUnmanagedWorkloads(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, counts[vpaSkipReasonAnnotationMissing])
+	})
+
+	t.Run("Counts standalone ReplicaSets when their controller runs", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(
+			&appsv1.ReplicaSet{ObjectMeta: objectMeta("legacy", nil)},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      "app-5d8f",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: DeploymentGVK.GroupVersion().String(),
+					Kind:       DeploymentGVK.Kind,
+					Name:       "app",
+					Controller: ptr.To(true),
+				}},
+			}},
+		).Build()
+		reporter, _ := newReporter(t, kubeClient)
+
+		counts, err := reporter.countUnmanagedWorkloads(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, counts[vpaSkipReasonAnnotationMissing], "ReplicaSets are opt-in")
+
+		reporter.Kinds = []string{"Deployment", "ReplicaSet"}
+		counts, err = reporter.countUnmanagedWorkloads(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, counts[vpaSkipReasonAnnotationMissing], "only the standalone ReplicaSet is counted")
+	})
+
+	t.Run("Counts additional workload kinds", func(t *testing.T) {
+		t.Parallel()
+
+		scheme := newScheme(t)
+		scheme.AddKnownTypeWithName(podSetGVK, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(podSetGVK.GroupVersion().WithKind("PodSetList"), &unstructured.UnstructuredList{})
+
+		newPodSet := func(name string, annotations map[string]string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(podSetGVK)
+			obj.SetNamespace("ns1")
+			obj.SetName(name)
+			obj.SetAnnotations(annotations)
+			return obj
+		}
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newPodSet("managed", map[string]string{"vpa/profile": "p1"}),
+			newPodSet("plain", nil),
+			newPodSet("restricted", map[string]string{"vpa/profile": "p2"}),
+		).Build()
+		reporter, _ := newReporter(t, kubeClient)
+		reporter.Kinds = []string{"Deployment", podSetGVK.Kind}
+		reporter.AdditionalKinds = []schema.GroupVersionKind{podSetGVK}
+
+		counts, err := reporter.countUnmanagedWorkloads(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, counts[vpaSkipReasonAnnotationMissing])
+		assert.Equal(t, 1, counts[vpaSkipReasonProfileKindNotAllowed])
+	})
+
+	t.Run("Returns list errors", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*appsv1.StatefulSetList); ok {
+					return errors.New("boom")
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+		reporter, _ := newReporter(t, kubeClient)
+
+		_, err := reporter.countUnmanagedWorkloads(context.Background())
+		require.Error(t, err)
+		assert.EqualError(t, err, "list statefulsets: boom")
+	})
+
+	t.Run("Recompute publishes the gauge", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(
+			&appsv1.Deployment{ObjectMeta: objectMeta("plain", nil)},
+		).Build()
+		reporter, promReg := newReporter(t, kubeClient)
+
+		reporter.recompute(context.Background())
+
+		families, err := promReg.Gather()
+		require.NoError(t, err)
+		got := map[string]float64{}
+		for _, mf := range families {
+			if mf.GetName() != "autovpa_workloads_unmanaged" {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				got[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+		assert.Equal(t, map[string]float64{
+			vpaSkipReasonAnnotationMissing:     1,
+			vpaSkipReasonProfileMissing:        0,
+			vpaSkipReasonProfileDisabled:       0,
+			vpaSkipReasonProfileKindNotAllowed: 0,
+			vpaSkipReasonInvalidInlineOverride: 0,
+		}, got)
+	})
+}
@@ -17,19 +17,27 @@ limitations under the License.
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
+	"strings"
 
 	"github.com/containeroo/autovpa/internal/config"
 	"github.com/containeroo/autovpa/internal/utils"
 
 	k8sautoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // vpaNeedsUpdate reports whether the relevant managed fields of the two VPAs differ.
@@ -40,7 +48,37 @@ func vpaNeedsUpdate(a, b *unstructured.Unstructured) bool {
 
 	return !apiequality.Semantic.DeepEqual(a.Object["spec"], b.Object["spec"]) ||
 		!maps.Equal(a.GetLabels(), b.GetLabels()) ||
-		!ownerRefsEqual(a.GetOwnerReferences(), b.GetOwnerReferences())
+		!maps.Equal(a.GetAnnotations(), b.GetAnnotations()) ||
+		!ownerRefsEqual(a.GetOwnerReferences(), b.GetOwnerReferences()) ||
+		hasManagedFinalizer(a) != hasManagedFinalizer(b)
+}
+
+// hasManagedFinalizer reports whether obj carries the operator's finalizer.
+func hasManagedFinalizer(obj client.Object) bool {
+	return controllerutil.ContainsFinalizer(obj, ManagedFinalizer)
+}
+
+// managedFieldsPath is the field path the API server reports managedFields
+// problems on.
+const managedFieldsPath = "metadata.managedFields"
+
+// isManagedFieldsError reports whether an apply error was caused by
+// inconsistent managedFields on the live object: an Invalid or Conflict status
+// with a cause on metadata.managedFields.
+func isManagedFieldsError(err error) bool {
+	if !apierrors.IsInvalid(err) && !apierrors.IsConflict(err) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return false
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Field == managedFieldsPath || strings.HasPrefix(cause.Field, managedFieldsPath+"[") {
+			return true
+		}
+	}
+	return false
 }
 
 // RenderVPAName renders and validates the VPA name using the provided template and data.
@@ -48,6 +86,67 @@ func RenderVPAName(tmpl string, data utils.NameTemplateData) (string, error) {
 	return utils.RenderNameTemplate(tmpl, data)
 }
 
+// DesiredVPAName returns the name of the VPA the operator manages for obj under
+// profile. An empty profile selects the (kind) default profile, and the profile's
+// nameTemplate override wins over the global template, as during reconciliation.
+// profile is exposed to templates as .RequestedProfile. The namespace is not
+// read, so templates see no .NamespaceLabels.
+func DesiredVPAName(profilesCfg ProfileConfig, obj client.Object, gvk schema.GroupVersionKind, profile string) (string, error) {
+	selectedProfile := profilesCfg.resolve(profile, gvk.Kind)
+	entry, found := profilesCfg.Entries[selectedProfile]
+	if !found {
+		return "", fmt.Errorf("profile %q not found", selectedProfile)
+	}
+	return RenderVPAName(profilesCfg.nameTemplate(entry), vpaNameData(obj, gvk, profile, selectedProfile))
+}
+
+// BuildManagedVPA returns the VPA the operator manages for obj under profile,
+// as it would be created: name, namespace, labels, annotations, spec and the
+// controller ownerRef pointing at obj. Profile selection follows DesiredVPAName.
+// The cluster is not read, so templates see no .NamespaceLabels and the spec is
+// not clamped to LimitRanges or ResourceQuotas; finalizers are not set.
+func BuildManagedVPA(
+	profilesCfg ProfileConfig,
+	metaCfg MetaConfig,
+	obj client.Object,
+	gvk schema.GroupVersionKind,
+	profile string,
+) (*unstructured.Unstructured, error) {
+	selectedProfile := profilesCfg.resolve(profile, gvk.Kind)
+	entry, found := profilesCfg.Entries[selectedProfile]
+	if !found {
+		return nil, fmt.Errorf("profile %q not found", selectedProfile)
+	}
+
+	b := &BaseReconciler{Profiles: profilesCfg, Meta: metaCfg}
+	desired, err := b.renderDesiredVPA(obj, gvk, vpaNameData(obj, gvk, profile, selectedProfile), entry, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	vpa := newVPAObject()
+	vpa.SetName(desired.Name)
+	vpa.SetNamespace(obj.GetNamespace())
+	vpa.SetLabels(desired.Labels)
+	vpa.SetAnnotations(desired.Annotations)
+	vpa.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(obj, gvk)})
+	vpa.Object["spec"] = desired.Spec
+	return vpa, nil
+}
+
+// vpaNameData returns the template data describing obj's VPA for the profile
+// selected by the requested profile name.
+func vpaNameData(obj client.Object, gvk schema.GroupVersionKind, requested, profile string) utils.NameTemplateData {
+	return utils.NameTemplateData{
+		WorkloadName:     obj.GetName(),
+		Namespace:        obj.GetNamespace(),
+		Kind:             gvk.Kind,
+		Profile:          profile,
+		RequestedProfile: requested,
+		Labels:           obj.GetLabels(),
+	}
+}
+
 // newVPAObject returns an empty VPA object with the correct GVK set.
 func newVPAObject() *unstructured.Unstructured {
 	obj := &unstructured.Unstructured{Object: map[string]any{}}
@@ -55,18 +154,65 @@ func newVPAObject() *unstructured.Unstructured {
 	return obj
 }
 
+// vpaSpecInput holds what buildVPASpec renders a workload's VPA spec from.
+// Only Profile, TargetGVK and WorkloadName are required.
+type vpaSpecInput struct {
+	Profile            config.Profile
+	DefaultUpdateMode  vpaautoscaling.UpdateMode // Injected when the profile sets no update mode.
+	DefaultRecommender string                    // Injected when the profile sets no recommenders.
+	DefaultControlled  []corev1.ResourceName     // controlledResources injected into policies that set none.
+	DefaultBounds      *containerLimits          // minAllowed/maxAllowed injected into policies that leave them unset.
+	Overrides          *containerLimits          // The workload's resource annotations, replacing the policy bounds.
+	Limits             *containerLimits          // The namespace's LimitRanges the policies are clamped to.
+	Quota              *containerLimits          // The namespace's remaining ResourceQuota, capping the policies further.
+	TargetGVK          schema.GroupVersionKind   // Kind of the workload; its apiVersion is used unless the profile overrides it.
+	WorkloadName       string
+
+	ContainerPolicyMode string   // How a config.AllContainers policy renders; see ContainerPolicyModeExplicit.
+	Containers          []string // Names of the workload's containers, for expanding a config.AllContainers policy.
+}
+
 // buildVPASpec creates a VPA spec from the profile and plugs in the workload targetRef,
 // returning it as an unstructured map for use in unstructured VPAs.
-func buildVPASpec(
-	profile config.ProfileSpec,
-	targetGVK schema.GroupVersionKind,
-	workloadName string,
-) (unstructuredSpec map[string]any, err error) {
-	spec := vpaautoscaling.VerticalPodAutoscalerSpec(profile)
+func buildVPASpec(in vpaSpecInput) (unstructuredSpec map[string]any, err error) {
+	spec := vpaautoscaling.VerticalPodAutoscalerSpec(in.Profile.Spec)
+	if in.DefaultUpdateMode != "" && (spec.UpdatePolicy == nil || spec.UpdatePolicy.UpdateMode == nil) {
+		// Copy the policy so the shared profile is never mutated.
+		policy := vpaautoscaling.PodUpdatePolicy{}
+		if spec.UpdatePolicy != nil {
+			policy = *spec.UpdatePolicy.DeepCopy()
+		}
+		policy.UpdateMode = &in.DefaultUpdateMode
+		spec.UpdatePolicy = &policy
+	}
+	if in.DefaultRecommender != "" && len(spec.Recommenders) == 0 {
+		spec.Recommenders = []*vpaautoscaling.VerticalPodAutoscalerRecommenderSelector{{Name: in.DefaultRecommender}}
+	}
+	if spec.ResourcePolicy != nil && allContainersIndex(spec.ResourcePolicy.ContainerPolicies) >= 0 {
+		// Copy the resource policy so the shared profile is never mutated.
+		spec.ResourcePolicy = spec.ResourcePolicy.DeepCopy()
+		expandAllContainers(&spec, in.ContainerPolicyMode, in.Containers)
+	}
+	if len(in.DefaultControlled) > 0 || in.DefaultBounds != nil || in.Overrides != nil || in.Limits != nil || in.Quota != nil {
+		// Copy the resource policy so the shared profile is never mutated.
+		spec.ResourcePolicy = spec.ResourcePolicy.DeepCopy()
+		applyDefaultControlledResources(&spec, in.DefaultControlled)
+		applyDefaultBounds(&spec, in.DefaultBounds)
+		applyResourceOverrides(&spec, in.Overrides)
+		clampContainerPolicies(&spec, in.Limits)
+		clampContainerPolicies(&spec, in.Quota)
+	}
+	if len(in.Profile.ExcludeContainers) > 0 {
+		if spec.ResourcePolicy == in.Profile.Spec.ResourcePolicy {
+			// Copy the resource policy so the shared profile is never mutated.
+			spec.ResourcePolicy = spec.ResourcePolicy.DeepCopy()
+		}
+		excludeContainers(&spec, in.Profile.ExcludeContainers)
+	}
 	spec.TargetRef = &k8sautoscalingv1.CrossVersionObjectReference{
-		APIVersion: targetGVK.GroupVersion().String(),
-		Kind:       targetGVK.Kind,
-		Name:       workloadName,
+		APIVersion: utils.DefaultIfZero(in.Profile.TargetAPIVersion, in.TargetGVK.GroupVersion().String()),
+		Kind:       in.TargetGVK.Kind,
+		Name:       in.WorkloadName,
 	}
 
 	// Unstructured objects are easier to work with than the typed ones.
@@ -78,6 +224,99 @@ func buildVPASpec(
 	return unstructuredSpec, nil
 }
 
+// applyDefaultControlledResources sets controlledResources on every container
+// policy that leaves it unset, adding a "*" policy when the profile has none.
+// The spec's resource policy must not be shared with the profile.
+func applyDefaultControlledResources(spec *vpaautoscaling.VerticalPodAutoscalerSpec, resources []corev1.ResourceName) {
+	if len(resources) == 0 {
+		return
+	}
+	if spec.ResourcePolicy == nil {
+		spec.ResourcePolicy = &vpaautoscaling.PodResourcePolicy{}
+	}
+	if len(spec.ResourcePolicy.ContainerPolicies) == 0 {
+		spec.ResourcePolicy.ContainerPolicies = []vpaautoscaling.ContainerResourcePolicy{
+			{ContainerName: vpaautoscaling.DefaultContainerResourcePolicy},
+		}
+	}
+
+	for i := range spec.ResourcePolicy.ContainerPolicies {
+		policy := &spec.ResourcePolicy.ContainerPolicies[i]
+		if policy.ControlledResources == nil {
+			policy.ControlledResources = ptr.To(slices.Clone(resources))
+		}
+	}
+}
+
+// allContainersIndex returns the index of the config.AllContainers policy in
+// policies, or -1.
+func allContainersIndex(policies []vpaautoscaling.ContainerResourcePolicy) int {
+	return slices.IndexFunc(policies, func(p vpaautoscaling.ContainerResourcePolicy) bool {
+		return p.ContainerName == config.AllContainers
+	})
+}
+
+// expandAllContainers renders the config.AllContainers policy. In wildcard
+// mode, or when the workload's containers are unknown, it becomes the "*"
+// policy; otherwise it is copied for every container without a policy of its
+// own. The spec's resource policy must not be shared with the profile.
+func expandAllContainers(spec *vpaautoscaling.VerticalPodAutoscalerSpec, mode string, containers []string) {
+	policies := spec.ResourcePolicy.ContainerPolicies
+	i := allContainersIndex(policies)
+	if mode == ContainerPolicyModeWildcard || len(containers) == 0 {
+		policies[i].ContainerName = vpaautoscaling.DefaultContainerResourcePolicy
+		return
+	}
+
+	expanded := make([]vpaautoscaling.ContainerResourcePolicy, 0, len(policies)+len(containers))
+	expanded = append(expanded, policies[:i]...)
+	for _, name := range containers {
+		if slices.ContainsFunc(policies, func(p vpaautoscaling.ContainerResourcePolicy) bool { return p.ContainerName == name }) {
+			continue
+		}
+		policy := *policies[i].DeepCopy()
+		policy.ContainerName = name
+		expanded = append(expanded, policy)
+	}
+	spec.ResourcePolicy.ContainerPolicies = append(expanded, policies[i+1:]...)
+}
+
+// excludeContainers turns off the VPA for the named containers by giving each
+// a container policy with mode Off, replacing any policy the profile set for
+// it. Other containers keep their own or the "*" policy. The spec's resource
+// policy must not be shared with the profile.
+func excludeContainers(spec *vpaautoscaling.VerticalPodAutoscalerSpec, names []string) {
+	if spec.ResourcePolicy == nil {
+		spec.ResourcePolicy = &vpaautoscaling.PodResourcePolicy{}
+	}
+
+	off := vpaautoscaling.ContainerScalingModeOff
+	for _, name := range names {
+		policy := vpaautoscaling.ContainerResourcePolicy{ContainerName: name, Mode: &off}
+		i := slices.IndexFunc(spec.ResourcePolicy.ContainerPolicies, func(p vpaautoscaling.ContainerResourcePolicy) bool {
+			return p.ContainerName == name
+		})
+		if i < 0 {
+			spec.ResourcePolicy.ContainerPolicies = append(spec.ResourcePolicy.ContainerPolicies, policy)
+			continue
+		}
+		spec.ResourcePolicy.ContainerPolicies[i] = policy
+	}
+}
+
+// ownerRefMatches reports whether ref points at owner by kind and name.
+// When both sides carry a UID, uidMismatch is true if they differ: the
+// reference then belongs to a previous object with the same name.
+func ownerRefMatches(ref metav1.OwnerReference, owner client.Object, kind string) (matches, uidMismatch bool) {
+	if ref.Kind != kind || ref.Name != owner.GetName() {
+		return false, false
+	}
+	if ref.UID != "" && owner.GetUID() != "" && ref.UID != owner.GetUID() {
+		return false, true
+	}
+	return true, false
+}
+
 // ownerRefsEqual compares owner reference slices.
 func ownerRefsEqual(a, b []metav1.OwnerReference) bool {
 	return apiequality.Semantic.DeepEqual(a, b)
@@ -93,3 +332,73 @@ func profileFromLabels(labels map[string]string, key string) string {
 	}
 	return "unknown"
 }
+
+// isSpecAuthoritative reports whether the VPA carries the spec-authoritative annotation.
+func isSpecAuthoritative(vpa *unstructured.Unstructured) bool {
+	return vpa.GetAnnotations()[SpecAuthoritativeAnnotation] == "true"
+}
+
+// parseProfileNames splits a comma-separated profile annotation into distinct,
+// trimmed profile names, keeping their order.
+func parseProfileNames(value string) []string {
+	var names []string
+	for part := range strings.SplitSeq(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || slices.Contains(names, part) {
+			continue
+		}
+		names = append(names, part)
+	}
+	return names
+}
+
+// workloadProfileNames returns the profiles requested by a workload's own
+// profile annotation, read from the first configured key that is set. With EmptyMeansDefault, a present annotation that names
+// no profile selects defaultProfile instead of opting out.
+func workloadProfileNames(annotations map[string]string, meta MetaConfig, defaultProfile string) []string {
+	value, present := meta.profileAnnotation(annotations)
+	if names := parseProfileNames(value); len(names) > 0 {
+		return names
+	}
+	if present && meta.EmptyMeansDefault {
+		return []string{defaultProfile}
+	}
+	return nil
+}
+
+// recommendedLabelPrefix prefixes the Kubernetes recommended labels, e.g.
+// app.kubernetes.io/name.
+const recommendedLabelPrefix = "app.kubernetes.io/"
+
+// recommendedLabels returns the recommended labels among labels, or nil when
+// none is present.
+func recommendedLabels(labels map[string]string) map[string]string {
+	var out map[string]string
+	for key, value := range labels {
+		if !strings.HasPrefix(key, recommendedLabelPrefix) {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// trackingAnnotations returns the subset of annotations whose keys are listed,
+// or nil when none of them is present.
+func trackingAnnotations(annotations map[string]string, keys []string) map[string]string {
+	var out map[string]string
+	for _, key := range keys {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[key] = value
+	}
+	return out
+}
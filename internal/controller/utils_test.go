@@ -17,17 +17,32 @@ limitations under the License.
 package controller
 
 import (
+	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/containeroo/autovpa/internal/config"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
 	"github.com/containeroo/autovpa/internal/utils"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestControllerVpaNeedsUpdate(t *testing.T) {
@@ -54,6 +69,14 @@ func TestControllerVpaNeedsUpdate(t *testing.T) {
 		b := a.DeepCopy()
 		assert.False(t, vpaNeedsUpdate(a, b))
 	})
+
+	t.Run("Returns true when managed finalizer differs", func(t *testing.T) {
+		t.Parallel()
+		a := newVPAObject()
+		b := newVPAObject()
+		b.SetFinalizers([]string{ManagedFinalizer})
+		assert.True(t, vpaNeedsUpdate(a, b))
+	})
 }
 
 func TestRenderVPAName(t *testing.T) {
@@ -83,19 +106,277 @@ func TestRenderVPAName(t *testing.T) {
 	})
 }
 
+func TestProfileConfig_resolve(t *testing.T) {
+	t.Parallel()
+
+	profilesCfg := ProfileConfig{
+		Default:      "standard",
+		KindDefaults: map[string]string{"DaemonSet": "off"},
+	}
+
+	t.Run("Empty request selects the global default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "standard", profilesCfg.resolve("", "Deployment"))
+	})
+
+	t.Run("Empty request selects the kind default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "off", profilesCfg.resolve("", "DaemonSet"))
+	})
+
+	t.Run("Default keyword selects the kind default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "off", profilesCfg.resolve("default", "DaemonSet"))
+	})
+
+	t.Run("Default keyword stays a profile name without a kind default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "default", profilesCfg.resolve("default", "Deployment"))
+	})
+
+	t.Run("Explicit profile wins over the kind default", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "standard", profilesCfg.resolve("standard", "DaemonSet"))
+	})
+}
+
+func TestDesiredVPAName(t *testing.T) {
+	t.Parallel()
+
+	profilesCfg := ProfileConfig{
+		NameTemplate: "{{ .WorkloadName }}-{{ .Profile }}-vpa",
+		Default:      "p1",
+		Entries: map[string]config.Profile{
+			"p1": {},
+			"p2": {NameTemplate: "{{ .Namespace }}-{{ .Kind | toLower }}-{{ .WorkloadName }}"},
+			"p3": {NameTemplate: "{{ .WorkloadName }}-{{ index .Labels \"team\" }}"},
+		},
+	}
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "demo",
+		Namespace: "ns1",
+		Labels:    map[string]string{"team": "blue"},
+	}}
+	gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+	t.Run("Uses the global template", func(t *testing.T) {
+		t.Parallel()
+		name, err := DesiredVPAName(profilesCfg, dep, gvk, "p1")
+		require.NoError(t, err)
+		assert.Equal(t, "demo-p1-vpa", name)
+	})
+
+	t.Run("Empty profile selects the default profile", func(t *testing.T) {
+		t.Parallel()
+		name, err := DesiredVPAName(profilesCfg, dep, gvk, "")
+		require.NoError(t, err)
+		assert.Equal(t, "demo-p1-vpa", name)
+	})
+
+	t.Run("Profile template overrides the global template", func(t *testing.T) {
+		t.Parallel()
+		name, err := DesiredVPAName(profilesCfg, dep, gvk, "p2")
+		require.NoError(t, err)
+		assert.Equal(t, "ns1-deployment-demo", name)
+	})
+
+	t.Run("Template reads workload labels", func(t *testing.T) {
+		t.Parallel()
+		name, err := DesiredVPAName(profilesCfg, dep, gvk, "p3")
+		require.NoError(t, err)
+		assert.Equal(t, "demo-blue", name)
+	})
+
+	t.Run("Matches the reconciled VPA name", func(t *testing.T) {
+		t.Parallel()
+		br := BaseReconciler{Profiles: profilesCfg}
+		for _, profile := range []string{"p1", "p2", "p3"} {
+			desired, err := br.buildDesiredVPA(t.Context(), dep, gvk, profile, profile, profilesCfg.Entries[profile], nil)
+			require.NoError(t, err)
+
+			name, err := DesiredVPAName(profilesCfg, dep, gvk, profile)
+			require.NoError(t, err)
+			assert.Equal(t, desired.Name, name, profile)
+		}
+	})
+
+	t.Run("Exposes the requested profile", func(t *testing.T) {
+		t.Parallel()
+		cfg := ProfileConfig{
+			NameTemplate: "{{ .WorkloadName }}-{{ .RequestedProfile }}-{{ .Profile }}",
+			Default:      "p2",
+			KindDefaults: map[string]string{"Deployment": "p1"},
+			Entries:      map[string]config.Profile{"p1": {}, "p2": {}},
+		}
+		name, err := DesiredVPAName(cfg, dep, gvk, "default")
+		require.NoError(t, err)
+		assert.Equal(t, "demo-default-p1", name)
+	})
+
+	t.Run("Errors on unknown profile", func(t *testing.T) {
+		t.Parallel()
+		_, err := DesiredVPAName(profilesCfg, dep, gvk, "missing")
+		require.EqualError(t, err, `profile "missing" not found`)
+	})
+}
+
+func TestBuildManagedVPA(t *testing.T) {
+	t.Parallel()
+
+	mode := vpaautoscaling.UpdateModeRecreate
+	profilesCfg := ProfileConfig{
+		NameTemplate: "{{ .WorkloadName }}-{{ .Profile }}-vpa",
+		Default:      "p1",
+		Entries: map[string]config.Profile{
+			"p1": {Spec: config.ProfileSpec{UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{UpdateMode: &mode}}},
+			"p2": {NameTemplate: "{{ .Namespace }}-{{ .WorkloadName }}"},
+		},
+	}
+	metaCfg := MetaConfig{
+		ProfileKey:          "vpa/profile",
+		ManagedLabel:        "vpa/managed",
+		TrackingAnnotations: []string{"argocd.argoproj.io/tracking-id"},
+		RecommendedLabels:   true,
+	}
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "ns1",
+			UID:       "demo-uid",
+			Labels:    map[string]string{"app.kubernetes.io/name": "demo"},
+			Annotations: map[string]string{
+				"vpa/profile":                    "p1",
+				"argocd.argoproj.io/tracking-id": "demo:apps/Deployment:ns1/demo",
+			},
+		}}
+	}
+
+	t.Run("Renders the full VPA", func(t *testing.T) {
+		t.Parallel()
+		vpa, err := BuildManagedVPA(profilesCfg, metaCfg, newDeployment(), DeploymentGVK, "p1")
+		require.NoError(t, err)
+
+		assert.Equal(t, vpaGVK, vpa.GroupVersionKind())
+		assert.Equal(t, "demo-p1-vpa", vpa.GetName())
+		assert.Equal(t, "ns1", vpa.GetNamespace())
+		assert.Equal(t, map[string]string{
+			"vpa/managed":            "true",
+			"vpa/profile":            "p1",
+			"app.kubernetes.io/name": "demo",
+		}, vpa.GetLabels())
+		assert.Equal(t, map[string]string{"argocd.argoproj.io/tracking-id": "demo:apps/Deployment:ns1/demo"}, vpa.GetAnnotations())
+
+		owner := metav1.GetControllerOf(vpa)
+		require.NotNil(t, owner)
+		assert.Equal(t, "Deployment", owner.Kind)
+		assert.Equal(t, "demo", owner.Name)
+		assert.Equal(t, types.UID("demo-uid"), owner.UID)
+
+		updateMode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+		assert.Equal(t, "Recreate", updateMode)
+		targetKind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+		assert.Equal(t, "Deployment", targetKind)
+	})
+
+	t.Run("Applies operator-wide overrides", func(t *testing.T) {
+		t.Parallel()
+		cfg := profilesCfg
+		cfg.ForceUpdateModeOff = true
+
+		vpa, err := BuildManagedVPA(cfg, metaCfg, newDeployment(), DeploymentGVK, "")
+		require.NoError(t, err)
+		updateMode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+		assert.Equal(t, "Off", updateMode)
+	})
+
+	t.Run("Matches the VPA the reconciler applies", func(t *testing.T) {
+		t.Parallel()
+		for _, profile := range []string{"p1", "p2"} {
+			dep := newDeployment()
+			dep.Annotations["vpa/profile"] = profile
+			kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build()
+			logger := logr.Discard()
+			r := &BaseReconciler{
+				KubeClient: kubeClient,
+				Logger:     &logger,
+				Recorder:   events.NewFakeRecorder(10),
+				Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+				Meta:       metaCfg,
+				Profiles:   profilesCfg,
+			}
+			_, err := r.ReconcileWorkload(t.Context(), dep, DeploymentGVK)
+			require.NoError(t, err, profile)
+
+			want, err := BuildManagedVPA(profilesCfg, metaCfg, dep, DeploymentGVK, profile)
+			require.NoError(t, err, profile)
+
+			got := newVPAObject()
+			require.NoError(t, kubeClient.Get(t.Context(), client.ObjectKeyFromObject(want), got), profile)
+			assert.Equal(t, want.GetLabels(), got.GetLabels(), profile)
+			assert.Equal(t, want.GetAnnotations(), got.GetAnnotations(), profile)
+			assert.Equal(t, want.GetOwnerReferences(), got.GetOwnerReferences(), profile)
+			assert.Equal(t, want.Object["spec"], got.Object["spec"], profile)
+		}
+	})
+
+	t.Run("Errors on unknown profile", func(t *testing.T) {
+		t.Parallel()
+		_, err := BuildManagedVPA(profilesCfg, metaCfg, newDeployment(), DeploymentGVK, "missing")
+		require.EqualError(t, err, `profile "missing" not found`)
+	})
+
+	t.Run("Errors on invalid name", func(t *testing.T) {
+		t.Parallel()
+		cfg := profilesCfg
+		cfg.NameTemplate = "Invalid_{{ .WorkloadName }}"
+		_, err := BuildManagedVPA(cfg, metaCfg, newDeployment(), DeploymentGVK, "p1")
+		require.Error(t, err)
+	})
+
+	t.Run("Expands __all__ for the workload's containers", func(t *testing.T) {
+		t.Parallel()
+		cfg := profilesCfg
+		cfg.Entries = map[string]config.Profile{"p1": {Spec: config.ProfileSpec{
+			ResourcePolicy: &vpaautoscaling.PodResourcePolicy{ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{
+				{ContainerName: config.AllContainers, MaxAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+			}},
+		}}}
+		dep := newDeployment()
+		dep.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app"}, {Name: "worker"}}
+
+		vpa, err := BuildManagedVPA(cfg, metaCfg, dep, DeploymentGVK, "p1")
+		require.NoError(t, err)
+		policies, _, _ := unstructured.NestedSlice(vpa.Object, "spec", "resourcePolicy", "containerPolicies")
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "app", "maxAllowed": map[string]any{"cpu": "2"}},
+			map[string]any{"containerName": "worker", "maxAllowed": map[string]any{"cpu": "2"}},
+		}, policies)
+
+		cfg.ContainerPolicyMode = ContainerPolicyModeWildcard
+		vpa, err = BuildManagedVPA(cfg, metaCfg, dep, DeploymentGVK, "p1")
+		require.NoError(t, err)
+		policies, _, _ = unstructured.NestedSlice(vpa.Object, "spec", "resourcePolicy", "containerPolicies")
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "*", "maxAllowed": map[string]any{"cpu": "2"}},
+		}, policies)
+	})
+}
+
 func TestControllerBuildVPASpec(t *testing.T) {
 	t.Parallel()
 
 	t.Run("Sets targetRef and merges profile", func(t *testing.T) {
 		t.Parallel()
-		profile := config.ProfileSpec{
-			UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
-				UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeRecreate),
+		profile := config.Profile{
+			Spec: config.ProfileSpec{
+				UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+					UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeRecreate),
+				},
 			},
 		}
 		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
 
-		spec, err := buildVPASpec(profile, gvk, "demo")
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, TargetGVK: gvk, WorkloadName: "demo"})
 		require.NoError(t, err)
 
 		target := spec["targetRef"].(map[string]any)
@@ -106,6 +387,398 @@ func TestControllerBuildVPASpec(t *testing.T) {
 		updatePolicy := spec["updatePolicy"].(map[string]any)
 		assert.Equal(t, string(vpaautoscaling.UpdateModeRecreate), updatePolicy["updateMode"])
 	})
+
+	t.Run("Uses profile targetApiVersion override", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{TargetAPIVersion: "argoproj.io/v1alpha1"}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Rollout")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		target := spec["targetRef"].(map[string]any)
+		assert.Equal(t, "argoproj.io/v1alpha1", target["apiVersion"])
+		assert.Equal(t, "Rollout", target["kind"])
+		assert.Equal(t, "demo", target["name"])
+	})
+
+	t.Run("Falls back to workload apiVersion without override", func(t *testing.T) {
+		t.Parallel()
+		gvk := appsv1.SchemeGroupVersion.WithKind("StatefulSet")
+
+		spec, err := buildVPASpec(vpaSpecInput{TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		target := spec["targetRef"].(map[string]any)
+		assert.Equal(t, "apps/v1", target["apiVersion"])
+		assert.Equal(t, "StatefulSet", target["kind"])
+	})
+
+	t.Run("Injects default update mode when profile has none", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			Spec: config.ProfileSpec{
+				UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{MinReplicas: ptr.To(int32(2))},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultUpdateMode: vpaautoscaling.UpdateModeOff, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		updatePolicy := spec["updatePolicy"].(map[string]any)
+		assert.Equal(t, string(vpaautoscaling.UpdateModeOff), updatePolicy["updateMode"])
+		assert.Equal(t, int64(2), updatePolicy["minReplicas"])
+		assert.Nil(t, profile.Spec.UpdatePolicy.UpdateMode, "shared profile must not be mutated")
+	})
+
+	t.Run("Injects default update mode without update policy", func(t *testing.T) {
+		t.Parallel()
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{DefaultUpdateMode: vpaautoscaling.UpdateModeInitial, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		updatePolicy := spec["updatePolicy"].(map[string]any)
+		assert.Equal(t, string(vpaautoscaling.UpdateModeInitial), updatePolicy["updateMode"])
+	})
+
+	t.Run("Keeps explicit profile update mode over default", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			Spec: config.ProfileSpec{
+				UpdatePolicy: &vpaautoscaling.PodUpdatePolicy{
+					UpdateMode: updateModePtr(t, vpaautoscaling.UpdateModeRecreate),
+				},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultUpdateMode: vpaautoscaling.UpdateModeOff, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		updatePolicy := spec["updatePolicy"].(map[string]any)
+		assert.Equal(t, string(vpaautoscaling.UpdateModeRecreate), updatePolicy["updateMode"])
+	})
+
+	t.Run("Writes profile recommenders", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			Spec: config.ProfileSpec{
+				Recommenders: []*vpaautoscaling.VerticalPodAutoscalerRecommenderSelector{{Name: "frugal"}},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultRecommender: "default-recommender", TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{map[string]any{"name": "frugal"}}, spec["recommenders"])
+	})
+
+	t.Run("Injects default recommender when profile has none", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultRecommender: "performance", TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{map[string]any{"name": "performance"}}, spec["recommenders"])
+		assert.Nil(t, profile.Spec.Recommenders, "shared profile must not be mutated")
+	})
+
+	t.Run("Omits recommenders without profile or default", func(t *testing.T) {
+		t.Parallel()
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.NotContains(t, spec, "recommenders")
+	})
+
+	defaultBounds := &containerLimits{
+		Min: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+		},
+		Max: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		},
+	}
+
+	t.Run("Injects default bounds into a wildcard policy", func(t *testing.T) {
+		t.Parallel()
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{DefaultBounds: defaultBounds, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"containerPolicies": []any{map[string]any{
+				"containerName": "*",
+				"minAllowed":    map[string]any{"cpu": "10m", "memory": "32Mi"},
+				"maxAllowed":    map[string]any{"cpu": "4", "memory": "8Gi"},
+			}},
+		}, spec["resourcePolicy"])
+	})
+
+	t.Run("Preserves explicit bounds and fills missing resources", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			Spec: config.ProfileSpec{
+				ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+					ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{
+						{
+							ContainerName: "app",
+							MinAllowed:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+							MaxAllowed: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("1"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+						{ContainerName: "*"},
+					},
+				},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultBounds: defaultBounds, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{
+				"containerName": "app",
+				"minAllowed":    map[string]any{"cpu": "250m", "memory": "32Mi"},
+				"maxAllowed":    map[string]any{"cpu": "1", "memory": "1Gi"},
+			},
+			map[string]any{
+				"containerName": "*",
+				"minAllowed":    map[string]any{"cpu": "10m", "memory": "32Mi"},
+				"maxAllowed":    map[string]any{"cpu": "4", "memory": "8Gi"},
+			},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+		assert.Len(t, profile.Spec.ResourcePolicy.ContainerPolicies[0].MinAllowed, 1, "shared profile must not be mutated")
+		assert.Nil(t, profile.Spec.ResourcePolicy.ContainerPolicies[1].MinAllowed, "shared profile must not be mutated")
+	})
+
+	t.Run("Skips defaults crossing an explicit bound", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			Spec: config.ProfileSpec{
+				ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+					ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+						ContainerName: "tiny",
+						MaxAllowed:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("5m")},
+						MinAllowed:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Gi")},
+					}},
+				},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultBounds: defaultBounds, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{map[string]any{
+			"containerName": "tiny",
+			"minAllowed":    map[string]any{"memory": "16Gi"},
+			"maxAllowed":    map[string]any{"cpu": "5m"},
+		}}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+	})
+
+	t.Run("Injects default controlled resources into a wildcard policy", func(t *testing.T) {
+		t.Parallel()
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{DefaultControlled: []corev1.ResourceName{corev1.ResourceCPU}, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"containerPolicies": []any{map[string]any{
+				"containerName":       "*",
+				"controlledResources": []any{"cpu"},
+			}},
+		}, spec["resourcePolicy"])
+	})
+
+	t.Run("Preserves explicit controlled resources", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			Spec: config.ProfileSpec{
+				ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+					ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{
+						{
+							ContainerName:       "app",
+							ControlledResources: &[]corev1.ResourceName{corev1.ResourceMemory},
+						},
+						{
+							ContainerName:       "sidecar",
+							ControlledResources: &[]corev1.ResourceName{},
+						},
+						{ContainerName: "*"},
+					},
+				},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultControlled: []corev1.ResourceName{corev1.ResourceCPU}, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "app", "controlledResources": []any{"memory"}},
+			map[string]any{"containerName": "sidecar", "controlledResources": []any{}},
+			map[string]any{"containerName": "*", "controlledResources": []any{"cpu"}},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+		assert.Nil(t, profile.Spec.ResourcePolicy.ContainerPolicies[2].ControlledResources, "shared profile must not be mutated")
+	})
+
+	t.Run("Turns excluded containers off", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			ExcludeContainers: []string{"istio-proxy"},
+			Spec: config.ProfileSpec{
+				ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+					ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+						ContainerName: vpaautoscaling.DefaultContainerResourcePolicy,
+						MaxAllowed:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+					}},
+				},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "*", "maxAllowed": map[string]any{"cpu": "2"}},
+			map[string]any{"containerName": "istio-proxy", "mode": "Off"},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+		assert.Len(t, profile.Spec.ResourcePolicy.ContainerPolicies, 1, "shared profile must not be mutated")
+	})
+
+	t.Run("Excluded containers replace their profile policy", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{
+			ExcludeContainers: []string{"istio-proxy"},
+			Spec: config.ProfileSpec{
+				ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+					ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+						ContainerName: "istio-proxy",
+						MinAllowed:    corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+					}},
+				},
+			},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "istio-proxy", "mode": "Off"},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+		assert.Nil(t, profile.Spec.ResourcePolicy.ContainerPolicies[0].Mode, "shared profile must not be mutated")
+	})
+
+	t.Run("Excluded containers skip default bounds", func(t *testing.T) {
+		t.Parallel()
+		profile := config.Profile{ExcludeContainers: []string{"istio-proxy", "linkerd-proxy"}}
+		defaultBounds := &containerLimits{
+			Min: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: profile, DefaultBounds: defaultBounds, TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "*", "minAllowed": map[string]any{"memory": "32Mi"}},
+			map[string]any{"containerName": "istio-proxy", "mode": "Off"},
+			map[string]any{"containerName": "linkerd-proxy", "mode": "Off"},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+	})
+
+	allContainersProfile := func() config.Profile {
+		return config.Profile{
+			ExcludeContainers: []string{"istio-proxy"},
+			Spec: config.ProfileSpec{
+				ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+					ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{
+						{ContainerName: config.AllContainers, MaxAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+						{ContainerName: "app", MaxAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Expands __all__ per container in explicit mode", func(t *testing.T) {
+		t.Parallel()
+		profile := allContainersProfile()
+		defaultBounds := &containerLimits{
+			Min: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+		}
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{
+			Profile:             profile,
+			DefaultBounds:       defaultBounds,
+			TargetGVK:           gvk,
+			WorkloadName:        "demo",
+			ContainerPolicyMode: ContainerPolicyModeExplicit,
+			Containers:          []string{"app", "worker", "istio-proxy"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "worker", "minAllowed": map[string]any{"memory": "32Mi"}, "maxAllowed": map[string]any{"cpu": "2"}},
+			map[string]any{"containerName": "istio-proxy", "mode": "Off"},
+			map[string]any{"containerName": "app", "minAllowed": map[string]any{"memory": "32Mi"}, "maxAllowed": map[string]any{"cpu": "4"}},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+		assert.Equal(t, allContainersProfile(), profile, "shared profile must not be mutated")
+	})
+
+	t.Run("Collapses __all__ to the wildcard in wildcard mode", func(t *testing.T) {
+		t.Parallel()
+		profile := allContainersProfile()
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{
+			Profile:             profile,
+			TargetGVK:           gvk,
+			WorkloadName:        "demo",
+			ContainerPolicyMode: ContainerPolicyModeWildcard,
+			Containers:          []string{"app", "worker", "istio-proxy"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "*", "maxAllowed": map[string]any{"cpu": "2"}},
+			map[string]any{"containerName": "app", "maxAllowed": map[string]any{"cpu": "4"}},
+			map[string]any{"containerName": "istio-proxy", "mode": "Off"},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+		assert.Equal(t, allContainersProfile(), profile, "shared profile must not be mutated")
+	})
+
+	t.Run("Collapses __all__ to the wildcard without known containers", func(t *testing.T) {
+		t.Parallel()
+		gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+
+		spec, err := buildVPASpec(vpaSpecInput{Profile: allContainersProfile(), TargetGVK: gvk, WorkloadName: "demo"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{
+			map[string]any{"containerName": "*", "maxAllowed": map[string]any{"cpu": "2"}},
+			map[string]any{"containerName": "app", "maxAllowed": map[string]any{"cpu": "4"}},
+			map[string]any{"containerName": "istio-proxy", "mode": "Off"},
+		}, spec["resourcePolicy"].(map[string]any)["containerPolicies"])
+	})
 }
 
 func TestControllerNewVPAObject(t *testing.T) {
@@ -124,6 +797,30 @@ func TestControllerNewVPAObject(t *testing.T) {
 	})
 }
 
+// TestControllerSetVPAGroupVersion mutates package state and therefore does not
+// run in parallel; serial tests finish before any parallel test resumes.
+func TestControllerSetVPAGroupVersion(t *testing.T) {
+	original := vpaGVK.GroupVersion()
+	t.Cleanup(func() { SetVPAGroupVersion(original) })
+
+	SetVPAGroupVersion(schema.GroupVersion{Group: "autoscaling.example.io", Version: "v1beta2"})
+
+	t.Run("Renders VPA objects with the overridden version", func(t *testing.T) {
+		obj := newVPAObject()
+		assert.Equal(t, "autoscaling.example.io/v1beta2", obj.GetAPIVersion())
+		assert.Equal(t, "VerticalPodAutoscaler", obj.GetKind())
+	})
+
+	t.Run("Overrides list and exported GVK", func(t *testing.T) {
+		assert.Equal(t, schema.GroupVersionKind{
+			Group:   "autoscaling.example.io",
+			Version: "v1beta2",
+			Kind:    "VerticalPodAutoscalerList",
+		}, vpaListGVK)
+		assert.Equal(t, vpaGVK, VPAGroupVersionKind())
+	})
+}
+
 func TestControllerOwnerRefsEqual(t *testing.T) {
 	t.Parallel()
 
@@ -149,3 +846,181 @@ func TestControllerOwnerRefsEqual(t *testing.T) {
 		assert.False(t, ownerRefsEqual(a, b))
 	})
 }
+
+func TestControllerIsManagedFieldsError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Detects invalid managedFields", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, isManagedFieldsError(newManagedFieldsError()))
+	})
+
+	t.Run("Detects conflicts on a managedFields entry", func(t *testing.T) {
+		t.Parallel()
+		err := &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status: metav1.StatusFailure,
+			Reason: metav1.StatusReasonConflict,
+			Code:   http.StatusConflict,
+			Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{
+				Type:  metav1.CauseTypeFieldValueInvalid,
+				Field: "metadata.managedFields[0]",
+			}}},
+		}}
+		assert.True(t, isManagedFieldsError(err))
+	})
+
+	t.Run("Ignores invalid errors on other fields", func(t *testing.T) {
+		t.Parallel()
+		err := apierrors.NewInvalid(vpaGVK.GroupKind(), "demo", field.ErrorList{
+			field.Invalid(field.NewPath("spec", "updatePolicy"), nil, "metadata.managedFields is mentioned here"),
+		})
+		assert.False(t, isManagedFieldsError(err))
+	})
+
+	t.Run("Ignores other errors", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, isManagedFieldsError(errors.New("metadata.managedFields must be nil")))
+		assert.False(t, isManagedFieldsError(apierrors.NewBadRequest("metadata.managedFields must be nil")))
+		assert.False(t, isManagedFieldsError(nil))
+	})
+}
+
+// newManagedFieldsError returns the error the API server returns for an apply
+// with invalid managedFields.
+func newManagedFieldsError() error {
+	return apierrors.NewInvalid(vpaGVK.GroupKind(), "demo", field.ErrorList{
+		field.Invalid(field.NewPath("metadata", "managedFields"), nil, "must be nil or contain valid entries"),
+	})
+}
+
+func TestControllerOwnerRefMatches(t *testing.T) {
+	t.Parallel()
+
+	owner := &appsv1.Deployment{}
+	owner.SetName("demo")
+	owner.SetUID("uid1")
+
+	t.Run("Matches kind, name and UID", func(t *testing.T) {
+		t.Parallel()
+		matches, mismatch := ownerRefMatches(metav1.OwnerReference{Kind: "Deployment", Name: "demo", UID: "uid1"}, owner, "Deployment")
+		assert.True(t, matches)
+		assert.False(t, mismatch)
+	})
+
+	t.Run("Matches without UID", func(t *testing.T) {
+		t.Parallel()
+		matches, mismatch := ownerRefMatches(metav1.OwnerReference{Kind: "Deployment", Name: "demo"}, owner, "Deployment")
+		assert.True(t, matches)
+		assert.False(t, mismatch)
+	})
+
+	t.Run("Reports UID mismatch for same name", func(t *testing.T) {
+		t.Parallel()
+		matches, mismatch := ownerRefMatches(metav1.OwnerReference{Kind: "Deployment", Name: "demo", UID: "uid0"}, owner, "Deployment")
+		assert.False(t, matches)
+		assert.True(t, mismatch)
+	})
+
+	t.Run("Ignores other kinds and names", func(t *testing.T) {
+		t.Parallel()
+		matches, mismatch := ownerRefMatches(metav1.OwnerReference{Kind: "StatefulSet", Name: "demo", UID: "uid0"}, owner, "Deployment")
+		assert.False(t, matches)
+		assert.False(t, mismatch)
+
+		matches, mismatch = ownerRefMatches(metav1.OwnerReference{Kind: "Deployment", Name: "other", UID: "uid0"}, owner, "Deployment")
+		assert.False(t, matches)
+		assert.False(t, mismatch)
+	})
+}
+
+func TestControllerParseProfileNames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Parses a single profile", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"p1"}, parseProfileNames("p1"))
+	})
+
+	t.Run("Splits, trims and deduplicates a list", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"p1", "p2"}, parseProfileNames(" p1, p2 ,p1,"))
+	})
+
+	t.Run("Returns nothing for empty values", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, parseProfileNames(" , "))
+	})
+}
+
+func TestControllerWorkloadProfileNames(t *testing.T) {
+	t.Parallel()
+
+	meta := MetaConfig{ProfileKey: "vpa/profile"}
+	metaEmptyDefault := MetaConfig{ProfileKey: "vpa/profile", EmptyMeansDefault: true}
+
+	t.Run("Returns the listed profiles", func(t *testing.T) {
+		t.Parallel()
+		annotations := map[string]string{"vpa/profile": "p1,p2"}
+		assert.Equal(t, []string{"p1", "p2"}, workloadProfileNames(annotations, metaEmptyDefault, "def"))
+	})
+
+	t.Run("Empty annotation opts out by default", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, workloadProfileNames(map[string]string{"vpa/profile": ""}, meta, "def"))
+	})
+
+	t.Run("Empty annotation selects the default when enabled", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"def"}, workloadProfileNames(map[string]string{"vpa/profile": " "}, metaEmptyDefault, "def"))
+	})
+
+	t.Run("Missing annotation opts out when enabled", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, workloadProfileNames(nil, metaEmptyDefault, "def"))
+	})
+
+	t.Run("Reads the first configured key that is set", func(t *testing.T) {
+		t.Parallel()
+		metaLegacy := MetaConfig{ProfileKey: "vpa/profile", LegacyProfileKeys: []string{"old/profile"}}
+
+		assert.Equal(t, []string{"p2"}, workloadProfileNames(map[string]string{"old/profile": "p2"}, metaLegacy, "def"))
+		assert.Equal(t, []string{"p1"}, workloadProfileNames(map[string]string{"vpa/profile": "p1", "old/profile": "p2"}, metaLegacy, "def"))
+		assert.Empty(t, workloadProfileNames(map[string]string{"vpa/profile": "", "old/profile": "p2"}, metaLegacy, "def"))
+	})
+}
+
+func TestControllerRecommendedLabels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Returns only app.kubernetes.io labels", func(t *testing.T) {
+		t.Parallel()
+		got := recommendedLabels(map[string]string{
+			"app.kubernetes.io/name": "demo",
+			"app.kubernetes.io":      "x",
+			"app":                    "demo",
+		})
+		assert.Equal(t, map[string]string{"app.kubernetes.io/name": "demo"}, got)
+	})
+
+	t.Run("Returns nil when nothing matches", func(t *testing.T) {
+		t.Parallel()
+		assert.Nil(t, recommendedLabels(map[string]string{"app": "demo"}))
+		assert.Nil(t, recommendedLabels(nil))
+	})
+}
+
+func TestControllerTrackingAnnotations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Returns only listed keys that are present", func(t *testing.T) {
+		t.Parallel()
+		got := trackingAnnotations(map[string]string{"a": "1", "b": "", "c": "3"}, []string{"a", "b", "d"})
+		assert.Equal(t, map[string]string{"a": "1", "b": ""}, got)
+	})
+
+	t.Run("Returns nil when nothing matches", func(t *testing.T) {
+		t.Parallel()
+		assert.Nil(t, trackingAnnotations(map[string]string{"c": "3"}, []string{"a"}))
+		assert.Nil(t, trackingAnnotations(nil, nil))
+	})
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/metrics"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagedVPAAgeReporter periodically relists managed VPAs and publishes how
+// long each of them has existed, for lifecycle dashboards.
+//
+// It runs as a manager Runnable on the leader only.
+type ManagedVPAAgeReporter struct {
+	KubeClient client.Client
+	Logger     *logr.Logger
+	Metrics    *metrics.Registry
+	Meta       MetaConfig
+	Interval   time.Duration // Time between recomputations.
+
+	now func() time.Time // Overridable clock for tests; defaults to time.Now.
+}
+
+// Start recomputes the gauge immediately and then on every interval until ctx is done.
+func (r *ManagedVPAAgeReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.recompute(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// recompute refreshes the gauge; failures are logged and retried on the next tick.
+func (r *ManagedVPAAgeReporter) recompute(ctx context.Context) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(vpaListGVK)
+	if err := r.KubeClient.List(ctx, list, r.Meta.managedSelector()); err != nil {
+		r.Logger.Error(fmt.Errorf("list managed VPAs: %w", err), "failed to recompute managed VPA ages")
+		return
+	}
+	r.Metrics.SetManagedVPAAges(managedVPAAges(list.Items, r.Meta.ProfileKey, r.clock()))
+}
+
+// clock returns the current time.
+func (r *ManagedVPAAgeReporter) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// managedVPAAges returns the age of each VPA at now, labeled with the profile
+// from profileKey. VPAs without a creationTimestamp are skipped; ages are
+// clamped at zero to tolerate clock skew.
+func managedVPAAges(vpas []unstructured.Unstructured, profileKey string, now time.Time) []metrics.VPAAge {
+	ages := make([]metrics.VPAAge, 0, len(vpas))
+	for i := range vpas {
+		created := vpas[i].GetCreationTimestamp()
+		if created.IsZero() {
+			continue
+		}
+		ages = append(ages, metrics.VPAAge{
+			Namespace: vpas[i].GetNamespace(),
+			Name:      vpas[i].GetName(),
+			Profile:   vpas[i].GetLabels()[profileKey],
+			Age:       max(now.Sub(created.Time), 0),
+		})
+	}
+	return ages
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestManagedVPAAges(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	newAgedVPA := func(name, profile string, created time.Time) unstructured.Unstructured {
+		vpa := newVPAObject()
+		vpa.SetNamespace("ns1")
+		vpa.SetName(name)
+		vpa.SetLabels(map[string]string{"vpa/profile": profile})
+		vpa.SetCreationTimestamp(metav1.NewTime(created))
+		return *vpa
+	}
+
+	t.Run("Computes ages of multiple VPAs", func(t *testing.T) {
+		t.Parallel()
+
+		ages := managedVPAAges([]unstructured.Unstructured{
+			newAgedVPA("fresh", "p1", now.Add(-30*time.Second)),
+			newAgedVPA("old", "p2", now.Add(-72*time.Hour)),
+		}, "vpa/profile", now)
+
+		assert.Equal(t, []internalmetrics.VPAAge{
+			{Namespace: "ns1", Name: "fresh", Profile: "p1", Age: 30 * time.Second},
+			{Namespace: "ns1", Name: "old", Profile: "p2", Age: 72 * time.Hour},
+		}, ages)
+	})
+
+	t.Run("Skips VPAs without creationTimestamp", func(t *testing.T) {
+		t.Parallel()
+
+		ages := managedVPAAges([]unstructured.Unstructured{
+			newAgedVPA("unknown", "p1", time.Time{}),
+			newAgedVPA("known", "p1", now.Add(-time.Minute)),
+		}, "vpa/profile", now)
+
+		require.Len(t, ages, 1)
+		assert.Equal(t, "known", ages[0].Name)
+	})
+
+	t.Run("Clamps future timestamps to zero", func(t *testing.T) {
+		t.Parallel()
+
+		ages := managedVPAAges([]unstructured.Unstructured{
+			newAgedVPA("skewed", "p1", now.Add(time.Minute)),
+		}, "vpa/profile", now)
+
+		require.Len(t, ages, 1)
+		assert.Zero(t, ages[0].Age)
+	})
+
+	t.Run("Returns empty for no VPAs", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, managedVPAAges(nil, "vpa/profile", now))
+	})
+}
+
+func TestManagedVPAAgeReporter_recompute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	newReporter := func(t *testing.T, kubeClient client.Client) (*ManagedVPAAgeReporter, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+		return &ManagedVPAAgeReporter{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta:       MetaConfig{ProfileKey: "vpa/profile", ManagedLabel: "vpa/managed"},
+			Interval:   time.Minute,
+			now:        func() time.Time { return now },
+		}, promReg
+	}
+
+	t.Run("Publishes ages of managed VPAs only", func(t *testing.T) {
+		t.Parallel()
+
+		managed := newVPAObject()
+		managed.SetNamespace("ns1")
+		managed.SetName("managed")
+		managed.SetLabels(map[string]string{"vpa/managed": "true", "vpa/profile": "p1"})
+		managed.SetCreationTimestamp(metav1.NewTime(now.Add(-2 * time.Hour)))
+		unmanaged := newVPAObject()
+		unmanaged.SetNamespace("ns1")
+		unmanaged.SetName("manual")
+		unmanaged.SetCreationTimestamp(metav1.NewTime(now.Add(-time.Hour)))
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(managed, unmanaged).Build()
+		reporter, promReg := newReporter(t, kubeClient)
+
+		reporter.recompute(context.Background())
+
+		assert.Equal(t, float64(7200), gaugeValue(t, promReg, "autovpa_managed_vpa_age_seconds", map[string]string{
+			"namespace": "ns1",
+			"name":      "managed",
+			"profile":   "p1",
+		}))
+		families, err := promReg.Gather()
+		require.NoError(t, err)
+		for _, mf := range families {
+			if mf.GetName() == "autovpa_managed_vpa_age_seconds" {
+				assert.Len(t, mf.GetMetric(), 1)
+			}
+		}
+	})
+
+	t.Run("Keeps the previous values on list errors", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return errors.New("boom")
+			},
+		}).Build()
+		reporter, promReg := newReporter(t, kubeClient)
+		reporter.Metrics.SetManagedVPAAges([]internalmetrics.VPAAge{{Namespace: "ns1", Name: "a", Profile: "p1", Age: time.Minute}})
+
+		reporter.recompute(context.Background())
+
+		assert.Equal(t, float64(60), gaugeValue(t, promReg, "autovpa_managed_vpa_age_seconds", map[string]string{
+			"namespace": "ns1",
+			"name":      "a",
+			"profile":   "p1",
+		}))
+	})
+}
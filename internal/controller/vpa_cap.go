@@ -0,0 +1,36 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+)
+
+// vpaCapReached reports whether namespace already holds MaxVPAsPerNamespace
+// managed VPAs, along with the current count. It always reports false when
+// the cap is disabled.
+func (b *BaseReconciler) vpaCapReached(ctx context.Context, namespace string) (bool, int, error) {
+	if b.MaxVPAsPerNamespace <= 0 {
+		return false, 0, nil
+	}
+
+	vpas, err := b.listManagedVPAs(ctx, namespace)
+	if err != nil {
+		return false, 0, err
+	}
+	return len(vpas) >= b.MaxVPAsPerNamespace, len(vpas), nil
+}
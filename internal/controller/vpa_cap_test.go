@@ -0,0 +1,152 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBaseReconciler_ReconcileWorkload_MaxVPAsPerNamespace(t *testing.T) {
+	t.Parallel()
+
+	newReconciler := func(t *testing.T, maxVPAs int, objs ...client.Object) (*BaseReconciler, *events.FakeRecorder, *prometheus.Registry) {
+		t.Helper()
+		logger := logr.Discard()
+		recorder := events.NewFakeRecorder(10)
+		promReg := prometheus.NewRegistry()
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			Logger:     &logger,
+			Recorder:   recorder,
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   profileKey,
+				ManagedLabel: managedLabelKey,
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": {}},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			MaxVPAsPerNamespace: maxVPAs,
+		}, recorder, promReg
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		dep.SetNamespace("ns1")
+		dep.SetName("demo")
+		dep.SetUID("uid1")
+		dep.SetAnnotations(map[string]string{profileKey: "p1"})
+		return dep
+	}
+
+	getVPA := func(t *testing.T, r *BaseReconciler) error {
+		t.Helper()
+		return r.KubeClient.Get(context.Background(), types.NamespacedName{
+			Name:      renderDeploymentVPAName(t, "ns1", "demo", "p1"),
+			Namespace: "ns1",
+		}, newVPAObject())
+	}
+
+	existing := func(t *testing.T) []client.Object {
+		return []client.Object{
+			newManagedVPA(t, "ns1", "a-p1-vpa", "p1"),
+			newManagedVPA(t, "ns1", "b-p1-vpa", "p1"),
+			newManagedVPA(t, "ns2", "c-p1-vpa", "p1"),
+		}
+	}
+
+	t.Run("Refuses to create a VPA at the cap", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		r, rec, promReg := newReconciler(t, 2, append(existing(t), dep)...)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		assert.True(t, apierrors.IsNotFound(getVPA(t, r)))
+		require.Len(t, rec.Events, 1)
+		assert.Equal(t, "Warning VPACapExceeded Namespace already has 2 managed VPAs (max 2); skipping VPA "+renderDeploymentVPAName(t, "ns1", "demo", "p1"), <-rec.Events)
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"reason":    vpaSkipReasonVPACapExceeded,
+		}))
+	})
+
+	t.Run("Creates a VPA below the cap", func(t *testing.T) {
+		t.Parallel()
+
+		// VPAs in other namespaces do not count towards the cap.
+		dep := newDeployment()
+		r, _, _ := newReconciler(t, 3, append(existing(t), dep)...)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		require.NoError(t, getVPA(t, r))
+	})
+
+	t.Run("Unlimited by default", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		r, _, _ := newReconciler(t, 0, append(existing(t), dep)...)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		require.NoError(t, getVPA(t, r))
+	})
+
+	t.Run("Keeps updating existing VPAs at the cap", func(t *testing.T) {
+		t.Parallel()
+
+		dep := newDeployment()
+		r, rec, _ := newReconciler(t, 1, dep)
+
+		_, err := r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		require.NoError(t, getVPA(t, r))
+
+		// A second reconcile finds its own VPA and never hits the cap.
+		_, err = r.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+		require.NoError(t, getVPA(t, r))
+		close(rec.Events)
+		for event := range rec.Events {
+			assert.NotContains(t, event, vpaEventVPACapExceeded)
+		}
+	})
+}
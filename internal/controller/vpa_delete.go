@@ -0,0 +1,112 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containeroo/autovpa/internal/metrics"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons for deleting a managed VPA, logged by deleteVPA.
+const (
+	deleteReasonObsolete            = "obsolete"
+	deleteReasonOptOut              = "opt_out"
+	deleteReasonWorkloadGone        = "workload_gone"
+	deleteReasonRotation            = "rotation"
+	deleteReasonOrphaned            = "orphaned"
+	deleteReasonOwnerGone           = "owner_gone"
+	deleteReasonMultipleControllers = "multiple_controllers"
+	deleteReasonTargetRefMismatch   = "targetref_mismatch"
+)
+
+// deleteVPA deletes a managed VPA for reason and records it: one "deleted VPA"
+// log line carrying the reason, the reason's deletion counter and the managed
+// gauge decrement. kind is the workload kind the VPA belongs to, if known.
+//
+// A VPA carrying ManagedFinalizer is decremented once the VPAReconciler removes
+// the finalizer instead. It reports false without error when the VPA was
+// already gone, in which case nothing is recorded.
+func deleteVPA(
+	ctx context.Context,
+	c client.Client,
+	log logr.Logger,
+	reg *metrics.Registry,
+	profileKey string,
+	vpa client.Object,
+	reason string,
+	kind string,
+) (bool, error) {
+	if err := c.Delete(ctx, vpa); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("delete VPA %s (%s): %w", vpa.GetName(), reason, err)
+	}
+
+	namespace := vpa.GetNamespace()
+	log.Info(
+		"deleted VPA",
+		"vpa", vpa.GetName(),
+		"namespace", namespace,
+		"kind", kind,
+		"reason", reason,
+	)
+
+	switch reason {
+	case deleteReasonObsolete:
+		reg.IncVPADeletedObsolete(namespace, kind)
+	case deleteReasonOptOut:
+		reg.IncVPADeletedOptOut(namespace, kind)
+	case deleteReasonWorkloadGone:
+		reg.IncVPADeletedWorkloadGone(namespace, kind)
+	case deleteReasonOwnerGone:
+		reg.IncVPADeletedOwnerGone(namespace, kind)
+	case deleteReasonOrphaned:
+		reg.IncVPADeletedOrphaned(namespace)
+	case deleteReasonMultipleControllers:
+		reg.IncVPADeletedMultipleControllers(namespace)
+	case deleteReasonTargetRefMismatch:
+		reg.IncVPADeletedTargetRefMismatch(namespace, kind)
+	}
+
+	if !hasManagedFinalizer(vpa) {
+		reg.DecVPAManaged(namespace, profileFromLabels(vpa.GetLabels(), profileKey))
+	}
+	return true, nil
+}
+
+// deleteVPA deletes a managed VPA of owner for reason; see the package-level
+// deleteVPA. The log line also names the workload, and a deletion is recorded
+// as the reconcile outcome.
+func (b *BaseReconciler) deleteVPA(ctx context.Context, owner, vpa client.Object, reason, kind string) (bool, error) {
+	log := b.Logger.WithValues("workload", owner.GetName())
+	deleted, err := deleteVPA(ctx, b.KubeClient, log, b.Metrics, b.Meta.ProfileKey, vpa, reason, kind)
+	if deleted {
+		recordOutcome(ctx, outcomeDeleted)
+	}
+	return deleted, err
+}
+
+// deleteVPA deletes a managed VPA for reason; see the package-level deleteVPA.
+func (r *VPAReconciler) deleteVPA(ctx context.Context, vpa client.Object, reason, kind string) (bool, error) {
+	return deleteVPA(ctx, r.KubeClient, *r.Logger, r.Metrics, r.Meta.ProfileKey, vpa, reason, kind)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// captureDeletions returns a logger and a func listing the "deleted VPA" lines
+// it logged.
+func captureDeletions() (logr.Logger, func() []string) {
+	var (
+		mu    sync.Mutex
+		lines []string
+	)
+	logger := funcr.New(func(_, args string) {
+		if !strings.Contains(args, `"msg"="deleted VPA"`) {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	return logger, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), lines...)
+	}
+}
+
+func TestDeleteVPA(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		reason string
+		kind   string
+		metric string
+		labels map[string]string
+	}{
+		{reason: deleteReasonObsolete, kind: "Deployment", metric: "autovpa_vpa_deleted_obsolete_total", labels: map[string]string{"namespace": "ns1", "kind": "Deployment"}},
+		{reason: deleteReasonOptOut, kind: "Deployment", metric: "autovpa_vpa_deleted_opt_out_total", labels: map[string]string{"namespace": "ns1", "kind": "Deployment"}},
+		{reason: deleteReasonWorkloadGone, kind: "StatefulSet", metric: "autovpa_vpa_deleted_workload_gone_total", labels: map[string]string{"namespace": "ns1", "kind": "StatefulSet"}},
+		{reason: deleteReasonOwnerGone, kind: "DaemonSet", metric: "autovpa_vpa_deleted_owner_gone_total", labels: map[string]string{"namespace": "ns1", "kind": "DaemonSet"}},
+		{reason: deleteReasonOrphaned, metric: "autovpa_vpa_deleted_orphaned_total", labels: map[string]string{"namespace": "ns1"}},
+		{reason: deleteReasonMultipleControllers, metric: "autovpa_vpa_deleted_multiple_controllers_total", labels: map[string]string{"namespace": "ns1"}},
+		{reason: deleteReasonTargetRefMismatch, kind: "Deployment", metric: "autovpa_vpa_deleted_targetref_mismatch_total", labels: map[string]string{"namespace": "ns1", "kind": "Deployment"}},
+		{reason: deleteReasonRotation, kind: "Deployment"},
+	}
+
+	for _, tc := range tests {
+		t.Run("Records "+tc.reason, func(t *testing.T) {
+			t.Parallel()
+
+			vpa := newManagedVPA(t, "ns1", "demo-vpa", "p1")
+			c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(vpa).Build()
+			logger, deletions := captureDeletions()
+			promReg := prometheus.NewRegistry()
+			reg := internalmetrics.NewRegistry(promReg)
+			reg.IncVPAManaged("ns1", "p1")
+
+			deleted, err := deleteVPA(context.Background(), c, logger, reg, profileKey, vpa, tc.reason, tc.kind)
+			require.NoError(t, err)
+			assert.True(t, deleted)
+
+			require.Len(t, deletions(), 1)
+			assert.Contains(t, deletions()[0], `"vpa"="demo-vpa"`)
+			assert.Contains(t, deletions()[0], `"namespace"="ns1"`)
+			assert.Contains(t, deletions()[0], `"reason"="`+tc.reason+`"`)
+
+			if tc.metric != "" {
+				assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, tc.metric, tc.labels))
+			}
+			assert.Equal(t, float64(0), gaugeValue(t, promReg, "autovpa_managed_vpa", map[string]string{"namespace": "ns1", "profile": "p1"}))
+		})
+	}
+
+	t.Run("Keeps the managed gauge for finalized VPAs", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newManagedVPA(t, "ns1", "demo-vpa", "p1")
+		vpa.SetFinalizers([]string{ManagedFinalizer})
+		c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(vpa).Build()
+		promReg := prometheus.NewRegistry()
+		reg := internalmetrics.NewRegistry(promReg)
+		reg.IncVPAManaged("ns1", "p1")
+
+		deleted, err := deleteVPA(context.Background(), c, logr.Discard(), reg, profileKey, vpa, deleteReasonOptOut, "Deployment")
+		require.NoError(t, err)
+		assert.True(t, deleted)
+		assert.Equal(t, float64(1), gaugeValue(t, promReg, "autovpa_managed_vpa", map[string]string{"namespace": "ns1", "profile": "p1"}))
+	})
+
+	t.Run("Ignores VPAs already gone", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newManagedVPA(t, "ns1", "missing", "p1")
+		c := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+		logger, deletions := captureDeletions()
+
+		deleted, err := deleteVPA(context.Background(), c, logger, internalmetrics.NewRegistry(prometheus.NewRegistry()), profileKey, vpa, deleteReasonOrphaned, "")
+		require.NoError(t, err)
+		assert.False(t, deleted)
+		assert.Empty(t, deletions())
+	})
+
+	t.Run("Wraps delete errors with the reason", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newManagedVPA(t, "ns1", "demo-vpa", "p1")
+		c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(vpa).WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				return errors.New("boom")
+			},
+		}).Build()
+
+		_, err := deleteVPA(context.Background(), c, logr.Discard(), internalmetrics.NewRegistry(prometheus.NewRegistry()), profileKey, vpa, deleteReasonObsolete, "Deployment")
+		require.EqualError(t, err, "delete VPA demo-vpa (obsolete): boom")
+	})
+}
+
+func TestDeleteVPA_ReasonPerPath(t *testing.T) {
+	t.Parallel()
+
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "demo", UID: "uid-1"}}
+	ownedVPA := func(t *testing.T) client.Object {
+		t.Helper()
+		vpa := newManagedVPA(t, "ns1", "demo-vpa", "p1")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: DeploymentGVK.GroupVersion().String(),
+			Kind:       DeploymentGVK.Kind,
+			Name:       dep.Name,
+			UID:        dep.UID,
+			Controller: ptr.To(true),
+		}})
+		return vpa
+	}
+	newBase := func(t *testing.T, logger logr.Logger, objs ...client.Object) *BaseReconciler {
+		t.Helper()
+		return &BaseReconciler{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build(),
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta:       MetaConfig{ProfileKey: profileKey, ManagedLabel: managedLabelKey},
+		}
+	}
+
+	t.Run("Obsolete", func(t *testing.T) {
+		t.Parallel()
+		logger, deletions := captureDeletions()
+		b := newBase(t, logger, ownedVPA(t))
+
+		require.NoError(t, b.DeleteObsoleteManagedVPAs(context.Background(), dep, DeploymentGVK.Kind, "other-vpa"))
+		require.Len(t, deletions(), 1)
+		assert.Contains(t, deletions()[0], `"workload"="demo"`)
+		assert.Contains(t, deletions()[0], `"reason"="obsolete"`)
+	})
+
+	t.Run("Opt-out", func(t *testing.T) {
+		t.Parallel()
+		logger, deletions := captureDeletions()
+		b := newBase(t, logger, ownedVPA(t))
+
+		require.NoError(t, b.DeleteManagedVPAsForOptOut(context.Background(), dep, DeploymentGVK.Kind))
+		require.Len(t, deletions(), 1)
+		assert.Contains(t, deletions()[0], `"reason"="opt_out"`)
+	})
+
+	t.Run("Workload gone", func(t *testing.T) {
+		t.Parallel()
+		logger, deletions := captureDeletions()
+		b := newBase(t, logger, ownedVPA(t))
+
+		require.NoError(t, b.DeleteManagedVPAsForGoneWorkload(context.Background(), dep, DeploymentGVK.Kind))
+		require.Len(t, deletions(), 1)
+		assert.Contains(t, deletions()[0], `"reason"="workload_gone"`)
+	})
+
+	vpaPaths := []struct {
+		name   string
+		reason string
+		refs   []metav1.OwnerReference
+	}{
+		{name: "Orphaned", reason: deleteReasonOrphaned},
+		{name: "Owner gone", reason: deleteReasonOwnerGone, refs: []metav1.OwnerReference{deploymentOwnerRef("demo")}},
+		{name: "Multiple controllers", reason: deleteReasonMultipleControllers, refs: []metav1.OwnerReference{deploymentOwnerRef("a"), deploymentOwnerRef("b")}},
+	}
+	for _, tc := range vpaPaths {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			vpa := newManagedVPA(t, "ns1", "demo-vpa", "p1")
+			vpa.SetOwnerReferences(tc.refs)
+			r := newTestVPAReconciler(t, vpa)
+			logger, deletions := captureDeletions()
+			r.Logger = &logger
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "demo-vpa"}})
+			require.NoError(t, err)
+			require.Len(t, deletions(), 1)
+			assert.Contains(t, deletions()[0], `"reason"="`+tc.reason+`"`)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VPAInventoryReporter logs how many managed VPAs exist per watched namespace
+// once the caches have synced, so operators can confirm the controller sees
+// existing resources after a (re)start.
+//
+// It runs on every replica, not only the leader, and exits after logging.
+type VPAInventoryReporter struct {
+	KubeClient client.Client
+	Logger     *logr.Logger
+	Meta       MetaConfig
+	Namespaces []string // Watched namespaces; empty means cluster-wide.
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (r *VPAInventoryReporter) NeedLeaderElection() bool {
+	return false
+}
+
+// Start logs the inventory once; failures are logged and never stop the manager.
+func (r *VPAInventoryReporter) Start(ctx context.Context) error {
+	counts, err := r.countManagedVPAs(ctx)
+	if err != nil {
+		r.Logger.Error(err, "failed to build managed VPA inventory")
+		return nil
+	}
+
+	namespaces := make([]string, 0, len(counts))
+	total := 0
+	for ns, count := range counts {
+		namespaces = append(namespaces, ns)
+		total += count
+	}
+	slices.Sort(namespaces)
+
+	for _, ns := range namespaces {
+		r.Logger.Info("managed VPA inventory", "namespace", ns, "count", counts[ns])
+	}
+	r.Logger.Info("managed VPA inventory complete", "namespaces", len(namespaces), "total", total)
+
+	return nil
+}
+
+// countManagedVPAs returns the number of managed VPAs keyed by namespace.
+// Every watched namespace is present, even without VPAs; in cluster-wide mode
+// only namespaces containing managed VPAs are reported.
+func (r *VPAInventoryReporter) countManagedVPAs(ctx context.Context) (map[string]int, error) {
+	counts := map[string]int{}
+
+	if len(r.Namespaces) == 0 {
+		if err := r.countNamespace(ctx, "", counts); err != nil {
+			return nil, err
+		}
+		return counts, nil
+	}
+
+	for _, ns := range r.Namespaces {
+		counts[ns] = 0
+		if err := r.countNamespace(ctx, ns, counts); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// countNamespace adds the managed VPAs in namespace ("" for all) to counts.
+func (r *VPAInventoryReporter) countNamespace(ctx context.Context, namespace string, counts map[string]int) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(vpaListGVK)
+
+	if err := r.KubeClient.List(ctx, list, client.InNamespace(namespace), r.Meta.managedSelector()); err != nil {
+		if namespace == "" {
+			return fmt.Errorf("list managed VPAs: %w", err)
+		}
+		return fmt.Errorf("list managed VPAs in namespace %q: %w", namespace, err)
+	}
+
+	for i := range list.Items {
+		counts[list.Items[i].GetNamespace()]++
+	}
+	return nil
+}
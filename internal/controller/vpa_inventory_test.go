@@ -0,0 +1,125 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestVPAInventoryReporter_countManagedVPAs(t *testing.T) {
+	t.Parallel()
+
+	newReporter := func(kubeClient client.Client, namespaces ...string) *VPAInventoryReporter {
+		logger := logr.Discard()
+		return &VPAInventoryReporter{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Meta:       MetaConfig{ProfileKey: profileKey, ManagedLabel: managedLabelKey},
+			Namespaces: namespaces,
+		}
+	}
+
+	unmanaged := newVPAObject()
+	unmanaged.SetNamespace("ns1")
+	unmanaged.SetName("foreign")
+
+	objs := []client.Object{
+		newManagedVPA(t, "ns1", "a", "p1"),
+		newManagedVPA(t, "ns1", "b", "p1"),
+		newManagedVPA(t, "ns2", "c", "p2"),
+		newManagedVPA(t, "ns3", "d", "p1"),
+		unmanaged,
+	}
+
+	t.Run("Counts cluster-wide by namespace", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+
+		counts, err := newReporter(kubeClient).countManagedVPAs(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"ns1": 2, "ns2": 1, "ns3": 1}, counts)
+	})
+
+	t.Run("Only counts watched namespaces", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(objs...).Build()
+
+		counts, err := newReporter(kubeClient, "ns1", "empty").countManagedVPAs(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"ns1": 2, "empty": 0}, counts)
+	})
+
+	t.Run("Returns list errors", func(t *testing.T) {
+		t.Parallel()
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return errors.New("boom")
+			},
+		}).Build()
+
+		_, err := newReporter(kubeClient, "ns1").countManagedVPAs(context.Background())
+		require.Error(t, err)
+		assert.EqualError(t, err, `list managed VPAs in namespace "ns1": boom`)
+	})
+}
+
+func TestVPAInventoryReporter_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Runs once without leader election", func(t *testing.T) {
+		t.Parallel()
+
+		logger := logr.Discard()
+		reporter := &VPAInventoryReporter{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).Build(),
+			Logger:     &logger,
+			Meta:       MetaConfig{ProfileKey: profileKey, ManagedLabel: managedLabelKey},
+		}
+
+		assert.False(t, reporter.NeedLeaderElection())
+		assert.NoError(t, reporter.Start(context.Background()))
+	})
+
+	t.Run("Swallows list errors", func(t *testing.T) {
+		t.Parallel()
+
+		logger := logr.Discard()
+		reporter := &VPAInventoryReporter{
+			KubeClient: fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+				List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+					return errors.New("boom")
+				},
+			}).Build(),
+			Logger: &logger,
+		}
+
+		assert.NoError(t, reporter.Start(context.Background()))
+	})
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VPAStartupPruner checks every managed VPA once after startup with the
+// VPAReconciler's ownership rules, so orphans left behind while the operator
+// was down are deleted in one sweep instead of whenever an event fires.
+//
+// It runs on the leader only and exits after the sweep.
+type VPAStartupPruner struct {
+	Reconciler *VPAReconciler // Reconciler whose checks are applied to each VPA.
+	Namespaces []string       // Watched namespaces; empty means cluster-wide.
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (p *VPAStartupPruner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the sweep once; failures are logged and never stop the manager.
+func (p *VPAStartupPruner) Start(ctx context.Context) error {
+	checked, err := p.sweep(ctx)
+	if err != nil {
+		p.Reconciler.Logger.Error(err, "startup prune of managed VPAs incomplete", "checked", checked)
+		return nil
+	}
+	p.Reconciler.Logger.Info("startup prune of managed VPAs complete", "checked", checked)
+	return nil
+}
+
+// sweep runs VPAReconciler.Reconcile for every managed VPA in the watched
+// namespaces and returns how many were checked. A failing namespace or VPA
+// does not stop the sweep; all errors are joined.
+func (p *VPAStartupPruner) sweep(ctx context.Context) (int, error) {
+	namespaces := p.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	checked := 0
+	var errs []error
+	for _, ns := range namespaces {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(vpaListGVK)
+		if err := p.Reconciler.KubeClient.List(ctx, list, client.InNamespace(ns), p.Reconciler.Meta.managedSelector()); err != nil {
+			if ns == "" {
+				errs = append(errs, fmt.Errorf("list managed VPAs: %w", err))
+			} else {
+				errs = append(errs, fmt.Errorf("list managed VPAs in namespace %q: %w", ns, err))
+			}
+			continue
+		}
+
+		for i := range list.Items {
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])}
+			if _, err := p.Reconciler.Reconcile(ctx, req); err != nil {
+				errs = append(errs, fmt.Errorf("check VPA %s: %w", req.NamespacedName, err))
+			}
+			checked++
+		}
+	}
+	return checked, errors.Join(errs...)
+}
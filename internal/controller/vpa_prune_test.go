@@ -0,0 +1,125 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestVPAStartupPruner_sweep(t *testing.T) {
+	t.Parallel()
+
+	// newObjects returns, per namespace, an orphaned VPA, a VPA whose owner is
+	// gone, a valid VPA with its owner and an unmanaged orphan.
+	newObjects := func(t *testing.T, namespaces ...string) []client.Object {
+		t.Helper()
+		var objs []client.Object
+		for _, ns := range namespaces {
+			orphan := newManagedVPA(t, ns, "orphan-vpa", "p1")
+
+			ownerGone := newManagedVPA(t, ns, "gone-vpa", "p1")
+			ownerGone.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef("gone")})
+
+			valid := newManagedVPA(t, ns, "valid-vpa", "p1")
+			valid.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef("app")})
+
+			unmanaged := newVPAObject()
+			unmanaged.SetNamespace(ns)
+			unmanaged.SetName("manual-vpa")
+
+			objs = append(objs, orphan, ownerGone, valid, unmanaged, newOwnerUnstructuredDeployment(t, ns, "app"))
+		}
+		return objs
+	}
+
+	exists := func(t *testing.T, c client.Client, ns, name string) bool {
+		t.Helper()
+		err := c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: name}, newVPAObject())
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	t.Run("Deletes invalid managed VPAs cluster-wide", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t, newObjects(t, "ns1", "ns2")...)
+		pruner := &VPAStartupPruner{Reconciler: r}
+
+		checked, err := pruner.sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 6, checked)
+
+		for _, ns := range []string{"ns1", "ns2"} {
+			assert.False(t, exists(t, r.KubeClient, ns, "orphan-vpa"))
+			assert.False(t, exists(t, r.KubeClient, ns, "gone-vpa"))
+			assert.True(t, exists(t, r.KubeClient, ns, "valid-vpa"))
+			assert.True(t, exists(t, r.KubeClient, ns, "manual-vpa"))
+		}
+	})
+
+	t.Run("Only checks watched namespaces", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t, newObjects(t, "ns1", "ns2")...)
+		pruner := &VPAStartupPruner{Reconciler: r, Namespaces: []string{"ns1"}}
+
+		checked, err := pruner.sweep(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 3, checked)
+
+		assert.False(t, exists(t, r.KubeClient, "ns1", "orphan-vpa"))
+		assert.True(t, exists(t, r.KubeClient, "ns2", "orphan-vpa"))
+		assert.True(t, exists(t, r.KubeClient, "ns2", "gone-vpa"))
+	})
+
+	t.Run("Continues after a failing namespace", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t, newObjects(t, "ns1", "ns2")...)
+		r.KubeClient = interceptor.NewClient(r.KubeClient.(client.WithWatch), interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if (&client.ListOptions{}).ApplyOptions(opts).Namespace == "ns1" {
+					return errors.New("boom")
+				}
+				return c.List(ctx, list, opts...)
+			},
+		})
+		pruner := &VPAStartupPruner{Reconciler: r, Namespaces: []string{"ns1", "ns2"}}
+
+		checked, err := pruner.sweep(context.Background())
+		require.EqualError(t, err, `list managed VPAs in namespace "ns1": boom`)
+		assert.Equal(t, 3, checked)
+		assert.True(t, exists(t, r.KubeClient, "ns1", "orphan-vpa"))
+		assert.False(t, exists(t, r.KubeClient, "ns2", "orphan-vpa"))
+
+		// Start logs the failure but never stops the manager.
+		require.NoError(t, pruner.Start(context.Background()))
+	})
+}
@@ -18,19 +18,26 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/containeroo/autovpa/internal/metrics"
 	"github.com/containeroo/autovpa/internal/predicates"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 // VPAReconciler enforces the *structural correctness* of managed VPAs.
@@ -39,8 +46,11 @@ import (
 // It does NOT manage desired state (spec, labels, naming).
 //
 // Responsibilities:
-//  1. Delete managed VPAs that have no valid controller ownerRef (orphans).
-//  2. Delete managed VPAs whose referenced owner object no longer exists.
+//  1. Delete managed VPAs that have no valid controller ownerRef (orphans)
+//     or more than one controller ownerRef.
+//  2. Delete managed VPAs whose targetRef kind differs from their controller
+//     owner's kind.
+//  3. Delete managed VPAs whose referenced owner object no longer exists.
 //
 // All desired-state reconciliation (create/update/snap-back) is handled
 // exclusively by workload reconcilers (DeploymentReconciler, etc.).
@@ -62,8 +72,32 @@ type VPAReconciler struct {
 
 	// Metrics holds the Metrics
 	Metrics *metrics.Registry
+
+	// AdditionalKinds lists extra workload kinds accepted as VPA owners.
+	AdditionalKinds []schema.GroupVersionKind
+
+	// MirrorRecommendations copies the VPA's target recommendation onto the
+	// owner workload's RecommendationAnnotation.
+	MirrorRecommendations bool
+
+	// Tracer records a span per reconcile; nil uses a no-op tracer.
+	Tracer trace.Tracer
+
+	// Locks serializes this reconciler with the workload reconcilers per
+	// owner workload; nil never serializes.
+	Locks *WorkloadLocks
+
+	// ownerFetchFailures counts consecutive owner-fetch failures per VPA.
+	ownerFetchMu       sync.Mutex
+	ownerFetchFailures map[types.NamespacedName]int
 }
 
+// Backoff bounds for requeues after transient owner-fetch failures.
+const (
+	ownerFetchBaseDelay = time.Second
+	ownerFetchMaxDelay  = 5 * time.Minute
+)
+
 // Kubernetes event reasons emitted by the VPAReconciler.
 const (
 	// vpaEventOrphaned is emitted when a managed VPA has no controller ownerRef.
@@ -71,23 +105,51 @@ const (
 
 	// vpaEventOwnerDeleted is emitted when the owner workload no longer exists.
 	vpaEventOwnerDeleted = "OwnerDeleted"
+
+	// vpaEventMultipleControllers is emitted when a managed VPA has more than
+	// one controller ownerRef.
+	vpaEventMultipleControllers = "MultipleControllers"
+
+	// vpaEventTargetRefMismatch is emitted when a managed VPA targets a
+	// different kind than its controller owner.
+	vpaEventTargetRefMismatch = "TargetRefMismatch"
 )
 
+// errMultipleControllers is returned by resolveOwnerGVK when a VPA carries
+// more than one controller ownerRef.
+var errMultipleControllers = errors.New("multiple controller ownerRefs")
+
 // Reconcile validates a managed VPA’s ownership and deletes invalid VPAs.
 //
 // A VPA is deleted when:
 //   - it carries the managed label, AND
 //   - it has no controller ownerRef, OR
-//   - its controller ownerRef points to a non-existent workload.
+//   - it has more than one controller ownerRef, OR
+//   - its spec.targetRef.kind differs from its controller ownerRef kind, OR
+//   - its controller ownerRef points to a non-existent workload, OR
+//   - its controller ownerRef UID differs from the workload's (recreated
+//     under the same name).
 //
 // The reconciler never creates or updates VPAs.
-// It only deletes invalid ones.
+// It only deletes invalid ones and, when finalizers are enabled, releases
+// ManagedFinalizer from VPAs being deleted.
 //
 // Errors are returned only for failed API operations;
 // “not found” conditions are treated as terminal and non-fatal.
 func (r *VPAReconciler) Reconcile(
 	ctx context.Context,
 	req ctrl.Request,
+) (ctrl.Result, error) {
+	ctx, span := startReconcileSpan(ctx, r.Tracer, "VPAReconciler.Reconcile", req.Namespace, vpaGVK.Kind)
+	result, err := r.reconcile(ctx, req)
+	endReconcileSpan(span, result, err)
+	return result, err
+}
+
+// reconcile implements Reconcile within its span.
+func (r *VPAReconciler) reconcile(
+	ctx context.Context,
+	req ctrl.Request,
 ) (ctrl.Result, error) {
 	log := r.Logger.WithValues(
 		"namespace", req.Namespace,
@@ -103,12 +165,22 @@ func (r *VPAReconciler) Reconcile(
 	}
 	if vpa == nil {
 		log.Info("managed VPA already deleted")
+		r.resetOwnerFetchFailures(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
+	setSpanProfile(ctx, profileFromLabels(vpa.GetLabels(), r.Meta.ProfileKey))
+
+	// Deleted VPA still carrying our finalizer → account for it and release it.
+	if !vpa.GetDeletionTimestamp().IsZero() && hasManagedFinalizer(vpa) {
+		r.resetOwnerFetchFailures(req.NamespacedName)
+		return ctrl.Result{}, r.finalizeVPA(ctx, log, vpa)
+	}
 
 	// Ignore unmanaged (user-owned) VPAs entirely.
 	if r.skipUnmanaged(vpa) {
+		// No delete event is admitted once the label is gone, so forget the backoff now.
 		log.Info("managed label removed; skipping VPA reconciliation")
+		r.resetOwnerFetchFailures(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
@@ -116,7 +188,27 @@ func (r *VPAReconciler) Reconcile(
 	vpaNamespace := vpa.GetNamespace()
 
 	// Validate controller ownerRef.
-	gvk, ownerName, found := r.resolveOwnerGVK(vpa)
+	gvk, ownerName, found, err := r.resolveOwnerGVK(vpa)
+	if errors.Is(err, errMultipleControllers) {
+		// Kubernetes allows a single controller; the VPA is malformed.
+		log.Info("managed VPA has multiple controller owners")
+
+		r.Recorder.Eventf(
+			vpa,
+			nil,
+			corev1.EventTypeWarning,
+			vpaEventMultipleControllers,
+			vpaActionDeleteVPA,
+			"%s/%s has more than one controller owner", vpaNamespace, vpaName,
+		)
+
+		r.resetOwnerFetchFailures(req.NamespacedName)
+		if _, err := r.deleteVPA(ctx, vpa, deleteReasonMultipleControllers, ""); err != nil {
+			r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "delete")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
 	if !found {
 		// Managed VPA without controller owner → orphan.
 		log.Info("orphaned managed VPA has no controller owner")
@@ -130,25 +222,80 @@ func (r *VPAReconciler) Reconcile(
 			"%s/%s has no controller owner", vpaNamespace, vpaName,
 		)
 
-		if err := r.deleteManagedVPA(ctx, vpa); err != nil {
+		r.resetOwnerFetchFailures(req.NamespacedName)
+		if _, err := r.deleteVPA(ctx, vpa, deleteReasonOrphaned, ""); err != nil {
 			r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "delete")
 			return ctrl.Result{}, err
 		}
+		return ctrl.Result{}, nil
+	}
+
+	// Do not delete a VPA while the owner's workload reconciler may be
+	// rendering or replacing it.
+	unlock := r.Locks.lock(gvk.Kind, types.NamespacedName{Namespace: vpaNamespace, Name: ownerName})
+	defer unlock()
+
+	// A targetRef pointing at another kind than the owner would scale the
+	// wrong workload (e.g. one recreated as a different kind under the same
+	// name); the workload reconciler renders a fresh VPA.
+	if targetKind, mismatch := targetRefKindMismatch(vpa, gvk.Kind); mismatch {
+		log.Info(
+			"managed VPA targets a different kind than its controller owner",
+			"ownerKind", gvk.Kind,
+			"ownerName", ownerName,
+			"targetKind", targetKind,
+		)
+
+		r.Recorder.Eventf(
+			vpa,
+			nil,
+			corev1.EventTypeWarning,
+			vpaEventTargetRefMismatch,
+			vpaActionDeleteVPA,
+			"%s/%s targets kind %s but is owned by %s %s", vpaNamespace, vpaName, targetKind, gvk.Kind, ownerName,
+		)
 
-		profile := profileFromLabels(vpa.GetLabels(), r.Meta.ProfileKey)
-		r.Metrics.IncVPADeletedOrphaned(vpaNamespace)
-		r.Metrics.DecVPAManaged(vpaNamespace, profile)
+		r.resetOwnerFetchFailures(req.NamespacedName)
+		if _, err := r.deleteVPA(ctx, vpa, deleteReasonTargetRefMismatch, gvk.Kind); err != nil {
+			r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "delete")
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
 	// Verify that the referenced owner object still exists.
 	owner, err := r.fetchOwner(ctx, gvk, vpaNamespace, ownerName)
-	if err != nil {
-		if !apierrors.IsNotFound(err) {
-			// Transient API error → retry.
-			r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "fetch_owner")
-			return ctrl.Result{}, err
-		}
+	if err != nil && !apierrors.IsNotFound(err) {
+		// Transient API error → retry with a capped exponential backoff.
+		r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "fetch_owner")
+
+		failures := r.recordOwnerFetchFailure(req.NamespacedName)
+		delay := ownerFetchBackoff(failures)
+		log.Error(
+			err, "failed to fetch VPA owner; requeuing",
+			"ownerKind", gvk.Kind,
+			"ownerName", ownerName,
+			"failures", failures,
+			"requeueAfter", delay,
+		)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	// Same name but a different UID → the workload was recreated and the
+	// VPA's real owner is gone.
+	if err == nil && controllerUIDMismatch(vpa, owner) {
+		log.Info(
+			"controller owner was recreated with a new UID",
+			"ownerKind", gvk.Kind,
+			"ownerName", ownerName,
+			"ownerUID", owner.GetUID(),
+		)
+		r.Metrics.IncVPAOwnerUIDMismatch(vpaNamespace, gvk.Kind)
+		owner = nil
+	}
+
+	if owner == nil {
+		r.resetOwnerFetchFailures(req.NamespacedName)
 
 		// Owner object is gone → delete managed VPA.
 		log.Info(
@@ -166,17 +313,15 @@ func (r *VPAReconciler) Reconcile(
 			"owner %s %s/%s gone; deleting VPA %s", gvk.Kind, vpaNamespace, ownerName, vpaName,
 		)
 
-		if err := r.deleteManagedVPA(ctx, vpa); err != nil {
+		if _, err := r.deleteVPA(ctx, vpa, deleteReasonOwnerGone, gvk.Kind); err != nil {
 			r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "delete")
 			return ctrl.Result{}, err
 		}
-
-		profile := profileFromLabels(vpa.GetLabels(), r.Meta.ProfileKey)
-		r.Metrics.IncVPADeletedOwnerGone(vpaNamespace, gvk.Kind)
-		r.Metrics.DecVPAManaged(vpaNamespace, profile)
 		return ctrl.Result{}, nil
 	}
 
+	r.resetOwnerFetchFailures(req.NamespacedName)
+
 	// Happy path: managed VPA with valid controller owner.
 	log.Info(
 		"managed VPA has valid controller owner",
@@ -184,6 +329,13 @@ func (r *VPAReconciler) Reconcile(
 		"ownerName", owner.GetName(),
 	)
 
+	if r.MirrorRecommendations {
+		if err := r.mirrorRecommendation(ctx, log, vpa, owner); err != nil {
+			r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "mirror_recommendation")
+			return ctrl.Result{}, err
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -191,54 +343,98 @@ func (r *VPAReconciler) Reconcile(
 //
 // The reconciler watches only VPAs and uses a structural predicate to ensure
 // it is triggered exclusively by meaningful lifecycle or ownership changes.
+// With MirrorRecommendations, recommendation changes are let through as well.
+// Admitted and filtered events are counted in autovpa_predicate_events_total.
 func (r *VPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	vpa := newVPAObject()
 
+	// Filter to structural transitions only.
+	filter := predicates.ManagedVPAStructuralLifecycle(r.Meta.ManagedLabel, r.Meta.managedMatchValue())
+	if r.MirrorRecommendations {
+		filter = predicate.Or(filter, predicates.ManagedVPARecommendationChanged(r.Meta.ManagedLabel, r.Meta.managedMatchValue()))
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		// Primary resource: VPAs.
 		For(vpa).
-		// Filter to structural transitions only.
-		WithEventFilter(
-			predicates.ManagedVPAStructuralLifecycle(r.Meta.ManagedLabel),
-		).
+		WithEventFilter(countedPredicate(r.Metrics, filter, "verticalpodautoscaler", vpaResource)).
 		Complete(r)
 }
 
 // resolveOwnerGVK extracts the controller ownerRef from a VPA and returns
 // its GroupVersionKind and name.
 //
-// Only controller ownerRefs for supported workload types are considered.
+// Only controller ownerRefs for supported workload types (built-in kinds and
+// AdditionalKinds) are considered.
 // If no valid controller ownerRef is found, found=false is returned.
+// More than one controller ownerRef, whatever its kind, is invalid and
+// returns errMultipleControllers.
 func (r *VPAReconciler) resolveOwnerGVK(
 	vpa *unstructured.Unstructured,
-) (gvk schema.GroupVersionKind, ownerName string, found bool) {
+) (gvk schema.GroupVersionKind, ownerName string, found bool, err error) {
+	var controllers []metav1.OwnerReference
 	for _, owner := range vpa.GetOwnerReferences() {
-		if owner.Controller == nil || !*owner.Controller {
-			continue
+		if owner.Controller != nil && *owner.Controller {
+			controllers = append(controllers, owner)
 		}
+	}
+	if len(controllers) > 1 {
+		return schema.GroupVersionKind{}, "", false, errMultipleControllers
+	}
 
+	for _, owner := range controllers {
 		switch owner.Kind {
 		case DeploymentGVK.Kind:
-			return DeploymentGVK, owner.Name, true
+			return DeploymentGVK, owner.Name, true, nil
 		case StatefulSetGVK.Kind:
-			return StatefulSetGVK, owner.Name, true
+			return StatefulSetGVK, owner.Name, true, nil
 		case DaemonSetGVK.Kind:
-			return DaemonSetGVK, owner.Name, true
+			return DaemonSetGVK, owner.Name, true, nil
+		case ReplicaSetGVK.Kind:
+			return ReplicaSetGVK, owner.Name, true, nil
+		}
+
+		// Additional kinds must match on group as well, since their kind
+		// names are not guaranteed to be unique.
+		ownerGV, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			continue
+		}
+		for _, gvk := range r.AdditionalKinds {
+			if gvk.Kind == owner.Kind && gvk.Group == ownerGV.Group {
+				return gvk, owner.Name, true, nil
+			}
 		}
 	}
 
-	return schema.GroupVersionKind{}, "", false
+	return schema.GroupVersionKind{}, "", false, nil
+}
+
+// targetRefKindMismatch returns the VPA's spec.targetRef.kind and reports
+// whether it is set and differs from ownerKind.
+func targetRefKindMismatch(vpa *unstructured.Unstructured, ownerKind string) (string, bool) {
+	kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+	return kind, kind != "" && kind != ownerKind
+}
+
+// controllerUIDMismatch reports whether the VPA's controller ownerRef carries
+// a UID that differs from the fetched owner's UID.
+func controllerUIDMismatch(vpa, owner client.Object) bool {
+	ref := metav1.GetControllerOf(vpa)
+	if ref == nil || ref.UID == "" || owner.GetUID() == "" {
+		return false
+	}
+	return ref.UID != owner.GetUID()
 }
 
 // skipUnmanaged returns true if the VPA does not carry the operator’s
-// managed label with value "true".
+// managed label (see MetaConfig.isManaged).
 //
 // Such VPAs are treated as user-managed and ignored entirely.
 func (r *VPAReconciler) skipUnmanaged(
 	vpa *unstructured.Unstructured,
 ) bool {
-	labels := vpa.GetLabels()
-	return labels[r.Meta.ManagedLabel] != "true"
+	return !r.Meta.isManaged(vpa.GetLabels())
 }
 
 // fetchOwner retrieves the controller owner object for a VPA.
@@ -263,6 +459,40 @@ func (r *VPAReconciler) fetchOwner(
 	return owner, nil
 }
 
+// recordOwnerFetchFailure increments and returns the consecutive owner-fetch
+// failure count for the VPA.
+func (r *VPAReconciler) recordOwnerFetchFailure(key types.NamespacedName) int {
+	r.ownerFetchMu.Lock()
+	defer r.ownerFetchMu.Unlock()
+
+	if r.ownerFetchFailures == nil {
+		r.ownerFetchFailures = map[types.NamespacedName]int{}
+	}
+	r.ownerFetchFailures[key]++
+	return r.ownerFetchFailures[key]
+}
+
+// resetOwnerFetchFailures forgets the owner-fetch failures of the VPA.
+func (r *VPAReconciler) resetOwnerFetchFailures(key types.NamespacedName) {
+	r.ownerFetchMu.Lock()
+	defer r.ownerFetchMu.Unlock()
+
+	delete(r.ownerFetchFailures, key)
+}
+
+// ownerFetchBackoff returns the requeue delay after the given number of
+// consecutive failures: doubling from ownerFetchBaseDelay, capped at ownerFetchMaxDelay.
+func ownerFetchBackoff(failures int) time.Duration {
+	delay := ownerFetchBaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= ownerFetchMaxDelay {
+			return ownerFetchMaxDelay
+		}
+	}
+	return delay
+}
+
 // fetchExistingVPA loads a VPA by name/namespace.
 //
 // If the VPA does not exist, (nil, nil) is returned.
@@ -280,18 +510,29 @@ func (r *VPAReconciler) fetchExistingVPA(
 	return obj, nil
 }
 
-// deleteManagedVPA deletes the given VPA.
+// finalizeVPA decrements the managed gauge for a VPA that is being deleted
+// and removes ManagedFinalizer so the deletion can complete.
 //
-// NotFound errors are ignored to make deletion idempotent.
-func (r *VPAReconciler) deleteManagedVPA(
+// Removing the finalizer uses an optimistic lock so finalizers added
+// concurrently by others are never dropped.
+func (r *VPAReconciler) finalizeVPA(
 	ctx context.Context,
-	vpa client.Object,
+	log logr.Logger,
+	vpa *unstructured.Unstructured,
 ) error {
-	if err := r.KubeClient.Delete(ctx, vpa); err != nil {
+	patchBase := client.MergeFromWithOptions(vpa.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(vpa, ManagedFinalizer)
+	if err := r.KubeClient.Patch(ctx, vpa, patchBase); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil
 		}
+		r.Metrics.IncReconcileErrors("vpa", vpaGVK.Kind, "remove_finalizer")
 		return err
 	}
+
+	if !r.skipUnmanaged(vpa) {
+		r.Metrics.DecVPAManaged(vpa.GetNamespace(), profileFromLabels(vpa.GetLabels(), r.Meta.ProfileKey))
+	}
+	log.Info("removed finalizer from deleted VPA")
 	return nil
 }
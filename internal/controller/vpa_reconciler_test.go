@@ -19,8 +19,10 @@ package controller
 import (
 	"context"
 	"testing"
+	"time"
 
 	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/containeroo/autovpa/test/testutils"
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -30,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
@@ -175,6 +178,43 @@ func TestVPAReconciler_Reconcile(t *testing.T) {
 		assert.True(t, apierrors.IsNotFound(err))
 	})
 
+	t.Run("Deletes managed VPA with multiple controller ownerRefs", func(t *testing.T) {
+		t.Parallel()
+
+		// Both owners exist, so only the duplicate controller makes the VPA invalid.
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		other := newOwnerUnstructuredDeployment(t, namespace, "other")
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{
+			deploymentOwnerRef(ownerName),
+			deploymentOwnerRef("other"),
+		})
+
+		r := newTestVPAReconciler(t, owner, other, vpa)
+		rec := events.NewFakeRecorder(1)
+		r.Recorder = rec
+		promReg := prometheus.NewRegistry()
+		r.Metrics = internalmetrics.NewRegistry(promReg)
+
+		_, err := r.Reconcile(
+			context.Background(),
+			ctrl.Request{NamespacedName: types.NamespacedName{Name: vpaName, Namespace: namespace}},
+		)
+		require.NoError(t, err)
+
+		err = r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+
+		require.Len(t, rec.Events, 1)
+		assert.Contains(t, <-rec.Events, "Warning MultipleControllers")
+
+		got := mustGetCounterValue(t, promReg, "autovpa_vpa_deleted_multiple_controllers_total", map[string]string{
+			"namespace": namespace,
+		})
+		assert.Equal(t, float64(1), got)
+	})
+
 	t.Run("Keeps managed VPA when controller owner exists and is valid", func(t *testing.T) {
 		t.Parallel()
 
@@ -195,6 +235,341 @@ func TestVPAReconciler_Reconcile(t *testing.T) {
 		err = r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), got)
 		require.NoError(t, err)
 	})
+
+	t.Run("Deletes managed VPA whose targetRef kind differs from its owner", func(t *testing.T) {
+		t.Parallel()
+
+		// The Deployment exists, but the VPA still targets the StatefulSet
+		// that previously carried the name.
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef(ownerName)})
+		require.NoError(t, unstructured.SetNestedStringMap(vpa.Object, map[string]string{
+			"apiVersion": StatefulSetGVK.GroupVersion().String(),
+			"kind":       StatefulSetGVK.Kind,
+			"name":       ownerName,
+		}, "spec", "targetRef"))
+
+		r := newTestVPAReconciler(t, owner, vpa)
+		rec := events.NewFakeRecorder(1)
+		r.Recorder = rec
+		promReg := prometheus.NewRegistry()
+		r.Metrics = internalmetrics.NewRegistry(promReg)
+
+		_, err := r.Reconcile(
+			context.Background(),
+			ctrl.Request{NamespacedName: types.NamespacedName{Name: vpaName, Namespace: namespace}},
+		)
+		require.NoError(t, err)
+
+		err = r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+
+		require.Len(t, rec.Events, 1)
+		assert.Contains(t, <-rec.Events, "Warning TargetRefMismatch")
+
+		got := mustGetCounterValue(t, promReg, "autovpa_vpa_deleted_targetref_mismatch_total", map[string]string{
+			"namespace": namespace,
+			"kind":      DeploymentGVK.Kind,
+		})
+		assert.Equal(t, float64(1), got)
+	})
+
+	t.Run("Keeps managed VPA whose targetRef kind matches its owner", func(t *testing.T) {
+		t.Parallel()
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef(ownerName)})
+		require.NoError(t, unstructured.SetNestedStringMap(vpa.Object, map[string]string{
+			"apiVersion": DeploymentGVK.GroupVersion().String(),
+			"kind":       DeploymentGVK.Kind,
+			"name":       ownerName,
+		}, "spec", "targetRef"))
+
+		r := newTestVPAReconciler(t, owner, vpa)
+
+		_, err := r.Reconcile(
+			context.Background(),
+			ctrl.Request{NamespacedName: types.NamespacedName{Name: vpaName, Namespace: namespace}},
+		)
+		require.NoError(t, err)
+
+		err = r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), newVPAObject())
+		require.NoError(t, err)
+	})
+
+	t.Run("Deletes managed VPA when owner was recreated with a new UID", func(t *testing.T) {
+		t.Parallel()
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		owner.SetUID("uid-new")
+
+		ref := deploymentOwnerRef(ownerName)
+		ref.UID = "uid-old"
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{ref})
+
+		r := newTestVPAReconciler(t, owner, vpa)
+
+		_, err := r.Reconcile(
+			context.Background(),
+			ctrl.Request{NamespacedName: types.NamespacedName{Name: vpaName, Namespace: namespace}},
+		)
+		require.NoError(t, err)
+
+		got := newVPAObject()
+		err = r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), got)
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("Keeps managed VPA when owner UID matches", func(t *testing.T) {
+		t.Parallel()
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		owner.SetUID("uid1")
+
+		ref := deploymentOwnerRef(ownerName)
+		ref.UID = "uid1"
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{ref})
+
+		r := newTestVPAReconciler(t, owner, vpa)
+
+		_, err := r.Reconcile(
+			context.Background(),
+			ctrl.Request{NamespacedName: types.NamespacedName{Name: vpaName, Namespace: namespace}},
+		)
+		require.NoError(t, err)
+
+		got := newVPAObject()
+		require.NoError(t, r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), got))
+	})
+
+	t.Run("Requeues with capped backoff when owner fetch fails", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef(ownerName)})
+
+		r := newTestVPAReconciler(t, vpa)
+		r.KubeClient = &testutils.MockClientWithError{
+			Client:      r.KubeClient,
+			GetErrorFor: testutils.NamedError{Name: ownerName, Namespace: namespace},
+		}
+		promReg := prometheus.NewRegistry()
+		r.Metrics = internalmetrics.NewRegistry(promReg)
+
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: vpaName, Namespace: namespace}}
+
+		res, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: time.Second}, res)
+
+		res, err = r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: 2 * time.Second}, res)
+
+		got := mustGetCounterValue(t, promReg, "autovpa_reconcile_errors_total", map[string]string{
+			"controller": "vpa",
+			"kind":       vpaGVK.Kind,
+			"reason":     "fetch_owner",
+		})
+		assert.Equal(t, float64(2), got)
+
+		// VPA must be kept on transient errors.
+		require.NoError(t, r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), newVPAObject()))
+	})
+
+	t.Run("Resets backoff once the owner can be fetched", func(t *testing.T) {
+		t.Parallel()
+
+		owner := newOwnerUnstructuredDeployment(t, namespace, ownerName)
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef(ownerName)})
+
+		r := newTestVPAReconciler(t, owner, vpa)
+		key := types.NamespacedName{Name: vpaName, Namespace: namespace}
+		r.recordOwnerFetchFailure(key)
+		r.recordOwnerFetchFailure(key)
+
+		res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Equal(t, 1, r.recordOwnerFetchFailure(key))
+	})
+
+	t.Run("Forgets backoff once the VPA is deleted", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t)
+		key := types.NamespacedName{Name: vpaName, Namespace: namespace}
+		r.recordOwnerFetchFailure(key)
+		r.recordOwnerFetchFailure(key)
+
+		res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.NotContains(t, r.ownerFetchFailures, key)
+	})
+
+	t.Run("Forgets backoff once the managed label is removed", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetLabels(map[string]string{})
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef(ownerName)})
+
+		r := newTestVPAReconciler(t, vpa)
+		key := types.NamespacedName{Name: vpaName, Namespace: namespace}
+		r.recordOwnerFetchFailure(key)
+
+		res, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.NotContains(t, r.ownerFetchFailures, key)
+	})
+}
+
+func TestVPAReconciler_Reconcile_Finalizer(t *testing.T) {
+	t.Parallel()
+
+	const namespace = "default"
+	const vpaName = "demo-vpa"
+	key := types.NamespacedName{Name: vpaName, Namespace: namespace}
+
+	// withMetrics swaps in a registry the test can gather from and seeds the
+	// managed gauge as if the VPA had been created by the operator.
+	withMetrics := func(t *testing.T, r *VPAReconciler) *prometheus.Registry {
+		t.Helper()
+		promReg := prometheus.NewRegistry()
+		r.Metrics = internalmetrics.NewRegistry(promReg)
+		r.Metrics.IncVPAManaged(namespace, "default")
+		return promReg
+	}
+
+	t.Run("Removes finalizer from deleted VPA and decrements gauge", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetFinalizers([]string{ManagedFinalizer})
+		vpa.SetDeletionTimestamp(ptr.To(metav1.Now()))
+
+		r := newTestVPAReconciler(t, vpa)
+		promReg := withMetrics(t, r)
+
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		got := newVPAObject()
+		err = r.KubeClient.Get(ctx, key, got)
+		assert.True(t, apierrors.IsNotFound(err), "VPA should be gone once the finalizer is removed")
+		assert.Equal(t, float64(0), gaugeValue(t, promReg, "autovpa_managed_vpa", map[string]string{
+			"namespace": namespace,
+			"profile":   "default",
+		}))
+	})
+
+	t.Run("Keeps foreign finalizers", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetFinalizers([]string{ManagedFinalizer, "example.com/other"})
+		vpa.SetDeletionTimestamp(ptr.To(metav1.Now()))
+
+		r := newTestVPAReconciler(t, vpa)
+		withMetrics(t, r)
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+
+		got := newVPAObject()
+		require.NoError(t, r.KubeClient.Get(ctx, key, got))
+		assert.Equal(t, []string{"example.com/other"}, got.GetFinalizers())
+	})
+
+	t.Run("Orphan deletion defers gauge decrement to the finalizer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		vpa := newManagedVPA(t, namespace, vpaName, "default")
+		vpa.SetFinalizers([]string{ManagedFinalizer})
+
+		r := newTestVPAReconciler(t, vpa)
+		promReg := withMetrics(t, r)
+		labels := map[string]string{"namespace": namespace, "profile": "default"}
+
+		// First pass deletes the orphan; the finalizer keeps it around.
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+
+		got := newVPAObject()
+		require.NoError(t, r.KubeClient.Get(ctx, key, got))
+		assert.False(t, got.GetDeletionTimestamp().IsZero())
+		assert.Equal(t, float64(1), gaugeValue(t, promReg, "autovpa_managed_vpa", labels))
+
+		// Second pass (triggered by the deletion update) releases it exactly once.
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+
+		err = r.KubeClient.Get(ctx, key, newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.Equal(t, float64(0), gaugeValue(t, promReg, "autovpa_managed_vpa", labels))
+	})
+}
+
+func TestVPAReconciler_ownerFetchBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Doubles per failure", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, time.Second, ownerFetchBackoff(1))
+		assert.Equal(t, 2*time.Second, ownerFetchBackoff(2))
+		assert.Equal(t, 8*time.Second, ownerFetchBackoff(4))
+	})
+
+	t.Run("Caps at the maximum delay", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, ownerFetchMaxDelay, ownerFetchBackoff(10))
+		assert.Equal(t, ownerFetchMaxDelay, ownerFetchBackoff(1000))
+	})
+}
+
+func TestTargetRefKindMismatch(t *testing.T) {
+	t.Parallel()
+
+	withTarget := func(kind string) *unstructured.Unstructured {
+		vpa := newVPAObject()
+		if kind != "" {
+			_ = unstructured.SetNestedField(vpa.Object, kind, "spec", "targetRef", "kind")
+		}
+		return vpa
+	}
+
+	t.Run("Matching kind", func(t *testing.T) {
+		t.Parallel()
+		kind, mismatch := targetRefKindMismatch(withTarget("Deployment"), "Deployment")
+		assert.Equal(t, "Deployment", kind)
+		assert.False(t, mismatch)
+	})
+
+	t.Run("Different kind", func(t *testing.T) {
+		t.Parallel()
+		kind, mismatch := targetRefKindMismatch(withTarget("StatefulSet"), "Deployment")
+		assert.Equal(t, "StatefulSet", kind)
+		assert.True(t, mismatch)
+	})
+
+	t.Run("Missing targetRef", func(t *testing.T) {
+		t.Parallel()
+		_, mismatch := targetRefKindMismatch(withTarget(""), "Deployment")
+		assert.False(t, mismatch)
+	})
 }
 
 func TestVPAReconciler_skipUnmanaged(t *testing.T) {
@@ -227,6 +602,20 @@ func TestVPAReconciler_skipUnmanaged(t *testing.T) {
 
 		assert.True(t, r.skipUnmanaged(vpa))
 	})
+
+	t.Run("Matches any value when the value is templated", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t)
+		r.Meta.ManagedLabelValue = `{{ index .Labels "team" }}`
+
+		vpa := newVPAObject()
+		vpa.SetLabels(map[string]string{managedLabelKey: "payments"})
+		assert.False(t, r.skipUnmanaged(vpa))
+
+		vpa.SetLabels(map[string]string{"other": "payments"})
+		assert.True(t, r.skipUnmanaged(vpa))
+	})
 }
 
 func TestVPAReconciler_resolveOwnerGVK(t *testing.T) {
@@ -242,13 +631,37 @@ func TestVPAReconciler_resolveOwnerGVK(t *testing.T) {
 			deploymentOwnerRef("demo"),
 		})
 
-		gvk, name, found := r.resolveOwnerGVK(vpa)
+		gvk, name, found, err := r.resolveOwnerGVK(vpa)
+		require.NoError(t, err)
 
 		assert.True(t, found)
 		assert.Equal(t, DeploymentGVK.Kind, gvk.Kind)
 		assert.Equal(t, "demo", name)
 	})
 
+	t.Run("Returns matching controller owner ref for ReplicaSet", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t)
+
+		vpa := newManagedVPA(t, "ns", "vpa", "p")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{
+			{
+				APIVersion: ReplicaSetGVK.GroupVersion().String(),
+				Kind:       ReplicaSetGVK.Kind,
+				Name:       "legacy",
+				Controller: ptr.To(true),
+			},
+		})
+
+		gvk, name, found, err := r.resolveOwnerGVK(vpa)
+		require.NoError(t, err)
+
+		assert.True(t, found)
+		assert.Equal(t, ReplicaSetGVK, gvk)
+		assert.Equal(t, "legacy", name)
+	})
+
 	t.Run("Returns not found when no controller ownerRef exists", func(t *testing.T) {
 		t.Parallel()
 
@@ -264,7 +677,8 @@ func TestVPAReconciler_resolveOwnerGVK(t *testing.T) {
 			},
 		})
 
-		gvk, name, found := r.resolveOwnerGVK(vpa)
+		gvk, name, found, err := r.resolveOwnerGVK(vpa)
+		require.NoError(t, err)
 
 		assert.False(t, found)
 		assert.Empty(t, name)
@@ -286,41 +700,87 @@ func TestVPAReconciler_resolveOwnerGVK(t *testing.T) {
 			},
 		})
 
-		gvk, name, found := r.resolveOwnerGVK(vpa)
+		gvk, name, found, err := r.resolveOwnerGVK(vpa)
+		require.NoError(t, err)
 
 		assert.False(t, found)
 		assert.Empty(t, name)
 		assert.Empty(t, gvk.Kind)
 	})
+	t.Run("Returns error for multiple controller ownerRefs", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestVPAReconciler(t)
+
+		vpa := newManagedVPA(t, "ns", "vpa", "p")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{
+			deploymentOwnerRef("demo"),
+			{
+				APIVersion: "batch/v1",
+				Kind:       "Job",
+				Name:       "job",
+				Controller: ptr.To(true),
+			},
+		})
+
+		gvk, name, found, err := r.resolveOwnerGVK(vpa)
+
+		require.ErrorIs(t, err, errMultipleControllers)
+		assert.False(t, found)
+		assert.Empty(t, name)
+		assert.Empty(t, gvk.Kind)
+	})
 }
 
-func TestVPAReconciler_deleteManagedVPA(t *testing.T) {
+func TestVPAReconciler_resolveOwnerGVK_AdditionalKinds(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Deletes existing VPA", func(t *testing.T) {
+	rolloutGVK := schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
+	t.Run("Returns configured additional kind", func(t *testing.T) {
 		t.Parallel()
 
+		r := newTestVPAReconciler(t)
+		r.AdditionalKinds = []schema.GroupVersionKind{rolloutGVK}
+
 		vpa := newManagedVPA(t, "ns", "vpa", "p")
-		r := newTestVPAReconciler(t, vpa)
+		vpa.SetOwnerReferences([]metav1.OwnerReference{
+			{
+				APIVersion: rolloutGVK.GroupVersion().String(),
+				Kind:       rolloutGVK.Kind,
+				Name:       "demo",
+				Controller: ptr.To(true),
+			},
+		})
 
-		err := r.deleteManagedVPA(context.Background(), vpa)
+		gvk, name, found, err := r.resolveOwnerGVK(vpa)
 		require.NoError(t, err)
 
-		got := newVPAObject()
-		err = r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), got)
-		assert.True(t, apierrors.IsNotFound(err))
+		assert.True(t, found)
+		assert.Equal(t, rolloutGVK, gvk)
+		assert.Equal(t, "demo", name)
 	})
 
-	t.Run("Ignores NotFound error", func(t *testing.T) {
+	t.Run("Ignores additional kind from another group", func(t *testing.T) {
 		t.Parallel()
 
 		r := newTestVPAReconciler(t)
+		r.AdditionalKinds = []schema.GroupVersionKind{rolloutGVK}
 
-		vpa := newManagedVPA(t, "ns", "missing", "p")
-		// Not created in client.
+		vpa := newManagedVPA(t, "ns", "vpa", "p")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{
+			{
+				APIVersion: "example.com/v1",
+				Kind:       rolloutGVK.Kind,
+				Name:       "demo",
+				Controller: ptr.To(true),
+			},
+		})
 
-		err := r.deleteManagedVPA(context.Background(), vpa)
+		_, _, found, err := r.resolveOwnerGVK(vpa)
 		require.NoError(t, err)
+
+		assert.False(t, found)
 	})
 }
 
@@ -375,6 +835,7 @@ func newTestVPAReconciler(t *testing.T, objs ...client.Object) *VPAReconciler {
 	scheme.AddKnownTypeWithName(DeploymentGVK, &unstructured.Unstructured{})
 	scheme.AddKnownTypeWithName(StatefulSetGVK, &unstructured.Unstructured{})
 	scheme.AddKnownTypeWithName(DaemonSetGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(ReplicaSetGVK, &unstructured.Unstructured{})
 
 	c := fake.NewClientBuilder().
 		WithScheme(scheme).
@@ -429,3 +890,25 @@ func newOwnerUnstructuredDeployment(t *testing.T, namespace, name string) *unstr
 	u.SetName(name)
 	return u
 }
+
+func gaugeValue(t *testing.T, g prometheus.Gatherer, metricName string, wantLabels map[string]string) float64 {
+	t.Helper()
+
+	mfs, err := g.Gather()
+	require.NoError(t, err)
+
+	for _, mf := range mfs {
+		if mf.GetName() != metricName {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if labelsMatch(m.GetLabel(), wantLabels) {
+				require.NotNil(t, m.GetGauge())
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+
+	t.Fatalf("metric %q with labels %#v not found in registry", metricName, wantLabels)
+	return 0
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requestsWithinBounds reports whether every container of the workload's pod
+// template requests resources within the minAllowed/maxAllowed bounds of the
+// rendered VPA spec. A container is matched by its own container policy or
+// the "*" policy; containers whose policy sets mode Off are ignored. A bounded
+// resource the container does not request counts as out of bounds. Specs
+// without any bound applying to a container are never within bounds, so the
+// check cannot silently skip every VPA.
+func requestsWithinBounds(obj client.Object, spec map[string]any) (bool, error) {
+	var typed vpaautoscaling.VerticalPodAutoscalerSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &typed); err != nil {
+		return false, fmt.Errorf("convert VPA spec: %w", err)
+	}
+	containers, err := workloadContainers(obj)
+	if err != nil {
+		return false, err
+	}
+
+	checked := false
+	for _, container := range containers {
+		policy := containerPolicyFor(typed.ResourcePolicy, container.Name)
+		if policy == nil {
+			continue
+		}
+		if policy.Mode != nil && *policy.Mode == vpaautoscaling.ContainerScalingModeOff {
+			continue
+		}
+		for name, lo := range policy.MinAllowed {
+			checked = true
+			if q, ok := container.Resources.Requests[name]; !ok || q.Cmp(lo) < 0 {
+				return false, nil
+			}
+		}
+		for name, hi := range policy.MaxAllowed {
+			checked = true
+			if q, ok := container.Resources.Requests[name]; !ok || q.Cmp(hi) > 0 {
+				return false, nil
+			}
+		}
+	}
+	return checked, nil
+}
+
+// containerPolicyFor returns the container policy named container, falling
+// back to the "*" policy, or nil when neither exists.
+func containerPolicyFor(policy *vpaautoscaling.PodResourcePolicy, container string) *vpaautoscaling.ContainerResourcePolicy {
+	if policy == nil {
+		return nil
+	}
+	var wildcard *vpaautoscaling.ContainerResourcePolicy
+	for i := range policy.ContainerPolicies {
+		p := &policy.ContainerPolicies[i]
+		switch p.ContainerName {
+		case container:
+			return p
+		case vpaautoscaling.DefaultContainerResourcePolicy:
+			wildcard = p
+		}
+	}
+	return wildcard
+}
+
+// workloadContainers returns the containers of the workload's pod template.
+// Unstructured workloads are read from spec.template.spec.containers.
+func workloadContainers(obj client.Object) ([]corev1.Container, error) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return o.Spec.Template.Spec.Containers, nil
+	case *appsv1.StatefulSet:
+		return o.Spec.Template.Spec.Containers, nil
+	case *appsv1.DaemonSet:
+		return o.Spec.Template.Spec.Containers, nil
+	case *appsv1.ReplicaSet:
+		return o.Spec.Template.Spec.Containers, nil
+	case *unstructured.Unstructured:
+		raw, _, err := unstructured.NestedSlice(o.Object, "spec", "template", "spec", "containers")
+		if err != nil {
+			return nil, fmt.Errorf("read containers: %w", err)
+		}
+		var podSpec corev1.PodSpec
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]any{"containers": raw}, &podSpec); err != nil {
+			return nil, fmt.Errorf("convert containers: %w", err)
+		}
+		return podSpec.Containers, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// boundsSpec renders a VPA spec with the given container policies.
+func boundsSpec(t *testing.T, policies ...vpaautoscaling.ContainerResourcePolicy) map[string]any {
+	t.Helper()
+	spec := vpaautoscaling.VerticalPodAutoscalerSpec{}
+	if len(policies) > 0 {
+		spec.ResourcePolicy = &vpaautoscaling.PodResourcePolicy{ContainerPolicies: policies}
+	}
+	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spec)
+	require.NoError(t, err)
+	return out
+}
+
+// deploymentWithRequests returns a Deployment whose containers request the given resources.
+func deploymentWithRequests(requests map[string]corev1.ResourceList) *appsv1.Deployment {
+	dep := &appsv1.Deployment{}
+	dep.SetNamespace("ns1")
+	dep.SetName("demo")
+	dep.SetUID("uid1")
+	dep.SetAnnotations(map[string]string{"vpa/profile": "p1"})
+	for _, name := range []string{"app", "sidecar"} {
+		list, ok := requests[name]
+		if !ok {
+			continue
+		}
+		dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers, corev1.Container{
+			Name:      name,
+			Resources: corev1.ResourceRequirements{Requests: list},
+		})
+	}
+	return dep
+}
+
+func TestRequestsWithinBounds(t *testing.T) {
+	t.Parallel()
+
+	wildcard := vpaautoscaling.ContainerResourcePolicy{
+		ContainerName: vpaautoscaling.DefaultContainerResourcePolicy,
+		MinAllowed:    resources("50m", "64Mi"),
+		MaxAllowed:    resources("500m", "512Mi"),
+	}
+
+	t.Run("Within wildcard bounds", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{
+			"app":     resources("100m", "128Mi"),
+			"sidecar": resources("50m", "512Mi"),
+		})
+		within, err := requestsWithinBounds(dep, boundsSpec(t, wildcard))
+		require.NoError(t, err)
+		assert.True(t, within)
+	})
+
+	t.Run("Below minAllowed", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{"app": resources("10m", "128Mi")})
+		within, err := requestsWithinBounds(dep, boundsSpec(t, wildcard))
+		require.NoError(t, err)
+		assert.False(t, within)
+	})
+
+	t.Run("Above maxAllowed", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{"app": resources("100m", "1Gi")})
+		within, err := requestsWithinBounds(dep, boundsSpec(t, wildcard))
+		require.NoError(t, err)
+		assert.False(t, within)
+	})
+
+	t.Run("Missing request for a bounded resource", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{"app": resources("100m", "")})
+		within, err := requestsWithinBounds(dep, boundsSpec(t, wildcard))
+		require.NoError(t, err)
+		assert.False(t, within)
+	})
+
+	t.Run("Named policy overrides wildcard", func(t *testing.T) {
+		t.Parallel()
+
+		named := vpaautoscaling.ContainerResourcePolicy{
+			ContainerName: "sidecar",
+			MaxAllowed:    resources("20m", ""),
+		}
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{
+			"app":     resources("100m", "128Mi"),
+			"sidecar": resources("10m", ""),
+		})
+		within, err := requestsWithinBounds(dep, boundsSpec(t, wildcard, named))
+		require.NoError(t, err)
+		assert.True(t, within)
+	})
+
+	t.Run("Ignores containers with mode Off", func(t *testing.T) {
+		t.Parallel()
+
+		off := vpaautoscaling.ContainerScalingModeOff
+		named := vpaautoscaling.ContainerResourcePolicy{ContainerName: "sidecar", Mode: &off}
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{
+			"app":     resources("100m", "128Mi"),
+			"sidecar": resources("4", "8Gi"),
+		})
+		within, err := requestsWithinBounds(dep, boundsSpec(t, wildcard, named))
+		require.NoError(t, err)
+		assert.True(t, within)
+	})
+
+	t.Run("Never within without bounds", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{"app": resources("100m", "128Mi")})
+		within, err := requestsWithinBounds(dep, boundsSpec(t))
+		require.NoError(t, err)
+		assert.False(t, within)
+
+		within, err = requestsWithinBounds(dep, boundsSpec(t, vpaautoscaling.ContainerResourcePolicy{ContainerName: "other", MinAllowed: resources("1m", "")}))
+		require.NoError(t, err)
+		assert.False(t, within)
+	})
+
+	t.Run("Reads unstructured workloads", func(t *testing.T) {
+		t.Parallel()
+
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{
+					"name":      "app",
+					"resources": map[string]any{"requests": map[string]any{"cpu": "100m", "memory": "128Mi"}},
+				}},
+			}}},
+		}}
+		within, err := requestsWithinBounds(obj, boundsSpec(t, wildcard))
+		require.NoError(t, err)
+		assert.True(t, within)
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_SkipIfWithinBounds(t *testing.T) {
+	t.Parallel()
+
+	profile := config.Profile{Spec: config.ProfileSpec{
+		ResourcePolicy: &vpaautoscaling.PodResourcePolicy{
+			ContainerPolicies: []vpaautoscaling.ContainerResourcePolicy{{
+				ContainerName: vpaautoscaling.DefaultContainerResourcePolicy,
+				MinAllowed:    resources("50m", "64Mi"),
+				MaxAllowed:    resources("500m", "512Mi"),
+			}},
+		},
+	}}
+
+	run := func(t *testing.T, skip bool, dep *appsv1.Deployment) (*prometheus.Registry, error) {
+		t.Helper()
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(dep).Build()
+		logger := logr.Discard()
+		promReg := prometheus.NewRegistry()
+		reconciler := &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(10),
+			Metrics:    internalmetrics.NewRegistry(promReg),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries:      map[string]config.Profile{"p1": profile},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			SkipIfWithinBounds: skip,
+		}
+
+		_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+		require.NoError(t, err)
+
+		return promReg, kubeClient.Get(context.Background(), types.NamespacedName{
+			Name:      renderDeploymentVPAName(t, "ns1", "demo", "p1"),
+			Namespace: "ns1",
+		}, newVPAObject())
+	}
+
+	t.Run("Skips VPA when requests are within bounds", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{"app": resources("100m", "128Mi")})
+		promReg, err := run(t, true, dep)
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.Equal(t, float64(1), mustGetCounterValue(t, promReg, "autovpa_vpa_skipped_total", map[string]string{
+			"namespace": "ns1",
+			"name":      "demo",
+			"kind":      "Deployment",
+			"reason":    "within_bounds",
+		}))
+	})
+
+	t.Run("Creates VPA when requests are out of bounds", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{"app": resources("1", "128Mi")})
+		_, err := run(t, true, dep)
+		require.NoError(t, err)
+	})
+
+	t.Run("Creates VPA when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		dep := deploymentWithRequests(map[string]corev1.ResourceList{"app": resources("100m", "128Mi")})
+		_, err := run(t, false, dep)
+		require.NoError(t, err)
+	})
+}
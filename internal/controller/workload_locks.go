@@ -0,0 +1,87 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WorkloadLocks serializes the workload reconciler of a workload with the VPA
+// reconciler checking the VPAs it owns, so the VPA reconciler cannot delete a
+// VPA while the workload reconciler renders or replaces it. Locks are keyed by
+// the workload's namespace, kind and name and dropped once no reconcile holds
+// or waits for them. A nil WorkloadLocks never blocks. All controllers share
+// one.
+type WorkloadLocks struct {
+	mu      sync.Mutex
+	entries map[workloadLockKey]*workloadLock
+}
+
+// workloadLockKey identifies a workload across controllers.
+type workloadLockKey struct {
+	kind string
+	name types.NamespacedName
+}
+
+// workloadLock is a per-workload mutex with the number of reconciles holding
+// or waiting for it.
+type workloadLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock blocks until no other reconcile holds the workload's lock and returns
+// the function releasing it. The release function must be called exactly once.
+func (l *WorkloadLocks) lock(kind string, name types.NamespacedName) (unlock func()) {
+	if l == nil {
+		return func() {}
+	}
+	key := workloadLockKey{kind: kind, name: name}
+
+	l.mu.Lock()
+	if l.entries == nil {
+		l.entries = map[workloadLockKey]*workloadLock{}
+	}
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &workloadLock{}
+		l.entries[key] = entry
+	}
+	entry.refs++
+	l.mu.Unlock()
+
+	entry.mu.Lock()
+	return func() {
+		entry.mu.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(l.entries, key)
+		}
+	}
+}
+
+// len returns the number of workloads with a held or awaited lock.
+func (l *WorkloadLocks) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containeroo/autovpa/internal/config"
+	"github.com/containeroo/autovpa/internal/flag"
+	internalmetrics "github.com/containeroo/autovpa/internal/metrics"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestWorkloadLocks(t *testing.T) {
+	t.Parallel()
+
+	demo := types.NamespacedName{Namespace: "ns1", Name: "demo"}
+
+	t.Run("Nil locks never block", func(t *testing.T) {
+		t.Parallel()
+
+		var locks *WorkloadLocks
+		unlock := locks.lock("Deployment", demo)
+		locks.lock("Deployment", demo)()
+		unlock()
+	})
+
+	t.Run("Serializes the same workload", func(t *testing.T) {
+		t.Parallel()
+
+		locks := &WorkloadLocks{}
+		unlock := locks.lock("Deployment", demo)
+
+		acquired := make(chan struct{})
+		go func() {
+			defer locks.lock("Deployment", demo)()
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("second lock acquired while the first is held")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		unlock()
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("second lock not acquired after release")
+		}
+	})
+
+	t.Run("Does not block other workloads", func(t *testing.T) {
+		t.Parallel()
+
+		locks := &WorkloadLocks{}
+		unlock := locks.lock("Deployment", demo)
+		defer unlock()
+
+		locks.lock("StatefulSet", demo)()
+		locks.lock("Deployment", types.NamespacedName{Namespace: "ns2", Name: "demo"})()
+		locks.lock("Deployment", types.NamespacedName{Namespace: "ns1", Name: "other"})()
+	})
+
+	t.Run("Drops released locks", func(t *testing.T) {
+		t.Parallel()
+
+		locks := &WorkloadLocks{}
+		unlock := locks.lock("Deployment", demo)
+		assert.Equal(t, 1, locks.len())
+
+		unlock()
+		assert.Zero(t, locks.len())
+	})
+}
+
+func TestBaseReconciler_ReconcileWorkload_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const reconciles = 8
+
+	// newReconciler returns a reconciler whose client calls are slowed down and
+	// counted, recording how many run at the same time.
+	newReconciler := func(t *testing.T, locks *WorkloadLocks, maxInFlight *atomic.Int32) *BaseReconciler {
+		t.Helper()
+		var inFlight atomic.Int32
+		track := func() func() {
+			n := inFlight.Add(1)
+			for {
+				cur := maxInFlight.Load()
+				if n <= cur || maxInFlight.CompareAndSwap(cur, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			return func() { inFlight.Add(-1) }
+		}
+
+		kubeClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				defer track()()
+				return c.Get(ctx, key, obj, opts...)
+			},
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				defer track()()
+				return c.List(ctx, list, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				defer track()()
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				defer track()()
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).Build()
+		logger := logr.Discard()
+
+		return &BaseReconciler{
+			KubeClient: kubeClient,
+			Logger:     &logger,
+			Recorder:   events.NewFakeRecorder(100),
+			Metrics:    internalmetrics.NewRegistry(prometheus.NewRegistry()),
+			Meta: MetaConfig{
+				ProfileKey:   "vpa/profile",
+				ManagedLabel: "vpa/managed",
+			},
+			Profiles: ProfileConfig{
+				Entries: map[string]config.Profile{
+					"p1": {},
+					"p2": {},
+				},
+				Default:      "p1",
+				NameTemplate: flag.DefaultNameTemplate,
+			},
+			Locks: locks,
+		}
+	}
+
+	// reconcileFlips reconciles the same Deployment concurrently, alternating
+	// its profile annotation between p1 and p2.
+	reconcileFlips := func(t *testing.T, reconciler *BaseReconciler) {
+		t.Helper()
+		var wg sync.WaitGroup
+		errs := make(chan error, reconciles)
+		for i := range reconciles {
+			dep := &appsv1.Deployment{}
+			dep.SetNamespace("ns1")
+			dep.SetName("demo")
+			dep.SetUID("uid1")
+			dep.SetAnnotations(map[string]string{"vpa/profile": []string{"p1", "p2"}[i%2]})
+
+			wg.Go(func() {
+				_, err := reconciler.ReconcileWorkload(context.Background(), dep, DeploymentGVK)
+				errs <- err
+			})
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+	}
+
+	t.Run("Serializes reconciles of the same workload", func(t *testing.T) {
+		t.Parallel()
+
+		var maxInFlight atomic.Int32
+		reconciler := newReconciler(t, &WorkloadLocks{}, &maxInFlight)
+
+		reconcileFlips(t, reconciler)
+
+		assert.Equal(t, int32(1), maxInFlight.Load(), "client calls of different reconciles overlapped")
+		assert.Zero(t, reconciler.Locks.len(), "locks must be released")
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(vpaGVK.GroupVersion().WithKind("VerticalPodAutoscalerList"))
+		require.NoError(t, reconciler.KubeClient.List(context.Background(), list, client.InNamespace("ns1")))
+		assert.Len(t, list.Items, 1, "only the last reconcile's VPA must remain")
+	})
+
+	t.Run("Overlaps without locks", func(t *testing.T) {
+		t.Parallel()
+
+		var maxInFlight atomic.Int32
+		reconciler := newReconciler(t, nil, &maxInFlight)
+
+		reconcileFlips(t, reconciler)
+
+		assert.Greater(t, maxInFlight.Load(), int32(1))
+	})
+}
+
+func TestVPAReconciler_Reconcile_WorkloadLocks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Waits for the owner's workload reconcile", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newManagedVPA(t, "ns1", "gone-vpa", "p1")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef("gone")})
+		r := newTestVPAReconciler(t, vpa)
+		r.Locks = &WorkloadLocks{}
+
+		// Stand in for the workload reconciler of the owner holding its lock.
+		unlock := r.Locks.lock(DeploymentGVK.Kind, types.NamespacedName{Namespace: "ns1", Name: "gone"})
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(vpa)})
+			done <- err
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("VPA reconcile finished while the workload lock is held")
+		case <-time.After(50 * time.Millisecond):
+		}
+		require.NoError(t, r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), newVPAObject()))
+
+		unlock()
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("VPA reconcile did not finish after the workload lock was released")
+		}
+		err := r.KubeClient.Get(context.Background(), client.ObjectKeyFromObject(vpa), newVPAObject())
+		assert.True(t, apierrors.IsNotFound(err), "owner-gone VPA must be deleted")
+	})
+
+	t.Run("Does not wait for other workloads", func(t *testing.T) {
+		t.Parallel()
+
+		vpa := newManagedVPA(t, "ns1", "gone-vpa", "p1")
+		vpa.SetOwnerReferences([]metav1.OwnerReference{deploymentOwnerRef("gone")})
+		r := newTestVPAReconciler(t, vpa)
+		r.Locks = &WorkloadLocks{}
+
+		unlock := r.Locks.lock(DeploymentGVK.Kind, types.NamespacedName{Namespace: "ns1", Name: "other"})
+		defer unlock()
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(vpa)})
+		require.NoError(t, err)
+	})
+}
@@ -17,36 +17,118 @@ limitations under the License.
 package flag
 
 import (
+	"errors"
+	"fmt"
 	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/containeroo/tinyflags"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 const (
 	profileAnnotation   string = "autovpa.containeroo.ch/profile"
 	managedLabel        string = "autovpa.containeroo.ch/managed"
 	DefaultNameTemplate string = "{{ .WorkloadName }}-{{ .Profile }}-vpa"
+	vpaAPIGroup         string = "autoscaling.k8s.io"
+	vpaAPIVersion       string = "v1"
+	fieldManager        string = "autovpa"
+	leaderElectionID    string = "fc1fdccd.autovpa.containeroo.ch"
+
+	// maxFieldManagerLength is the longest field manager name the API server accepts.
+	maxFieldManagerLength = 128
 )
 
 // Options holds all configuration options for the application.
 type Options struct {
-	WatchNamespaces     []string       // Namespaces to watch
-	MetricsAddr         string         // Address for the metrics server
-	LeaderElection      bool           // Enable leader election
-	ProbeAddr           string         // Address for health and readiness probes
-	SecureMetrics       bool           // Serve metrics over HTTPS
-	EnableHTTP2         bool           // Enable HTTP/2 for servers
-	EnableMetrics       bool           // Enable or disable metrics
-	LogEncoder          string         // Log format: "json" or "console"
-	LogStacktraceLevel  string         // Stacktrace log level
-	LogDev              bool           // Enable development logging mode
-	ProfileAnnotation   string         // Annotation key workloads must set to request a profile.
-	ManagedLabel        string         // Label key to mark VPAs as managed by the operator.
-	DefaultNameTemplate string         // Template used to render managed VPA names; can be overridden per profile.
-	ConfigPath          string         // Path to the Config containing VPA profiles.
-	CRDCheck            bool           // Enable the check for the VPA CRD.
-	SkipManagerStart    bool           // Skip starting the manager (used by tests).
-	OverriddenValues    map[string]any // CLI overrides
+	WatchNamespaces            []string                  // Namespaces to watch
+	WatchNamespaceFile         string                    // File listing namespaces to watch, one per line; merged with WatchNamespaces.
+	NamespaceSelector          string                    // Label selector for namespaces to watch, resolved at startup; merged with WatchNamespaces.
+	AdditionalTargetKinds      []schema.GroupVersionKind // Extra workload kinds reconciled generically (group/version/Kind).
+	ResyncPeriod               time.Duration             // Period for forced cache resyncs (0 keeps the controller-runtime default).
+	UnmanagedInterval          time.Duration             // Interval for recomputing the unmanaged workloads gauge (0 disables).
+	VPAAgeInterval             time.Duration             // Interval for recomputing the managed VPA age gauge (0 disables).
+	StartupSyncTimeout         time.Duration             // Time the initial cache sync may take before readiness fails (0 waits forever).
+	ShutdownTimeout            time.Duration             // Time in-flight reconciles get to finish on shutdown (0 skips, negative waits forever).
+	ApplyFailureThreshold      int                       // Consecutive VPA apply failures that pause a controller's applies (0 disables).
+	MaxVPAsPerNamespace        int                       // Managed VPAs a namespace may hold before creations are refused (0 means unlimited).
+	MetricsAddr                string                    // Address for the metrics server
+	LeaderElection             bool                      // Enable leader election
+	LeaderElectionID           string                    // Name of the leader election lease.
+	LeaseDuration              time.Duration             // Time non-leaders wait before taking over the lease.
+	RenewDeadline              time.Duration             // Time the leader retries renewing the lease before giving up.
+	RetryPeriod                time.Duration             // Time between leader election attempts.
+	ProbeAddr                  string                    // Address for health and readiness probes
+	SecureMetrics              bool                      // Serve metrics over HTTPS
+	MetricsCertDir             string                    // Directory with the metrics serving certificate (empty uses a self-signed one).
+	MetricsCertName            string                    // File name of the metrics serving certificate in MetricsCertDir.
+	MetricsKeyName             string                    // File name of the metrics serving key in MetricsCertDir.
+	MetricsPath                string                    // HTTP path serving metrics, in addition to /metrics.
+	OTelEndpoint               string                    // OTLP/gRPC endpoint URL receiving reconcile traces (empty disables tracing).
+	EnableHTTP2                bool                      // Enable HTTP/2 for servers
+	DefaultingWebhook          bool                      // Serve the webhook defaulting the profile annotation in opted-in namespaces.
+	EnableMetrics              bool                      // Enable or disable metrics
+	LogEncoder                 string                    // Log format: "json" or "console"
+	LogStacktraceLevel         string                    // Stacktrace log level
+	LogDev                     bool                      // Enable development logging mode
+	LogLevel                   string                    // Minimum log level: "error", "info" or "debug"
+	EnableDeployments          bool                      // Run the Deployment controller.
+	EnableStatefulSets         bool                      // Run the StatefulSet controller.
+	EnableDaemonSets           bool                      // Run the DaemonSet controller.
+	EnableReplicaSets          bool                      // Run the controller for standalone ReplicaSets.
+	DeploymentWorkers          int                       // Concurrent reconciles of the Deployment controller.
+	StatefulSetWorkers         int                       // Concurrent reconciles of the StatefulSet controller.
+	DaemonSetWorkers           int                       // Concurrent reconciles of the DaemonSet controller.
+	ReplicaSetWorkers          int                       // Concurrent reconciles of the ReplicaSet controller.
+	ReconcileOnlyKinds         []string                  // Built-in kinds to run controllers for; overrides the Enable* defaults when set.
+	ProfileAnnotations         []string                  // Annotation keys workloads set to request a profile, in priority order.
+	ManagedLabel               string                    // Label key to mark VPAs as managed by the operator.
+	ManagedLabelValue          string                    // Managed label value; may be a name template rendered per workload.
+	TrackingAnnotations        []string                  // Workload annotation keys copied onto managed VPAs.
+	RecommendedLabels          bool                      // Copy the workload's app.kubernetes.io/* labels onto managed VPAs.
+	DefaultNameTemplate        string                    // Template used to render managed VPA names; can be overridden per profile.
+	StrictNameTemplates        bool                      // Reject name templates that ignore .Kind instead of warning.
+	DefaultUpdateMode          string                    // Update mode injected into profiles that do not set one (empty keeps the VPA default).
+	DefaultRecommender         string                    // Recommender injected into profiles that do not set recommenders (empty keeps the VPA default).
+	DefaultControlledResources []corev1.ResourceName     // controlledResources injected into container policies that do not set it.
+	DefaultMinAllowed          corev1.ResourceList       // minAllowed injected into container policies that do not set it.
+	DefaultMaxAllowed          corev1.ResourceList       // maxAllowed injected into container policies that do not set it.
+	ContainerPolicyMode        string                    // How __all__ container policies render: "explicit" (per container) or "wildcard" ("*").
+	ConfigPath                 string                    // Path to the Config containing VPA profiles.
+	ConfigFormat               string                    // Encoding of the config file: "auto", "yaml" or "json".
+	ConfigReloadInterval       time.Duration             // Interval for re-reading the config file and applying changed profiles (0 disables).
+	VPAAPIGroup                string                    // API group serving the VerticalPodAutoscaler resource.
+	VPAAPIVersion              string                    // API version of the VerticalPodAutoscaler resource.
+	FieldManager               string                    // Field manager name used for server-side apply of VPAs.
+	OwnerBlockDeletion         bool                      // Set blockOwnerDeletion=true on VPA ownerRefs.
+	ObsoleteAction             string                    // What to do with obsolete managed VPAs (delete or release).
+	NoObsoleteCleanup          bool                      // Only log obsolete managed VPAs instead of acting on them.
+	ErrorAnnotations           bool                      // Write the latest reconcile error onto the workload.
+	NamespaceDefaults          bool                      // Fall back to the namespace default-profile annotation.
+	EmptyMeansDefault          bool                      // Treat an empty profile annotation as the default profile.
+	WarnAnnotationTypos        bool                      // Warn about annotation keys resembling the profile annotation.
+	CreateOnly                 bool                      // Create missing VPAs but never update existing ones.
+	UseFinalizers              bool                      // Add a finalizer to managed VPAs to track out-of-band deletions.
+	PruneOnStartup             bool                      // Check every managed VPA once after startup and delete invalid ones.
+	RespectLimitRanges         bool                      // Clamp container policy bounds to the namespace's LimitRanges.
+	RespectResourceQuota       bool                      // Clamp container policy bounds to the namespace's remaining quota.
+	SkipIfHPA                  bool                      // Skip creating VPAs for workloads scaled by a CPU/memory HPA.
+	SkipIfWithinBounds         bool                      // Skip VPAs while container requests lie within the profile's bounds.
+	MirrorRecommendations      bool                      // Copy VPA target recommendations onto the owner workload.
+	DisableEvents              bool                      // Drop Kubernetes events instead of recording them.
+	ForceUpdateModeOff         bool                      // Render every managed VPA with updateMode Off, overriding profiles.
+	DowngradeUpdateMode        bool                      // Render InPlaceOrRecreate as Recreate when the VPA lacks in-place updates.
+	CRDCheck                   bool                      // Enable the check for the VPA CRD.
+	SkipManagerStart           bool                      // Skip starting the manager (used by tests).
+	SkipNameValidation         bool                      // Allow controller names already used in this process (used by tests).
+	OverriddenValues           map[string]any            // CLI overrides
 }
 
 // ParseArgs parses CLI flags into Options and handles --help/--version output.
@@ -58,8 +140,8 @@ func ParseArgs(args []string, version string) (Options, error) {
 	tf.EnvPrefix("AUTO_VPA")
 	tf.HideEnvs()
 	tf.Note("*) These variables are available in the template string: " +
-		"\".WorkloadName\", \".Namespace\", \".Kind\", \".Profile\".\n" +
-		"Template functions: toLower, replace, trim, truncate, dnsLabel.\n\n" +
+		"\".WorkloadName\", \".Namespace\", \".Kind\", \".Profile\", \".RequestedProfile\", \".Labels\", \".NamespaceLabels\".\n" +
+		"Template functions: toLower, toUpper, title, replace, trim, trimPrefix, trimSuffix, truncate, dnsLabel, regexReplace.\n\n" +
 		"Each flag can also be set via environment variable using the AUTO_VPA_ prefix, " +
 		"e.g.: --log-encoder=json → AUTO_VPA_LOG_ENCODER=json")
 
@@ -67,26 +149,214 @@ func ParseArgs(args []string, version string) (Options, error) {
 	tf.StringVar(&opts.ConfigPath, "config", "config.yaml", "Path to configuration file").
 		Short("c").
 		Value()
+	tf.StringVar(&opts.ConfigFormat, "config-format", "auto", "Format of the configuration file (auto, yaml, json); auto picks JSON for .json files and documents starting with \"{\"").
+		Choices("auto", "yaml", "json").
+		HideAllowed().
+		Value()
+	tf.DurationVar(&opts.ConfigReloadInterval, "config-reload-interval", 0, "Interval for re-reading the configuration file and applying changed profiles (0 disables)").
+		Placeholder("DURATION").
+		Value()
 	tf.Bool("disable-crd-check", false, "Disable the check for the VPA CRD").
 		Finalize(func(v bool) bool {
 			opts.CRDCheck = !v
 			return v
 		}).
 		Value()
-	tf.StringVar(&opts.ProfileAnnotation, "profile-annotation", profileAnnotation, "Annotation key workloads must set to request a profile").
+	tf.StringSliceVar(&opts.ProfileAnnotations, "profile-annotation", []string{profileAnnotation}, "Annotation key workloads set to request a profile; a comma-separated list is read in priority order, the first key also labels VPAs").
 		Placeholder("ANNOTATION").
 		Value()
+	tf.BoolVar(&opts.RecommendedLabels, "propagate-recommended-labels", false, "Copy the workload's app.kubernetes.io/* recommended labels onto managed VPAs; the managed and profile labels take precedence").
+		Strict().
+		HideAllowed().
+		Value()
 	tf.StringVar(&opts.ManagedLabel, "managed-label", managedLabel, "Label key to mark VPAs as managed by the operator").
 		Placeholder("LABEL").
 		Value()
+	tf.StringVar(&opts.ManagedLabelValue, "managed-label-value", "true", "Value of the managed label; may be a name template, e.g. {{ index .Labels \"team\" }}").
+		Placeholder("VALUE").
+		Value()
+	tf.StringSliceVar(&opts.TrackingAnnotations, "propagate-tracking-annotations", nil, "Workload annotation keys copied onto managed VPAs, e.g. GitOps tracking ids (can be repeated or comma-separated)").
+		Placeholder("ANNOTATION").
+		Value()
+	tf.BoolVar(&opts.OwnerBlockDeletion, "owner-block-deletion", true, "Set blockOwnerDeletion on VPA ownerRefs; disable when RBAC denies access to owner finalizers").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.StringVar(&opts.ObsoleteAction, "obsolete-action", "delete", "What to do with managed VPAs a workload no longer needs after a profile or name template change (delete, release); release removes the managed label and ownerRef instead of deleting").
+		Choices("delete", "release").
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.ErrorAnnotations, "write-error-annotations", false, "Write the latest reconcile error into the workload annotation autovpa.containeroo.ch/last-error and remove it once a reconcile succeeds").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.NoObsoleteCleanup, "disable-obsolete-cleanup", false, "Only log managed VPAs a workload no longer needs instead of deleting or releasing them; may leave duplicate VPAs for one workload").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.NamespaceDefaults, "namespace-default-profile", false, "Use the namespace annotation autovpa.containeroo.ch/default-profile for workloads without a profile annotation (requires read access to namespaces)").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.EmptyMeansDefault, "empty-annotation-means-default", false, "Treat a present but empty profile annotation as opting into the default profile instead of opting out").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.WarnAnnotationTypos, "profile-annotation-required", false, "Emit a PossibleProfileAnnotationTypo warning for workload annotation keys within two edits of a profile annotation key").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.RespectLimitRanges, "respect-limitranges", false, "Clamp VPA container policy minAllowed/maxAllowed to the namespace's Container-type LimitRanges (requires read access to limitranges)").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.RespectResourceQuota, "respect-resource-quota", false, "Clamp VPA container policy maxAllowed to the quota left in the namespace's ResourceQuotas (requires read access to resourcequotas)").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.SkipIfWithinBounds, "skip-if-within-bounds", false, "Skip creating or updating a VPA while the workload's container requests lie within the profile's minAllowed/maxAllowed").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.SkipIfHPA, "skip-if-hpa", false, "Skip creating VPAs for workloads an HPA scales on CPU or memory (requires read access to horizontalpodautoscalers)").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.CreateOnly, "create-only", false, "Create missing VPAs but never update existing ones (obsolete and opt-out deletions still happen)").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.UseFinalizers, "use-finalizers", false, "Add the finalizer autovpa.containeroo.ch/managed to managed VPAs so out-of-band deletions keep the managed gauge accurate (deletion then waits for the operator)").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.PruneOnStartup, "prune-on-startup", false, "Once the caches have synced, check every managed VPA in the watched namespaces like the VPA controller does and delete orphaned or otherwise invalid ones").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.MirrorRecommendations, "mirror-recommendations", false, "Copy the VPA target recommendation onto the owner workload annotation autovpa.containeroo.ch/recommendation").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.DisableEvents, "disable-events", false, "Do not record Kubernetes events; logs and metrics are unaffected").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.ForceUpdateModeOff, "force-update-mode-off", false, "Render every managed VPA with updateMode Off (recommendations only), overriding profiles and --default-update-mode").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.DowngradeUpdateMode, "downgrade-unsupported-update-mode", false, "Render updateMode InPlaceOrRecreate as Recreate when the VPA installation does not support in-place updates").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.StringVar(&opts.DefaultUpdateMode, "default-update-mode", "", "Update mode for profiles without updatePolicy.updateMode (Off, Initial, Recreate, InPlaceOrRecreate)").
+		Placeholder("MODE").
+		Value()
+	tf.StringVar(&opts.DefaultRecommender, "default-recommender", "", "Recommender name for profiles without recommenders (empty uses the cluster's default recommender)").
+		Placeholder("NAME").
+		Value()
+	defaultControlledResources := tf.StringSlice("default-controlled-resources", nil, "Resources (cpu, memory) injected as controlledResources into container policies that set none (can be repeated or comma-separated)").
+		Placeholder("RESOURCE").
+		Value()
+	tf.StringVar(&opts.ContainerPolicyMode, "container-policy-mode", "explicit", "How profile container policies named __all__ render (explicit, wildcard); explicit adds one policy per workload container, wildcard a single \"*\" policy").
+		Choices("explicit", "wildcard").
+		HideAllowed().
+		Value()
+	defaultMinCPU := tf.String("default-min-cpu", "", "CPU minAllowed injected into container policies that set none (e.g. 10m)").
+		Placeholder("QUANTITY").
+		Value()
+	defaultMinMemory := tf.String("default-min-memory", "", "Memory minAllowed injected into container policies that set none (e.g. 32Mi)").
+		Placeholder("QUANTITY").
+		Value()
+	defaultMaxCPU := tf.String("default-max-cpu", "", "CPU maxAllowed injected into container policies that set none (e.g. 4)").
+		Placeholder("QUANTITY").
+		Value()
+	defaultMaxMemory := tf.String("default-max-memory", "", "Memory maxAllowed injected into container policies that set none (e.g. 8Gi)").
+		Placeholder("QUANTITY").
+		Value()
 	tf.StringVar(&opts.DefaultNameTemplate, "vpa-name-template", DefaultNameTemplate, "Template used to render managed VPA names; override per profile with nameTemplate *\n").
 		Placeholder("TEMPLATE-STRING").
 		Value()
+	tf.BoolVar(&opts.StrictNameTemplates, "strict-name-templates", false, "Fail startup when a name template does not reference .Kind instead of only warning; the built-in default template is exempt").
+		Strict().
+		HideAllowed().
+		Value()
 
 	// Controller
+	tf.StringVar(&opts.VPAAPIGroup, "vpa-api-group", vpaAPIGroup, "API group serving the VerticalPodAutoscaler resource").
+		Placeholder("GROUP").
+		Value()
+	tf.StringVar(&opts.VPAAPIVersion, "vpa-api-version", vpaAPIVersion, "API version of the VerticalPodAutoscaler resource").
+		Placeholder("VERSION").
+		Value()
+	tf.StringVar(&opts.FieldManager, "field-manager", fieldManager, "Field manager name used when applying VPAs (set a unique name per instance to avoid ownership conflicts)").
+		Placeholder("NAME").
+		Value()
 	tf.StringSliceVar(&opts.WatchNamespaces, "watch-namespace", nil, "Namespaces to watch (can be repeated or comma-separated)").
 		Placeholder("NAMESPACE").
 		Value()
+	tf.StringVar(&opts.WatchNamespaceFile, "watch-namespace-file", "", "File listing namespaces to watch, one per line, added to --watch-namespace (changes need a restart)").
+		Placeholder("PATH").
+		Value()
+	tf.StringVar(&opts.NamespaceSelector, "namespace-label-selector", "", "Watch namespaces matching this label selector (e.g. autovpa=enabled), added to --watch-namespace; resolved at startup, so relabelled namespaces need a restart").
+		Placeholder("SELECTOR").
+		Value()
+	tf.BoolVar(&opts.EnableDeployments, "enable-deployments", true, "Manage VPAs for Deployments").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.EnableStatefulSets, "enable-statefulsets", true, "Manage VPAs for StatefulSets").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.EnableDaemonSets, "enable-daemonsets", true, "Manage VPAs for DaemonSets").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.BoolVar(&opts.EnableReplicaSets, "enable-replicasets", false, "Manage VPAs for standalone ReplicaSets not controlled by a Deployment").
+		Strict().
+		HideAllowed().
+		Value()
+	tf.IntVar(&opts.DeploymentWorkers, "deployment-workers", 1, "Concurrent reconciles of the Deployment controller").
+		Placeholder("COUNT").
+		Value()
+	tf.IntVar(&opts.StatefulSetWorkers, "statefulset-workers", 1, "Concurrent reconciles of the StatefulSet controller").
+		Placeholder("COUNT").
+		Value()
+	tf.IntVar(&opts.DaemonSetWorkers, "daemonset-workers", 1, "Concurrent reconciles of the DaemonSet controller").
+		Placeholder("COUNT").
+		Value()
+	tf.IntVar(&opts.ReplicaSetWorkers, "replicaset-workers", 1, "Concurrent reconciles of the ReplicaSet controller").
+		Placeholder("COUNT").
+		Value()
+	tf.StringSliceVar(&opts.ReconcileOnlyKinds, "reconcile-only-kinds", nil, "Run controllers only for the listed built-in kinds (Deployment, StatefulSet, DaemonSet); shorthand for disabling the others, not combinable with --enable-deployments, --enable-statefulsets or --enable-daemonsets").
+		Placeholder("KIND").
+		Value()
+	additionalTargetKinds := tf.StringSlice("additional-target-kind", nil, "Additional workload kind to manage VPAs for, as group/version/Kind (can be repeated or comma-separated)").
+		Placeholder("GROUP/VERSION/KIND").
+		Value()
+	tf.DurationVar(&opts.ResyncPeriod, "resync-period", 0, "Period for forced reconciliation of all workloads (0 keeps the controller-runtime default; short periods increase API load)").
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&opts.UnmanagedInterval, "unmanaged-workloads-interval", time.Minute, "Interval for recomputing the unmanaged workloads gauge (0 disables)").
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&opts.VPAAgeInterval, "managed-vpa-age-interval", time.Minute, "Interval for recomputing the managed VPA age gauge (0 disables)").
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&opts.ShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second, "Time in-flight reconciles get to finish on shutdown (0 skips the drain, negative waits forever)").
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&opts.StartupSyncTimeout, "startup-reconcile-timeout", 5*time.Minute, "Time the initial cache sync may take before the readiness check fails (0 waits forever)").
+		Placeholder("DURATION").
+		Value()
+	tf.IntVar(&opts.ApplyFailureThreshold, "apply-failure-threshold", 0, "Consecutive VPA apply failures after which a controller pauses its applies for a while (0 disables)").
+		Placeholder("COUNT").
+		Value()
+	tf.IntVar(&opts.MaxVPAsPerNamespace, "max-vpas-per-namespace", 0, "Refuse to create more managed VPAs in a namespace than this, guarding against runaway name templates (0 means unlimited)").
+		Placeholder("COUNT").
+		Value()
 
 	// Metrics
 	tf.BoolVar(&opts.EnableMetrics, "metrics-enabled", true, "Enable or disable the metrics endpoint").
@@ -100,11 +370,30 @@ func ParseArgs(args []string, version string) (Options, error) {
 		Strict().
 		HideAllowed().
 		Value()
+	tf.StringVar(&opts.MetricsCertDir, "metrics-cert-dir", "", "Directory with the metrics serving certificate (empty uses a self-signed certificate)").
+		Placeholder("DIR").
+		Value()
+	tf.StringVar(&opts.MetricsCertName, "metrics-cert-name", "tls.crt", "File name of the metrics serving certificate in --metrics-cert-dir").
+		Placeholder("FILE").
+		Value()
+	tf.StringVar(&opts.MetricsKeyName, "metrics-key-name", "tls.key", "File name of the metrics serving key in --metrics-cert-dir").
+		Placeholder("FILE").
+		Value()
+	tf.StringVar(&opts.MetricsPath, "metrics-path", "/metrics", "HTTP path serving metrics; /metrics keeps being served as well").
+		Placeholder("PATH").
+		Value()
+	tf.StringVar(&opts.OTelEndpoint, "otel-endpoint", "", "OTLP/gRPC endpoint URL receiving reconcile traces, e.g. http://otel-collector:4317 (empty disables tracing)").
+		Placeholder("URL").
+		Value()
 
 	// Server
 	healthProbeaddress := tf.TCPAddr("health-probe-bind-address", &net.TCPAddr{IP: nil, Port: 8081}, "Health and readiness probe address").
 		Placeholder("ADDR:PORT").
 		Value()
+	tf.BoolVar(&opts.DefaultingWebhook, "enable-defaulting-webhook", false, "Serve a mutating webhook that annotates new workloads in namespaces labelled autovpa.containeroo.ch/profile-defaulting=enabled with the default profile").
+		Strict().
+		HideAllowed().
+		Value()
 	tf.BoolVar(&opts.EnableHTTP2, "enable-http2", false, "Enable HTTP/2 for servers").
 		Strict().
 		HideAllowed().
@@ -113,9 +402,24 @@ func ParseArgs(args []string, version string) (Options, error) {
 		Strict().
 		HideAllowed().
 		Value()
+	tf.StringVar(&opts.LeaderElectionID, "leader-election-id", leaderElectionID, "Name of the leader election lease; give independent deployments in one cluster distinct IDs").
+		Placeholder("ID").
+		Value()
+	tf.DurationVar(&opts.LeaseDuration, "leader-election-lease-duration", 15*time.Second, "Time non-leader replicas wait before taking over an unrenewed lease").
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&opts.RenewDeadline, "leader-election-renew-deadline", 10*time.Second, "Time the leader keeps retrying to renew its lease before giving it up").
+		Placeholder("DURATION").
+		Value()
+	tf.DurationVar(&opts.RetryPeriod, "leader-election-retry-period", 2*time.Second, "Time between leader election attempts").
+		Placeholder("DURATION").
+		Value()
 	tf.BoolVar(&opts.SkipManagerStart, "skip-manager-start", false, "Skip starting the manager (tests only)").
 		HideAllowed().
 		Value()
+	tf.BoolVar(&opts.SkipNameValidation, "skip-controller-name-validation", false, "Allow running the operator more than once per process (tests only)").
+		Hidden().
+		Value()
 
 	// Logging
 	tf.StringVar(&opts.LogEncoder, "log-encoder", "json", "Log format (json, console)").
@@ -123,6 +427,10 @@ func ParseArgs(args []string, version string) (Options, error) {
 		HideAllowed().
 		Value()
 	tf.BoolVar(&opts.LogDev, "log-devel", false, "Enable development mode logging").Value()
+	tf.StringVar(&opts.LogLevel, "log-level", "info", "Minimum log level (error, info, debug); defaults to debug with --log-devel").
+		Choices("error", "info", "debug").
+		HideAllowed().
+		Value()
 	tf.StringVar(&opts.LogStacktraceLevel, "log-stacktrace-level", "panic", "Stacktrace log level").
 		Choices("info", "error", "panic").
 		HideAllowed().
@@ -132,9 +440,297 @@ func ParseArgs(args []string, version string) (Options, error) {
 		return Options{}, err
 	}
 
+	for _, raw := range *additionalTargetKinds {
+		gvk, err := parseTargetKind(raw)
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid --additional-target-kind %q: %w", raw, err)
+		}
+		opts.AdditionalTargetKinds = append(opts.AdditionalTargetKinds, gvk)
+	}
+
+	if opts.NamespaceSelector != "" {
+		if _, err := labels.Parse(opts.NamespaceSelector); err != nil {
+			return Options{}, fmt.Errorf("invalid --namespace-label-selector %q: %w", opts.NamespaceSelector, err)
+		}
+	}
+
+	if len(opts.ReconcileOnlyKinds) > 0 {
+		if err := applyReconcileOnlyKinds(&opts, tf.OverriddenValues()); err != nil {
+			return Options{}, err
+		}
+	}
+
+	// Development logging keeps its debug output unless a level is given.
+	if _, ok := tf.OverriddenValues()["log-level"]; opts.LogDev && !ok {
+		opts.LogLevel = "debug"
+	}
+
+	if !opts.EnableDeployments && !opts.EnableStatefulSets && !opts.EnableDaemonSets && !opts.EnableReplicaSets && len(opts.AdditionalTargetKinds) == 0 {
+		return Options{}, errors.New("no workload kind enabled: set one of --enable-deployments, --enable-statefulsets, --enable-daemonsets, --enable-replicasets or --additional-target-kind")
+	}
+
+	for _, w := range []struct {
+		name    string
+		workers int
+	}{
+		{"deployment-workers", opts.DeploymentWorkers},
+		{"statefulset-workers", opts.StatefulSetWorkers},
+		{"daemonset-workers", opts.DaemonSetWorkers},
+		{"replicaset-workers", opts.ReplicaSetWorkers},
+	} {
+		if w.workers < 1 {
+			return Options{}, fmt.Errorf("--%s must be at least 1", w.name)
+		}
+	}
+
+	if strings.TrimSpace(opts.LeaderElectionID) == "" {
+		return Options{}, errors.New("--leader-election-id must not be empty")
+	}
+	if opts.RetryPeriod <= 0 {
+		return Options{}, errors.New("--leader-election-retry-period must be positive")
+	}
+	if opts.RenewDeadline <= opts.RetryPeriod {
+		return Options{}, errors.New("--leader-election-renew-deadline must be greater than --leader-election-retry-period")
+	}
+	if opts.LeaseDuration <= opts.RenewDeadline {
+		return Options{}, errors.New("--leader-election-lease-duration must be greater than --leader-election-renew-deadline")
+	}
+
+	if !strings.HasPrefix(opts.MetricsPath, "/") {
+		return Options{}, errors.New("--metrics-path must start with \"/\"")
+	}
+
+	if opts.OTelEndpoint != "" {
+		if u, err := url.Parse(opts.OTelEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return Options{}, fmt.Errorf("--otel-endpoint must be an http:// or https:// URL, got %q", opts.OTelEndpoint)
+		}
+	}
+
+	if opts.ApplyFailureThreshold < 0 {
+		return Options{}, errors.New("--apply-failure-threshold must not be negative")
+	}
+	if opts.MaxVPAsPerNamespace < 0 {
+		return Options{}, errors.New("--max-vpas-per-namespace must not be negative")
+	}
+
+	var err error
+	if opts.DefaultControlledResources, err = parseControlledResources(*defaultControlledResources); err != nil {
+		return Options{}, err
+	}
+	if opts.DefaultMinAllowed, err = parseResourceBounds("min", *defaultMinCPU, *defaultMinMemory); err != nil {
+		return Options{}, err
+	}
+	if opts.DefaultMaxAllowed, err = parseResourceBounds("max", *defaultMaxCPU, *defaultMaxMemory); err != nil {
+		return Options{}, err
+	}
+	for name, minQ := range opts.DefaultMinAllowed {
+		if maxQ, ok := opts.DefaultMaxAllowed[name]; ok && minQ.Cmp(maxQ) > 0 {
+			return Options{}, fmt.Errorf("--default-min-%s %s exceeds --default-max-%s %s", name, minQ.String(), name, maxQ.String())
+		}
+	}
+
+	if strings.TrimSpace(opts.FieldManager) == "" {
+		return Options{}, errors.New("--field-manager must not be empty")
+	}
+	if len(opts.FieldManager) > maxFieldManagerLength {
+		return Options{}, fmt.Errorf("--field-manager must be at most %d characters", maxFieldManagerLength)
+	}
+
 	opts.MetricsAddr = (*metricsBindAddress).String()
 	opts.ProbeAddr = (*healthProbeaddress).String()
 	opts.OverriddenValues = tf.OverriddenValues()
 
 	return opts, nil
 }
+
+// Summary returns the resolved options keyed by flag name, for logging the
+// effective configuration as a single structured entry.
+// "crd-check" reports the effective state of --disable-crd-check; test-only
+// options and the raw CLI overrides are omitted.
+func (o Options) Summary() map[string]any {
+	kinds := make([]string, 0, len(o.AdditionalTargetKinds))
+	for _, gvk := range o.AdditionalTargetKinds {
+		kinds = append(kinds, gvk.GroupVersion().String()+"/"+gvk.Kind)
+	}
+
+	return map[string]any{
+		"config":                            o.ConfigPath,
+		"config-format":                     o.ConfigFormat,
+		"config-reload-interval":            o.ConfigReloadInterval.String(),
+		"crd-check":                         o.CRDCheck,
+		"profile-annotation":                o.ProfileAnnotations,
+		"managed-label":                     o.ManagedLabel,
+		"managed-label-value":               o.ManagedLabelValue,
+		"propagate-tracking-annotations":    o.TrackingAnnotations,
+		"propagate-recommended-labels":      o.RecommendedLabels,
+		"owner-block-deletion":              o.OwnerBlockDeletion,
+		"obsolete-action":                   o.ObsoleteAction,
+		"disable-obsolete-cleanup":          o.NoObsoleteCleanup,
+		"write-error-annotations":           o.ErrorAnnotations,
+		"namespace-default-profile":         o.NamespaceDefaults,
+		"empty-annotation-means-default":    o.EmptyMeansDefault,
+		"profile-annotation-required":       o.WarnAnnotationTypos,
+		"create-only":                       o.CreateOnly,
+		"use-finalizers":                    o.UseFinalizers,
+		"prune-on-startup":                  o.PruneOnStartup,
+		"respect-limitranges":               o.RespectLimitRanges,
+		"respect-resource-quota":            o.RespectResourceQuota,
+		"skip-if-hpa":                       o.SkipIfHPA,
+		"skip-if-within-bounds":             o.SkipIfWithinBounds,
+		"mirror-recommendations":            o.MirrorRecommendations,
+		"disable-events":                    o.DisableEvents,
+		"default-update-mode":               o.DefaultUpdateMode,
+		"force-update-mode-off":             o.ForceUpdateModeOff,
+		"downgrade-unsupported-update-mode": o.DowngradeUpdateMode,
+		"default-recommender":               o.DefaultRecommender,
+		"default-controlled-resources":      o.DefaultControlledResources,
+		"container-policy-mode":             o.ContainerPolicyMode,
+		"default-min-cpu":                   quantityString(o.DefaultMinAllowed, corev1.ResourceCPU),
+		"default-min-memory":                quantityString(o.DefaultMinAllowed, corev1.ResourceMemory),
+		"default-max-cpu":                   quantityString(o.DefaultMaxAllowed, corev1.ResourceCPU),
+		"default-max-memory":                quantityString(o.DefaultMaxAllowed, corev1.ResourceMemory),
+		"vpa-name-template":                 o.DefaultNameTemplate,
+		"strict-name-templates":             o.StrictNameTemplates,
+		"vpa-api-group":                     o.VPAAPIGroup,
+		"vpa-api-version":                   o.VPAAPIVersion,
+		"field-manager":                     o.FieldManager,
+		"watch-namespace":                   o.WatchNamespaces,
+		"watch-namespace-file":              o.WatchNamespaceFile,
+		"namespace-label-selector":          o.NamespaceSelector,
+		"enable-deployments":                o.EnableDeployments,
+		"enable-statefulsets":               o.EnableStatefulSets,
+		"enable-daemonsets":                 o.EnableDaemonSets,
+		"enable-replicasets":                o.EnableReplicaSets,
+		"deployment-workers":                o.DeploymentWorkers,
+		"statefulset-workers":               o.StatefulSetWorkers,
+		"daemonset-workers":                 o.DaemonSetWorkers,
+		"replicaset-workers":                o.ReplicaSetWorkers,
+		"reconcile-only-kinds":              o.ReconcileOnlyKinds,
+		"additional-target-kind":            kinds,
+		"resync-period":                     o.ResyncPeriod.String(),
+		"unmanaged-workloads-interval":      o.UnmanagedInterval.String(),
+		"managed-vpa-age-interval":          o.VPAAgeInterval.String(),
+		"apply-failure-threshold":           o.ApplyFailureThreshold,
+		"max-vpas-per-namespace":            o.MaxVPAsPerNamespace,
+		"startup-reconcile-timeout":         o.StartupSyncTimeout.String(),
+		"graceful-shutdown-timeout":         o.ShutdownTimeout.String(),
+		"metrics-enabled":                   o.EnableMetrics,
+		"metrics-bind-address":              o.MetricsAddr,
+		"metrics-secure":                    o.SecureMetrics,
+		"metrics-cert-dir":                  o.MetricsCertDir,
+		"metrics-cert-name":                 o.MetricsCertName,
+		"metrics-key-name":                  o.MetricsKeyName,
+		"metrics-path":                      o.MetricsPath,
+		"otel-endpoint":                     o.OTelEndpoint,
+		"enable-http2":                      o.EnableHTTP2,
+		"enable-defaulting-webhook":         o.DefaultingWebhook,
+		"health-probe-bind-address":         o.ProbeAddr,
+		"leader-elect":                      o.LeaderElection,
+		"leader-election-id":                o.LeaderElectionID,
+		"leader-election-lease-duration":    o.LeaseDuration.String(),
+		"leader-election-renew-deadline":    o.RenewDeadline.String(),
+		"leader-election-retry-period":      o.RetryPeriod.String(),
+		"log-encoder":                       o.LogEncoder,
+		"log-devel":                         o.LogDev,
+		"log-level":                         o.LogLevel,
+		"log-stacktrace-level":              o.LogStacktraceLevel,
+	}
+}
+
+// applyReconcileOnlyKinds enables exactly the built-in kinds listed in
+// opts.ReconcileOnlyKinds (case-insensitive, singular or plural). Explicit
+// --enable-<kind> flags are rejected as they would contradict the list.
+func applyReconcileOnlyKinds(opts *Options, overridden map[string]any) error {
+	for _, name := range []string{"enable-deployments", "enable-statefulsets", "enable-daemonsets"} {
+		if _, ok := overridden[name]; ok {
+			return fmt.Errorf("--reconcile-only-kinds cannot be combined with --%s", name)
+		}
+	}
+
+	opts.EnableDeployments, opts.EnableStatefulSets, opts.EnableDaemonSets = false, false, false
+	for _, kind := range opts.ReconcileOnlyKinds {
+		switch strings.TrimSuffix(strings.ToLower(strings.TrimSpace(kind)), "s") {
+		case "deployment":
+			opts.EnableDeployments = true
+		case "statefulset":
+			opts.EnableStatefulSets = true
+		case "daemonset":
+			opts.EnableDaemonSets = true
+		default:
+			return fmt.Errorf("invalid --reconcile-only-kinds %q: must be Deployment, StatefulSet or DaemonSet", kind)
+		}
+	}
+	return nil
+}
+
+// parseTargetKind parses "group/version/Kind" (or "version/Kind" for the core group).
+func parseTargetKind(s string) (schema.GroupVersionKind, error) {
+	idx := strings.LastIndex(s, "/")
+	if idx < 0 {
+		return schema.GroupVersionKind{}, errors.New("expected group/version/Kind")
+	}
+	gv, err := schema.ParseGroupVersion(s[:idx])
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	kind := s[idx+1:]
+	if gv.Version == "" || kind == "" {
+		return schema.GroupVersionKind{}, errors.New("expected group/version/Kind")
+	}
+	return gv.WithKind(kind), nil
+}
+
+// parseControlledResources parses the --default-controlled-resources values
+// (case-insensitive) into resource names, rejecting unknown and repeated ones.
+func parseControlledResources(raw []string) ([]corev1.ResourceName, error) {
+	var resources []corev1.ResourceName
+	for _, entry := range raw {
+		name := corev1.ResourceName(strings.ToLower(strings.TrimSpace(entry)))
+		if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+			return nil, fmt.Errorf("invalid --default-controlled-resources %q: must be cpu or memory", entry)
+		}
+		if slices.Contains(resources, name) {
+			return nil, fmt.Errorf("invalid --default-controlled-resources: %q listed more than once", entry)
+		}
+		resources = append(resources, name)
+	}
+	return resources, nil
+}
+
+// parseResourceBounds parses the --default-<bound>-cpu and --default-<bound>-memory
+// quantities into a resource list. Unset flags are left out; nil means none set.
+func parseResourceBounds(bound, cpu, memory string) (corev1.ResourceList, error) {
+	var list corev1.ResourceList
+	for _, entry := range []struct {
+		name corev1.ResourceName
+		raw  string
+	}{
+		{name: corev1.ResourceCPU, raw: cpu},
+		{name: corev1.ResourceMemory, raw: memory},
+	} {
+		if entry.raw == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(entry.raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --default-%s-%s %q: %w", bound, entry.name, entry.raw, err)
+		}
+		if q.Sign() < 0 {
+			return nil, fmt.Errorf("invalid --default-%s-%s %q: must not be negative", bound, entry.name, entry.raw)
+		}
+		if list == nil {
+			list = corev1.ResourceList{}
+		}
+		list[entry.name] = q
+	}
+	return list, nil
+}
+
+// quantityString returns the quantity for name in list, or "" when unset.
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	q, ok := list[name]
+	if !ok {
+		return ""
+	}
+	return q.String()
+}
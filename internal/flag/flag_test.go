@@ -17,11 +17,18 @@ limitations under the License.
 package flag
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/containeroo/tinyflags"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestHelpRequested(t *testing.T) {
@@ -50,19 +57,81 @@ func TestParseArgs(t *testing.T) {
 		opts, err := ParseArgs(args, "0.0.0")
 
 		assert.NoError(t, err)
-		assert.Equal(t, profileAnnotation, opts.ProfileAnnotation)
+		assert.Equal(t, []string{profileAnnotation}, opts.ProfileAnnotations)
 		assert.Equal(t, managedLabel, opts.ManagedLabel)
 		assert.Equal(t, DefaultNameTemplate, opts.DefaultNameTemplate)
 		assert.Equal(t, "config.yaml", opts.ConfigPath)
+		assert.Equal(t, "auto", opts.ConfigFormat)
 		assert.Equal(t, ":8443", opts.MetricsAddr)
 		assert.Equal(t, ":8081", opts.ProbeAddr)
 		assert.True(t, opts.LeaderElection)
+		assert.Equal(t, "fc1fdccd.autovpa.containeroo.ch", opts.LeaderElectionID)
+		assert.Equal(t, 15*time.Second, opts.LeaseDuration)
+		assert.Equal(t, 10*time.Second, opts.RenewDeadline)
+		assert.Equal(t, 2*time.Second, opts.RetryPeriod)
 		assert.True(t, opts.EnableMetrics)
 		assert.True(t, opts.SecureMetrics)
+		assert.Empty(t, opts.MetricsCertDir)
+		assert.Equal(t, "tls.crt", opts.MetricsCertName)
+		assert.Equal(t, "tls.key", opts.MetricsKeyName)
+		assert.Equal(t, "/metrics", opts.MetricsPath)
+		assert.Empty(t, opts.OTelEndpoint)
 		assert.False(t, opts.EnableHTTP2)
+		assert.False(t, opts.DefaultingWebhook)
 		assert.Equal(t, "json", opts.LogEncoder)
 		assert.Equal(t, "panic", opts.LogStacktraceLevel)
 		assert.False(t, opts.LogDev)
+		assert.Equal(t, "info", opts.LogLevel)
+		assert.Equal(t, time.Minute, opts.UnmanagedInterval)
+		assert.Equal(t, time.Minute, opts.VPAAgeInterval)
+		assert.Equal(t, 5*time.Minute, opts.StartupSyncTimeout)
+		assert.Equal(t, "true", opts.ManagedLabelValue)
+		assert.Equal(t, 30*time.Second, opts.ShutdownTimeout)
+		assert.Zero(t, opts.ApplyFailureThreshold)
+		assert.Zero(t, opts.MaxVPAsPerNamespace)
+		assert.Empty(t, opts.TrackingAnnotations)
+		assert.False(t, opts.RecommendedLabels)
+		assert.Zero(t, opts.ResyncPeriod)
+		assert.Zero(t, opts.ConfigReloadInterval)
+		assert.Equal(t, "explicit", opts.ContainerPolicyMode)
+		assert.Empty(t, opts.DefaultUpdateMode)
+		assert.Empty(t, opts.DefaultRecommender)
+		assert.Nil(t, opts.DefaultControlledResources)
+		assert.Nil(t, opts.DefaultMinAllowed)
+		assert.Nil(t, opts.DefaultMaxAllowed)
+		assert.True(t, opts.OwnerBlockDeletion)
+		assert.Equal(t, "delete", opts.ObsoleteAction)
+		assert.False(t, opts.NoObsoleteCleanup)
+		assert.False(t, opts.ErrorAnnotations)
+		assert.False(t, opts.NamespaceDefaults)
+		assert.False(t, opts.EmptyMeansDefault)
+		assert.False(t, opts.WarnAnnotationTypos)
+		assert.False(t, opts.CreateOnly)
+		assert.False(t, opts.UseFinalizers)
+		assert.False(t, opts.PruneOnStartup)
+		assert.False(t, opts.RespectLimitRanges)
+		assert.False(t, opts.RespectResourceQuota)
+		assert.False(t, opts.SkipIfHPA)
+		assert.False(t, opts.SkipIfWithinBounds)
+		assert.False(t, opts.StrictNameTemplates)
+		assert.False(t, opts.MirrorRecommendations)
+		assert.False(t, opts.DisableEvents)
+		assert.False(t, opts.ForceUpdateModeOff)
+		assert.False(t, opts.DowngradeUpdateMode)
+		assert.True(t, opts.EnableDeployments)
+		assert.True(t, opts.EnableStatefulSets)
+		assert.True(t, opts.EnableDaemonSets)
+		assert.False(t, opts.EnableReplicaSets)
+		assert.Equal(t, 1, opts.DeploymentWorkers)
+		assert.Equal(t, 1, opts.StatefulSetWorkers)
+		assert.Equal(t, 1, opts.DaemonSetWorkers)
+		assert.Equal(t, 1, opts.ReplicaSetWorkers)
+		assert.Empty(t, opts.ReconcileOnlyKinds)
+		assert.Equal(t, "autoscaling.k8s.io", opts.VPAAPIGroup)
+		assert.Equal(t, "v1", opts.VPAAPIVersion)
+		assert.Equal(t, "autovpa", opts.FieldManager)
+		assert.Empty(t, opts.WatchNamespaceFile)
+		assert.Empty(t, opts.NamespaceSelector)
 	})
 
 	t.Run("Override values", func(t *testing.T) {
@@ -74,34 +143,420 @@ func TestParseArgs(t *testing.T) {
 			"--managed-label", "custom.managed",
 			"--vpa-name-template", "{{ .Namespace }}-{{ .WorkloadName }}",
 			"--config", "/tmp/profiles.yaml",
+			"--config-format", "json",
 			"--metrics-bind-address", ":9090",
 			"--health-probe-bind-address", ":9091",
 			"--leader-elect=false",
+			"--leader-election-id", "team-a.autovpa.containeroo.ch",
+			"--leader-election-lease-duration", "1m",
+			"--leader-election-renew-deadline", "40s",
+			"--leader-election-retry-period", "5s",
 			"--metrics-enabled=false",
 			"--metrics-secure=false",
+			"--metrics-cert-dir", "/certs",
+			"--metrics-cert-name", "cert.pem",
+			"--metrics-key-name", "key.pem",
+			"--metrics-path", "/autovpa/metrics",
+			"--otel-endpoint", "http://otel-collector:4317",
 			"--enable-http2=false",
+			"--enable-defaulting-webhook=true",
 			"--log-encoder", "console",
 			"--log-stacktrace-level", "info",
 			"--log-devel",
+			"--log-level", "debug",
+			"--unmanaged-workloads-interval", "30s",
+			"--managed-vpa-age-interval", "5m",
+			"--resync-period", "15m",
+			"--config-reload-interval", "20s",
+			"--container-policy-mode", "wildcard",
+			"--startup-reconcile-timeout", "10m",
+			"--apply-failure-threshold", "5",
+			"--max-vpas-per-namespace", "100",
+			"--managed-label-value", `{{ index .Labels "team" }}`,
+			"--graceful-shutdown-timeout", "2m",
+			"--default-update-mode", "Off",
+			"--default-recommender", "frugal",
+			"--default-controlled-resources", "CPU",
+			"--default-min-cpu", "10m",
+			"--default-min-memory", "32Mi",
+			"--default-max-memory", "8Gi",
+			"--owner-block-deletion=false",
+			"--obsolete-action", "release",
+			"--disable-obsolete-cleanup=true",
+			"--write-error-annotations=true",
+			"--namespace-default-profile=true",
+			"--empty-annotation-means-default=true",
+			"--profile-annotation-required=true",
+			"--create-only=true",
+			"--use-finalizers=true",
+			"--prune-on-startup=true",
+			"--respect-limitranges=true",
+			"--respect-resource-quota=true",
+			"--skip-if-hpa=true",
+			"--skip-if-within-bounds=true",
+			"--strict-name-templates=true",
+			"--mirror-recommendations=true",
+			"--disable-events=true",
+			"--force-update-mode-off=true",
+			"--downgrade-unsupported-update-mode=true",
+			"--enable-statefulsets=false",
+			"--enable-daemonsets=false",
+			"--enable-replicasets=true",
+			"--deployment-workers=4",
+			"--statefulset-workers=2",
+			"--daemonset-workers=3",
+			"--replicaset-workers=5",
+			"--vpa-api-group", "autoscaling.example.io",
+			"--vpa-api-version", "v1beta2",
+			"--field-manager", "autovpa-team-a",
+			"--watch-namespace-file", "/etc/autovpa/namespaces",
+			"--namespace-label-selector", "autovpa=enabled,tier notin (sandbox)",
+			"--propagate-tracking-annotations", "argocd.argoproj.io/tracking-id,kustomize.toolkit.fluxcd.io/name",
+			"--propagate-recommended-labels=true",
 		}
 
 		opts, err := ParseArgs(args, "0.0.0")
 
 		require.NoError(t, err)
-		assert.Equal(t, "custom.profile", opts.ProfileAnnotation)
+		assert.Equal(t, []string{"custom.profile"}, opts.ProfileAnnotations)
 		assert.Equal(t, "custom.managed", opts.ManagedLabel)
 		assert.Equal(t, false, opts.CRDCheck)
 		assert.Equal(t, "{{ .Namespace }}-{{ .WorkloadName }}", opts.DefaultNameTemplate)
 		assert.Equal(t, "/tmp/profiles.yaml", opts.ConfigPath)
+		assert.Equal(t, "json", opts.ConfigFormat)
 		assert.Equal(t, ":9090", opts.MetricsAddr)
 		assert.Equal(t, ":9091", opts.ProbeAddr)
 		assert.False(t, opts.LeaderElection)
+		assert.Equal(t, "team-a.autovpa.containeroo.ch", opts.LeaderElectionID)
+		assert.Equal(t, time.Minute, opts.LeaseDuration)
+		assert.Equal(t, 40*time.Second, opts.RenewDeadline)
+		assert.Equal(t, 5*time.Second, opts.RetryPeriod)
 		assert.False(t, opts.EnableMetrics)
 		assert.False(t, opts.SecureMetrics)
+		assert.Equal(t, "/certs", opts.MetricsCertDir)
+		assert.Equal(t, "cert.pem", opts.MetricsCertName)
+		assert.Equal(t, "key.pem", opts.MetricsKeyName)
+		assert.Equal(t, "/autovpa/metrics", opts.MetricsPath)
+		assert.Equal(t, "http://otel-collector:4317", opts.OTelEndpoint)
+		assert.True(t, opts.DefaultingWebhook)
 		assert.False(t, opts.EnableHTTP2)
 		assert.Equal(t, "console", opts.LogEncoder)
 		assert.Equal(t, "info", opts.LogStacktraceLevel)
 		assert.True(t, opts.LogDev)
+		assert.Equal(t, "debug", opts.LogLevel)
+		assert.Equal(t, 30*time.Second, opts.UnmanagedInterval)
+		assert.Equal(t, 5*time.Minute, opts.VPAAgeInterval)
+		assert.Equal(t, 15*time.Minute, opts.ResyncPeriod)
+		assert.Equal(t, 20*time.Second, opts.ConfigReloadInterval)
+		assert.Equal(t, "wildcard", opts.ContainerPolicyMode)
+		assert.Equal(t, 10*time.Minute, opts.StartupSyncTimeout)
+		assert.Equal(t, 5, opts.ApplyFailureThreshold)
+		assert.Equal(t, 100, opts.MaxVPAsPerNamespace)
+		assert.Equal(t, `{{ index .Labels "team" }}`, opts.ManagedLabelValue)
+		assert.Equal(t, 2*time.Minute, opts.ShutdownTimeout)
+		assert.Equal(t, "Off", opts.DefaultUpdateMode)
+		assert.Equal(t, "frugal", opts.DefaultRecommender)
+		assert.Equal(t, []corev1.ResourceName{corev1.ResourceCPU}, opts.DefaultControlledResources)
+		assert.Equal(t, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+		}, opts.DefaultMinAllowed)
+		assert.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")}, opts.DefaultMaxAllowed)
+		assert.False(t, opts.OwnerBlockDeletion)
+		assert.Equal(t, "release", opts.ObsoleteAction)
+		assert.True(t, opts.NoObsoleteCleanup)
+		assert.True(t, opts.ErrorAnnotations)
+		assert.True(t, opts.NamespaceDefaults)
+		assert.True(t, opts.EmptyMeansDefault)
+		assert.True(t, opts.WarnAnnotationTypos)
+		assert.True(t, opts.CreateOnly)
+		assert.True(t, opts.UseFinalizers)
+		assert.True(t, opts.PruneOnStartup)
+		assert.True(t, opts.RespectLimitRanges)
+		assert.True(t, opts.RespectResourceQuota)
+		assert.True(t, opts.SkipIfHPA)
+		assert.True(t, opts.SkipIfWithinBounds)
+		assert.True(t, opts.StrictNameTemplates)
+		assert.True(t, opts.MirrorRecommendations)
+		assert.True(t, opts.DisableEvents)
+		assert.True(t, opts.ForceUpdateModeOff)
+		assert.True(t, opts.DowngradeUpdateMode)
+		assert.True(t, opts.EnableDeployments)
+		assert.False(t, opts.EnableStatefulSets)
+		assert.False(t, opts.EnableDaemonSets)
+		assert.True(t, opts.EnableReplicaSets)
+		assert.Equal(t, 4, opts.DeploymentWorkers)
+		assert.Equal(t, 2, opts.StatefulSetWorkers)
+		assert.Equal(t, 3, opts.DaemonSetWorkers)
+		assert.Equal(t, 5, opts.ReplicaSetWorkers)
+		assert.Equal(t, "autoscaling.example.io", opts.VPAAPIGroup)
+		assert.Equal(t, "v1beta2", opts.VPAAPIVersion)
+		assert.Equal(t, "autovpa-team-a", opts.FieldManager)
+		assert.Equal(t, "/etc/autovpa/namespaces", opts.WatchNamespaceFile)
+		assert.Equal(t, "autovpa=enabled,tier notin (sandbox)", opts.NamespaceSelector)
+		assert.Equal(t, []string{"argocd.argoproj.io/tracking-id", "kustomize.toolkit.fluxcd.io/name"}, opts.TrackingAnnotations)
+		assert.True(t, opts.RecommendedLabels)
+	})
+
+	t.Run("Additional target kinds", func(t *testing.T) {
+		t.Parallel()
+
+		args := []string{
+			"--additional-target-kind", "argoproj.io/v1alpha1/Rollout",
+			"--additional-target-kind", "v1/Pod",
+		}
+
+		opts, err := ParseArgs(args, "0.0.0")
+
+		require.NoError(t, err)
+		assert.Equal(t, []schema.GroupVersionKind{
+			{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"},
+			{Version: "v1", Kind: "Pod"},
+		}, opts.AdditionalTargetKinds)
+	})
+
+	t.Run("Profile annotation priority list", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs([]string{"--profile-annotation", "autovpa.containeroo.ch/profile,legacy.example.com/profile"}, "0.0.0")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"autovpa.containeroo.ch/profile", "legacy.example.com/profile"}, opts.ProfileAnnotations)
+	})
+
+	t.Run("Empty profile annotation", func(t *testing.T) {
+		t.Parallel()
+
+		for _, raw := range []string{"", "a,,b"} {
+			_, err := ParseArgs([]string{"--profile-annotation", raw}, "0.0.0")
+			require.Error(t, err, raw)
+			assert.Contains(t, err.Error(), "--profile-annotation")
+		}
+	})
+
+	t.Run("No workload kind enabled", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{
+			"--enable-deployments=false",
+			"--enable-statefulsets=false",
+			"--enable-daemonsets=false",
+		}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "no workload kind enabled: set one of --enable-deployments, --enable-statefulsets, --enable-daemonsets, --enable-replicasets or --additional-target-kind")
+	})
+
+	t.Run("Only ReplicaSets enabled", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs([]string{
+			"--enable-deployments=false",
+			"--enable-statefulsets=false",
+			"--enable-daemonsets=false",
+			"--enable-replicasets=true",
+		}, "0.0.0")
+		require.NoError(t, err)
+		assert.True(t, opts.EnableReplicaSets)
+	})
+
+	t.Run("Reconcile only kinds", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs([]string{"--reconcile-only-kinds", "deployments,DaemonSet"}, "0.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"deployments", "DaemonSet"}, opts.ReconcileOnlyKinds)
+		assert.True(t, opts.EnableDeployments)
+		assert.False(t, opts.EnableStatefulSets)
+		assert.True(t, opts.EnableDaemonSets)
+	})
+
+	t.Run("Invalid reconcile only kinds", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--reconcile-only-kinds", "Deployment,CronJob"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, `invalid --reconcile-only-kinds "CronJob": must be Deployment, StatefulSet or DaemonSet`)
+	})
+
+	t.Run("Reconcile only kinds conflicts with enable flags", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--reconcile-only-kinds", "Deployment", "--enable-statefulsets=true"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--reconcile-only-kinds cannot be combined with --enable-statefulsets")
+	})
+
+	t.Run("Invalid default bounds", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--default-min-cpu", "a lot"}, "0.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid --default-min-cpu "a lot": `)
+
+		_, err = ParseArgs([]string{"--default-max-memory=-1Gi"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, `invalid --default-max-memory "-1Gi": must not be negative`)
+
+		_, err = ParseArgs([]string{"--default-min-cpu", "2", "--default-max-cpu", "500m"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--default-min-cpu 2 exceeds --default-max-cpu 500m")
+	})
+
+	t.Run("Development logging defaults to debug level", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs([]string{"--log-devel"}, "0.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, "debug", opts.LogLevel)
+
+		opts, err = ParseArgs([]string{"--log-devel", "--log-level", "info"}, "0.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, "info", opts.LogLevel)
+	})
+
+	t.Run("Invalid default controlled resources", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs([]string{"--default-controlled-resources", "memory, cpu"}, "0.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, []corev1.ResourceName{corev1.ResourceMemory, corev1.ResourceCPU}, opts.DefaultControlledResources)
+
+		_, err = ParseArgs([]string{"--default-controlled-resources", "cpu,storage"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, `invalid --default-controlled-resources "storage": must be cpu or memory`)
+
+		_, err = ParseArgs([]string{"--default-controlled-resources", "cpu,CPU"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, `invalid --default-controlled-resources: "CPU" listed more than once`)
+	})
+
+	t.Run("Invalid field manager", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--field-manager", " "}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--field-manager must not be empty")
+
+		_, err = ParseArgs([]string{"--field-manager", strings.Repeat("a", 129)}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--field-manager must be at most 128 characters")
+	})
+
+	t.Run("Invalid config format", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--config-format", "toml"}, "0.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--config-format")
+	})
+
+	t.Run("Invalid obsolete action", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--obsolete-action", "orphan"}, "0.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--obsolete-action")
+	})
+
+	t.Run("Empty leader election ID", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--leader-election-id", " "}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--leader-election-id must not be empty")
+	})
+
+	t.Run("Leader election timings out of order", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--leader-election-retry-period=0s"}, "0.0.0")
+		assert.EqualError(t, err, "--leader-election-retry-period must be positive")
+
+		_, err = ParseArgs([]string{"--leader-election-renew-deadline", "2s"}, "0.0.0")
+		assert.EqualError(t, err, "--leader-election-renew-deadline must be greater than --leader-election-retry-period")
+
+		_, err = ParseArgs([]string{"--leader-election-lease-duration", "10s"}, "0.0.0")
+		assert.EqualError(t, err, "--leader-election-lease-duration must be greater than --leader-election-renew-deadline")
+	})
+
+	t.Run("Relative metrics path", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--metrics-path", "metrics"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, `--metrics-path must start with "/"`)
+	})
+
+	t.Run("Invalid otel endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		for _, endpoint := range []string{"otel-collector:4317", "grpc://otel-collector:4317", "http://"} {
+			_, err := ParseArgs([]string{"--otel-endpoint", endpoint}, "0.0.0")
+			require.Error(t, err, endpoint)
+			assert.EqualError(t, err, fmt.Sprintf("--otel-endpoint must be an http:// or https:// URL, got %q", endpoint))
+		}
+	})
+
+	t.Run("Negative apply failure threshold", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--apply-failure-threshold=-1"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--apply-failure-threshold must not be negative")
+	})
+
+	t.Run("Invalid namespace label selector", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--namespace-label-selector", "autovpa in enabled"}, "0.0.0")
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `invalid --namespace-label-selector "autovpa in enabled"`)
+	})
+
+	t.Run("Workers below one", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--daemonset-workers=0"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--daemonset-workers must be at least 1")
+	})
+
+	t.Run("Negative max VPAs per namespace", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--max-vpas-per-namespace=-1"}, "0.0.0")
+		require.Error(t, err)
+		assert.EqualError(t, err, "--max-vpas-per-namespace must not be negative")
+	})
+
+	t.Run("Only additional target kinds", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs([]string{
+			"--enable-deployments=false",
+			"--enable-statefulsets=false",
+			"--enable-daemonsets=false",
+			"--additional-target-kind", "argoproj.io/v1alpha1/Rollout",
+		}, "0.0.0")
+		require.NoError(t, err)
+		assert.Len(t, opts.AdditionalTargetKinds, 1)
+	})
+
+	t.Run("Invalid resync period", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseArgs([]string{"--resync-period", "often"}, "0.0.0")
+		require.Error(t, err)
+	})
+
+	t.Run("Invalid additional target kind", func(t *testing.T) {
+		t.Parallel()
+
+		for _, raw := range []string{"Rollout", "argoproj.io/v1alpha1/", "a/b/c/Kind"} {
+			_, err := ParseArgs([]string{"--additional-target-kind", raw}, "0.0.0")
+			require.Error(t, err, raw)
+			assert.Contains(t, err.Error(), "invalid --additional-target-kind")
+		}
 	})
 
 	t.Run("Invalid flag", func(t *testing.T) {
@@ -231,3 +686,41 @@ func TestParseArgs(t *testing.T) {
 		assert.EqualError(t, err, "invalid value for flag --health-probe-bind-address: invalid TCP address \":invalid\": lookup tcp/invalid: unknown port")
 	})
 }
+
+func TestOptionsSummary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Defaults", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs(nil, "0.0.0")
+		require.NoError(t, err)
+
+		summary := opts.Summary()
+		assert.Equal(t, "config.yaml", summary["config"])
+		assert.Equal(t, DefaultNameTemplate, summary["vpa-name-template"])
+		assert.Equal(t, ":8443", summary["metrics-bind-address"])
+		assert.Equal(t, "0s", summary["resync-period"])
+		assert.Equal(t, "1m0s", summary["unmanaged-workloads-interval"])
+		assert.Equal(t, "1m0s", summary["managed-vpa-age-interval"])
+		assert.Equal(t, "info", summary["log-level"])
+		assert.Empty(t, summary["additional-target-kind"])
+		assert.NotContains(t, summary, "skip-manager-start")
+	})
+
+	t.Run("Renders namespaces and target kinds", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ParseArgs([]string{
+			"--watch-namespace", "ns1,ns2",
+			"--additional-target-kind", "argoproj.io/v1alpha1/Rollout",
+			"--disable-crd-check", "true",
+		}, "0.0.0")
+		require.NoError(t, err)
+
+		summary := opts.Summary()
+		assert.Equal(t, []string{"ns1", "ns2"}, summary["watch-namespace"])
+		assert.Equal(t, []string{"argoproj.io/v1alpha1/Rollout"}, summary["additional-target-kind"])
+		assert.Equal(t, false, summary["crd-check"])
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package logging
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/containeroo/autovpa/internal/flag"
@@ -33,31 +34,56 @@ const (
 	EncoderJSON    string = "json"
 	EncoderConsole string = "console"
 
+	LevelDebug string = "debug"
 	LevelInfo  string = "info"
 	LevelError string = "error"
 	LevelPanic string = "panic"
 )
 
 // InitLogging initializes logging based on provided configuration.
-func InitLogging(flags flag.Options, w io.Writer) logr.Logger {
-	logger := setupLogger(flags, w)
+func InitLogging(flags flag.Options, w io.Writer) (logr.Logger, error) {
+	logger, err := setupLogger(flags, w)
+	if err != nil {
+		return logr.Logger{}, err
+	}
 
 	log.SetLogger(logger)
 	klog.SetLogger(logger)
 
-	return logger
+	return logger, nil
 }
 
 // setupLogger configures and returns a logr.Logger based on given configuration.
-func setupLogger(flags flag.Options, w io.Writer) logr.Logger {
+func setupLogger(flags flag.Options, w io.Writer) (logr.Logger, error) {
+	level, err := logLevel(flags.LogLevel)
+	if err != nil {
+		return logr.Logger{}, err
+	}
+
 	opts := zap.Options{
 		Development:     flags.LogDev,
 		DestWriter:      w,
 		Encoder:         encoder(flags.LogEncoder),
+		Level:           level,
 		StacktraceLevel: stacktraceLevel(flags.LogStacktraceLevel),
 	}
 
-	return zap.New(zap.UseFlagOptions(&opts))
+	return zap.New(zap.UseFlagOptions(&opts)), nil
+}
+
+// logLevel returns the zap.AtomicLevel for the given name.
+// An empty name defaults to info; "debug" enables V(1) log lines.
+func logLevel(name string) (uzap.AtomicLevel, error) {
+	switch name {
+	case LevelDebug:
+		return uzap.NewAtomicLevelAt(uzap.DebugLevel), nil
+	case LevelInfo, "":
+		return uzap.NewAtomicLevelAt(uzap.InfoLevel), nil
+	case LevelError:
+		return uzap.NewAtomicLevelAt(uzap.ErrorLevel), nil
+	default:
+		return uzap.AtomicLevel{}, fmt.Errorf("invalid log level %q: must be one of %s, %s, %s", name, LevelError, LevelInfo, LevelDebug)
+	}
 }
 
 // encoder returns the appropriate zapcore.Encoder based on name.
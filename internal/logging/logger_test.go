@@ -25,6 +25,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	uzap "go.uber.org/zap"
 	zapcore "go.uber.org/zap/zapcore"
 )
@@ -42,7 +43,8 @@ func TestInitLogging(t *testing.T) {
 		}
 		var buf bytes.Buffer
 
-		logger := InitLogging(opts, &buf)
+		logger, err := InitLogging(opts, &buf)
+		require.NoError(t, err)
 		assert.NotEqual(t, logr.Logger{}, logger)
 	})
 
@@ -56,7 +58,8 @@ func TestInitLogging(t *testing.T) {
 		}
 		var buf bytes.Buffer
 
-		logger := InitLogging(opts, &buf)
+		logger, err := InitLogging(opts, &buf)
+		require.NoError(t, err)
 		assert.NotEqual(t, logr.Logger{}, logger)
 	})
 }
@@ -73,9 +76,112 @@ func TestSetupLogger(t *testing.T) {
 		}
 
 		var buf bytes.Buffer
-		logger := setupLogger(opts, &buf)
+		logger, err := setupLogger(opts, &buf)
+		require.NoError(t, err)
 		assert.NotEqual(t, logr.Logger{}, logger)
 	})
+
+	t.Run("Info level hides V(1)", func(t *testing.T) {
+		t.Parallel()
+		opts := flag.Options{
+			LogEncoder: "json",
+			LogLevel:   "info",
+		}
+
+		var buf bytes.Buffer
+		logger, err := setupLogger(opts, &buf)
+		require.NoError(t, err)
+
+		logger.Info("visible")
+		logger.V(1).Info("hidden")
+		assert.Contains(t, buf.String(), "visible")
+		assert.NotContains(t, buf.String(), "hidden")
+	})
+
+	t.Run("Debug level shows V(1) without devel mode", func(t *testing.T) {
+		t.Parallel()
+		opts := flag.Options{
+			LogEncoder: "json",
+			LogLevel:   "debug",
+		}
+
+		var buf bytes.Buffer
+		logger, err := setupLogger(opts, &buf)
+		require.NoError(t, err)
+
+		logger.V(1).Info("diff")
+		assert.Contains(t, buf.String(), "diff")
+	})
+
+	t.Run("Devel mode with debug level shows V(1)", func(t *testing.T) {
+		t.Parallel()
+		opts := flag.Options{
+			LogDev:     true,
+			LogEncoder: "console",
+			LogLevel:   "debug",
+		}
+
+		var buf bytes.Buffer
+		logger, err := setupLogger(opts, &buf)
+		require.NoError(t, err)
+
+		logger.V(1).Info("diff")
+		assert.Contains(t, buf.String(), "diff")
+	})
+
+	t.Run("Error level hides info", func(t *testing.T) {
+		t.Parallel()
+		opts := flag.Options{
+			LogEncoder: "json",
+			LogLevel:   "error",
+		}
+
+		var buf bytes.Buffer
+		logger, err := setupLogger(opts, &buf)
+		require.NoError(t, err)
+
+		logger.Info("hidden")
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("Invalid level", func(t *testing.T) {
+		t.Parallel()
+		opts := flag.Options{
+			LogEncoder: "json",
+			LogLevel:   "verbose",
+		}
+
+		var buf bytes.Buffer
+		_, err := setupLogger(opts, &buf)
+		assert.EqualError(t, err, `invalid log level "verbose": must be one of error, info, debug`)
+	})
+}
+
+func TestLogLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]zapcore.Level{
+		"debug": zapcore.DebugLevel,
+		"info":  zapcore.InfoLevel,
+		"":      zapcore.InfoLevel,
+		"error": zapcore.ErrorLevel,
+	}
+	for name, want := range tests {
+		t.Run("Level "+name, func(t *testing.T) {
+			t.Parallel()
+
+			level, err := logLevel(name)
+			require.NoError(t, err)
+			assert.Equal(t, want, level.Level())
+		})
+	}
+
+	t.Run("Invalid level", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := logLevel("trace")
+		assert.EqualError(t, err, `invalid log level "trace": must be one of error, info, debug`)
+	})
 }
 
 func TestEncoder(t *testing.T) {
@@ -16,7 +16,13 @@ limitations under the License.
 
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
 
 // Registry provides a typed façade for recording AutoVPA Prometheus metrics.
 type Registry struct {
@@ -29,8 +35,33 @@ type Registry struct {
 	vpaDeletedWorkloadGone *prometheus.CounterVec
 	vpaDeletedOwnerGone    *prometheus.CounterVec
 	vpaDeletedOrphaned     *prometheus.CounterVec
+	vpaDeletedMultiCtrl    *prometheus.CounterVec
+	vpaDeletedTargetRef    *prometheus.CounterVec
 	vpaManaged             *prometheus.GaugeVec
 	vpaReconcileErrors     *prometheus.CounterVec
+	vpaApplyConflicts      *prometheus.CounterVec
+	vpaOwnerUIDMismatch    *prometheus.CounterVec
+	profileChanges         *prometheus.CounterVec
+	workloadsUnmanaged     *prometheus.GaugeVec
+	profilePolicies        *prometheus.GaugeVec
+	vpaApplyNoop           *prometheus.CounterVec
+	circuitOpen            *prometheus.GaugeVec
+	vpaAge                 *prometheus.GaugeVec
+	vpaReleasedObsolete    *prometheus.CounterVec
+	buildInfo              *prometheus.GaugeVec
+	annotationTypos        *prometheus.CounterVec
+	predicateEvents        *prometheus.CounterVec
+	reconcileOutcomes      *prometheus.CounterVec
+	configReloads          *prometheus.CounterVec
+	configLastReload       prometheus.Gauge
+}
+
+// VPAAge is the age of one managed VPA, as published by SetManagedVPAAges.
+type VPAAge struct {
+	Namespace string
+	Name      string
+	Profile   string
+	Age       time.Duration
 }
 
 // NewRegistry creates and registers all AutoVPA metrics with the provided
@@ -104,6 +135,22 @@ func NewRegistry(reg prometheus.Registerer) *Registry {
 		[]string{"namespace"},
 	)
 
+	vpaDeletedMultiCtrl := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_vpa_deleted_multiple_controllers_total",
+			Help: "Total number of managed VPAs deleted because they had more than one controller owner reference.",
+		},
+		[]string{"namespace"},
+	)
+
+	vpaDeletedTargetRef := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_vpa_deleted_targetref_mismatch_total",
+			Help: "Total number of managed VPAs deleted because their targetRef kind did not match their controller owner's kind.",
+		},
+		[]string{"namespace", "kind"},
+	)
+
 	vpaManaged := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "autovpa_managed_vpa",
@@ -120,6 +167,125 @@ func NewRegistry(reg prometheus.Registerer) *Registry {
 		[]string{"controller", "kind", "reason"},
 	)
 
+	vpaApplyConflicts := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_vpa_apply_conflicts_total",
+			Help: "Total number of VPA applies that conflicted with fields owned by another field manager and were force-applied.",
+		},
+		[]string{"namespace", "kind"},
+	)
+
+	vpaOwnerUIDMismatch := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_vpa_owner_uid_mismatch_total",
+			Help: "Total number of managed VPAs whose owner reference matched a workload by name but not by UID.",
+		},
+		[]string{"namespace", "kind"},
+	)
+
+	profileChanges := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_profile_changes_total",
+			Help: "Total number of workloads that switched VPA profiles (old/new profile lists are comma-separated).",
+		},
+		[]string{"namespace", "kind", "old_profile", "new_profile"},
+	)
+
+	workloadsUnmanaged := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "autovpa_workloads_unmanaged",
+			Help: "Current number of workloads not getting a VPA, labeled by reason (recomputed periodically).",
+		},
+		[]string{"reason"},
+	)
+
+	profilePolicies := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "autovpa_profile_container_policies",
+			Help: "Number of container policies per profile in the loaded configuration.",
+		},
+		[]string{"profile"},
+	)
+
+	vpaApplyNoop := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_vpa_apply_noop_total",
+			Help: "Total number of VPA applies the API server accepted without changing the VPA.",
+		},
+		[]string{"namespace", "kind"},
+	)
+
+	circuitOpen := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "autovpa_circuit_open",
+			Help: "Whether a controller has paused VPA applies after consecutive apply failures (1 open, 0 closed).",
+		},
+		[]string{"controller"},
+	)
+
+	vpaAge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "autovpa_managed_vpa_age_seconds",
+			Help: "Time since each managed VPA was created, labeled by namespace, name, and profile (recomputed periodically).",
+		},
+		[]string{"namespace", "name", "profile"},
+	)
+
+	vpaReleasedObsolete := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_vpa_released_obsolete_total",
+			Help: "Total number of obsolete managed VPAs released (unlabeled and unowned) instead of deleted.",
+		},
+		[]string{"namespace", "kind"},
+	)
+
+	buildInfo := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "autovpa_build_info",
+			Help: "Build information of the running operator; always 1, labeled by version.",
+		},
+		[]string{"version"},
+	)
+
+	annotationTypos := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_profile_annotation_typos_total",
+			Help: "Total number of reconciles that found an annotation key resembling the profile annotation, labeled by namespace and kind.",
+		},
+		[]string{"namespace", "kind"},
+	)
+
+	predicateEvents := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_predicate_events_total",
+			Help: "Total number of watch events seen by controller predicates, labeled by controller, resource, event type and whether the event was admitted or filtered.",
+		},
+		[]string{"controller", "resource", "event", "result"},
+	)
+
+	reconcileOutcomes := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_reconcile_outcomes_total",
+			Help: "Total number of workload reconciles, labeled by kind and outcome (created, updated, deleted, noop, skipped:<reason>, error).",
+		},
+		[]string{"kind", "outcome"},
+	)
+
+	configReloads := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "autovpa_config_reloads_total",
+			Help: "Total number of profile configuration reloads, labeled by result (success or failure).",
+		},
+		[]string{"result"},
+	)
+
+	configLastReload := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "autovpa_config_last_reload_timestamp_seconds",
+			Help: "Unix time of the last successful profile configuration reload.",
+		},
+	)
+
 	reg.MustRegister(
 		vpaCreated,
 		vpaUpdated,
@@ -129,8 +295,25 @@ func NewRegistry(reg prometheus.Registerer) *Registry {
 		vpaDeletedWorkloadGone,
 		vpaDeletedOwnerGone,
 		vpaDeletedOrphaned,
+		vpaDeletedMultiCtrl,
+		vpaDeletedTargetRef,
 		vpaManaged,
 		vpaReconcileErrors,
+		vpaApplyConflicts,
+		vpaOwnerUIDMismatch,
+		profileChanges,
+		workloadsUnmanaged,
+		profilePolicies,
+		vpaApplyNoop,
+		circuitOpen,
+		vpaAge,
+		vpaReleasedObsolete,
+		buildInfo,
+		annotationTypos,
+		predicateEvents,
+		reconcileOutcomes,
+		configReloads,
+		configLastReload,
 	)
 
 	return &Registry{
@@ -143,11 +326,46 @@ func NewRegistry(reg prometheus.Registerer) *Registry {
 		vpaDeletedWorkloadGone: vpaDeletedWorkloadGone,
 		vpaDeletedOwnerGone:    vpaDeletedOwnerGone,
 		vpaDeletedOrphaned:     vpaDeletedOrphaned,
+		vpaDeletedMultiCtrl:    vpaDeletedMultiCtrl,
+		vpaDeletedTargetRef:    vpaDeletedTargetRef,
 		vpaManaged:             vpaManaged,
 		vpaReconcileErrors:     vpaReconcileErrors,
+		vpaApplyConflicts:      vpaApplyConflicts,
+		vpaOwnerUIDMismatch:    vpaOwnerUIDMismatch,
+		profileChanges:         profileChanges,
+		workloadsUnmanaged:     workloadsUnmanaged,
+		profilePolicies:        profilePolicies,
+		vpaApplyNoop:           vpaApplyNoop,
+		circuitOpen:            circuitOpen,
+		vpaAge:                 vpaAge,
+		vpaReleasedObsolete:    vpaReleasedObsolete,
+		buildInfo:              buildInfo,
+		annotationTypos:        annotationTypos,
+		predicateEvents:        predicateEvents,
+		reconcileOutcomes:      reconcileOutcomes,
+		configReloads:          configReloads,
+		configLastReload:       configLastReload,
 	}
 }
 
+var (
+	controllerRegistry     *Registry
+	controllerRegistryOnce sync.Once
+)
+
+// NewControllerRegistry registers all AutoVPA metrics with controller-runtime's
+// global registry. The manager's metrics server serves that registry, so the
+// AutoVPA metrics are exposed next to the controller and workqueue metrics
+// (reconcile totals, queue depth, adds, retries) on the same endpoint.
+// The global registry lives as long as the process, so the metrics are
+// registered once and later calls return the same Registry.
+func NewControllerRegistry() *Registry {
+	controllerRegistryOnce.Do(func() {
+		controllerRegistry = NewRegistry(crmetrics.Registry)
+	})
+	return controllerRegistry
+}
+
 // IncVPACreated increments the counter for created VPAs.
 func (r *Registry) IncVPACreated(namespace, name, kind, profile string) {
 	r.vpaCreated.WithLabelValues(namespace, name, kind, profile).Inc()
@@ -168,6 +386,11 @@ func (r *Registry) IncVPADeletedObsolete(namespace, kind string) {
 	r.vpaDeletedObsolete.WithLabelValues(namespace, kind).Inc()
 }
 
+// IncVPAReleasedObsolete increments the counter for obsolete VPAs released instead of deleted.
+func (r *Registry) IncVPAReleasedObsolete(namespace, kind string) {
+	r.vpaReleasedObsolete.WithLabelValues(namespace, kind).Inc()
+}
+
 // IncVPADeletedOptOut increments the counter for opt-out deletions.
 func (r *Registry) IncVPADeletedOptOut(namespace, kind string) {
 	r.vpaDeletedOptOut.WithLabelValues(namespace, kind).Inc()
@@ -188,6 +411,18 @@ func (r *Registry) IncVPADeletedOrphaned(namespace string) {
 	r.vpaDeletedOrphaned.WithLabelValues(namespace).Inc()
 }
 
+// IncVPADeletedMultipleControllers increments the counter for VPAs deleted
+// because they carried more than one controller ownerRef.
+func (r *Registry) IncVPADeletedMultipleControllers(namespace string) {
+	r.vpaDeletedMultiCtrl.WithLabelValues(namespace).Inc()
+}
+
+// IncVPADeletedTargetRefMismatch increments the counter for VPAs deleted
+// because their targetRef kind differed from their controller owner's kind.
+func (r *Registry) IncVPADeletedTargetRefMismatch(namespace, kind string) {
+	r.vpaDeletedTargetRef.WithLabelValues(namespace, kind).Inc()
+}
+
 // IncVPAManaged increments the gauge tracking managed VPAs.
 func (r *Registry) IncVPAManaged(namespace, profile string) {
 	r.vpaManaged.WithLabelValues(namespace, profile).Inc()
@@ -202,3 +437,91 @@ func (r *Registry) DecVPAManaged(namespace, profile string) {
 func (r *Registry) IncReconcileErrors(controller, kind, reason string) {
 	r.vpaReconcileErrors.WithLabelValues(controller, kind, reason).Inc()
 }
+
+// IncVPAApplyConflicts increments the counter for force-resolved apply conflicts.
+func (r *Registry) IncVPAApplyConflicts(namespace, kind string) {
+	r.vpaApplyConflicts.WithLabelValues(namespace, kind).Inc()
+}
+
+// IncVPAApplyNoop increments the counter for VPA applies that changed nothing.
+func (r *Registry) IncVPAApplyNoop(namespace, kind string) {
+	r.vpaApplyNoop.WithLabelValues(namespace, kind).Inc()
+}
+
+// IncVPAOwnerUIDMismatch increments the counter for VPAs owned by a previous
+// incarnation of a workload with the same name.
+func (r *Registry) IncVPAOwnerUIDMismatch(namespace, kind string) {
+	r.vpaOwnerUIDMismatch.WithLabelValues(namespace, kind).Inc()
+}
+
+// IncProfileChanges increments the counter for workloads switching profiles.
+func (r *Registry) IncProfileChanges(namespace, kind, oldProfile, newProfile string) {
+	r.profileChanges.WithLabelValues(namespace, kind, oldProfile, newProfile).Inc()
+}
+
+// SetWorkloadsUnmanaged replaces the unmanaged workloads gauge with the given counts per reason.
+func (r *Registry) SetWorkloadsUnmanaged(counts map[string]int) {
+	r.workloadsUnmanaged.Reset()
+	for reason, count := range counts {
+		r.workloadsUnmanaged.WithLabelValues(reason).Set(float64(count))
+	}
+}
+
+// SetProfileContainerPolicies replaces the container policies gauge with the given counts per profile.
+func (r *Registry) SetProfileContainerPolicies(counts map[string]int) {
+	r.profilePolicies.Reset()
+	for profile, count := range counts {
+		r.profilePolicies.WithLabelValues(profile).Set(float64(count))
+	}
+}
+
+// SetCircuitOpen records whether the controller's apply circuit is open.
+func (r *Registry) SetCircuitOpen(controller string, open bool) {
+	value := 0.0
+	if open {
+		value = 1
+	}
+	r.circuitOpen.WithLabelValues(controller).Set(value)
+}
+
+// SetManagedVPAAges replaces the managed VPA age gauge with the given ages,
+// so deleted VPAs drop out.
+func (r *Registry) SetManagedVPAAges(ages []VPAAge) {
+	r.vpaAge.Reset()
+	for _, a := range ages {
+		r.vpaAge.WithLabelValues(a.Namespace, a.Name, a.Profile).Set(a.Age.Seconds())
+	}
+}
+
+// SetBuildInfo publishes the operator version as the only build info series.
+func (r *Registry) SetBuildInfo(version string) {
+	r.buildInfo.Reset()
+	r.buildInfo.WithLabelValues(version).Set(1)
+}
+
+// IncProfileAnnotationTypo increments the counter for workloads carrying a
+// likely misspelling of the profile annotation.
+func (r *Registry) IncProfileAnnotationTypo(namespace, kind string) {
+	r.annotationTypos.WithLabelValues(namespace, kind).Inc()
+}
+
+// IncPredicateEvent increments the counter for a watch event evaluated by a
+// controller predicate. result is "admitted" or "filtered".
+func (r *Registry) IncPredicateEvent(controller, resource, event, result string) {
+	r.predicateEvents.WithLabelValues(controller, resource, event, result).Inc()
+}
+
+// IncReconcileOutcome increments the counter for a finished workload reconcile.
+func (r *Registry) IncReconcileOutcome(kind, outcome string) {
+	r.reconcileOutcomes.WithLabelValues(kind, outcome).Inc()
+}
+
+// IncConfigReloads increments the counter for configuration reloads with the given result.
+func (r *Registry) IncConfigReloads(result string) {
+	r.configReloads.WithLabelValues(result).Inc()
+}
+
+// SetConfigLastReload records the time of the last successful configuration reload.
+func (r *Registry) SetConfigLastReload(t time.Time) {
+	r.configLastReload.Set(float64(t.UnixNano()) / float64(time.Second))
+}
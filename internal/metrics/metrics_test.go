@@ -17,11 +17,19 @@ limitations under the License.
 package metrics
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func withIsolatedPrometheusRegistry(t *testing.T, fn func()) {
@@ -52,8 +60,25 @@ func resetAll(r *Registry) {
 	r.vpaDeletedWorkloadGone.Reset()
 	r.vpaDeletedOwnerGone.Reset()
 	r.vpaDeletedOrphaned.Reset()
+	r.vpaDeletedMultiCtrl.Reset()
+	r.vpaDeletedTargetRef.Reset()
 	r.vpaManaged.Reset()
 	r.vpaReconcileErrors.Reset()
+	r.vpaApplyConflicts.Reset()
+	r.vpaOwnerUIDMismatch.Reset()
+	r.profileChanges.Reset()
+	r.workloadsUnmanaged.Reset()
+	r.profilePolicies.Reset()
+	r.vpaApplyNoop.Reset()
+	r.circuitOpen.Reset()
+	r.vpaAge.Reset()
+	r.vpaReleasedObsolete.Reset()
+	r.buildInfo.Reset()
+	r.annotationTypos.Reset()
+	r.predicateEvents.Reset()
+	r.reconcileOutcomes.Reset()
+	r.configReloads.Reset()
+	r.configLastReload.Set(0)
 }
 
 func TestRegistryMetrics_AllMethods(t *testing.T) {
@@ -127,6 +152,22 @@ func TestRegistryMetrics_AllMethods(t *testing.T) {
 			assert.Equal(t, float64(1), val)
 		})
 
+		t.Run("IncVPADeletedMultipleControllers increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncVPADeletedMultipleControllers("ns1")
+			val := testutil.ToFloat64(r.vpaDeletedMultiCtrl.WithLabelValues("ns1"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncVPADeletedTargetRefMismatch increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncVPADeletedTargetRefMismatch("ns1", "StatefulSet")
+			val := testutil.ToFloat64(r.vpaDeletedTargetRef.WithLabelValues("ns1", "StatefulSet"))
+			assert.Equal(t, float64(1), val)
+		})
+
 		t.Run("IncVPAManaged increments gauge", func(t *testing.T) {
 			resetAll(r)
 
@@ -153,5 +194,197 @@ func TestRegistryMetrics_AllMethods(t *testing.T) {
 			val := testutil.ToFloat64(r.vpaReconcileErrors.WithLabelValues("autovpa", "Deployment", "api_error"))
 			assert.Equal(t, float64(1), val)
 		})
+
+		t.Run("IncVPAApplyConflicts increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncVPAApplyConflicts("ns1", "Deployment")
+			val := testutil.ToFloat64(r.vpaApplyConflicts.WithLabelValues("ns1", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncVPAApplyNoop increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncVPAApplyNoop("ns1", "Deployment")
+			val := testutil.ToFloat64(r.vpaApplyNoop.WithLabelValues("ns1", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("SetCircuitOpen sets", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetCircuitOpen("Deployment", true)
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.circuitOpen.WithLabelValues("Deployment")))
+
+			r.SetCircuitOpen("Deployment", false)
+			assert.Equal(t, float64(0), testutil.ToFloat64(r.circuitOpen.WithLabelValues("Deployment")))
+		})
+
+		t.Run("IncVPAReleasedObsolete increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncVPAReleasedObsolete("ns1", "Deployment")
+			val := testutil.ToFloat64(r.vpaReleasedObsolete.WithLabelValues("ns1", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("SetBuildInfo sets version", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetBuildInfo("v1.2.3")
+			expected := `
+# HELP autovpa_build_info Build information of the running operator; always 1, labeled by version.
+# TYPE autovpa_build_info gauge
+autovpa_build_info{version="v1.2.3"} 1
+`
+			require.NoError(t, testutil.CollectAndCompare(r.buildInfo, strings.NewReader(expected)))
+
+			r.SetBuildInfo("v1.2.4")
+			assert.Equal(t, 1, testutil.CollectAndCount(r.buildInfo))
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.buildInfo.WithLabelValues("v1.2.4")))
+		})
+
+		t.Run("IncProfileAnnotationTypo increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncProfileAnnotationTypo("ns1", "Deployment")
+			val := testutil.ToFloat64(r.annotationTypos.WithLabelValues("ns1", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncPredicateEvent increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncPredicateEvent("deployment", "Deployment", "update", "filtered")
+			r.IncPredicateEvent("deployment", "Deployment", "update", "filtered")
+			r.IncPredicateEvent("deployment", "Deployment", "update", "admitted")
+			assert.Equal(t, float64(2), testutil.ToFloat64(r.predicateEvents.WithLabelValues("deployment", "Deployment", "update", "filtered")))
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.predicateEvents.WithLabelValues("deployment", "Deployment", "update", "admitted")))
+		})
+
+		t.Run("IncReconcileOutcome increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncReconcileOutcome("Deployment", "skipped:hpa_conflict")
+			val := testutil.ToFloat64(r.reconcileOutcomes.WithLabelValues("Deployment", "skipped:hpa_conflict"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncConfigReloads increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncConfigReloads("success")
+			r.IncConfigReloads("failure")
+			r.IncConfigReloads("failure")
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.configReloads.WithLabelValues("success")))
+			assert.Equal(t, float64(2), testutil.ToFloat64(r.configReloads.WithLabelValues("failure")))
+		})
+
+		t.Run("SetConfigLastReload sets unix seconds", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetConfigLastReload(time.Unix(1700000000, 500000000))
+			assert.Equal(t, 1700000000.5, testutil.ToFloat64(r.configLastReload))
+		})
+
+		t.Run("SetManagedVPAAges replaces", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetManagedVPAAges([]VPAAge{
+				{Namespace: "ns1", Name: "a", Profile: "p1", Age: 90 * time.Second},
+				{Namespace: "ns1", Name: "b", Profile: "p2", Age: time.Hour},
+			})
+			assert.Equal(t, float64(90), testutil.ToFloat64(r.vpaAge.WithLabelValues("ns1", "a", "p1")))
+			assert.Equal(t, float64(3600), testutil.ToFloat64(r.vpaAge.WithLabelValues("ns1", "b", "p2")))
+
+			r.SetManagedVPAAges([]VPAAge{
+				{Namespace: "ns1", Name: "b", Profile: "p2", Age: 2 * time.Hour},
+			})
+			assert.Equal(t, 1, testutil.CollectAndCount(r.vpaAge))
+			assert.Equal(t, float64(7200), testutil.ToFloat64(r.vpaAge.WithLabelValues("ns1", "b", "p2")))
+		})
+
+		t.Run("IncVPAOwnerUIDMismatch increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncVPAOwnerUIDMismatch("ns1", "Deployment")
+			val := testutil.ToFloat64(r.vpaOwnerUIDMismatch.WithLabelValues("ns1", "Deployment"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("IncProfileChanges increments", func(t *testing.T) {
+			resetAll(r)
+
+			r.IncProfileChanges("ns1", "Deployment", "p1", "p2")
+			val := testutil.ToFloat64(r.profileChanges.WithLabelValues("ns1", "Deployment", "p1", "p2"))
+			assert.Equal(t, float64(1), val)
+		})
+
+		t.Run("SetWorkloadsUnmanaged replaces gauge values", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetWorkloadsUnmanaged(map[string]int{"annotation_missing": 3, "stale": 1})
+			r.SetWorkloadsUnmanaged(map[string]int{"annotation_missing": 2, "profile_missing": 1})
+
+			assert.Equal(t, float64(2), testutil.ToFloat64(r.workloadsUnmanaged.WithLabelValues("annotation_missing")))
+			assert.Equal(t, float64(1), testutil.ToFloat64(r.workloadsUnmanaged.WithLabelValues("profile_missing")))
+			assert.Equal(t, 2, testutil.CollectAndCount(r.workloadsUnmanaged))
+		})
+
+		t.Run("SetProfileContainerPolicies replaces gauge values", func(t *testing.T) {
+			resetAll(r)
+
+			r.SetProfileContainerPolicies(map[string]int{"p1": 1, "removed": 4})
+			r.SetProfileContainerPolicies(map[string]int{"p1": 3, "p2": 0})
+
+			assert.Equal(t, float64(3), testutil.ToFloat64(r.profilePolicies.WithLabelValues("p1")))
+			assert.Equal(t, float64(0), testutil.ToFloat64(r.profilePolicies.WithLabelValues("p2")))
+			assert.Equal(t, 2, testutil.CollectAndCount(r.profilePolicies))
+		})
+	})
+}
+
+func TestNewControllerRegistry(t *testing.T) {
+	t.Run("Exposes controller-runtime metric families", func(t *testing.T) {
+		r := NewControllerRegistry()
+		r.IncVPACreated("ns1", "demo", "Deployment", "p1")
+
+		// Starting a controller initialises its reconcile metrics and creates
+		// its named workqueue, just like the manager does for our reconcilers.
+		ctrl, err := controller.NewUnmanaged("metrics-test", controller.Options{
+			Reconciler: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			}),
+			SkipNameValidation: ptr.To(true),
+		})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+		require.NoError(t, ctrl.Start(ctx))
+
+		families, err := crmetrics.Registry.Gather()
+		require.NoError(t, err)
+
+		names := make(map[string]bool, len(families))
+		for _, mf := range families {
+			names[mf.GetName()] = true
+		}
+		for _, name := range []string{
+			"autovpa_vpa_created_total",
+			"controller_runtime_reconcile_total",
+			"controller_runtime_reconcile_errors_total",
+			"controller_runtime_max_concurrent_reconciles",
+			"controller_runtime_active_workers",
+			"workqueue_adds_total",
+			"workqueue_retries_total",
+		} {
+			assert.True(t, names[name], "missing metric family %q", name)
+		}
+	})
+
+	t.Run("Returns the same registry on every call", func(t *testing.T) {
+		assert.Same(t, NewControllerRegistry(), NewControllerRegistry())
 	})
 }
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Event types and results reported by Counted.
+const (
+	EventCreate  = "create"
+	EventUpdate  = "update"
+	EventDelete  = "delete"
+	EventGeneric = "generic"
+
+	ResultAdmitted = "admitted"
+	ResultFiltered = "filtered"
+)
+
+// EventCounter records the outcome of one predicate evaluation.
+type EventCounter interface {
+	IncPredicateEvent(controller, resource, event, result string)
+}
+
+// Counted wraps p so every evaluation is reported to counter, labeled with
+// the controller and watched resource, the event type and whether p admitted
+// or filtered the event. The decision of p is returned unchanged. A nil
+// counter returns p as is.
+func Counted(p predicate.Predicate, counter EventCounter, controller, resource string) predicate.Predicate {
+	if counter == nil {
+		return p
+	}
+
+	count := func(eventType string, admitted bool) bool {
+		result := ResultFiltered
+		if admitted {
+			result = ResultAdmitted
+		}
+		counter.IncPredicateEvent(controller, resource, eventType, result)
+		return admitted
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return count(EventCreate, p.Create(e))
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return count(EventUpdate, p.Update(e))
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return count(EventDelete, p.Delete(e))
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return count(EventGeneric, p.Generic(e))
+		},
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+type fakeEventCounter struct {
+	mu     sync.Mutex
+	counts map[[4]string]int
+}
+
+func (f *fakeEventCounter) IncPredicateEvent(controller, resource, event, result string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.counts == nil {
+		f.counts = map[[4]string]int{}
+	}
+	f.counts[[4]string{controller, resource, event, result}]++
+}
+
+func (f *fakeEventCounter) get(event, result string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts[[4]string{"deployment", "Deployment", event, result}]
+}
+
+func TestCounted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Counts admitted and filtered events per type", func(t *testing.T) {
+		t.Parallel()
+
+		counter := &fakeEventCounter{}
+		pred := Counted(ProfileAnnotationLifecycle([]string{"a"}), counter, "deployment", "Deployment")
+
+		objWith := &unstructured.Unstructured{}
+		objWith.SetAnnotations(map[string]string{"a": "b"})
+		objWithout := &unstructured.Unstructured{}
+
+		assert.True(t, pred.Create(event.CreateEvent{Object: objWith}))
+		assert.False(t, pred.Create(event.CreateEvent{Object: objWithout}))
+		assert.False(t, pred.Create(event.CreateEvent{Object: objWithout}))
+		assert.True(t, pred.Delete(event.DeleteEvent{Object: objWith}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: objWith}))
+
+		assert.Equal(t, 1, counter.get(EventCreate, ResultAdmitted))
+		assert.Equal(t, 2, counter.get(EventCreate, ResultFiltered))
+		assert.Equal(t, 1, counter.get(EventDelete, ResultAdmitted))
+		assert.Equal(t, 0, counter.get(EventDelete, ResultFiltered))
+		assert.Equal(t, 1, counter.get(EventGeneric, ResultFiltered))
+		assert.Equal(t, 0, counter.get(EventUpdate, ResultAdmitted))
+	})
+
+	t.Run("Update decisions are passed through", func(t *testing.T) {
+		t.Parallel()
+
+		counter := &fakeEventCounter{}
+		pred := Counted(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectNew.GetName() == "keep"
+			},
+		}, counter, "deployment", "Deployment")
+
+		keep := &unstructured.Unstructured{}
+		keep.SetName("keep")
+		drop := &unstructured.Unstructured{}
+		drop.SetName("drop")
+
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: keep, ObjectNew: keep}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: drop, ObjectNew: drop}))
+
+		assert.Equal(t, 1, counter.get(EventUpdate, ResultAdmitted))
+		assert.Equal(t, 1, counter.get(EventUpdate, ResultFiltered))
+	})
+
+	t.Run("Nil counter returns predicate unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		inner := predicate.GenerationChangedPredicate{}
+		assert.Equal(t, inner, Counted(inner, nil, "deployment", "Deployment"))
+	})
+}
@@ -17,6 +17,12 @@ limitations under the License.
 package predicates
 
 import (
+	"maps"
+	"slices"
+
+	"github.com/containeroo/autovpa/internal/utils"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -25,6 +31,10 @@ import (
 // relevant to the operator’s profile annotation on workload resources
 // (Deployments, StatefulSets, DaemonSets).
 //
+// annotations lists the profile annotation keys in priority order; the value
+// of the first key set on the workload is the profile, so a workload can be
+// migrated from an old key to a new one without being reconciled in between.
+//
 // This predicate determines *when a workload should be reconciled* based on
 // opt-in semantics controlled via an annotation.
 //
@@ -33,20 +43,24 @@ import (
 //     (annotation present and non-empty).
 //   - Update: enqueue if:
 //   - opt-in was added or removed,
-//   - the profile value changed, or
+//   - the resolved profile value changed (including a switch of the winning key
+//     to one with a different value),
+//   - any of the given extra annotations changed while opted-in,
+//   - the event is a periodic cache resync (unchanged resourceVersion), or
 //   - deletion has just started (for cleanup).
 //   - Delete: enqueue only if the workload was opted-in, so managed VPAs
 //     can be cleaned up.
 //   - Generic: disabled to avoid noisy resyncs.
-func ProfileAnnotationLifecycle(annotation string) predicate.Predicate {
+func ProfileAnnotationLifecycle(annotations []string, extraKeys ...string) predicate.Predicate {
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			return hasNonEmptyAnnotation(e.Object, annotation)
+			_, has := firstAnnotationValue(e.Object, annotations)
+			return has
 		},
 
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			oldVal, oldHas := annotationValue(e.ObjectOld, annotation)
-			newVal, newHas := annotationValue(e.ObjectNew, annotation)
+			oldVal, oldHas := firstAnnotationValue(e.ObjectOld, annotations)
+			newVal, newHas := firstAnnotationValue(e.ObjectNew, annotations)
 
 			// Opt-in added or removed.
 			if oldHas != newHas {
@@ -63,6 +77,16 @@ func ProfileAnnotationLifecycle(annotation string) predicate.Predicate {
 				return true
 			}
 
+			// Propagated annotation changed (e.g. GitOps tracking id).
+			if annotationsChanged(e.ObjectOld, e.ObjectNew, extraKeys...) {
+				return true
+			}
+
+			// Periodic resync (--resync-period) → reconcile as a safety net.
+			if isResync(e.ObjectOld, e.ObjectNew) {
+				return true
+			}
+
 			// Deletion started → allow cleanup.
 			if deletionJustStarted(e.ObjectOld, e.ObjectNew) {
 				return true
@@ -72,7 +96,8 @@ func ProfileAnnotationLifecycle(annotation string) predicate.Predicate {
 		},
 
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			return hasNonEmptyAnnotation(e.Object, annotation)
+			_, has := firstAnnotationValue(e.Object, annotations)
+			return has
 		},
 
 		GenericFunc: func(event.GenericEvent) bool {
@@ -90,22 +115,23 @@ func ProfileAnnotationLifecycle(annotation string) predicate.Predicate {
 // It deliberately ignores spec, label drift, and status churn.
 //
 // Semantics:
-//   - Create: enqueue only if the VPA is managed (label == "true").
+//   - Create: enqueue only if the VPA is managed (label == managedValue, or
+//     present at all when managedValue is empty).
 //   - Update: enqueue if:
 //   - managed label toggled,
 //   - deletion started, or
 //   - controller ownerRef changed.
 //   - Delete: enqueue only if the deleted VPA was managed.
 //   - Generic: disabled to avoid noisy resyncs.
-func ManagedVPAStructuralLifecycle(managedLabel string) predicate.Predicate {
+func ManagedVPAStructuralLifecycle(managedLabel, managedValue string) predicate.Predicate {
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			return hasTrueLabel(e.Object, managedLabel)
+			return hasManagedLabel(e.Object, managedLabel, managedValue)
 		},
 
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			oldHas := hasTrueLabel(e.ObjectOld, managedLabel)
-			newHas := hasTrueLabel(e.ObjectNew, managedLabel)
+			oldHas := hasManagedLabel(e.ObjectOld, managedLabel, managedValue)
+			newHas := hasManagedLabel(e.ObjectNew, managedLabel, managedValue)
 
 			// Managed label toggled.
 			if oldHas != newHas {
@@ -131,7 +157,7 @@ func ManagedVPAStructuralLifecycle(managedLabel string) predicate.Predicate {
 		},
 
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			return hasTrueLabel(e.Object, managedLabel)
+			return hasManagedLabel(e.Object, managedLabel, managedValue)
 		},
 
 		GenericFunc: func(event.GenericEvent) bool {
@@ -154,19 +180,20 @@ func ManagedVPAStructuralLifecycle(managedLabel string) predicate.Predicate {
 //   - managed label toggled,
 //   - deletion started,
 //   - controller ownerRef changed,
-//   - operator-owned labels changed (managed/profile), or
+//   - operator-owned labels changed (managed/profile),
+//   - any of the given operator annotations changed, or
 //   - spec changed.
 //   - Delete: enqueue only if the deleted VPA was managed.
 //   - Generic: disabled to avoid noisy resyncs.
-func ManagedVPALifecycle(managedLabel, profileKey string) predicate.Predicate {
+func ManagedVPALifecycle(managedLabel, managedValue, profileKey string, annotationKeys ...string) predicate.Predicate {
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			return hasTrueLabel(e.Object, managedLabel)
+			return hasManagedLabel(e.Object, managedLabel, managedValue)
 		},
 
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			oldHas := hasTrueLabel(e.ObjectOld, managedLabel)
-			newHas := hasTrueLabel(e.ObjectNew, managedLabel)
+			oldHas := hasManagedLabel(e.ObjectOld, managedLabel, managedValue)
+			newHas := hasManagedLabel(e.ObjectNew, managedLabel, managedValue)
 
 			// Managed label toggled.
 			if oldHas != newHas {
@@ -193,6 +220,11 @@ func ManagedVPALifecycle(managedLabel, profileKey string) predicate.Predicate {
 				return true
 			}
 
+			// Operator annotation toggled (e.g. spec-authoritative removed).
+			if annotationsChanged(e.ObjectOld, e.ObjectNew, annotationKeys...) {
+				return true
+			}
+
 			// Spec drift.
 			if specChanged(e.ObjectOld, e.ObjectNew) {
 				return true
@@ -202,9 +234,243 @@ func ManagedVPALifecycle(managedLabel, profileKey string) predicate.Predicate {
 		},
 
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			return hasTrueLabel(e.Object, managedLabel)
+			return hasManagedLabel(e.Object, managedLabel, managedValue)
+		},
+
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// AnnotationValueChanged returns a predicate that reacts when the given
+// annotation is set or its value changes, e.g. a Namespace's default profile.
+//
+// Semantics:
+//   - Create: enqueue only if the annotation is present and non-empty.
+//   - Update: enqueue if the annotation value changed (including add/remove).
+//   - Delete: disabled; deleting the object removes its dependents anyway.
+//   - Generic: disabled to avoid noisy resyncs.
+func AnnotationValueChanged(annotation string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return hasNonEmptyAnnotation(e.Object, annotation)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldVal, _ := annotationValue(e.ObjectOld, annotation)
+			newVal, _ := annotationValue(e.ObjectNew, annotation)
+			return oldVal != newVal
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// AnnotationPresent returns a predicate that reacts to objects carrying any
+// of the given annotation keys, whatever its value. Combine it with
+// ProfileAnnotationLifecycle when an empty value still means opted in.
+//
+// Semantics:
+//   - Create: enqueue if any annotation key is present (even if empty).
+//   - Update: enqueue if the object gained its first or lost its last key.
+//   - Delete: enqueue if any annotation key is present, so VPAs can be cleaned up.
+//   - Generic: disabled to avoid noisy resyncs.
+func AnnotationPresent(annotations ...string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return hasAnyAnnotationKey(e.Object, annotations)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return hasAnyAnnotationKey(e.ObjectOld, annotations) != hasAnyAnnotationKey(e.ObjectNew, annotations)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return hasAnyAnnotationKey(e.Object, annotations)
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// AnnotationKeyNearMiss returns a predicate that reacts to objects carrying an
+// annotation key within maxDistance edits of one of keys without matching it,
+// so likely misspellings of the profile annotation reach the reconciler.
+//
+// Semantics:
+//   - Create: enqueue if any near-miss key is present.
+//   - Update: enqueue if the set of near-miss keys changed and is not empty.
+//   - Delete/Generic: disabled; a misspelled key never created a VPA.
+func AnnotationKeyNearMiss(keys []string, maxDistance int) predicate.Predicate {
+	nearMisses := func(annotations map[string]string) []string {
+		return utils.NearMisses(annotations, keys, maxDistance)
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return len(nearMisses(e.Object.GetAnnotations())) > 0
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			newKeys := nearMisses(e.ObjectNew.GetAnnotations())
+			return len(newKeys) > 0 && !slices.Equal(nearMisses(e.ObjectOld.GetAnnotations()), newKeys)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// ContainerSetChanged returns a predicate that reacts when an opted-in
+// workload's pod template gains, loses or renames a container, so VPAs derived
+// from the container set are rendered again. Image, resource and pod template
+// annotation changes (e.g. from `kubectl rollout restart`) do not count.
+//
+// Semantics:
+//   - Update: enqueue if any of the annotation keys is set on the new object
+//     and the container names (including init containers) differ.
+//   - Create/Delete/Generic: disabled; ProfileAnnotationLifecycle covers them.
+func ContainerSetChanged(annotations ...string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !hasAnyAnnotationKey(e.ObjectNew, annotations) {
+				return false
+			}
+			return !slices.Equal(containerNames(e.ObjectOld), containerNames(e.ObjectNew))
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// AnyCreate returns a predicate that reacts to every create event and
+// ignores all other events. Combine it with predicate.Or to additionally
+// reconcile objects as soon as they appear.
+func AnyCreate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(event.UpdateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// LabelsChanged returns a predicate that reacts only to updates changing the
+// object's labels and ignores all other events.
+func LabelsChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return !maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// NotControlledBy returns a predicate that ignores objects whose controller
+// ownerRef points at kind, e.g. ReplicaSets managed by a Deployment.
+//
+// Semantics:
+//   - Create/Delete/Generic: allow only if the object is not controlled by kind.
+//   - Update: allow only if the new object is not controlled by kind.
+func NotControlledBy(kind string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return !controlledBy(obj, kind)
+	})
+}
+
+// ControllerOwnerChanged returns a predicate that reacts only to updates
+// adding, removing or replacing the object's controller ownerRef, e.g. when
+// a Deployment adopts an orphaned ReplicaSet.
+func ControllerOwnerChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return controllerOwnerRefChanged(e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// IgnoreAnnotationOnlyUpdates returns a predicate that drops updates whose
+// only change is to the given annotation keys, e.g. annotations the operator
+// writes onto the objects it watches. Combine it with predicate.And so writing
+// such an annotation can never requeue the object.
+//
+// Semantics:
+//   - Update: drop if one of the keys changed while the generation, the labels
+//     and all other annotations stayed the same; allow otherwise.
+//   - Create/Delete/Generic: allowed; the combined predicate decides.
+func IgnoreAnnotationOnlyUpdates(keys ...string) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+			return !onlyAnnotationsChanged(e.ObjectOld, e.ObjectNew, keys...)
 		},
+	}
+}
 
+// ManagedVPARecommendationChanged returns a predicate that reacts when the
+// status recommendation of a managed VPA changes. It complements
+// ManagedVPAStructuralLifecycle when recommendations are mirrored onto workloads.
+//
+// Semantics:
+//   - Create: enqueue only if the VPA is managed and already has a recommendation.
+//   - Update: enqueue if the VPA is managed and status.recommendation changed.
+//   - Delete: disabled; there is nothing left to mirror.
+//   - Generic: disabled to avoid noisy resyncs.
+func ManagedVPARecommendationChanged(managedLabel, managedValue string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return hasManagedLabel(e.Object, managedLabel, managedValue) && hasRecommendation(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return hasManagedLabel(e.ObjectNew, managedLabel, managedValue) && recommendationChanged(e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(event.DeleteEvent) bool {
+			return false
+		},
 		GenericFunc: func(event.GenericEvent) bool {
 			return false
 		},
@@ -21,6 +21,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -29,7 +31,7 @@ import (
 func TestProfileAnnotationLifecycle(t *testing.T) {
 	t.Parallel()
 
-	pred := ProfileAnnotationLifecycle("a")
+	pred := ProfileAnnotationLifecycle([]string{"a"})
 
 	objWith := &unstructured.Unstructured{}
 	objWith.SetAnnotations(map[string]string{"a": "b"})
@@ -86,6 +88,25 @@ func TestProfileAnnotationLifecycle(t *testing.T) {
 		assert.False(t, pred.Update(e))
 	})
 
+	t.Run("Update allowed on periodic resync while opted-in", func(t *testing.T) {
+		t.Parallel()
+		oldObj := &unstructured.Unstructured{}
+		oldObj.SetAnnotations(map[string]string{"a": "b"})
+		oldObj.SetResourceVersion("42")
+
+		e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: oldObj.DeepCopy()}
+		assert.True(t, pred.Update(e))
+	})
+
+	t.Run("Update denied on periodic resync while opted-out", func(t *testing.T) {
+		t.Parallel()
+		oldObj := &unstructured.Unstructured{}
+		oldObj.SetResourceVersion("42")
+
+		e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: oldObj.DeepCopy()}
+		assert.False(t, pred.Update(e))
+	})
+
 	t.Run("Update allowed when deletion just started", func(t *testing.T) {
 		t.Parallel()
 		oldObj := &unstructured.Unstructured{}
@@ -99,6 +120,32 @@ func TestProfileAnnotationLifecycle(t *testing.T) {
 		assert.True(t, pred.Update(e))
 	})
 
+	t.Run("Update allowed when extra annotation changes while opted-in", func(t *testing.T) {
+		t.Parallel()
+		pred := ProfileAnnotationLifecycle([]string{"a"}, "track")
+
+		oldObj := &unstructured.Unstructured{}
+		oldObj.SetAnnotations(map[string]string{"a": "b", "track": "1"})
+		newObj := oldObj.DeepCopy()
+		newObj.SetAnnotations(map[string]string{"a": "b", "track": "2"})
+
+		e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}
+		assert.True(t, pred.Update(e))
+	})
+
+	t.Run("Update denied when extra annotation changes while opted-out", func(t *testing.T) {
+		t.Parallel()
+		pred := ProfileAnnotationLifecycle([]string{"a"}, "track")
+
+		oldObj := &unstructured.Unstructured{}
+		oldObj.SetAnnotations(map[string]string{"track": "1"})
+		newObj := oldObj.DeepCopy()
+		newObj.SetAnnotations(map[string]string{"track": "2"})
+
+		e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}
+		assert.False(t, pred.Update(e))
+	})
+
 	t.Run("Delete allowed when annotation exists and non-empty", func(t *testing.T) {
 		t.Parallel()
 		e := event.DeleteEvent{Object: objWith}
@@ -118,10 +165,76 @@ func TestProfileAnnotationLifecycle(t *testing.T) {
 	})
 }
 
+func TestProfileAnnotationLifecycle_PriorityKeys(t *testing.T) {
+	t.Parallel()
+
+	pred := ProfileAnnotationLifecycle([]string{"new", "old"})
+
+	withAnnotations := func(annotations map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+
+	t.Run("Create allowed for legacy key only", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Create(event.CreateEvent{Object: withAnnotations(map[string]string{"old": "gold"})}))
+	})
+
+	t.Run("Create denied when winning key is empty", func(t *testing.T) {
+		t.Parallel()
+		obj := withAnnotations(map[string]string{"new": "", "old": "gold"})
+		assert.False(t, pred.Create(event.CreateEvent{Object: obj}))
+	})
+
+	t.Run("Update denied when migrating to the new key with the same profile", func(t *testing.T) {
+		t.Parallel()
+		oldObj := withAnnotations(map[string]string{"old": "gold"})
+		both := withAnnotations(map[string]string{"old": "gold", "new": "gold"})
+		migrated := withAnnotations(map[string]string{"new": "gold"})
+
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: both}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: both, ObjectNew: migrated}))
+	})
+
+	t.Run("Update allowed when the new key overrides the legacy profile", func(t *testing.T) {
+		t.Parallel()
+		oldObj := withAnnotations(map[string]string{"old": "gold"})
+		newObj := withAnnotations(map[string]string{"old": "gold", "new": "silver"})
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}))
+	})
+
+	t.Run("Update allowed when the legacy key changes without the new key", func(t *testing.T) {
+		t.Parallel()
+		oldObj := withAnnotations(map[string]string{"old": "gold"})
+		newObj := withAnnotations(map[string]string{"old": "silver"})
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}))
+	})
+
+	t.Run("Update denied when the shadowed legacy key changes", func(t *testing.T) {
+		t.Parallel()
+		oldObj := withAnnotations(map[string]string{"new": "gold", "old": "gold"})
+		newObj := withAnnotations(map[string]string{"new": "gold", "old": "silver"})
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}))
+	})
+
+	t.Run("Update allowed when the last key is removed", func(t *testing.T) {
+		t.Parallel()
+		oldObj := withAnnotations(map[string]string{"old": "gold"})
+		newObj := withAnnotations(nil)
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}))
+	})
+
+	t.Run("Delete allowed for legacy key only", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Delete(event.DeleteEvent{Object: withAnnotations(map[string]string{"old": "gold"})}))
+	})
+}
+
 func TestManagedVPAStructuralLifecycle(t *testing.T) {
 	t.Parallel()
 
-	pred := ManagedVPAStructuralLifecycle("m")
+	pred := ManagedVPAStructuralLifecycle("m", "true")
 
 	objManaged := &unstructured.Unstructured{}
 	objManaged.SetLabels(map[string]string{"m": "true"})
@@ -220,7 +333,7 @@ func TestManagedVPAStructuralLifecycle(t *testing.T) {
 func TestManagedVPALifecycle(t *testing.T) {
 	t.Parallel()
 
-	pred := ManagedVPALifecycle("m", "k")
+	pred := ManagedVPALifecycle("m", "true", "k", "a")
 
 	t.Run("Update allowed when spec changes on managed VPA", func(t *testing.T) {
 		t.Parallel()
@@ -272,4 +385,499 @@ func TestManagedVPALifecycle(t *testing.T) {
 		e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}
 		assert.False(t, pred.Update(e))
 	})
+	t.Run("Update allowed when operator annotation changes on managed VPA", func(t *testing.T) {
+		t.Parallel()
+
+		oldObj := &unstructured.Unstructured{}
+		oldObj.SetLabels(map[string]string{"m": "true"})
+		oldObj.SetAnnotations(map[string]string{"a": "true"})
+
+		newObj := oldObj.DeepCopy()
+		newObj.SetAnnotations(nil)
+
+		e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}
+		assert.True(t, pred.Update(e))
+	})
+
+	t.Run("Update denied when unrelated annotation changes on managed VPA", func(t *testing.T) {
+		t.Parallel()
+
+		oldObj := &unstructured.Unstructured{}
+		oldObj.SetLabels(map[string]string{"m": "true"})
+
+		newObj := oldObj.DeepCopy()
+		newObj.SetAnnotations(map[string]string{"other": "x"})
+
+		e := event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}
+		assert.False(t, pred.Update(e))
+	})
+}
+
+func TestAnnotationValueChanged(t *testing.T) {
+	t.Parallel()
+
+	pred := AnnotationValueChanged("a")
+
+	objWith := &unstructured.Unstructured{}
+	objWith.SetAnnotations(map[string]string{"a": "b"})
+
+	objWithDifferent := &unstructured.Unstructured{}
+	objWithDifferent.SetAnnotations(map[string]string{"a": "c"})
+
+	objWithout := &unstructured.Unstructured{}
+
+	t.Run("Create allowed when annotation set", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Create(event.CreateEvent{Object: objWith}))
+	})
+
+	t.Run("Create denied when annotation missing", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Create(event.CreateEvent{Object: objWithout}))
+	})
+
+	t.Run("Update allowed when value added, changed, or removed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: objWithout, ObjectNew: objWith}))
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: objWith, ObjectNew: objWithDifferent}))
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: objWith, ObjectNew: objWithout}))
+	})
+
+	t.Run("Update denied when value unchanged", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: objWith, ObjectNew: objWith}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: objWithout, ObjectNew: objWithout}))
+	})
+
+	t.Run("Delete and Generic denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: objWith}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: objWith}))
+	})
+}
+
+func TestAnnotationPresent(t *testing.T) {
+	t.Parallel()
+
+	pred := AnnotationPresent("a")
+
+	objEmpty := &unstructured.Unstructured{}
+	objEmpty.SetAnnotations(map[string]string{"a": ""})
+
+	objWithout := &unstructured.Unstructured{}
+
+	t.Run("Create and Delete allowed when annotation present, even if empty", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Create(event.CreateEvent{Object: objEmpty}))
+		assert.True(t, pred.Delete(event.DeleteEvent{Object: objEmpty}))
+	})
+
+	t.Run("Create and Delete denied when annotation missing", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Create(event.CreateEvent{Object: objWithout}))
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: objWithout}))
+	})
+
+	t.Run("Update allowed when annotation added or removed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: objWithout, ObjectNew: objEmpty}))
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: objEmpty, ObjectNew: objWithout}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: objEmpty, ObjectNew: objEmpty}))
+	})
+
+	t.Run("Any of several keys counts as present", func(t *testing.T) {
+		t.Parallel()
+		pred := AnnotationPresent("new", "old")
+
+		objOld := &unstructured.Unstructured{}
+		objOld.SetAnnotations(map[string]string{"old": ""})
+		objBoth := &unstructured.Unstructured{}
+		objBoth.SetAnnotations(map[string]string{"old": "", "new": ""})
+
+		assert.True(t, pred.Create(event.CreateEvent{Object: objOld}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: objOld, ObjectNew: objBoth}))
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: objOld, ObjectNew: objWithout}))
+	})
+
+	t.Run("Generic denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Generic(event.GenericEvent{Object: objEmpty}))
+	})
+}
+
+func TestAnnotationKeyNearMiss(t *testing.T) {
+	t.Parallel()
+
+	pred := AnnotationKeyNearMiss([]string{"vpa/profile"}, 2)
+
+	objTypo := &unstructured.Unstructured{}
+	objTypo.SetAnnotations(map[string]string{"vpa/profle": "default"})
+
+	objExact := &unstructured.Unstructured{}
+	objExact.SetAnnotations(map[string]string{"vpa/profile": "default"})
+
+	objWithout := &unstructured.Unstructured{}
+
+	t.Run("Create allowed only for near-miss keys", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Create(event.CreateEvent{Object: objTypo}))
+		assert.False(t, pred.Create(event.CreateEvent{Object: objExact}))
+		assert.False(t, pred.Create(event.CreateEvent{Object: objWithout}))
+	})
+
+	t.Run("Update allowed when a near-miss key appears", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: objWithout, ObjectNew: objTypo}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: objTypo, ObjectNew: objTypo}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: objTypo, ObjectNew: objExact}))
+	})
+
+	t.Run("Delete and Generic denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: objTypo}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: objTypo}))
+	})
+}
+
+func TestAnyCreate(t *testing.T) {
+	t.Parallel()
+
+	pred := AnyCreate()
+	obj := &unstructured.Unstructured{}
+
+	t.Run("Create allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Create(event.CreateEvent{Object: obj}))
+	})
+
+	t.Run("Other events denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}))
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: obj}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: obj}))
+	})
+}
+
+func TestLabelsChanged(t *testing.T) {
+	t.Parallel()
+
+	pred := LabelsChanged()
+	newObj := func(labels map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{}}
+		u.SetLabels(labels)
+		return u
+	}
+
+	t.Run("Update with changed labels allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(map[string]string{"team": "a"}),
+			ObjectNew: newObj(map[string]string{"team": "b"}),
+		}))
+	})
+
+	t.Run("Update with equal labels denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(map[string]string{"team": "a"}),
+			ObjectNew: newObj(map[string]string{"team": "a"}),
+		}))
+	})
+
+	t.Run("Other events denied", func(t *testing.T) {
+		t.Parallel()
+		obj := newObj(nil)
+		assert.False(t, pred.Create(event.CreateEvent{Object: obj}))
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: obj}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: obj}))
+	})
+}
+
+func TestNotControlledBy(t *testing.T) {
+	t.Parallel()
+
+	pred := NotControlledBy("Deployment")
+	newObj := func(ownerKind string) *appsv1.ReplicaSet {
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs"}}
+		if ownerKind != "" {
+			tval := true
+			rs.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: "owner", Controller: &tval}}
+		}
+		return rs
+	}
+
+	t.Run("Standalone object allowed", func(t *testing.T) {
+		t.Parallel()
+		obj := newObj("")
+		assert.True(t, pred.Create(event.CreateEvent{Object: obj}))
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}))
+		assert.True(t, pred.Delete(event.DeleteEvent{Object: obj}))
+		assert.True(t, pred.Generic(event.GenericEvent{Object: obj}))
+	})
+
+	t.Run("Object controlled by another kind allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Create(event.CreateEvent{Object: newObj("Rollout")}))
+	})
+
+	t.Run("Object controlled by kind denied", func(t *testing.T) {
+		t.Parallel()
+		obj := newObj("Deployment")
+		assert.False(t, pred.Create(event.CreateEvent{Object: obj}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: newObj(""), ObjectNew: obj}))
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: obj}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: obj}))
+	})
+}
+
+func TestControllerOwnerChanged(t *testing.T) {
+	t.Parallel()
+
+	pred := ControllerOwnerChanged()
+	newObj := func(ownerName string) *appsv1.ReplicaSet {
+		rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs"}}
+		if ownerName != "" {
+			tval := true
+			rs.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: ownerName, Controller: &tval}}
+		}
+		return rs
+	}
+
+	t.Run("Update adding a controller allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: newObj(""), ObjectNew: newObj("app")}))
+	})
+
+	t.Run("Update removing a controller allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: newObj("app"), ObjectNew: newObj("")}))
+	})
+
+	t.Run("Update keeping the controller denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: newObj("app"), ObjectNew: newObj("app")}))
+	})
+
+	t.Run("Other events denied", func(t *testing.T) {
+		t.Parallel()
+		obj := newObj("app")
+		assert.False(t, pred.Create(event.CreateEvent{Object: obj}))
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: obj}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: obj}))
+	})
+}
+
+func TestIgnoreAnnotationOnlyUpdates(t *testing.T) {
+	t.Parallel()
+
+	const key = "autovpa/last-error"
+	pred := IgnoreAnnotationOnlyUpdates(key)
+	newObj := func(generation int64, labels, annotations map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{}}
+		u.SetGeneration(generation)
+		u.SetLabels(labels)
+		u.SetAnnotations(annotations)
+		return u
+	}
+
+	t.Run("Update changing only the key denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(1, nil, map[string]string{"vpa/profile": "p1"}),
+			ObjectNew: newObj(1, nil, map[string]string{"vpa/profile": "p1", key: "boom"}),
+		}))
+		assert.False(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(1, nil, map[string]string{key: "boom"}),
+			ObjectNew: newObj(1, nil, nil),
+		}))
+	})
+
+	t.Run("Update changing other annotations allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(1, nil, map[string]string{"vpa/profile": "p1"}),
+			ObjectNew: newObj(1, nil, map[string]string{"vpa/profile": "p2", key: "boom"}),
+		}))
+	})
+
+	t.Run("Update changing the generation or labels allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(1, nil, nil),
+			ObjectNew: newObj(2, nil, map[string]string{key: "boom"}),
+		}))
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(1, nil, nil),
+			ObjectNew: newObj(1, map[string]string{"team": "a"}, map[string]string{key: "boom"}),
+		}))
+	})
+
+	t.Run("Update without key change allowed", func(t *testing.T) {
+		t.Parallel()
+		obj := newObj(1, nil, map[string]string{key: "boom"})
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}))
+	})
+
+	t.Run("Other events allowed", func(t *testing.T) {
+		t.Parallel()
+		obj := newObj(1, nil, nil)
+		assert.True(t, pred.Create(event.CreateEvent{Object: obj}))
+		assert.True(t, pred.Delete(event.DeleteEvent{Object: obj}))
+		assert.True(t, pred.Generic(event.GenericEvent{Object: obj}))
+	})
+}
+
+func TestContainerSetChanged(t *testing.T) {
+	t.Parallel()
+
+	const profileKey = "vpa/profile"
+	pred := ContainerSetChanged(profileKey)
+
+	newDeployment := func(annotations map[string]string, containers ...string) *appsv1.Deployment {
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+		for _, name := range containers {
+			dep.Spec.Template.Spec.Containers = append(dep.Spec.Template.Spec.Containers, corev1.Container{Name: name, Image: "app:v1"})
+		}
+		return dep
+	}
+	optedIn := map[string]string{profileKey: "p1"}
+
+	t.Run("Update with added container allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newDeployment(optedIn, "app"),
+			ObjectNew: newDeployment(optedIn, "app", "sidecar"),
+		}))
+	})
+
+	t.Run("Update with renamed container allowed", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newDeployment(optedIn, "app"),
+			ObjectNew: newDeployment(optedIn, "server"),
+		}))
+	})
+
+	t.Run("Update with added init container allowed", func(t *testing.T) {
+		t.Parallel()
+		newObj := newDeployment(optedIn, "app")
+		newObj.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "migrate"}}
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newDeployment(optedIn, "app"),
+			ObjectNew: newObj,
+		}))
+	})
+
+	t.Run("Update with reordered containers denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newDeployment(optedIn, "app", "sidecar"),
+			ObjectNew: newDeployment(optedIn, "sidecar", "app"),
+		}))
+	})
+
+	t.Run("Rollout restart denied", func(t *testing.T) {
+		t.Parallel()
+		newObj := newDeployment(optedIn, "app")
+		newObj.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2026-10-17T10:00:00Z"}
+		newObj.Spec.Template.Spec.Containers[0].Image = "app:v2"
+		assert.False(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newDeployment(optedIn, "app"),
+			ObjectNew: newObj,
+		}))
+	})
+
+	t.Run("Update of a workload not opted in denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newDeployment(nil, "app"),
+			ObjectNew: newDeployment(nil, "app", "sidecar"),
+		}))
+	})
+
+	t.Run("Update of a ReplicaSet with added container allowed", func(t *testing.T) {
+		t.Parallel()
+		newReplicaSet := func(containers ...string) *appsv1.ReplicaSet {
+			rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Annotations: optedIn}}
+			for _, name := range containers {
+				rs.Spec.Template.Spec.Containers = append(rs.Spec.Template.Spec.Containers, corev1.Container{Name: name})
+			}
+			return rs
+		}
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newReplicaSet("app"),
+			ObjectNew: newReplicaSet("app", "sidecar"),
+		}))
+	})
+
+	t.Run("Update of an unstructured workload allowed", func(t *testing.T) {
+		t.Parallel()
+		newObj := func(containers ...any) *unstructured.Unstructured {
+			u := &unstructured.Unstructured{Object: map[string]any{
+				"spec": map[string]any{"template": map[string]any{"spec": map[string]any{"containers": containers}}},
+			}}
+			u.SetAnnotations(optedIn)
+			return u
+		}
+		assert.True(t, pred.Update(event.UpdateEvent{
+			ObjectOld: newObj(map[string]any{"name": "app"}),
+			ObjectNew: newObj(map[string]any{"name": "app"}, map[string]any{"name": "sidecar"}),
+		}))
+	})
+
+	t.Run("Other events denied", func(t *testing.T) {
+		t.Parallel()
+		obj := newDeployment(optedIn, "app")
+		assert.False(t, pred.Create(event.CreateEvent{Object: obj}))
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: obj}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: obj}))
+	})
+}
+
+func TestManagedVPARecommendationChanged(t *testing.T) {
+	t.Parallel()
+
+	pred := ManagedVPARecommendationChanged("managed", "true")
+
+	newVPA := func(managed bool, target string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{}}
+		if managed {
+			u.SetLabels(map[string]string{"managed": "true"})
+		}
+		if target != "" {
+			u.Object["status"] = map[string]any{
+				"recommendation": map[string]any{
+					"containerRecommendations": []any{
+						map[string]any{"containerName": "app", "target": map[string]any{"cpu": target}},
+					},
+				},
+			}
+		}
+		return u
+	}
+
+	t.Run("Create allowed for managed VPA with recommendation", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Create(event.CreateEvent{Object: newVPA(true, "100m")}))
+		assert.False(t, pred.Create(event.CreateEvent{Object: newVPA(true, "")}))
+		assert.False(t, pred.Create(event.CreateEvent{Object: newVPA(false, "100m")}))
+	})
+
+	t.Run("Update allowed when recommendation changes", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: newVPA(true, ""), ObjectNew: newVPA(true, "100m")}))
+		assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: newVPA(true, "100m"), ObjectNew: newVPA(true, "200m")}))
+	})
+
+	t.Run("Update denied when unchanged or unmanaged", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: newVPA(true, "100m"), ObjectNew: newVPA(true, "100m")}))
+		assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: newVPA(false, ""), ObjectNew: newVPA(false, "100m")}))
+	})
+
+	t.Run("Delete and Generic denied", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, pred.Delete(event.DeleteEvent{Object: newVPA(true, "100m")}))
+		assert.False(t, pred.Generic(event.GenericEvent{Object: newVPA(true, "100m")}))
+	})
 }
@@ -17,8 +17,12 @@ limitations under the License.
 package predicates
 
 import (
+	"maps"
 	"reflect"
+	"slices"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,15 +45,52 @@ func annotationValue(obj client.Object, key string) (value string, present bool)
 	return v, true
 }
 
+// hasAnnotationKey returns true if the annotation key is set on obj,
+// regardless of its value.
+func hasAnnotationKey(obj client.Object, key string) bool {
+	if obj == nil {
+		return false
+	}
+	_, ok := obj.GetAnnotations()[key]
+	return ok
+}
+
+// firstAnnotationValue returns the value of the first key in keys that is set
+// on obj, so earlier keys take priority over later ones. Like annotationValue,
+// "present" is false when no key is set or the winning value is empty.
+func firstAnnotationValue(obj client.Object, keys []string) (value string, present bool) {
+	if obj == nil {
+		return "", false
+	}
+	ann := obj.GetAnnotations()
+	for _, key := range keys {
+		if v, ok := ann[key]; ok {
+			return v, v != ""
+		}
+	}
+	return "", false
+}
+
+// hasAnyAnnotationKey returns true if any of the annotation keys is set on obj,
+// regardless of its value.
+func hasAnyAnnotationKey(obj client.Object, keys []string) bool {
+	for _, key := range keys {
+		if hasAnnotationKey(obj, key) {
+			return true
+		}
+	}
+	return false
+}
+
 // hasNonEmptyAnnotation returns true if obj contains the annotation key with a non-empty value.
 func hasNonEmptyAnnotation(obj client.Object, key string) bool {
 	_, ok := annotationValue(obj, key)
 	return ok
 }
 
-// hasTrueLabel returns true if obj contains the label key with value "true".
-// This matches controller behavior where "managed" is label == "true", not just presence.
-func hasTrueLabel(obj client.Object, key string) bool {
+// hasManagedLabel returns true if obj carries the label key with the given value.
+// An empty value matches any value, for managed label values rendered per workload.
+func hasManagedLabel(obj client.Object, key, value string) bool {
 	if obj == nil {
 		return false
 	}
@@ -57,7 +98,11 @@ func hasTrueLabel(obj client.Object, key string) bool {
 	if labels == nil {
 		return false
 	}
-	return labels[key] == "true"
+	got, ok := labels[key]
+	if value == "" {
+		return ok
+	}
+	return ok && got == value
 }
 
 // deletionJustStarted returns true if deletion was requested on the new object
@@ -67,6 +112,13 @@ func deletionJustStarted(oldObj, newObj client.Object) bool {
 		!newObj.GetDeletionTimestamp().IsZero()
 }
 
+// isResync returns true for informer resync events, where the object did not
+// change and both sides carry the same non-empty resourceVersion.
+func isResync(oldObj, newObj client.Object) bool {
+	rv := newObj.GetResourceVersion()
+	return rv != "" && oldObj.GetResourceVersion() == rv
+}
+
 // controllerOwnerRef returns the single controller ownerRef (controller=true),
 // or nil if none exists.
 func controllerOwnerRef(obj client.Object) *metav1.OwnerReference {
@@ -82,6 +134,12 @@ func controllerOwnerRef(obj client.Object) *metav1.OwnerReference {
 	return nil
 }
 
+// controlledBy reports whether obj has a controller ownerRef of kind.
+func controlledBy(obj client.Object, kind string) bool {
+	ref := controllerOwnerRef(obj)
+	return ref != nil && ref.Kind == kind
+}
+
 // controllerOwnerRefChanged returns true if the controller ownerRef changed
 // (including added/removed).
 func controllerOwnerRefChanged(oldObj, newObj client.Object) bool {
@@ -106,6 +164,31 @@ func specChanged(oldObj, newObj client.Object) bool {
 	return !reflect.DeepEqual(oldU.Object["spec"], newU.Object["spec"])
 }
 
+// hasRecommendation returns true if the unstructured object has a
+// non-empty "status.recommendation" field.
+func hasRecommendation(obj client.Object) bool {
+	u, ok := unstructuredObject(obj)
+	if !ok {
+		return false
+	}
+	rec, found, _ := unstructured.NestedMap(u.Object, "status", "recommendation")
+	return found && len(rec) > 0
+}
+
+// recommendationChanged returns true if the unstructured "status.recommendation"
+// field changed. If the objects are not unstructured, it returns false (conservative).
+func recommendationChanged(oldObj, newObj client.Object) bool {
+	oldU, ok1 := unstructuredObject(oldObj)
+	newU, ok2 := unstructuredObject(newObj)
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	oldRec, _, _ := unstructured.NestedFieldNoCopy(oldU.Object, "status", "recommendation")
+	newRec, _, _ := unstructured.NestedFieldNoCopy(newU.Object, "status", "recommendation")
+	return !reflect.DeepEqual(oldRec, newRec)
+}
+
 // operatorLabelsChanged returns true if any operator-owned labels differ.
 // This avoids requeueing on user-added labels while still allowing “snap back”.
 func operatorLabelsChanged(oldObj, newObj client.Object, keys ...string) bool {
@@ -119,3 +202,84 @@ func operatorLabelsChanged(oldObj, newObj client.Object, keys ...string) bool {
 	}
 	return false
 }
+
+// annotationsChanged returns true if any of the given annotation keys differ.
+func annotationsChanged(oldObj, newObj client.Object, keys ...string) bool {
+	oldA := oldObj.GetAnnotations()
+	newA := newObj.GetAnnotations()
+
+	for _, k := range keys {
+		if oldA[k] != newA[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyAnnotationsChanged returns true if any of the given annotation keys
+// differ while the generation, labels and all other annotations are equal.
+func onlyAnnotationsChanged(oldObj, newObj client.Object, keys ...string) bool {
+	if !annotationsChanged(oldObj, newObj, keys...) {
+		return false
+	}
+	if oldObj.GetGeneration() != newObj.GetGeneration() || !maps.Equal(oldObj.GetLabels(), newObj.GetLabels()) {
+		return false
+	}
+
+	oldA := maps.Clone(oldObj.GetAnnotations())
+	newA := maps.Clone(newObj.GetAnnotations())
+	for _, k := range keys {
+		delete(oldA, k)
+		delete(newA, k)
+	}
+	return maps.Equal(oldA, newA)
+}
+
+// containerNames returns the sorted names of the pod template's containers and
+// init containers, or nil when obj has no pod template. Unstructured objects
+// are read from spec.template.spec, like the generic workload kinds.
+func containerNames(obj client.Object) []string {
+	var spec *corev1.PodSpec
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.ReplicaSet:
+		spec = &o.Spec.Template.Spec
+	case *unstructured.Unstructured:
+		return unstructuredContainerNames(o)
+	default:
+		return nil
+	}
+
+	var names []string
+	for _, c := range spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	for _, c := range spec.Containers {
+		names = append(names, c.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// unstructuredContainerNames returns the sorted container and init container
+// names under spec.template.spec of an unstructured workload.
+func unstructuredContainerNames(obj *unstructured.Unstructured) []string {
+	var names []string
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", field)
+		for _, c := range containers {
+			if container, ok := c.(map[string]any); ok {
+				if name, ok := container["name"].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
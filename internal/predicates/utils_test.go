@@ -71,6 +71,99 @@ func TestAnnotationValue(t *testing.T) {
 	})
 }
 
+func TestHasAnnotationKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Returns false on nil object", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, hasAnnotationKey(nil, "a"))
+	})
+
+	t.Run("Returns false when annotation missing", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"b": "c"})
+		assert.False(t, hasAnnotationKey(obj, "a"))
+	})
+
+	t.Run("Returns true when annotation exists but empty", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"a": ""})
+		assert.True(t, hasAnnotationKey(obj, "a"))
+	})
+}
+
+func TestFirstAnnotationValue(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"new", "old"}
+
+	t.Run("Returns false on nil object", func(t *testing.T) {
+		t.Parallel()
+		_, ok := firstAnnotationValue(nil, keys)
+		assert.False(t, ok)
+	})
+
+	t.Run("Returns false when no key is set", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"other": "x"})
+		_, ok := firstAnnotationValue(obj, keys)
+		assert.False(t, ok)
+	})
+
+	t.Run("Falls back to later keys", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"old": "gold"})
+		v, ok := firstAnnotationValue(obj, keys)
+		assert.True(t, ok)
+		assert.Equal(t, "gold", v)
+	})
+
+	t.Run("Earlier keys take priority", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"new": "silver", "old": "gold"})
+		v, ok := firstAnnotationValue(obj, keys)
+		assert.True(t, ok)
+		assert.Equal(t, "silver", v)
+	})
+
+	t.Run("Empty earlier key wins over later keys", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"new": "", "old": "gold"})
+		v, ok := firstAnnotationValue(obj, keys)
+		assert.False(t, ok)
+		assert.Empty(t, v)
+	})
+}
+
+func TestHasAnyAnnotationKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Returns false on nil object", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, hasAnyAnnotationKey(nil, []string{"a", "b"}))
+	})
+
+	t.Run("Returns true when any key is set", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"b": ""})
+		assert.True(t, hasAnyAnnotationKey(obj, []string{"a", "b"}))
+	})
+
+	t.Run("Returns false when no key is set", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{"c": "x"})
+		assert.False(t, hasAnyAnnotationKey(obj, []string{"a", "b"}))
+	})
+}
+
 func TestHasNonEmptyAnnotation(t *testing.T) {
 	t.Parallel()
 
@@ -101,39 +194,47 @@ func TestHasNonEmptyAnnotation(t *testing.T) {
 	})
 }
 
-func TestHasTrueLabel(t *testing.T) {
+func TestHasManagedLabel(t *testing.T) {
 	t.Parallel()
 
 	t.Run("Returns false on nil object", func(t *testing.T) {
 		t.Parallel()
-		assert.False(t, hasTrueLabel(nil, "managed"))
+		assert.False(t, hasManagedLabel(nil, "managed", "true"))
 	})
 
 	t.Run("Returns false when labels are nil", func(t *testing.T) {
 		t.Parallel()
 		obj := &unstructured.Unstructured{}
-		assert.False(t, hasTrueLabel(obj, "managed"))
+		assert.False(t, hasManagedLabel(obj, "managed", "true"))
 	})
 
 	t.Run("Returns false when label missing", func(t *testing.T) {
 		t.Parallel()
 		obj := &unstructured.Unstructured{}
 		obj.SetLabels(map[string]string{"other": "true"})
-		assert.False(t, hasTrueLabel(obj, "managed"))
+		assert.False(t, hasManagedLabel(obj, "managed", "true"))
+		assert.False(t, hasManagedLabel(obj, "managed", ""))
 	})
 
-	t.Run("Returns false when label exists but not true", func(t *testing.T) {
+	t.Run("Returns false when label value differs", func(t *testing.T) {
 		t.Parallel()
 		obj := &unstructured.Unstructured{}
 		obj.SetLabels(map[string]string{"managed": "false"})
-		assert.False(t, hasTrueLabel(obj, "managed"))
+		assert.False(t, hasManagedLabel(obj, "managed", "true"))
 	})
 
-	t.Run(`Returns true when label value is "true"`, func(t *testing.T) {
+	t.Run("Returns true when label value matches", func(t *testing.T) {
 		t.Parallel()
 		obj := &unstructured.Unstructured{}
 		obj.SetLabels(map[string]string{"managed": "true"})
-		assert.True(t, hasTrueLabel(obj, "managed"))
+		assert.True(t, hasManagedLabel(obj, "managed", "true"))
+	})
+
+	t.Run("Matches any value when value is empty", func(t *testing.T) {
+		t.Parallel()
+		obj := &unstructured.Unstructured{}
+		obj.SetLabels(map[string]string{"managed": "payments"})
+		assert.True(t, hasManagedLabel(obj, "managed", ""))
 	})
 }
 
@@ -20,14 +20,14 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
 	"strings"
-	"text/template"
-	"unicode/utf8"
+
+	"github.com/containeroo/autovpa/pkg/nametemplate"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
@@ -36,13 +36,8 @@ import (
 )
 
 // NameTemplateData describes fields available when rendering name templates.
-// These map to template variables (.WorkloadName, .Namespace, .Kind, .Profile).
-type NameTemplateData struct {
-	WorkloadName string
-	Namespace    string
-	Kind         string
-	Profile      string
-}
+// See nametemplate.Data for the template variables.
+type NameTemplateData = nametemplate.Data
 
 // ValidateUniqueKeys ensures all provided annotation/label values are unique.
 // Returns an error if the map is empty or if any duplicate values are found.
@@ -102,6 +97,51 @@ func MergeMaps(a map[string]string, b map[string]string) map[string]string {
 	return out
 }
 
+// EditDistance returns the Levenshtein distance between a and b, counted in
+// runes: the number of single-rune insertions, deletions and substitutions
+// needed to turn a into b.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// Only the previous row of the distance matrix is needed.
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// NearMisses returns the sorted keys of m that are within maxDistance edits of
+// one of targets without matching any target exactly.
+func NearMisses(m map[string]string, targets []string, maxDistance int) []string {
+	var out []string
+	for key := range m {
+		if slices.Contains(targets, key) {
+			continue
+		}
+		for _, target := range targets {
+			if EditDistance(key, target) <= maxDistance {
+				out = append(out, key)
+				break
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 // ToCacheOptions returns cache.Options configured to watch the given namespaces.
 // If no namespaces are provided, it returns an empty Options which watches all namespaces.
 func ToCacheOptions(watchNamespaces []string) cache.Options {
@@ -117,90 +157,57 @@ func ToCacheOptions(watchNamespaces []string) cache.Options {
 	return cache.Options{DefaultNamespaces: nsMap}
 }
 
-// EnsureVPAResource verifies the VerticalPodAutoscaler CRD is installed.
-func EnsureVPAResource(restCfg *rest.Config) error {
+// EnsureVPAResource verifies the VerticalPodAutoscaler CRD is installed and
+// serves the given group/version/kind.
+func EnsureVPAResource(restCfg *rest.Config, gvk schema.GroupVersionKind) error {
+	available, err := VPAResourceAvailable(restCfg, gvk)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return fmt.Errorf("verticalpodautoscaler CRD not installed: %w", &meta.NoKindMatchError{
+			GroupKind:        gvk.GroupKind(),
+			SearchedVersions: []string{gvk.Version},
+		})
+	}
+	return nil
+}
+
+// VPAResourceAvailable reports whether the API server serves gvk. A missing
+// CRD is not an error; discovery failures are.
+func VPAResourceAvailable(restCfg *rest.Config, gvk schema.GroupVersionKind) (bool, error) {
 	disco, err := discovery.NewDiscoveryClientForConfig(restCfg)
 	if err != nil {
-		return fmt.Errorf("create discovery client: %w", err)
+		return false, fmt.Errorf("create discovery client: %w", err)
 	}
 
 	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(disco))
-	_, err = mapper.RESTMapping(schema.GroupKind{Group: "autoscaling.k8s.io", Kind: "VerticalPodAutoscaler"}, "v1")
-	if err != nil {
+	if _, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
 		if meta.IsNoMatchError(err) {
-			return fmt.Errorf("verticalpodautoscaler CRD not installed: %w", err)
+			return false, nil
 		}
-		return fmt.Errorf("discover verticalpodautoscaler CRD: %w", err)
+		return false, fmt.Errorf("discover verticalpodautoscaler CRD: %w", err)
 	}
-	return nil
+	return true, nil
 }
 
 // RenderNameTemplate renders and validates the provided template as a DNS-1123 subdomain.
+// It delegates to the public nametemplate package.
 func RenderNameTemplate(tmpl string, data NameTemplateData) (string, error) {
-	if strings.TrimSpace(tmpl) == "" {
-		return "", errors.New("template must not be empty")
-	}
-
-	parsed, err := template.New("name").
-		Funcs(template.FuncMap{
-			"toLower":  strings.ToLower,
-			"replace":  strings.ReplaceAll,
-			"trim":     strings.TrimSpace,
-			"truncate": truncateRunes,
-			"dnsLabel": dnsLabel,
-		}).
-		Option("missingkey=error").
-		Parse(tmpl)
-	if err != nil {
-		return "", fmt.Errorf("parse template: %w", err)
-	}
-
-	var rendered strings.Builder
-	if err := parsed.Execute(&rendered, data); err != nil {
-		return "", fmt.Errorf("render template: %w", err)
-	}
-
-	name := rendered.String()
-	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
-		return "", fmt.Errorf("rendered name %q is not a valid DNS-1123 subdomain: %s", name, strings.Join(errs, ", "))
-	}
+	return nametemplate.Render(tmpl, data)
+}
 
-	return name, nil
+// IsTemplate reports whether s contains template actions.
+func IsTemplate(s string) bool {
+	return nametemplate.IsTemplate(s)
 }
 
-// truncateRunes trims the string to at most n runes.
-func truncateRunes(s string, n int) string {
-	if n <= 0 {
-		return ""
-	}
-	if utf8.RuneCountInString(s) <= n {
-		return s
-	}
-	var b strings.Builder
-	for i, r := range s {
-		if i >= n {
-			break
-		}
-		b.WriteRune(r)
-	}
-	return b.String()
+// UsesNamespaceLabels reports whether tmpl reads .NamespaceLabels.
+func UsesNamespaceLabels(tmpl string) bool {
+	return nametemplate.UsesNamespaceLabels(tmpl)
 }
 
-// dnsLabel normalizes a string to a DNS-1123-friendly token.
-// Valid characters are a-z, 0-9, - and .
-func dnsLabel(s string) string {
-	s = strings.ToLower(s)
-	var b strings.Builder
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
-			b.WriteRune(r)
-		} else {
-			b.WriteRune('-')
-		}
-	}
-	out := strings.Trim(b.String(), "-.")
-	if out == "" {
-		return "vpa"
-	}
-	return out
+// RenderLabelValue renders the provided template and validates the result as a label value.
+func RenderLabelValue(tmpl string, data NameTemplateData) (string, error) {
+	return nametemplate.RenderLabelValue(tmpl, data)
 }
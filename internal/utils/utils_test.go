@@ -19,14 +19,17 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 )
 
@@ -85,6 +88,64 @@ func TestUtilsDefaultIfZero(t *testing.T) {
 	})
 }
 
+func TestUtilsEditDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{name: "Equal strings", a: "vpa/profile", b: "vpa/profile", want: 0},
+		{name: "Both empty", a: "", b: "", want: 0},
+		{name: "One empty", a: "", b: "abc", want: 3},
+		{name: "Substitution", a: "vpa/profile", b: "vpa/profle", want: 1},
+		{name: "Insertion", a: "vpa/profile", b: "vpa/profiles", want: 1},
+		{name: "Transposition counts twice", a: "vpa/profile", b: "vpa/porfile", want: 2},
+		{name: "Classic example", a: "kitten", b: "sitting", want: 3},
+		{name: "Counts runes not bytes", a: "größe", b: "grösse", want: 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, EditDistance(tc.a, tc.b))
+			assert.Equal(t, tc.want, EditDistance(tc.b, tc.a))
+		})
+	}
+}
+
+func TestUtilsNearMisses(t *testing.T) {
+	t.Parallel()
+
+	targets := []string{"vpa/profile", "old/profile"}
+
+	t.Run("Returns sorted close keys", func(t *testing.T) {
+		t.Parallel()
+		out := NearMisses(map[string]string{
+			"vpa/profiles": "",
+			"vpa/proflie":  "",
+			"app":          "",
+		}, targets, 2)
+		assert.Equal(t, []string{"vpa/profiles", "vpa/proflie"}, out)
+	})
+
+	t.Run("Skips exact matches of any target", func(t *testing.T) {
+		t.Parallel()
+		out := NearMisses(map[string]string{
+			"vpa/profile": "",
+			"old/profile": "",
+		}, targets, 2)
+		assert.Empty(t, out)
+	})
+
+	t.Run("Respects max distance", func(t *testing.T) {
+		t.Parallel()
+		out := NearMisses(map[string]string{"vpa/porfiles": ""}, targets, 2)
+		assert.Empty(t, out)
+	})
+}
+
 func TestUtilsMergeMaps(t *testing.T) {
 	t.Parallel()
 
@@ -139,7 +200,7 @@ func TestUtilsEnsureVPAResource(t *testing.T) {
 		t.Parallel()
 
 		cfg := newDiscoveryConfig(t, true)
-		err := EnsureVPAResource(cfg)
+		err := EnsureVPAResource(cfg, vpaGVK)
 		assert.NoError(t, err)
 	})
 
@@ -147,120 +208,121 @@ func TestUtilsEnsureVPAResource(t *testing.T) {
 		t.Parallel()
 
 		cfg := newDiscoveryConfig(t, false)
-		err := EnsureVPAResource(cfg)
+		err := EnsureVPAResource(cfg, vpaGVK)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "verticalpodautoscaler CRD not installed")
 	})
-}
-
-func TestUtilsRenderNameTemplate(t *testing.T) {
-	t.Parallel()
 
-	t.Run("Empty template", func(t *testing.T) {
+	t.Run("Configured version not served", func(t *testing.T) {
 		t.Parallel()
-		out, err := RenderNameTemplate("", NameTemplateData{
-			WorkloadName: "DemoApp",
-			Profile:      "P1",
-		})
-		require.Error(t, err)
-		assert.Empty(t, out)
-		assert.EqualError(t, err, "template must not be empty")
-	})
 
-	t.Run("Template parse error", func(t *testing.T) {
-		t.Parallel()
-		out, err := RenderNameTemplate("{{ .Invalid ", NameTemplateData{
-			WorkloadName: "DemoApp",
-			Profile:      "P1",
+		cfg := newDiscoveryConfig(t, true)
+		err := EnsureVPAResource(cfg, schema.GroupVersionKind{
+			Group:   "autoscaling.k8s.io",
+			Version: "v1beta2",
+			Kind:    "VerticalPodAutoscaler",
 		})
 		require.Error(t, err)
-		assert.Empty(t, out)
-		assert.EqualError(t, err, "parse template: template: name:1: unclosed action")
+		assert.Contains(t, err.Error(), "verticalpodautoscaler CRD not installed")
 	})
+}
 
-	t.Run("Invalid template", func(t *testing.T) {
-		t.Parallel()
-		out, err := RenderNameTemplate("{{ .Invalid }}", NameTemplateData{
-			WorkloadName: "DemoApp",
-			Profile:      "P1",
-		})
-		require.Error(t, err)
-		assert.Empty(t, out)
-		assert.EqualError(t, err, "render template: template: name:1:3: executing \"name\" at <.Invalid>: can't evaluate field Invalid in type utils.NameTemplateData")
-	})
+func TestUtilsVPAResourceAvailable(t *testing.T) {
+	t.Parallel()
 
-	t.Run("Renders with helpers", func(t *testing.T) {
+	t.Run("Detects the CRD becoming available", func(t *testing.T) {
 		t.Parallel()
-		out, err := RenderNameTemplate("{{ toLower .WorkloadName }}-{{ dnsLabel .Profile }}", NameTemplateData{
-			WorkloadName: "DemoApp",
-			Profile:      "P1",
-		})
+
+		include := &atomic.Bool{}
+		cfg := &rest.Config{
+			Host:      "http://discovery.invalid",
+			Transport: toggleDiscoveryRoundTripper{includeVPA: include},
+		}
+
+		available, err := VPAResourceAvailable(cfg, vpaGVK)
 		require.NoError(t, err)
-		assert.Equal(t, "demoapp-p1", out)
-	})
+		assert.False(t, available)
 
-	t.Run("Fails on invalid render", func(t *testing.T) {
-		t.Parallel()
-		_, err := RenderNameTemplate("INVALID", NameTemplateData{WorkloadName: "demo"})
-		require.Error(t, err)
-	})
+		include.Store(true)
+		available, err = VPAResourceAvailable(cfg, vpaGVK)
+		require.NoError(t, err)
+		assert.True(t, available)
 
-	t.Run("Fails DNS validation", func(t *testing.T) {
-		t.Parallel()
-		_, err := RenderNameTemplate("Demo", NameTemplateData{WorkloadName: "demo"})
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "not a valid DNS-1123 subdomain")
+		include.Store(false)
+		available, err = VPAResourceAvailable(cfg, vpaGVK)
+		require.NoError(t, err)
+		assert.False(t, available)
 	})
 
-	t.Run("Truncates when using helper", func(t *testing.T) {
+	t.Run("Discovery failure", func(t *testing.T) {
 		t.Parallel()
-		out, err := RenderNameTemplate("{{ truncate .WorkloadName 3 }}-vpa", NameTemplateData{
-			WorkloadName: "demoooo",
-		})
-		require.NoError(t, err)
-		assert.Equal(t, "dem-vpa", out)
+
+		cfg := &rest.Config{
+			Host:      "http://discovery.invalid",
+			Transport: failingRoundTripper{},
+		}
+		available, err := VPAResourceAvailable(cfg, vpaGVK)
+		require.Error(t, err)
+		assert.False(t, available)
+		assert.Contains(t, err.Error(), "discover verticalpodautoscaler CRD")
 	})
 }
 
-func TestUtilsTruncateRunes(t *testing.T) {
+func TestUtilsRenderNameTemplate(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Returns empty when limit non-positive", func(t *testing.T) {
+	t.Run("Delegates to nametemplate", func(t *testing.T) {
 		t.Parallel()
-		assert.Equal(t, "", truncateRunes("hello", 0))
-		assert.Equal(t, "", truncateRunes("hello", -1))
+		out, err := RenderNameTemplate(`{{ .WorkloadName }}-{{ .Profile }}`, NameTemplateData{
+			WorkloadName: "demo",
+			Profile:      "p1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "demo-p1", out)
 	})
 
-	t.Run("Returns original when shorter than limit", func(t *testing.T) {
+	t.Run("Returns validation errors", func(t *testing.T) {
 		t.Parallel()
-		assert.Equal(t, "hi", truncateRunes("hi", 5))
+		_, err := RenderNameTemplate("", NameTemplateData{})
+		assert.EqualError(t, err, "template must not be empty")
 	})
+}
+
+func TestUtilsIsTemplate(t *testing.T) {
+	t.Parallel()
 
-	t.Run("Truncates by rune count", func(t *testing.T) {
+	t.Run("Delegates to nametemplate", func(t *testing.T) {
 		t.Parallel()
-		assert.Equal(t, "hé", truncateRunes("héllo", 2))
+		assert.False(t, IsTemplate("true"))
+		assert.True(t, IsTemplate("{{ .Profile }}"))
 	})
 }
 
-func TestUtilsDNSLabel(t *testing.T) {
+func TestUtilsUsesNamespaceLabels(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Normalizes allowed characters", func(t *testing.T) {
+	t.Run("Delegates to nametemplate", func(t *testing.T) {
 		t.Parallel()
-		assert.Equal(t, "app-1", dnsLabel("App_1"))
+		assert.False(t, UsesNamespaceLabels(`{{ index .Labels "team" }}`))
+		assert.True(t, UsesNamespaceLabels(`{{ index .NamespaceLabels "team" }}`))
 	})
+}
 
-	t.Run("Trims disallowed edges", func(t *testing.T) {
-		t.Parallel()
-		assert.Equal(t, "mid", dnsLabel("-mid."))
-	})
+func TestUtilsRenderLabelValue(t *testing.T) {
+	t.Parallel()
 
-	t.Run("Defaults to vpa on empty", func(t *testing.T) {
+	t.Run("Delegates to nametemplate", func(t *testing.T) {
 		t.Parallel()
-		assert.Equal(t, "vpa", dnsLabel("???"))
+		out, err := RenderLabelValue(`{{ index .Labels "team" }}`, NameTemplateData{
+			Labels: map[string]string{"team": "payments"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "payments", out)
 	})
 }
 
+var vpaGVK = schema.GroupVersionKind{Group: "autoscaling.k8s.io", Version: "v1", Kind: "VerticalPodAutoscaler"}
+
 type discoveryRoundTripper struct {
 	includeVPA bool
 }
@@ -281,6 +343,23 @@ func (d discoveryRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 	}
 }
 
+// toggleDiscoveryRoundTripper serves discovery with the VPA group while
+// includeVPA is set, so tests can install and remove the CRD.
+type toggleDiscoveryRoundTripper struct {
+	includeVPA *atomic.Bool
+}
+
+func (d toggleDiscoveryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return discoveryRoundTripper{includeVPA: d.includeVPA.Load()}.RoundTrip(req)
+}
+
+// failingRoundTripper fails every request like an unreachable API server.
+type failingRoundTripper struct{}
+
+func (failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
 func jsonResponse(obj any) *http.Response {
 	body, _ := json.Marshal(obj)
 	return &http.Response{
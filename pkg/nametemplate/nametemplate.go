@@ -0,0 +1,204 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nametemplate renders AutoVPA name templates.
+//
+// It is the public API behind the --vpa-name-template flag, per-profile
+// nameTemplate overrides and templated managed label values, so templates can
+// be validated by tooling and tests outside this module.
+package nametemplate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Data describes the fields available when rendering templates.
+// These map to template variables (.WorkloadName, .Namespace, .Kind, .Profile,
+// .RequestedProfile, .Labels, .NamespaceLabels); workload labels are read with
+// e.g. {{ index .Labels "team" }} and namespace labels with
+// {{ index .NamespaceLabels "team" }}.
+//
+// Profile is the profile actually used, while RequestedProfile is the name as
+// written in the workload annotation, e.g. "default" before it resolves to a
+// kind default.
+type Data struct {
+	WorkloadName     string
+	Namespace        string
+	Kind             string
+	Profile          string
+	RequestedProfile string
+	Labels           map[string]string
+	NamespaceLabels  map[string]string
+}
+
+// UsesNamespaceLabels reports whether tmpl reads .NamespaceLabels, so callers
+// only look up the namespace when a template needs it.
+func UsesNamespaceLabels(tmpl string) bool {
+	return strings.Contains(tmpl, ".NamespaceLabels")
+}
+
+// Render renders tmpl with data and validates the result as a DNS-1123
+// subdomain, the format required for VPA names.
+func Render(tmpl string, data Data) (string, error) {
+	name, err := render(tmpl, data)
+	if err != nil {
+		return "", err
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", fmt.Errorf("rendered name %q is not a valid DNS-1123 subdomain: %s", name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// IsTemplate reports whether s contains template actions.
+func IsTemplate(s string) bool {
+	return strings.Contains(s, "{{")
+}
+
+// RenderLabelValue renders tmpl with data and validates the result as a
+// Kubernetes label value.
+func RenderLabelValue(tmpl string, data Data) (string, error) {
+	value, err := render(tmpl, data)
+	if err != nil {
+		return "", err
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return "", fmt.Errorf("rendered label value %q is invalid: %s", value, strings.Join(errs, ", "))
+	}
+	return value, nil
+}
+
+// Funcs returns the helper functions available in templates. The returned
+// map is a fresh copy and may be modified by the caller.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"toLower":      strings.ToLower,
+		"toUpper":      strings.ToUpper,
+		"title":        title,
+		"replace":      strings.ReplaceAll,
+		"trim":         strings.TrimSpace,
+		"trimPrefix":   trimPrefix,
+		"trimSuffix":   trimSuffix,
+		"truncate":     truncateRunes,
+		"dnsLabel":     dnsLabel,
+		"regexReplace": regexReplace,
+	}
+}
+
+// render parses and executes tmpl with the helper functions.
+func render(tmpl string, data Data) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		return "", errors.New("template must not be empty")
+	}
+
+	parsed, err := template.New("name").
+		Funcs(Funcs()).
+		Option("missingkey=error").
+		Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+
+	var rendered strings.Builder
+	if err := parsed.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+
+	return rendered.String(), nil
+}
+
+// truncateRunes trims the string to at most n runes.
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	var b strings.Builder
+	for i, r := range s {
+		if i >= n {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// title capitalizes the first letter of every word, where words are separated
+// by any non-alphanumeric rune. The rest of each word is left unchanged.
+func title(s string) string {
+	var b strings.Builder
+	startOfWord := true
+	for _, r := range s {
+		isAlnum := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if startOfWord && isAlnum {
+			r = unicode.ToUpper(r)
+		}
+		startOfWord = !isAlnum
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// trimPrefix removes prefix from s if present. The string comes last so the
+// helper can be used in pipelines.
+func trimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+// trimSuffix removes suffix from s if present. The string comes last so the
+// helper can be used in pipelines.
+func trimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+// regexReplace replaces all matches of pattern in s with repl.
+// repl may reference capture groups ($1, ${name}).
+func regexReplace(pattern, repl, s string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("regexReplace: invalid pattern %q: %w", pattern, err)
+	}
+	return re.ReplaceAllString(s, repl), nil
+}
+
+// dnsLabel normalizes a string to a DNS-1123-friendly token.
+// Valid characters are a-z, 0-9, - and .
+func dnsLabel(s string) string {
+	s = strings.ToLower(s)
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	out := strings.Trim(b.String(), "-.")
+	if out == "" {
+		return "vpa"
+	}
+	return out
+}
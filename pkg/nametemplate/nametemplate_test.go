@@ -0,0 +1,437 @@
+/*
+Copyright 2026 containeroo.ch
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nametemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty template", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render("", Data{
+			WorkloadName: "DemoApp",
+			Profile:      "P1",
+		})
+		require.Error(t, err)
+		assert.Empty(t, out)
+		assert.EqualError(t, err, "template must not be empty")
+	})
+
+	t.Run("Template parse error", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render("{{ .Invalid ", Data{
+			WorkloadName: "DemoApp",
+			Profile:      "P1",
+		})
+		require.Error(t, err)
+		assert.Empty(t, out)
+		assert.EqualError(t, err, "parse template: template: name:1: unclosed action")
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render("{{ .Invalid }}", Data{
+			WorkloadName: "DemoApp",
+			Profile:      "P1",
+		})
+		require.Error(t, err)
+		assert.Empty(t, out)
+		assert.EqualError(t, err, "render template: template: name:1:3: executing \"name\" at <.Invalid>: can't evaluate field Invalid in type nametemplate.Data")
+	})
+
+	t.Run("Renders with helpers", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render("{{ toLower .WorkloadName }}-{{ dnsLabel .Profile }}", Data{
+			WorkloadName: "DemoApp",
+			Profile:      "P1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "demoapp-p1", out)
+	})
+
+	t.Run("Distinguishes requested and resolved profile", func(t *testing.T) {
+		t.Parallel()
+		data := Data{WorkloadName: "demo", Profile: "p1", RequestedProfile: "default"}
+
+		out, err := Render("{{ .WorkloadName }}-{{ .RequestedProfile }}-vpa", data)
+		require.NoError(t, err)
+		assert.Equal(t, "demo-default-vpa", out)
+
+		out, err = Render("{{ .WorkloadName }}-{{ .Profile }}-vpa", data)
+		require.NoError(t, err)
+		assert.Equal(t, "demo-p1-vpa", out)
+	})
+
+	t.Run("Fails on invalid render", func(t *testing.T) {
+		t.Parallel()
+		_, err := Render("INVALID", Data{WorkloadName: "demo"})
+		require.Error(t, err)
+	})
+
+	t.Run("Fails DNS validation", func(t *testing.T) {
+		t.Parallel()
+		_, err := Render("Demo", Data{WorkloadName: "demo"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a valid DNS-1123 subdomain")
+	})
+
+	t.Run("Truncates when using helper", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render("{{ truncate .WorkloadName 3 }}-vpa", Data{
+			WorkloadName: "demoooo",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "dem-vpa", out)
+	})
+
+	t.Run("Replaces with regex helper", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ regexReplace "-v[0-9]+$" "" .WorkloadName }}-vpa`, Data{
+			WorkloadName: "api-v2",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "api-vpa", out)
+	})
+
+	t.Run("Propagates invalid regex pattern", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ regexReplace "(" "" .WorkloadName }}`, Data{
+			WorkloadName: "demo",
+		})
+		require.Error(t, err)
+		assert.Empty(t, out)
+		assert.Contains(t, err.Error(), `regexReplace: invalid pattern "("`)
+	})
+
+	t.Run("Trims environment suffix", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ .WorkloadName | trimSuffix "-prod" }}-{{ .Profile }}-vpa`, Data{
+			WorkloadName: "api-prod",
+			Profile:      "p1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "api-p1-vpa", out)
+	})
+
+	t.Run("Trims prefix", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ trimPrefix "legacy-" .WorkloadName }}`, Data{WorkloadName: "legacy-api"})
+		require.NoError(t, err)
+		assert.Equal(t, "api", out)
+	})
+
+	t.Run("Keeps names without the affix", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ .WorkloadName | trimSuffix "-prod" | trimPrefix "legacy-" }}`, Data{WorkloadName: "api-staging"})
+		require.NoError(t, err)
+		assert.Equal(t, "api-staging", out)
+	})
+
+	t.Run("Fails DNS validation when trimming leaves a dangling dash", func(t *testing.T) {
+		t.Parallel()
+		_, err := Render(`{{ .WorkloadName | trimSuffix "prod" }}`, Data{WorkloadName: "api-prod"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `rendered name "api-" is not a valid DNS-1123 subdomain`)
+	})
+
+	t.Run("Fails when trimming leaves an empty name", func(t *testing.T) {
+		t.Parallel()
+		_, err := Render(`{{ .WorkloadName | trimPrefix "api" }}`, Data{WorkloadName: "api"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `rendered name "" is not a valid DNS-1123 subdomain`)
+	})
+
+	t.Run("Casing helpers combined with toLower", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ toUpper .WorkloadName | toLower }}-{{ title .Profile | dnsLabel }}`, Data{
+			WorkloadName: "demo",
+			Profile:      "p1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "demo-p1", out)
+	})
+
+	t.Run("Fails DNS validation when toUpper output stays uppercase", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ toUpper .WorkloadName }}`, Data{WorkloadName: "demo"})
+		require.Error(t, err)
+		assert.Empty(t, out)
+		assert.Contains(t, err.Error(), `rendered name "DEMO" is not a valid DNS-1123 subdomain`)
+	})
+
+	t.Run("Fails DNS validation when title output stays uppercase", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ title .WorkloadName }}`, Data{WorkloadName: "demo-app"})
+		require.Error(t, err)
+		assert.Empty(t, out)
+		assert.Contains(t, err.Error(), `rendered name "Demo-App" is not a valid DNS-1123 subdomain`)
+	})
+
+	t.Run("Reads workload labels", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ index .Labels "team" }}-{{ .WorkloadName }}`, Data{
+			WorkloadName: "demo",
+			Labels:       map[string]string{"team": "payments"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "payments-demo", out)
+	})
+
+	t.Run("Reads namespace labels", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ .WorkloadName }}-{{ index .NamespaceLabels "team" }}-vpa`, Data{
+			WorkloadName:    "demo",
+			NamespaceLabels: map[string]string{"team": "payments"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "demo-payments-vpa", out)
+	})
+
+	t.Run("Missing namespace label renders empty", func(t *testing.T) {
+		t.Parallel()
+		out, err := Render(`{{ .WorkloadName }}-{{ index .NamespaceLabels "team" }}-vpa`, Data{WorkloadName: "demo"})
+		require.NoError(t, err)
+		assert.Equal(t, "demo--vpa", out)
+	})
+}
+
+func TestUsesNamespaceLabels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Template without namespace labels", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, UsesNamespaceLabels(`{{ .WorkloadName }}-{{ index .Labels "team" }}`))
+	})
+
+	t.Run("Template with namespace labels", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, UsesNamespaceLabels(`{{ index .NamespaceLabels "team" }}-{{ .WorkloadName }}`))
+	})
+}
+
+func TestIsTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Plain string", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, IsTemplate("true"))
+	})
+
+	t.Run("Template string", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, IsTemplate(`{{ index .Labels "team" }}`))
+	})
+}
+
+func TestRenderLabelValue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Renders workload label", func(t *testing.T) {
+		t.Parallel()
+		out, err := RenderLabelValue(`{{ index .Labels "team" }}`, Data{
+			Labels: map[string]string{"team": "Payments_EU"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Payments_EU", out)
+	})
+
+	t.Run("Allows missing label", func(t *testing.T) {
+		t.Parallel()
+		out, err := RenderLabelValue(`{{ index .Labels "team" }}`, Data{})
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("Empty template", func(t *testing.T) {
+		t.Parallel()
+		_, err := RenderLabelValue("", Data{})
+		assert.EqualError(t, err, "template must not be empty")
+	})
+
+	t.Run("Rejects invalid label value", func(t *testing.T) {
+		t.Parallel()
+		out, err := RenderLabelValue(`{{ .Namespace }}/{{ .WorkloadName }}`, Data{
+			Namespace:    "ns",
+			WorkloadName: "demo",
+		})
+		require.Error(t, err)
+		assert.Empty(t, out)
+		assert.Contains(t, err.Error(), `rendered label value "ns/demo" is invalid`)
+	})
+}
+
+func TestFuncs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Lists all helpers", func(t *testing.T) {
+		t.Parallel()
+		names := make([]string, 0)
+		for name := range Funcs() {
+			names = append(names, name)
+		}
+		assert.ElementsMatch(t, []string{
+			"toLower", "toUpper", "title", "replace", "trim", "trimPrefix", "trimSuffix", "truncate", "dnsLabel", "regexReplace",
+		}, names)
+	})
+
+	t.Run("Returns a fresh copy", func(t *testing.T) {
+		t.Parallel()
+		funcs := Funcs()
+		delete(funcs, "toLower")
+		assert.Contains(t, Funcs(), "toLower")
+	})
+
+	t.Run("Helpers render through templates", func(t *testing.T) {
+		t.Parallel()
+		tests := map[string]struct {
+			tmpl string
+			want string
+		}{
+			"toLower":      {tmpl: `{{ toLower .WorkloadName }}`, want: "demo-app"},
+			"toUpper":      {tmpl: `{{ toUpper .Profile }}`, want: "P1"},
+			"title":        {tmpl: `{{ title .WorkloadName }}`, want: "Demo-App"},
+			"replace":      {tmpl: `{{ replace .WorkloadName "-" "_" }}`, want: "Demo_App"},
+			"trim":         {tmpl: `{{ trim "  x  " }}`, want: "x"},
+			"trimPrefix":   {tmpl: `{{ trimPrefix "Demo-" .WorkloadName }}`, want: "App"},
+			"trimSuffix":   {tmpl: `{{ .WorkloadName | trimSuffix "-App" }}`, want: "Demo"},
+			"truncate":     {tmpl: `{{ truncate .WorkloadName 4 }}`, want: "Demo"},
+			"dnsLabel":     {tmpl: `{{ dnsLabel .Kind }}`, want: "stateful-set"},
+			"regexReplace": {tmpl: `{{ regexReplace "-.*$" "" .WorkloadName }}`, want: "Demo"},
+		}
+		data := Data{WorkloadName: "Demo-App", Profile: "p1", Kind: "Stateful Set"}
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				out, err := RenderLabelValue(tc.tmpl, data)
+				require.NoError(t, err)
+				assert.Equal(t, tc.want, out)
+			})
+		}
+	})
+}
+
+func TestTruncateRunes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Returns empty when limit non-positive", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "", truncateRunes("hello", 0))
+		assert.Equal(t, "", truncateRunes("hello", -1))
+	})
+
+	t.Run("Returns original when shorter than limit", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "hi", truncateRunes("hi", 5))
+	})
+
+	t.Run("Truncates by rune count", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "hé", truncateRunes("héllo", 2))
+	})
+}
+
+func TestDNSLabel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Normalizes allowed characters", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "app-1", dnsLabel("App_1"))
+	})
+
+	t.Run("Trims disallowed edges", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "mid", dnsLabel("-mid."))
+	})
+
+	t.Run("Defaults to vpa on empty", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "vpa", dnsLabel("???"))
+	})
+}
+
+func TestTitle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Capitalizes every word", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "Api-Server.V2 Demo_App", title("api-server.v2 demo_app"))
+	})
+
+	t.Run("Leaves remaining letters untouched", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "MyAPP", title("myAPP"))
+	})
+
+	t.Run("Handles empty input", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, title(""))
+	})
+}
+
+func TestTrimPrefix(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "api", trimPrefix("legacy-", "legacy-api"))
+	assert.Equal(t, "api", trimPrefix("legacy-", "api"))
+	assert.Equal(t, "legacy-api", trimPrefix("", "legacy-api"))
+}
+
+func TestTrimSuffix(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "api", trimSuffix("-prod", "api-prod"))
+	assert.Equal(t, "api-prod-eu", trimSuffix("-prod", "api-prod-eu"))
+	assert.Equal(t, "api-prod", trimSuffix("", "api-prod"))
+}
+
+func TestRegexReplace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Replaces all matches", func(t *testing.T) {
+		t.Parallel()
+		out, err := regexReplace("[^a-z0-9-]+", "-", "my_app.v1")
+		require.NoError(t, err)
+		assert.Equal(t, "my-app-v1", out)
+	})
+
+	t.Run("Expands capture groups", func(t *testing.T) {
+		t.Parallel()
+		out, err := regexReplace("^(.*)-canary$", "${1}", "web-canary")
+		require.NoError(t, err)
+		assert.Equal(t, "web", out)
+	})
+
+	t.Run("Returns input when nothing matches", func(t *testing.T) {
+		t.Parallel()
+		out, err := regexReplace("[0-9]+", "", "demo")
+		require.NoError(t, err)
+		assert.Equal(t, "demo", out)
+	})
+
+	t.Run("Fails on invalid pattern", func(t *testing.T) {
+		t.Parallel()
+		_, err := regexReplace("[a-", "", "demo")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `regexReplace: invalid pattern "[a-"`)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"os/exec"
 	"testing"
 
+	"github.com/containeroo/autovpa/internal/app"
 	"github.com/containeroo/autovpa/test/testutils"
 
 	. "github.com/onsi/ginkgo/v2" // nolint:staticcheck
@@ -64,6 +65,7 @@ var _ = BeforeSuite(func() {
 	testutils.K8sClient, err = client.New(testCfg, client.Options{Scheme: testScheme})
 	Expect(err).NotTo(HaveOccurred())
 	testutils.NSManager = testutils.NewNamespaceManager()
+	testutils.RunOperator = app.Run
 
 	By("building autovpa binary")
 	cmd := exec.Command("go", "build", "-o", "../../bin/autovpa", "../../cmd/")
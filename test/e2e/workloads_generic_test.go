@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/containeroo/autovpa/internal/config"
 	"github.com/containeroo/autovpa/internal/controller"
 	"github.com/containeroo/autovpa/internal/utils"
 	"github.com/containeroo/autovpa/test/testutils"
@@ -27,6 +28,8 @@ import (
 	. "github.com/onsi/ginkgo/v2" // nolint:staticcheck
 	. "github.com/onsi/gomega"    // nolint:staticcheck
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	vpaautoscaling "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -252,9 +255,9 @@ var _ = Describe("Generic", Serial, Ordered, func() {
 		testutils.ExpectVPA(ctx, dep.GetNamespace(), newVPAName, managedLabel)
 		testutils.ExpectVPANotFound(ctx, dep.GetNamespace(), vpaName)
 
-		By("Verifying the expected 'deleted obsolete VPA' log line was emitted")
+		By("Verifying the expected 'deleted VPA' log line was emitted")
 		testutils.ContainsLogs(
-			fmt.Sprintf("\"deleted obsolete VPA\",\"vpa\":%q,\"namespace\":%q,\"workload\":%q", vpaName, ns, dep.Name),
+			fmt.Sprintf("\"deleted VPA\",\"workload\":%q,\"vpa\":%q,\"namespace\":%q,\"kind\":\"Deployment\",\"reason\":\"obsolete\"", dep.Name, vpaName, ns),
 			4*time.Second,
 			1*time.Second,
 		)
@@ -296,6 +299,53 @@ var _ = Describe("Generic", Serial, Ordered, func() {
 		testutils.ExpectVPASpec(ctx, dep.GetNamespace(), vpaName, expected)
 	})
 
+	It("Keeps the profile container policy for workloads with explicit resources", func(ctx SpecContext) {
+		name := testutils.GenerateUniqueName("dep")
+
+		By("Creating an opted-in Deployment with container requests and limits")
+		dep := testutils.CreateDeployment(ctx, ns, name,
+			testutils.WithAnnotation(profileKey, "auto"),
+			testutils.WithResources(
+				corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("50m"),
+					corev1.ResourceMemory: resource.MustParse("32Mi"),
+				},
+				corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("200m"),
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			),
+		)
+
+		By("Waiting for the managed VPA to exist")
+		vpaName := dep.GetName() + "-vpa"
+		testutils.ExpectVPA(ctx, dep.GetNamespace(), vpaName, managedLabel)
+
+		By("Verifying the wildcard policy targets the workload unchanged by its resources")
+		testutils.ExpectVPASpec(ctx, dep.GetNamespace(), vpaName, map[string]any{
+			"targetRef": map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"name":       dep.GetName(),
+			},
+			"resourcePolicy": map[string]any{
+				"containerPolicies": []any{
+					map[string]any{
+						"containerName": "*",
+						"controlledResources": []any{
+							"cpu",
+							"memory",
+						},
+						"minAllowed": map[string]any{
+							"cpu":    "20m",
+							"memory": "64Mi",
+						},
+					},
+				},
+			},
+		})
+	})
+
 	It("Deployment restart does not trigger a VPA update", func(ctx SpecContext) {
 		name := testutils.GenerateUniqueName("dep")
 
@@ -486,4 +536,45 @@ var _ = Describe("Generic", Serial, Ordered, func() {
 		By("Waiting for the obsolete VPA to be deleted")
 		testutils.ExpectVPANotFound(ctx, dep.GetNamespace(), vpaName)
 	})
+
+	It("Skips workloads in a terminating namespace", func(ctx SpecContext) {
+		name := testutils.GenerateUniqueName("dep")
+		const holdFinalizer = "autovpa.containeroo.ch/e2e-hold"
+
+		By("Creating a Deployment that is not opted in and holds namespace deletion")
+		dep := testutils.CreateDeployment(ctx, ns, name, testutils.WithFinalizer(holdFinalizer))
+		DeferCleanup(func(ctx SpecContext) {
+			patch := client.MergeFrom(dep.DeepCopy())
+			dep.SetFinalizers(nil)
+			Expect(client.IgnoreNotFound(testutils.K8sClient.Patch(ctx, dep, patch))).To(Succeed())
+		})
+
+		By("Deleting the namespace and waiting for it to terminate")
+		testutils.NSManager.DeleteNamespace(ctx, ns)
+		Eventually(func(g Gomega) {
+			namespace := &corev1.Namespace{}
+			g.Expect(testutils.K8sClient.Get(ctx, client.ObjectKey{Name: ns}, namespace)).To(Succeed())
+			g.Expect(namespace.Status.Phase).To(Equal(corev1.NamespaceTerminating))
+			g.Expect(testutils.K8sClient.Get(ctx, client.ObjectKeyFromObject(dep), dep)).To(Succeed())
+			g.Expect(dep.GetDeletionTimestamp()).ToNot(BeNil())
+		}).WithContext(ctx).Within(30 * time.Second).ProbeEvery(1 * time.Second).Should(Succeed())
+
+		By("Opting the Deployment in")
+		patch := client.MergeFrom(dep.DeepCopy())
+		dep.SetAnnotations(map[string]string{profileKey: "default"})
+		Expect(testutils.K8sClient.Patch(ctx, dep, patch)).To(Succeed())
+
+		By("Verifying the workload is skipped without creating a VPA")
+		testutils.ContainsLogs(
+			fmt.Sprintf("\"namespace terminating; skipping VPA reconciliation\",\"namespace\":%q,\"workload\":%q", ns, dep.Name),
+			10*time.Second,
+			1*time.Second,
+		)
+		vpaName, err := controller.DesiredVPAName(controller.ProfileConfig{
+			NameTemplate: VPANameTemplate,
+			Entries:      map[string]config.Profile{"default": {}},
+		}, dep, DeploymentGVK, "default")
+		Expect(err).ToNot(HaveOccurred())
+		testutils.ExpectVPANotFound(ctx, ns, vpaName)
+	})
 })
@@ -41,9 +41,9 @@ var _ = Describe("Namespaced mode", Serial, Ordered, func() {
 		By("Creating the watched namespace")
 		ns = testutils.NSManager.CreateNamespace(context.Background())
 
-		By("Starting operator in namespaced mode, watching only the created namespace")
+		By("Starting operator in-process in namespaced mode, watching only the created namespace")
 		configPath := testutils.WriteProfiles("autovpa-profiles.yaml")
-		testutils.StartOperatorWithFlags([]string{
+		testutils.StartOperatorInProcess([]string{
 			"--leader-elect=false",
 			"--metrics-enabled=false",
 			"--profile-annotation=" + profileKey,
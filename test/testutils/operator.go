@@ -28,8 +28,14 @@ import (
 	. "github.com/onsi/gomega"    // nolint:staticcheck
 )
 
+// RunOperator is the operator entrypoint used by StartOperatorInProcess. The e2e
+// suite sets it to app.Run; testutils cannot import the app package because
+// the controller unit tests import testutils.
+var RunOperator func(ctx context.Context, version string, args []string, stdOut, stdErr io.Writer) error
+
 var (
 	operatorCmd    *exec.Cmd
+	operatorDone   chan error
 	operatorCancel context.CancelFunc
 )
 
@@ -57,7 +63,34 @@ func StartOperatorWithFlags(flags []string) {
 	CountLogOccurrences(`starting manager`, 1, 90*time.Second, 2*time.Second)
 }
 
-// StopOperator stops the operator process.
+// StartOperatorInProcess runs RunOperator with the given flags in a goroutine of
+// the test process and checks that it is ready. It talks to the same cluster
+// as the suite (via KUBECONFIG) and writes its logs to LogBuffer, so specs can
+// assert on them without building and spawning the binary.
+func StartOperatorInProcess(flags []string) {
+	Expect(RunOperator).NotTo(BeNil(), "testutils.RunOperator must be set to app.Run")
+
+	// Stop any previously running operator to avoid port conflicts.
+	StopOperator()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	operatorCancel = cancel
+
+	output := io.MultiWriter(LogBuffer, GinkgoWriter)
+	args := append(append([]string{}, flags...), "--skip-controller-name-validation=true")
+
+	done := make(chan error, 1)
+	go func() {
+		defer GinkgoRecover()
+		done <- RunOperator(ctx, "e2e", args, output, output)
+	}()
+	operatorDone = done
+
+	// Wait until Operator is ready
+	CountLogOccurrences(`starting manager`, 1, 90*time.Second, 2*time.Second)
+}
+
+// StopOperator stops the operator, whether started as a process or in-process.
 func StopOperator() {
 	if operatorCancel != nil {
 		operatorCancel()
@@ -68,7 +101,12 @@ func StopOperator() {
 		operatorCmd.Wait() // nolint:errcheck
 	}
 
+	if operatorDone != nil {
+		Eventually(operatorDone, 30*time.Second).Should(Receive(Succeed()), "in-process operator did not stop")
+	}
+
 	LogBuffer.Reset()
 	operatorCmd = nil
+	operatorDone = nil
 	operatorCancel = nil
 }
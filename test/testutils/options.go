@@ -18,6 +18,7 @@ package testutils
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -55,6 +56,13 @@ func WithLabel(key, value string) Option {
 	}
 }
 
+// WithFinalizer adds a finalizer to a resource.
+func WithFinalizer(finalizer string) Option {
+	return func(resource client.Object) {
+		resource.SetFinalizers(append(resource.GetFinalizers(), finalizer))
+	}
+}
+
 // WithReplicas sets the replicas for a resource.
 func WithReplicas(replicas int32) Option {
 	return func(resource client.Object) {
@@ -66,3 +74,26 @@ func WithReplicas(replicas int32) Option {
 		}
 	}
 }
+
+// WithResources sets the resource requests and limits on every container of a workload.
+func WithResources(requests, limits corev1.ResourceList) Option {
+	return func(resource client.Object) {
+		var podSpec *corev1.PodSpec
+		switch obj := resource.(type) {
+		case *appsv1.Deployment:
+			podSpec = &obj.Spec.Template.Spec
+		case *appsv1.StatefulSet:
+			podSpec = &obj.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			podSpec = &obj.Spec.Template.Spec
+		default:
+			return
+		}
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].Resources = corev1.ResourceRequirements{
+				Requests: requests.DeepCopy(),
+				Limits:   limits.DeepCopy(),
+			}
+		}
+	}
+}